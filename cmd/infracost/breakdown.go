@@ -50,6 +50,8 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable with --terraform-force-cli")
 	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table", "html"})
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().Bool("recommendations", false, "Show local cost optimisation recommendations for resources (experimental)")
+	cmd.Flags().Bool("carbon", false, "Show estimated carbon emissions in kgCO2e alongside costs (experimental)")
 
 	// This is deprecated and will show a warning if used without --terraform-force-cli
 	_ = cmd.Flags().MarkHidden("terraform-use-state")
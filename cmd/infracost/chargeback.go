@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/chargeback"
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func chargebackCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chargeback",
+		Short: "Allocate costs from Infracost JSON files to owners",
+		Long: `Allocate the monthly cost of each resource in Infracost JSON files to owners.
+
+Owners are found using, in order:
+  1. Resource tags, see --tag-key
+  2. Path ownership rules from a CODEOWNERS style file, see --owners-file
+  3. A fallback owner, see --fallback
+
+Shared resources can be split between owners by percentage, either in the tag
+value or in the ownership file, e.g. "team-a:60,team-b:40". Any cost that is not
+allocated to an owner is reported as unallocated.`,
+		Example: `  Allocate costs using the team tag:
+
+      infracost chargeback --path infracost.json --tag-key team
+
+  Allocate costs using tags, then path ownership rules, then a fallback owner:
+
+      infracost chargeback --path infracost.json --owners-file OWNERS --fallback platform
+
+  Create a CSV report of each resource allocation:
+
+      infracost chargeback --path "out*.json" --owners-file OWNERS --format csv --out-file chargeback.csv # glob needs quotes
+
+  Example ownership file, the last matching rule wins:
+
+      # pattern          owners
+      *                  platform
+      /apps/payments/    team-payments
+      /shared/           team-a:60 team-b:40`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := output.LoadPaths(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs)
+			if errors.As(err, &clierror.WarningError{}) {
+				ui.PrintWarningf(cmd.ErrOrStderr(), err.Error())
+			} else if err != nil {
+				return err
+			}
+
+			opts := chargeback.Options{}
+			opts.TagKeys, _ = cmd.Flags().GetStringSlice("tag-key")
+			opts.Fallback, _ = cmd.Flags().GetString("fallback")
+
+			if ownersFile, _ := cmd.Flags().GetString("owners-file"); ownersFile != "" {
+				opts.Ownership, err = chargeback.LoadOwnership(ownersFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			report, err := chargeback.Allocate(combined, opts)
+			if err != nil {
+				return err
+			}
+
+			format, _ := cmd.Flags().GetString("format")
+			b, err := chargeback.Format(format, report)
+			if err != nil {
+				return err
+			}
+
+			if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
+				return saveOutFile(ctx, cmd, outFile, b)
+			}
+
+			cmd.Println(string(b))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	cmd.Flags().StringP("out-file", "o", "", "Save output to a file, helpful with format flag")
+	cmd.Flags().StringSlice("tag-key", []string{"team"}, "Comma separated list of resource tag keys used to find the owner of a resource")
+	cmd.Flags().String("owners-file", "", "Path to a CODEOWNERS style file mapping paths to owners")
+	cmd.Flags().String("fallback", "", "Owner for resources that are not matched by a tag or ownership rule")
+	newEnumFlag(cmd, "format", "table", "Output format", []string{"table", "csv", "json"})
+
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	return cmd
+}
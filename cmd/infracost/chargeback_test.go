@@ -0,0 +1,29 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestChargebackHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"chargeback", "--help"}, nil)
+}
+
+func TestChargebackTagsAndOwners(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"chargeback", "--path", "./testdata/chargeback_tags_and_owners/infracost.json", "--owners-file", "./testdata/chargeback_tags_and_owners/OWNERS"}, nil)
+}
+
+func TestChargebackFallback(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"chargeback", "--path", "./testdata/chargeback_tags_and_owners/infracost.json", "--fallback", "unowned"}, nil)
+}
+
+func TestChargebackFormatCSV(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"chargeback", "--format", "csv", "--path", "./testdata/chargeback_tags_and_owners/infracost.json", "--owners-file", "./testdata/chargeback_tags_and_owners/OWNERS"}, nil)
+}
+
+func TestChargebackFormatJSON(t *testing.T) {
+	opts := DefaultOptions()
+	opts.IsJSON = true
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"chargeback", "--format", "json", "--path", "./testdata/chargeback_tags_and_owners/infracost.json", "--owners-file", "./testdata/chargeback_tags_and_owners/OWNERS"}, opts)
+}
@@ -27,6 +27,7 @@ var (
 	versionRegex     = regexp.MustCompile(`Infracost v.*`)
 	panicRegex       = regexp.MustCompile(`runtime\serror:([\w\d\n\r\[\]\:\/\.\\(\)\+\,\{\}\*\@\s\?]*)Environment`)
 	pathRegex        = regexp.MustCompile(`(/.*/)(infracost/infracost/cmd/infracost/testdata/.*)`)
+	durationRegex    = regexp.MustCompile(`(took |"duration": ")([0-9.]+[a-zµ]+)+`)
 )
 
 type GoldenFileOptions = struct {
@@ -225,6 +226,7 @@ func stripDynamicValues(actual []byte) []byte {
 	actual = versionRegex.ReplaceAll(actual, []byte("Infracost vREPLACED_VERSION"))
 	actual = panicRegex.ReplaceAll(actual, []byte("runtime error: REPLACED ERROR\nEnvironment"))
 	actual = pathRegex.ReplaceAll(actual, []byte("REPLACED_PROJECT_PATH/$2"))
+	actual = durationRegex.ReplaceAll(actual, []byte("${1}REPLACED_DURATION"))
 
 	return actual
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func debugCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Debug how Infracost evaluates your projects",
+		Long:  "Debug how Infracost evaluates your projects",
+		Example: `  Render the evaluation graph of a Terraform directory:
+
+      infracost debug graph --path /code`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmds := []*cobra.Command{debugGraphCmd(ctx)}
+	cmd.AddCommand(cmds...)
+
+	return cmd
+}
+
+func debugGraphCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Render the evaluation graph of Terraform directories",
+		Long: `Render the evaluation graph of Terraform directories.
+
+Each vertex of the graph is a block, or a local value, of the project and its
+modules. Vertices are evaluated after all the vertices that they have an edge
+from. Each vertex is shown with its evaluation status, how long it took to
+evaluate and the value it evaluated to. The status is one of:
+
+  evaluated  the vertex was evaluated and its value is known
+  mocked     the value of the vertex uses mocked or unknown values
+  errored    the vertex couldn't be evaluated
+  skipped    the vertex wasn't visited`,
+		Example: `  Render the evaluation graph in the Graphviz DOT language:
+
+      infracost debug graph --path /code | dot -Tsvg > graph.svg
+
+  Render the evaluation graph as a Mermaid flowchart:
+
+      infracost debug graph --path /code --format mermaid`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			return runDebugGraph(cmd, ctx)
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path flag")
+	cmd.Flags().StringSlice("exclude-path", nil, "Paths of directories to exclude, glob patterns need quotes")
+	newEnumFlag(cmd, "format", "dot", "Output format", []string{"dot", "mermaid", "json"})
+
+	_ = cmd.MarkFlagDirname("path")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+
+	return cmd
+}
+
+// debugGraphProject is the evaluation graph of a single project.
+type debugGraphProject struct {
+	Path string `json:"path"`
+	*hcl.GraphExport
+}
+
+func runDebugGraph(cmd *cobra.Command, runCtx *config.RunContext) error {
+	// The evaluation graph is only built by the graph evaluator so make sure
+	// that it's used, without changing how any other commands are run.
+	if os.Getenv("INFRACOST_GRAPH_EVALUATOR") != "true" {
+		os.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+		defer os.Unsetenv("INFRACOST_GRAPH_EVALUATOR")
+	}
+
+	var projects []debugGraphProject
+
+	for _, project := range runCtx.Config.Projects {
+		detected, err := providers.Detect(runCtx, project, true)
+		if err != nil {
+			return err
+		}
+
+		for _, provider := range detected {
+			hp, ok := provider.(*terraform.HCLProvider)
+			if !ok {
+				continue
+			}
+
+			parsed := hp.Module()
+			if parsed.Error != nil {
+				return parsed.Error
+			}
+
+			g := hp.Parser.Graph()
+			if g == nil {
+				continue
+			}
+
+			projects = append(projects, debugGraphProject{
+				Path:        filepath.Join(project.Path, hp.RelativePath()),
+				GraphExport: g.Export(),
+			})
+		}
+	}
+
+	if len(projects) == 0 {
+		return fmt.Errorf("No Terraform directories found. Check the %s flag is the path to a directory containing Terraform files", ui.PrimaryString("--path"))
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	if format == "json" {
+		b, err := json.MarshalIndent(projects, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		cmd.Println(string(b))
+		return nil
+	}
+
+	parts := make([]string, 0, len(projects))
+	for _, p := range projects {
+		if format == "mermaid" {
+			parts = append(parts, fmt.Sprintf("%%%% Terraform directory: %s\n%s", p.Path, p.Mermaid()))
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("// Terraform directory: %s\n%s", p.Path, p.DOT()))
+	}
+
+	cmd.Print(strings.Join(parts, "\n"))
+
+	return nil
+}
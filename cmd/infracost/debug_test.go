@@ -0,0 +1,31 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestDebugHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"debug", "--help"}, nil)
+}
+
+func TestDebugGraphHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"debug", "graph", "--help"}, nil)
+}
+
+func TestDebugGraph(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"debug", "graph", "--path", "./testdata/debug_graph"}, nil)
+}
+
+func TestDebugGraphMermaid(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"debug", "graph", "--path", "./testdata/debug_graph", "--format", "mermaid"}, nil)
+}
+
+func TestDebugGraphJSON(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"debug", "graph", "--path", "./testdata/debug_graph", "--format", "json"}, nil)
+}
+
+func TestDebugGraphNoTerraform(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"debug", "graph", "--path", "./testdata/example_plan.json"}, nil)
+}
@@ -63,6 +63,7 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().String("compare-to", "", "Path to Infracost JSON file to compare against")
 	newEnumFlag(cmd, "format", "diff", "Output format", []string{"json", "diff"})
 	cmd.Flags().String("out-file", "", "Save output to a file")
+	cmd.Flags().Bool("carbon", false, "Show estimated carbon emissions in kgCO2e alongside costs (experimental)")
 
 	return cmd
 }
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/explain"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func explainCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <address>",
+		Short: "Explain how the cost of a resource was calculated",
+		Long: `Explain how the cost of a resource was calculated.
+
+For each cost component of the resource this shows the product and price
+filters used to look up the price, the matched product and price, and the
+quantity arithmetic. It also shows the usage values that were applied and
+where they came from: the usage file, resource_type_default_usage, an
+Infracost Cloud estimate or the resource default.`,
+		Example: `  Explain a resource in a Terraform directory:
+
+      infracost explain aws_instance.web --path /code
+
+  Explain a resource using values from a usage file:
+
+      infracost explain 'module.app.aws_lambda_function.api["prod"]' --path /code --usage-file infracost-usage.yml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			return runExplain(cmd, ctx, args[0])
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().String("out-file", "", "Save output to a file, helpful with format flag")
+	newEnumFlag(cmd, "format", "text", "Output format", []string{"text", "json"})
+
+	return cmd
+}
+
+func runExplain(cmd *cobra.Command, runCtx *config.RunContext, address string) error {
+	// The matched product attributes aren't needed for normal runs so they are
+	// only requested from the pricing API when explaining a resource.
+	apiclient.GetPricingAPIClient(runCtx).IncludeProductAttributes = true
+
+	pr, err := newParallelRunner(cmd, runCtx)
+	if err != nil {
+		return err
+	}
+
+	projectResults, err := pr.run()
+	if err != nil {
+		return err
+	}
+
+	var e *explain.Explanation
+
+	for _, result := range projectResults {
+		for _, project := range result.projectOut.projects {
+			partial, r := findResource(project, address)
+			if partial == nil {
+				continue
+			}
+
+			usageMap := schema.NewUsageMap(map[string]*schema.UsageData{})
+			if f := result.ctx.ProjectConfig.UsageFile; f != "" {
+				usageFile, err := usage.LoadUsageFile(f)
+				if err != nil {
+					return err
+				}
+				usageMap = usageFile.ToUsageDataMap()
+			}
+
+			e = explain.New(project.Name, partial, r, usageMap, runCtx.Config.Currency)
+			break
+		}
+
+		if e != nil {
+			break
+		}
+	}
+
+	if e == nil {
+		return fmt.Errorf("Resource %s not found. Check the address matches a resource in the output of 'infracost breakdown --format json'", address)
+	}
+
+	var b []byte
+	if runCtx.Config.Format == "json" {
+		b, err = explain.ToJSON(e)
+	} else {
+		b, err = explain.ToText(e)
+	}
+	if err != nil {
+		return err
+	}
+
+	pricingClient := apiclient.GetPricingAPIClient(runCtx)
+	err = pricingClient.AddEvent("infracost-explain", runCtx.EventEnv())
+	if err != nil {
+		logging.Logger.Debug().Err(err).Msg("could not report `infracost-explain` event")
+	}
+
+	if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
+		return saveOutFile(runCtx, cmd, outFile, b)
+	}
+
+	cmd.Println(string(b))
+
+	return nil
+}
+
+// findResource returns the partial resource with the given address and the
+// resource that was built from it. Project.BuildResources builds the resources
+// in the same order as the partial resources so they can be matched by index.
+func findResource(project *schema.Project, address string) (*schema.PartialResource, *schema.Resource) {
+	for i, partial := range project.PartialResources {
+		if partial.Address != address || i >= len(project.Resources) {
+			continue
+		}
+
+		return partial, project.Resources[i]
+	}
+
+	return nil, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/infracost/infracost/internal/hcl"
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/vcs"
 )
@@ -219,7 +221,129 @@ func (g *generateConfigCommand) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func newGenerateCommand() *cobra.Command {
+type generateTFVarsCommand struct {
+	runCtx  *config.RunContext
+	outFile string
+}
+
+func newGenerateTFVarsCommand(ctx *config.RunContext) *cobra.Command {
+	gen := generateTFVarsCommand{runCtx: ctx}
+
+	cmd := &cobra.Command{
+		Use:   "tfvars",
+		Short: "Generate a Terraform var file template for missing variables",
+		Long: `Generate a Terraform var file template for the missing variables of Terraform directories.
+
+The template has a commented out value for each variable that doesn't have a value
+and is used by resources, so it can change the costs. Fill in the values and pass the
+file to Infracost with the --terraform-var-file flag.`,
+		Example: `
+      infracost generate tfvars --path /code --out-file infracost.tfvars
+      `,
+		Args: cobra.NoArgs,
+		RunE: gen.run,
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path, terraform-var-file or terraform-var flags")
+	cmd.Flags().StringSlice("terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
+	cmd.Flags().StringSlice("terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
+	cmd.Flags().StringSlice("exclude-path", nil, "Paths of directories to exclude, glob patterns need quotes")
+	cmd.Flags().StringVar(&gen.outFile, "out-file", "", "Save output to a file")
+
+	_ = cmd.MarkFlagDirname("path")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+
+	return cmd
+}
+
+func (g *generateTFVarsCommand) run(cmd *cobra.Command, args []string) error {
+	err := loadRunFlags(g.runCtx.Config, cmd)
+	if err != nil {
+		return err
+	}
+
+	type projectVars struct {
+		path string
+		vars []hcl.MissingVariable
+	}
+
+	var found bool
+	var projects []projectVars
+
+	for _, project := range g.runCtx.Config.Projects {
+		detected, err := providers.Detect(g.runCtx, project, true)
+		if err != nil {
+			return err
+		}
+
+		for _, provider := range detected {
+			hp, ok := provider.(*terraform.HCLProvider)
+			if !ok {
+				continue
+			}
+			found = true
+
+			parsed := hp.Module()
+			if parsed.Error != nil {
+				return parsed.Error
+			}
+
+			var vars []hcl.MissingVariable
+			for _, v := range parsed.Module.MissingVariables {
+				if v.CostRelevant {
+					vars = append(vars, v)
+				}
+			}
+
+			if len(vars) > 0 {
+				projects = append(projects, projectVars{path: filepath.Join(project.Path, hp.RelativePath()), vars: vars})
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("No Terraform directories found. Check the %s flag is the path to a directory containing Terraform files", ui.PrimaryString("--path"))
+	}
+
+	if len(projects) == 0 {
+		ui.PrintSuccess(cmd.ErrOrStderr(), "All the variables that are used by resources have values, there's nothing to generate.")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for i, p := range projects {
+		// a var file is only for a single directory so label each template
+		// when there are multiple.
+		if len(projects) > 1 {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(fmt.Sprintf("# Terraform directory: %s\n", p.path))
+		}
+
+		buf.Write(hcl.TFVarsTemplate(p.vars))
+	}
+
+	var out io.Writer = cmd.OutOrStdout()
+	if g.outFile != "" {
+		f, err := os.Create(g.outFile)
+		if err != nil {
+			return fmt.Errorf("could not create out file %s: %s", g.outFile, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	_, err = buf.WriteTo(out)
+	if err != nil {
+		return fmt.Errorf("could not write file %s: %s", g.outFile, err)
+	}
+
+	return nil
+}
+
+func newGenerateCommand(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Generate configuration to help run Infracost",
@@ -227,6 +351,10 @@ func newGenerateCommand() *cobra.Command {
 		Example: ` Generate Infracost config file from a template file:
 
       infracost generate config --repo-path . --template-path infracost.yml.tmpl
+
+  Generate a Terraform var file template for missing variables:
+
+      infracost generate tfvars --path . --out-file infracost.tfvars
       `,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -235,6 +363,7 @@ func newGenerateCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newGenerateConfigCommand())
+	cmd.AddCommand(newGenerateTFVarsCommand(ctx))
 
 	return cmd
 }
@@ -30,6 +30,20 @@ func TestGenerateConfig(t *testing.T) {
 		nil)
 }
 
+func TestGenerateTfvars(t *testing.T) {
+	dir := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(
+		t,
+		dir,
+		[]string{
+			"generate",
+			"tfvars",
+			"--path",
+			path.Join("./testdata", dir),
+		},
+		nil)
+}
+
 func TestGenerateConfigWarning(t *testing.T) {
 	dir := testutil.CalcGoldenFileTestdataDirName()
 	GoldenFileCommandTest(
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func graphCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show the dependency graph of Terragrunt units",
+		Long: `Show the dependency graph of Terragrunt units.
+
+Terragrunt units are evaluated in the order they are listed, so that the
+outputs of a unit are evaluated before they are used by a dependency block in
+another unit. Units that are in a dependency cycle are marked, any outputs of a
+unit in the cycle that are used before that unit has been evaluated are mocked.`,
+		Example: `  Show the dependency graph of a Terragrunt directory:
+
+      infracost graph --path /code
+
+  Show the dependency graph as JSON:
+
+      infracost graph --path /code --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			return runGraph(cmd, ctx)
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the Terragrunt directory")
+	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path flag")
+	cmd.Flags().StringSlice("exclude-path", nil, "Paths of directories to exclude, glob patterns need quotes")
+	newEnumFlag(cmd, "format", "text", "Output format", []string{"text", "json"})
+
+	_ = cmd.MarkFlagDirname("path")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+
+	return cmd
+}
+
+func runGraph(cmd *cobra.Command, runCtx *config.RunContext) error {
+	var graphs []*terraform.TerragruntGraph
+
+	for _, project := range runCtx.Config.Projects {
+		detected, err := providers.Detect(runCtx, project, true)
+		if err != nil {
+			return err
+		}
+
+		var projectGraphs []*terraform.TerragruntGraph
+		for _, provider := range detected {
+			tg, ok := provider.(*terraform.TerragruntHCLProvider)
+			if !ok {
+				continue
+			}
+
+			g, err := tg.Graph()
+			if err != nil {
+				return err
+			}
+
+			projectGraphs = append(projectGraphs, g)
+		}
+
+		if len(projectGraphs) > 0 {
+			graphs = append(graphs, terraform.MergeTerragruntGraphs(project.Path, projectGraphs...))
+		}
+	}
+
+	if len(graphs) == 0 {
+		return fmt.Errorf("No Terragrunt directories found. Check the %s flag is the path to a directory containing a terragrunt.hcl file", ui.PrimaryString("--path"))
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	if format == "json" {
+		b, err := json.MarshalIndent(graphs, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		cmd.Println(string(b))
+		return nil
+	}
+
+	parts := make([]string, 0, len(graphs))
+	for _, g := range graphs {
+		parts = append(parts, fmt.Sprintf("%s %s\n\n%s", ui.BoldString("Terragrunt directory:"), g.Path, g.String()))
+	}
+
+	cmd.Print(strings.Join(parts, "\n"))
+
+	return nil
+}
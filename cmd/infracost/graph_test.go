@@ -0,0 +1,23 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestGraphHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"graph", "--help"}, nil)
+}
+
+func TestGraphTerragrunt(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"graph", "--path", "./testdata/graph_terragrunt"}, nil)
+}
+
+func TestGraphTerragruntJSON(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"graph", "--path", "./testdata/graph_terragrunt", "--format", "json"}, nil)
+}
+
+func TestGraphNoTerragrunt(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"graph", "--path", "./testdata/example_plan.json"}, nil)
+}
@@ -190,12 +190,17 @@ func newRootCmd(ctx *config.RunContext) *cobra.Command {
 	rootCmd.AddCommand(configureCmd(ctx))
 	rootCmd.AddCommand(diffCmd(ctx))
 	rootCmd.AddCommand(breakdownCmd(ctx))
+	rootCmd.AddCommand(explainCmd(ctx))
+	rootCmd.AddCommand(graphCmd(ctx))
+	rootCmd.AddCommand(debugCmd(ctx))
+	rootCmd.AddCommand(whatifCmd(ctx))
 	rootCmd.AddCommand(outputCmd(ctx))
 	rootCmd.AddCommand(uploadCmd(ctx))
+	rootCmd.AddCommand(chargebackCmd(ctx))
 	rootCmd.AddCommand(commentCmd(ctx))
 	rootCmd.AddCommand(completionCmd())
 	rootCmd.AddCommand(figAutocompleteCmd())
-	rootCmd.AddCommand(newGenerateCommand())
+	rootCmd.AddCommand(newGenerateCommand(ctx))
 
 	rootCmd.SetUsageTemplate(fmt.Sprintf(`%s{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
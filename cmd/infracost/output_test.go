@@ -219,3 +219,29 @@ func TestOutputTerraformOutFileTable(t *testing.T) {
 func TestOutputJSONArrayPath(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--path", "[\"./testdata/example_out.json\", \"./testdata/terraform_v0.14*breakdown.json\"]"}, nil)
 }
+
+func TestOutputFormatTableWithRecommendations(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName, []string{"output", "--format", "table", "--path", path.Join("./testdata", testName, "infracost.json")}, nil)
+}
+
+func TestOutputFormatGitHubCommentWithRecommendations(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--path", "./testdata/output_format_table_with_recommendations/infracost.json"}, nil)
+}
+
+func TestOutputFormatBitbucketCommentWithRecommendations(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "bitbucket-comment", "--path", "./testdata/output_format_table_with_recommendations/infracost.json"}, nil)
+}
+
+func TestOutputFormatTableWithCarbon(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	GoldenFileCommandTest(t, testName, []string{"output", "--format", "table", "--path", path.Join("./testdata", testName, "infracost.json")}, nil)
+}
+
+func TestOutputFormatGitHubCommentWithCarbon(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--path", "./testdata/output_format_table_with_carbon/infracost.json"}, nil)
+}
+
+func TestOutputFormatBitbucketCommentWithCarbon(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "bitbucket-comment", "--path", "./testdata/output_format_table_with_carbon/infracost.json"}, nil)
+}
@@ -22,11 +22,14 @@ import (
 	"github.com/infracost/infracost/internal/vcs"
 
 	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/carbon"
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/output"
 	"github.com/infracost/infracost/internal/prices"
 	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/recommendations"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/usage"
@@ -49,6 +52,8 @@ func addRunFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice("terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
 	cmd.Flags().StringSlice("terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
 	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory or JSON/plan file")
+	cmd.Flags().StringSlice("parameter-file", nil, "Load CloudFormation or ARM template parameter files. Provided files must be relative to the template's directory")
+	cmd.Flags().StringSlice("parameter-overrides", nil, "Set value for a CloudFormation or ARM template parameter, similar to the --parameter-overrides flag of 'aws cloudformation deploy'")
 
 	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
 	cmd.Flags().String("usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
@@ -71,6 +76,8 @@ func addRunFlags(cmd *cobra.Command) {
 
 	cmd.Flags().Bool("sync-usage-file", false, "Sync usage-file with missing resources, needs usage-file too (experimental)")
 
+	cmd.Flags().Bool("prompt-missing-vars", false, "Prompt for the values of missing Terraform variables when running interactively. Values are saved to .infracost/prompted.tfvars in the Terraform directory")
+
 	_ = cmd.MarkFlagFilename("path", "json", "tf")
 	_ = cmd.MarkFlagFilename("config-file", "yml")
 	_ = cmd.MarkFlagFilename("usage-file", "yml")
@@ -337,6 +344,23 @@ func (r *parallelRunner) printParallelMsg(queue []projectJob) {
 	}
 }
 
+// addRecommendations evaluates the local recommendation rules against the
+// priced project. Errors are logged rather than returned since recommendations
+// shouldn't stop the costs from being shown.
+func (r *parallelRunner) addRecommendations(project *schema.Project) {
+	engine := recommendations.NewEngine(terraform.BuildResourceFromData, func(resources []*schema.Resource) error {
+		return prices.GetPricesConcurrent(r.runCtx, apiclient.GetPricingAPIClient(r.runCtx), resources)
+	})
+
+	recs, err := engine.Evaluate(project)
+	if err != nil {
+		logging.Logger.Warn().Err(err).Msgf("could not evaluate recommendations for project %s", project.Name)
+		return
+	}
+
+	project.Recommendations = recs
+}
+
 func (r *parallelRunner) runProvider(job projectJob) (*projectOutput, error) {
 	mux := r.pathMuxs[job.ctx.ProjectConfig.Path]
 	if mux != nil {
@@ -474,6 +498,14 @@ func (r *parallelRunner) runProvider(job projectJob) (*projectOutput, error) {
 		}
 		schema.CalculateCosts(project)
 
+		if r.runCtx.Config.Carbon {
+			carbon.NewEstimator().Estimate(project)
+		}
+
+		if r.runCtx.Config.Recommendations {
+			r.addRecommendations(project)
+		}
+
 		project.CalculateDiff()
 	}
 
@@ -719,12 +751,14 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		cmd.Flags().Changed("terraform-plan-flags") ||
 		cmd.Flags().Changed("terraform-var-file") ||
 		cmd.Flags().Changed("terraform-var") ||
+		cmd.Flags().Changed("parameter-file") ||
+		cmd.Flags().Changed("parameter-overrides") ||
 		cmd.Flags().Changed("terraform-init-flags") ||
 		cmd.Flags().Changed("terraform-workspace"))
 
 	if hasConfigFile && hasProjectFlags {
 		m := "--config-file flag cannot be used with the following flags: "
-		m += "--path, --project-name, --terraform-*, --usage-file"
+		m += "--path, --project-name, --terraform-*, --parameter-*, --usage-file"
 		ui.PrintUsage(cmd)
 		return errors.New(m)
 	}
@@ -739,6 +773,11 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		projectCfg.TerraformVarFiles, _ = cmd.Flags().GetStringSlice("terraform-var-file")
 		tfVars, _ := cmd.Flags().GetStringSlice("terraform-var")
 		projectCfg.TerraformVars = tfVarsToMap(tfVars)
+		projectCfg.CloudFormationParameterFiles, _ = cmd.Flags().GetStringSlice("parameter-file")
+		cfnParams, _ := cmd.Flags().GetStringSlice("parameter-overrides")
+		projectCfg.CloudFormationParameters = tfVarsToMap(cfnParams)
+		projectCfg.ARMParameterFiles = projectCfg.CloudFormationParameterFiles
+		projectCfg.ARMParameters = projectCfg.CloudFormationParameters
 		projectCfg.UsageFile, _ = cmd.Flags().GetString("usage-file")
 		projectCfg.Name, _ = cmd.Flags().GetString("project-name")
 		projectCfg.TerraformForceCLI, _ = cmd.Flags().GetBool("terraform-force-cli")
@@ -779,6 +818,9 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
+	cfg.PromptMissingVars, _ = cmd.Flags().GetBool("prompt-missing-vars")
+	cfg.Recommendations, _ = cmd.Flags().GetBool("recommendations")
+	cfg.Carbon, _ = cmd.Flags().GetBool("carbon")
 
 	includeAllFields := "all"
 	validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
@@ -0,0 +1,17 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_requesting_account_id  = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+variable "environment" {
+  type    = string
+  default = "production"
+
+  validation {
+    condition     = contains(["dev", "staging"], var.environment)
+    error_message = "The environment must be dev or staging."
+  }
+}
@@ -0,0 +1,22 @@
+variable "size" {
+  default = "t3.micro"
+}
+
+variable "missing" {}
+
+locals {
+  name = "web-${var.missing}"
+}
+
+module "db" {
+  source = "./mod"
+  size   = var.size
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = var.size
+  tags = {
+    Name = local.name
+  }
+}
@@ -0,0 +1,9 @@
+variable "size" {}
+
+resource "aws_db_instance" "db" {
+  instance_class = "db.${var.size}"
+}
+
+output "class" {
+  value = aws_db_instance.db.instance_class
+}
@@ -0,0 +1,50 @@
+provider "aws" {
+  region = "us-east-1"
+}
+
+variable "instance_type" {
+  type        = string
+  description = "The instance type of the web servers."
+
+  validation {
+    condition     = startswith(var.instance_type, "t3.")
+    error_message = "Only t3 instances are allowed."
+  }
+}
+
+variable "instance_count" {
+  type = number
+}
+
+variable "disk_sizes" {
+  type        = list(number)
+  description = "The sizes of the data volumes, in GB."
+}
+
+variable "owner" {
+  description = "The team that owns the servers."
+}
+
+variable "ami" {
+  type    = string
+  default = "ami-674cbc1e"
+}
+
+locals {
+  sizes = var.disk_sizes
+}
+
+resource "aws_instance" "web" {
+  count         = var.instance_count
+  ami           = var.ami
+  instance_type = var.instance_type
+}
+
+module "disks" {
+  source = "./modules/disks"
+  sizes  = local.sizes
+}
+
+output "owner" {
+  value = var.owner
+}
@@ -0,0 +1,9 @@
+variable "sizes" {
+  type = list(number)
+}
+
+resource "aws_ebs_volume" "data" {
+  count             = length(var.sizes)
+  availability_zone = "us-east-1a"
+  size              = var.sizes[count.index]
+}
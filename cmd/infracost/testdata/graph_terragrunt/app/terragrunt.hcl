@@ -0,0 +1,11 @@
+terraform {
+  source = "../modules//app"
+}
+
+dependency "network" {
+  config_path = "../network"
+}
+
+inputs = {
+  subnet_id = dependency.network.outputs.subnet_id
+}
@@ -0,0 +1,23 @@
+variable "subnet_id" {}
+
+variable "instance_type" {
+  default = "t3.micro"
+}
+
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_requesting_account_id  = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_instance" "app" {
+  ami           = "ami-674cbc1e"
+  instance_type = var.instance_type
+  subnet_id     = var.subnet_id
+}
+
+output "instance_type" {
+  value = aws_instance.app.instance_type
+}
@@ -0,0 +1,3 @@
+output "subnet_id" {
+  value = "subnet-0a1b2c3d"
+}
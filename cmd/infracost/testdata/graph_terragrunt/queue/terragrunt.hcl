@@ -0,0 +1,16 @@
+terraform {
+  source = "../modules//app"
+}
+
+dependency "network" {
+  config_path = "../network"
+}
+
+dependency "worker" {
+  config_path = "../worker"
+}
+
+inputs = {
+  subnet_id     = dependency.network.outputs.subnet_id
+  instance_type = dependency.worker.outputs.instance_type
+}
@@ -0,0 +1,12 @@
+terraform {
+  source = "../modules//app"
+}
+
+dependency "queue" {
+  config_path = "../queue"
+}
+
+inputs = {
+  subnet_id     = "subnet-0e1f2a3b"
+  instance_type = dependency.queue.outputs.instance_type
+}
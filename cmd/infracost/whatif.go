@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/whatif"
+)
+
+func whatifCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whatif",
+		Short: "Show the cost impact of attribute, region and usage changes without editing code",
+		Long: `Show the cost impact of attribute, region and usage changes without editing code.
+
+Each scenario applies overrides to the parsed resources before they are priced,
+and is shown as a diff against the baseline cost. Scenarios can be defined in a
+scenarios file, or with the --set, --set-usage and --region flags.
+
+Resources are matched by resource type, or by a glob pattern matched against
+the resource address.`,
+		Example: `  Show the cost of moving all EC2 instances to Graviton:
+
+      infracost whatif --path /code --set 'aws_instance:instance_type=m7g.large'
+
+  Show the cost of moving AWS resources to eu-west-1 with more Lambda requests:
+
+      infracost whatif --path /code --region aws=eu-west-1 --set-usage 'module.api.*:monthly_requests=50000000'
+
+  Compare several scenarios from a file:
+
+      infracost whatif --path /code --scenarios-file scenarios.yml
+
+  Example scenarios file:
+
+      version: 0.1
+      scenarios:
+        - name: graviton
+          resources:
+            - match: aws_instance
+              attributes:
+                instance_type: m7g.large
+        - name: eu-west-1
+          regions:
+            aws: eu-west-1`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scenarios, err := loadScenarios(cmd)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			err = loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			return runWhatif(cmd, ctx, scenarios)
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().String("scenarios-file", "", "Path to a scenarios file")
+	cmd.Flags().StringArray("set", nil, "Override a resource attribute, in the format <match>:<attribute>=<value>")
+	cmd.Flags().StringArray("set-usage", nil, "Override a resource usage value, in the format <match>:<key>=<value>")
+	cmd.Flags().StringArray("region", nil, "Override the region of all resources of a provider, in the format <provider>=<region>")
+	cmd.Flags().String("scenario-name", "whatif", "Name of the scenario created from the set, set-usage and region flags")
+	cmd.Flags().String("out-file", "", "Save output to a file, helpful with format flag")
+	newEnumFlag(cmd, "format", "diff", "Output format", []string{"json", "diff", "table"})
+
+	_ = cmd.MarkFlagFilename("scenarios-file", "yml", "yaml")
+
+	return cmd
+}
+
+func loadScenarios(cmd *cobra.Command) ([]*whatif.Scenario, error) {
+	var scenarios []*whatif.Scenario
+
+	if path, _ := cmd.Flags().GetString("scenarios-file"); path != "" {
+		s, err := whatif.LoadScenariosFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		scenarios = append(scenarios, s...)
+	}
+
+	sets, _ := cmd.Flags().GetStringArray("set")
+	usageSets, _ := cmd.Flags().GetStringArray("set-usage")
+	regions, _ := cmd.Flags().GetStringArray("region")
+
+	if len(sets) > 0 || len(usageSets) > 0 || len(regions) > 0 {
+		name, _ := cmd.Flags().GetString("scenario-name")
+
+		s, err := whatif.NewScenario(name, sets, usageSets, regions)
+		if err != nil {
+			return nil, err
+		}
+
+		scenarios = append(scenarios, s)
+	}
+
+	if len(scenarios) == 0 {
+		return nil, errors.New("No scenarios specified. Use --scenarios-file, or the --set, --set-usage or --region flags")
+	}
+
+	return scenarios, nil
+}
+
+func runWhatif(cmd *cobra.Command, runCtx *config.RunContext, scenarios []*whatif.Scenario) error {
+	pr, err := newParallelRunner(cmd, runCtx)
+	if err != nil {
+		return err
+	}
+
+	projectResults, err := pr.run()
+	if err != nil {
+		return err
+	}
+
+	pricingClient := apiclient.GetPricingAPIClient(runCtx)
+
+	var projects []*schema.Project
+	for _, result := range projectResults {
+		for _, project := range result.projectOut.projects {
+			if project.Metadata.HasErrors() {
+				projects = append(projects, project)
+				continue
+			}
+
+			for _, s := range scenarios {
+				res := s.Apply(project, terraform.NewPartialResourceFromData)
+
+				if len(res.Changed) > 0 {
+					if err := prices.GetPricesConcurrent(runCtx, pricingClient, res.Changed); err != nil {
+						return err
+					}
+				}
+
+				schema.CalculateCosts(res.Project)
+				res.Project.CalculateDiff()
+
+				projects = append(projects, res.Project)
+			}
+		}
+	}
+
+	r, err := output.ToOutputFormat(runCtx.Config, projects)
+	if err != nil {
+		return err
+	}
+
+	r.IsCIRun = runCtx.IsCIRun()
+	r.Currency = runCtx.Config.Currency
+	r.Metadata = output.NewMetadata(runCtx)
+
+	b, err := output.FormatOutput(strings.ToLower(runCtx.Config.Format), r, output.Options{
+		DashboardEndpoint: runCtx.Config.DashboardEndpoint,
+		ShowSkipped:       runCtx.Config.ShowSkipped,
+		NoColor:           runCtx.Config.NoColor,
+		Fields:            runCtx.Config.Fields,
+		CurrencyFormat:    runCtx.Config.CurrencyFormat,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = pricingClient.AddEvent("infracost-whatif", runCtx.EventEnv())
+	if err != nil {
+		logging.Logger.Debug().Err(err).Msg("could not report `infracost-whatif` event")
+	}
+
+	if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
+		return saveOutFile(runCtx, cmd, outFile, b)
+	}
+
+	cmd.Println(string(b))
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestWhatifHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"whatif", "--help"}, nil)
+}
+
+func TestWhatifNoScenarios(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"whatif", "--path", "./testdata/example_plan.json"}, nil)
+}
+
+func TestWhatifInvalidSet(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"whatif", "--path", "./testdata/example_plan.json", "--set", "aws_instance.web_app"}, nil)
+}
@@ -36,6 +36,9 @@ type PricingAPIClient struct {
 	APIClient
 	Currency       string
 	EventsDisabled bool
+	// IncludeProductAttributes requests the hash and attributes of the matched
+	// products in price queries. This is used to explain how a resource was priced.
+	IncludeProductAttributes bool
 
 	cacheFile string
 
@@ -245,16 +248,26 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 	v["productFilter"] = product
 	v["priceFilter"] = price
 
+	productFields := ""
+	if c.IncludeProductAttributes {
+		productFields = `
+				productHash
+				attributes {
+					key
+					value
+				}`
+	}
+
 	query := fmt.Sprintf(`
 		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
-			products(filter: $productFilter) {
+			products(filter: $productFilter) {%s
 				prices(filter: $priceFilter) {
 					priceHash
 					%s
 				}
 			}
 		}
-	`, c.Currency)
+	`, productFields, c.Currency)
 
 	return GraphQLQuery{query, v}
 }
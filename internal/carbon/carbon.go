@@ -0,0 +1,378 @@
+// Package carbon estimates the greenhouse gas emissions of resources from the
+// same cost components that are used to estimate their cost. It follows the
+// Cloud Carbon Footprint methodology: the energy used by compute, storage and
+// networking is estimated from the usage quantities, multiplied by the data
+// center PUE and then by the carbon intensity of the grid in the region.
+//
+// Only operational emissions are estimated, embodied emissions of the hardware
+// are not included.
+package carbon
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+//go:embed data/coefficients.json
+var coefficientsJSON []byte
+
+// Unit is the unit of the emissions estimates.
+const Unit = "kgCO2e"
+
+type architecture struct {
+	MinWatts float64 `json:"minWatts"`
+	MaxWatts float64 `json:"maxWatts"`
+}
+
+type family struct {
+	Architecture  string  `json:"architecture"`
+	MemoryPerVCPU float64 `json:"memoryPerVCPU"`
+}
+
+type gcpFamily struct {
+	Architecture  string             `json:"architecture"`
+	MemoryPerVCPU map[string]float64 `json:"memoryPerVCPU"`
+}
+
+type sharedCore struct {
+	VCPU   float64 `json:"vCPU"`
+	Memory float64 `json:"memory"`
+}
+
+// Coefficients are the published coefficients used to estimate emissions.
+type Coefficients struct {
+	Source               string                        `json:"source"`
+	Utilization          float64                       `json:"utilization"`
+	DefaultGridIntensity float64                       `json:"defaultGridIntensity"`
+	MemoryWattsPerGB     float64                       `json:"memoryWattsPerGB"`
+	NetworkKWhPerGB      float64                       `json:"networkKWhPerGB"`
+	StorageKWhPerTBHour  map[string]float64            `json:"storageKWhPerTBHour"`
+	PUE                  map[string]float64            `json:"pue"`
+	StorageReplication   map[string]float64            `json:"storageReplication"`
+	Architectures        map[string]architecture       `json:"architectures"`
+	GridIntensity        map[string]map[string]float64 `json:"gridIntensity"`
+	AWSFamilies          map[string]family             `json:"awsFamilies"`
+	AWSBurstableMemory   map[string]float64            `json:"awsBurstableMemory"`
+	AzureSeries          map[string]family             `json:"azureSeries"`
+	GCPFamilies          map[string]gcpFamily          `json:"gcpFamilies"`
+	GCPSharedCore        map[string]sharedCore         `json:"gcpSharedCore"`
+}
+
+// DefaultCoefficients returns the coefficients from the embedded dataset.
+func DefaultCoefficients() *Coefficients {
+	var c Coefficients
+	// The embedded dataset is checked by the tests so this can't fail at runtime
+	_ = json.Unmarshal(coefficientsJSON, &c)
+	return &c
+}
+
+// Estimator estimates the emissions of cost components.
+type Estimator struct {
+	c *Coefficients
+}
+
+// NewEstimator returns an Estimator that uses the embedded coefficients.
+func NewEstimator() *Estimator {
+	return &Estimator{c: DefaultCoefficients()}
+}
+
+// Estimate sets the monthly emissions of every cost component of the project
+// resources that can be estimated, and the totals of the resources.
+func (e *Estimator) Estimate(project *schema.Project) {
+	for _, r := range project.AllResources() {
+		e.EstimateResource(r)
+	}
+}
+
+// EstimateResource sets the monthly emissions of the cost components and sub
+// resources of r, and the total for r. The total is left nil if none of the
+// cost components could be estimated.
+func (e *Estimator) EstimateResource(r *schema.Resource) {
+	var total *decimal.Decimal
+
+	add := func(d *decimal.Decimal) {
+		if d == nil {
+			return
+		}
+
+		if total == nil {
+			total = &decimal.Zero
+		}
+
+		t := total.Add(*d)
+		total = &t
+	}
+
+	for _, c := range r.CostComponents {
+		c.MonthlyCO2e = e.EstimateCostComponent(c)
+		add(c.MonthlyCO2e)
+	}
+
+	for _, s := range r.SubResources {
+		e.EstimateResource(s)
+		add(s.MonthlyCO2e)
+	}
+
+	r.MonthlyCO2e = total
+}
+
+var (
+	storageNameRegex  = regexp.MustCompile(`(?i)(storage|volume|snapshot|backup|disk)`)
+	ssdRegex          = regexp.MustCompile(`(?i)(ssd|gp2|gp3|io1|io2|premium|provisioned iops)`)
+	networkNameRegex  = regexp.MustCompile(`(?i)(data transfer|egress|outbound|inter-region|internet)`)
+	gbUnitRegex       = regexp.MustCompile(`(?i)^GB(-months?)?$`)
+	azureSKURegex     = regexp.MustCompile(`^Standard_([A-Z]+?)(\d+)`)
+	gcpMachineRegex   = regexp.MustCompile(`^([a-z0-9]+)-([a-z]+)-(\d+)$`)
+	awsSizeMultiplier = regexp.MustCompile(`^(\d+)xlarge$`)
+)
+
+// EstimateCostComponent returns the monthly emissions of the cost component in
+// kgCO2e, or nil if it can't be estimated.
+func (e *Estimator) EstimateCostComponent(c *schema.CostComponent) *decimal.Decimal {
+	if c.ProductFilter == nil || c.ProductFilter.VendorName == nil {
+		return nil
+	}
+
+	quantity := monthlyQuantity(c)
+	if quantity == nil {
+		return nil
+	}
+
+	vendor := *c.ProductFilter.VendorName
+	pue, ok := e.c.PUE[vendor]
+	if !ok {
+		return nil
+	}
+
+	var kWh float64
+
+	switch {
+	case instanceName(c.ProductFilter) != "":
+		watts, ok := e.instanceWatts(vendor, instanceName(c.ProductFilter))
+		if !ok || !strings.HasPrefix(strings.ToLower(c.Unit), "hour") {
+			return nil
+		}
+
+		kWh = *quantity * watts / 1000
+	case gbUnitRegex.MatchString(c.Unit) && networkNameRegex.MatchString(c.Name):
+		kWh = *quantity * e.c.NetworkKWhPerGB
+	case gbUnitRegex.MatchString(c.Unit) && storageNameRegex.MatchString(c.Name):
+		kind := "hdd"
+		if ssdRegex.MatchString(c.Name) {
+			kind = "ssd"
+		}
+
+		replication := 1.0
+		if c.ProductFilter.Service != nil {
+			if r, ok := e.c.StorageReplication[*c.ProductFilter.Service]; ok {
+				replication = r
+			}
+		}
+
+		tbHours := *quantity / 1000 * schema.HourToMonthUnitMultiplier.InexactFloat64()
+		kWh = tbHours * e.c.StorageKWhPerTBHour[kind] * replication
+	default:
+		return nil
+	}
+
+	intensity := e.gridIntensity(vendor, c.ProductFilter.Region)
+	co2e := decimal.NewFromFloat(kWh * pue * intensity).Round(6)
+
+	return &co2e
+}
+
+// monthlyQuantity returns the monthly quantity in the base unit of the cost
+// component, without calculating the costs of the cost component.
+func monthlyQuantity(c *schema.CostComponent) *float64 {
+	var q float64
+
+	switch {
+	case c.MonthlyQuantity != nil:
+		q = c.MonthlyQuantity.InexactFloat64()
+	case c.HourlyQuantity != nil:
+		q = c.HourlyQuantity.Mul(schema.HourToMonthUnitMultiplier).InexactFloat64()
+	default:
+		return nil
+	}
+
+	return &q
+}
+
+func (e *Estimator) gridIntensity(vendor string, region *string) float64 {
+	if region == nil {
+		return e.c.DefaultGridIntensity
+	}
+
+	if v, ok := e.c.GridIntensity[vendor][*region]; ok {
+		return v
+	}
+
+	return e.c.DefaultGridIntensity
+}
+
+// instanceName returns the instance type from the attribute filters of a
+// compute product filter.
+func instanceName(f *schema.ProductFilter) string {
+	for _, a := range f.AttributeFilters {
+		if a.Key != "instanceType" && a.Key != "armSkuName" && a.Key != "machineType" {
+			continue
+		}
+
+		if a.Value != nil {
+			return *a.Value
+		}
+
+		if a.ValueRegex != nil {
+			v := strings.TrimPrefix(*a.ValueRegex, "/")
+			v = strings.TrimSuffix(v, "/i")
+			v = strings.TrimPrefix(v, "^")
+			v = strings.TrimSuffix(v, "$")
+			return v
+		}
+	}
+
+	return ""
+}
+
+// instanceWatts returns the average power usage of an instance type in watts.
+func (e *Estimator) instanceWatts(vendor, name string) (float64, bool) {
+	var vCPU, memory float64
+	var arch string
+	var ok bool
+
+	switch vendor {
+	case "aws":
+		vCPU, memory, arch, ok = e.awsInstance(name)
+	case "azure":
+		vCPU, memory, arch, ok = e.azureInstance(name)
+	case "gcp":
+		vCPU, memory, arch, ok = e.gcpInstance(name)
+	}
+
+	if !ok {
+		return 0, false
+	}
+
+	a, ok := e.c.Architectures[arch]
+	if !ok {
+		a = e.c.Architectures["default"]
+	}
+
+	cpuWatts := a.MinWatts + e.c.Utilization*(a.MaxWatts-a.MinWatts)
+
+	return vCPU*cpuWatts + memory*e.c.MemoryWattsPerGB, true
+}
+
+func (e *Estimator) awsInstance(name string) (float64, float64, string, bool) {
+	// RDS, ElastiCache and other services prefix the instance type
+	for _, prefix := range []string{"db.", "cache."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return 0, 0, "", false
+	}
+
+	f, ok := e.c.AWSFamilies[parts[0]]
+	if !ok {
+		return 0, 0, "", false
+	}
+
+	size := parts[1]
+	burstable := strings.HasPrefix(parts[0], "t")
+
+	var vCPU float64
+	switch {
+	case size == "nano" || size == "micro" || size == "small":
+		vCPU = 2
+	case size == "medium" && burstable:
+		vCPU = 2
+	case size == "medium":
+		vCPU = 1
+	case size == "large":
+		vCPU = 2
+	case size == "xlarge":
+		vCPU = 4
+	case awsSizeMultiplier.MatchString(size):
+		n, _ := strconv.ParseFloat(awsSizeMultiplier.FindStringSubmatch(size)[1], 64)
+		vCPU = n * 4
+	default:
+		return 0, 0, "", false
+	}
+
+	memory := vCPU * f.MemoryPerVCPU
+	if burstable {
+		m, ok := e.c.AWSBurstableMemory[size]
+		if !ok {
+			return 0, 0, "", false
+		}
+		memory = m
+	}
+
+	return vCPU, memory, f.Architecture, true
+}
+
+func (e *Estimator) azureInstance(name string) (float64, float64, string, bool) {
+	m := azureSKURegex.FindStringSubmatch(name)
+	if m == nil {
+		return 0, 0, "", false
+	}
+
+	f, ok := e.c.AzureSeries[m[1]]
+	if !ok {
+		return 0, 0, "", false
+	}
+
+	vCPU, err := strconv.ParseFloat(m[2], 64)
+	if err != nil || vCPU == 0 {
+		return 0, 0, "", false
+	}
+
+	arch := f.Architecture
+	// The "a" additive feature means the VM runs on AMD processors and "p" on
+	// ARM processors, e.g. Standard_D4as_v5 or Standard_D4ps_v5
+	suffix := strings.ToLower(strings.SplitN(strings.TrimPrefix(name, m[0]), "_", 2)[0])
+	if strings.HasPrefix(suffix, "a") {
+		arch = "amd"
+	} else if strings.HasPrefix(suffix, "p") {
+		arch = "arm"
+	}
+
+	return vCPU, vCPU * f.MemoryPerVCPU, arch, true
+}
+
+func (e *Estimator) gcpInstance(name string) (float64, float64, string, bool) {
+	if s, ok := e.c.GCPSharedCore[name]; ok {
+		return s.VCPU, s.Memory, "default", true
+	}
+
+	m := gcpMachineRegex.FindStringSubmatch(name)
+	if m == nil {
+		return 0, 0, "", false
+	}
+
+	f, ok := e.c.GCPFamilies[m[1]]
+	if !ok {
+		return 0, 0, "", false
+	}
+
+	memoryPerVCPU, ok := f.MemoryPerVCPU[m[2]]
+	if !ok {
+		return 0, 0, "", false
+	}
+
+	vCPU, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return 0, 0, "", false
+	}
+
+	return vCPU, vCPU * memoryPerVCPU, f.Architecture, true
+}
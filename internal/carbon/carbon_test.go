@@ -0,0 +1,233 @@
+package carbon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestCoefficientsJSON(t *testing.T) {
+	var c Coefficients
+	require.NoError(t, json.Unmarshal(coefficientsJSON, &c))
+
+	assert.NotEmpty(t, c.Source)
+	assert.Contains(t, c.Architectures, "default")
+
+	for name, f := range c.AWSFamilies {
+		assert.Contains(t, c.Architectures, f.Architecture, name)
+	}
+	for name, f := range c.AzureSeries {
+		assert.Contains(t, c.Architectures, f.Architecture, name)
+	}
+	for name, f := range c.GCPFamilies {
+		assert.Contains(t, c.Architectures, f.Architecture, name)
+	}
+	for vendor := range c.GridIntensity {
+		assert.Contains(t, c.PUE, vendor)
+	}
+}
+
+func TestEstimateCostComponent(t *testing.T) {
+	tests := []struct {
+		name      string
+		component *schema.CostComponent
+		expected  string
+	}{
+		{
+			name: "aws instance",
+			component: &schema.CostComponent{
+				Name:           "Instance usage (Linux/UNIX, on-demand, m5.large)",
+				Unit:           "hours",
+				HourlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("us-east-1"),
+					Service:    testutil.StrPtr("AmazonEC2"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "instanceType", Value: testutil.StrPtr("m5.large")},
+					},
+				},
+			},
+			expected: "2.476816",
+		},
+		{
+			name: "aws rds instance with regex filter",
+			component: &schema.CostComponent{
+				Name:           "Database instance (on-demand, Single-AZ, db.m5.large)",
+				Unit:           "hours",
+				HourlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("us-east-1"),
+					Service:    testutil.StrPtr("AmazonRDS"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "instanceType", ValueRegex: testutil.StrPtr("/^db.m5.large$/i")},
+					},
+				},
+			},
+			expected: "2.476816",
+		},
+		{
+			name: "aws ssd storage",
+			component: &schema.CostComponent{
+				Name:            "Storage (general purpose SSD, gp3)",
+				Unit:            "GB",
+				MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(100)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("us-east-1"),
+					Service:    testutil.StrPtr("AmazonEC2"),
+				},
+			},
+			expected: "0.075379",
+		},
+		{
+			name: "network in unknown region",
+			component: &schema.CostComponent{
+				Name:            "Outbound data transfer",
+				Unit:            "GB",
+				MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1000)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("unknown-region-1"),
+					Service:    testutil.StrPtr("AWSDataTransfer"),
+				},
+			},
+			expected: "0.539125",
+		},
+		{
+			name: "requests are not estimated",
+			component: &schema.CostComponent{
+				Name:            "Requests",
+				Unit:            "1M requests",
+				MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(10)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("us-east-1"),
+					Service:    testutil.StrPtr("AWSLambda"),
+				},
+			},
+		},
+		{
+			name: "unknown instance type",
+			component: &schema.CostComponent{
+				Name:           "Instance usage",
+				Unit:           "hours",
+				HourlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("us-east-1"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "instanceType", Value: testutil.StrPtr("zz9.large")},
+					},
+				},
+			},
+		},
+		{
+			name: "usage based component without quantity",
+			component: &schema.CostComponent{
+				Name: "Storage",
+				Unit: "GB",
+				ProductFilter: &schema.ProductFilter{
+					VendorName: testutil.StrPtr("aws"),
+					Region:     testutil.StrPtr("us-east-1"),
+				},
+			},
+		},
+	}
+
+	e := NewEstimator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := e.EstimateCostComponent(tt.component)
+			if tt.expected == "" {
+				assert.Nil(t, actual)
+				return
+			}
+
+			require.NotNil(t, actual)
+			assert.Equal(t, tt.expected, actual.String())
+		})
+	}
+}
+
+func TestInstanceWatts(t *testing.T) {
+	e := NewEstimator()
+
+	tests := []struct {
+		vendor   string
+		name     string
+		expected float64
+		ok       bool
+	}{
+		{"aws", "m5.large", 7.886, true},
+		{"aws", "m5.2xlarge", 31.544, true},
+		{"aws", "t3.medium", 4.75 + 4*0.392, true},
+		{"azure", "Standard_D4s_v3", 4*2.375 + 16*0.392, true},
+		{"gcp", "n2-standard-2", 7.886, true},
+		{"gcp", "e2-micro", 0.25*2.12 + 0.392, true},
+		{"aws", "invalid", 0, false},
+		{"azure", "Basic_A1", 0, false},
+		{"gcp", "n2-unknown-2", 0, false},
+	}
+
+	for _, tt := range tests {
+		actual, ok := e.instanceWatts(tt.vendor, tt.name)
+		assert.Equal(t, tt.ok, ok, tt.name)
+		assert.InDelta(t, tt.expected, actual, 0.0001, tt.name)
+	}
+}
+
+func TestEstimateResource(t *testing.T) {
+	instance := &schema.CostComponent{
+		Name:           "Instance usage",
+		Unit:           "hours",
+		HourlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName: testutil.StrPtr("aws"),
+			Region:     testutil.StrPtr("us-east-1"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: testutil.StrPtr("m5.large")},
+			},
+		},
+	}
+	volume := &schema.CostComponent{
+		Name:            "Storage (general purpose SSD, gp3)",
+		Unit:            "GB",
+		MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(100)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName: testutil.StrPtr("aws"),
+			Region:     testutil.StrPtr("us-east-1"),
+			Service:    testutil.StrPtr("AmazonEC2"),
+		},
+	}
+	requests := &schema.CostComponent{
+		Name:            "Requests",
+		Unit:            "1M requests",
+		MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1)),
+	}
+
+	r := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{instance},
+		SubResources: []*schema.Resource{
+			{Name: "root_block_device", CostComponents: []*schema.CostComponent{volume}},
+			{Name: "other", CostComponents: []*schema.CostComponent{requests}},
+		},
+	}
+
+	NewEstimator().EstimateResource(r)
+
+	require.NotNil(t, r.MonthlyCO2e)
+	assert.Equal(t, "2.552195", r.MonthlyCO2e.String())
+	assert.Equal(t, "0.075379", r.SubResources[0].MonthlyCO2e.String())
+	assert.Nil(t, r.SubResources[1].MonthlyCO2e)
+	assert.Nil(t, requests.MonthlyCO2e)
+}
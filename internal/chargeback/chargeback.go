@@ -0,0 +1,237 @@
+// Package chargeback allocates the costs in an Infracost output to owners
+// using resource tags and CODEOWNERS style path ownership rules.
+package chargeback
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+const (
+	SourceTag      = "tag"
+	SourcePath     = "path"
+	SourceFallback = "fallback"
+)
+
+// Options configures how resource costs are allocated to owners.
+type Options struct {
+	// TagKeys are the resource tag keys checked, in order, for an owner.
+	TagKeys []string
+	// Ownership holds the path ownership rules used when a resource is not
+	// tagged with an owner.
+	Ownership *Ownership
+	// Fallback is the owner that is assigned any resources that are not matched
+	// by a tag or an ownership rule. If empty these resources are reported as
+	// unallocated.
+	Fallback string
+}
+
+// Allocation is the portion of a single resource's cost assigned to an owner.
+type Allocation struct {
+	Project      string          `json:"project"`
+	Name         string          `json:"name"`
+	ResourceType string          `json:"resourceType,omitempty"`
+	Path         string          `json:"path,omitempty"`
+	Source       string          `json:"source,omitempty"`
+	Rule         string          `json:"rule,omitempty"`
+	Percent      decimal.Decimal `json:"percent"`
+	MonthlyCost  decimal.Decimal `json:"monthlyCost"`
+}
+
+// OwnerCost is the total monthly cost allocated to an owner.
+type OwnerCost struct {
+	Owner       string          `json:"owner"`
+	MonthlyCost decimal.Decimal `json:"monthlyCost"`
+	Allocations []Allocation    `json:"allocations"`
+}
+
+func (o *OwnerCost) add(a Allocation) {
+	o.MonthlyCost = o.MonthlyCost.Add(a.MonthlyCost)
+	o.Allocations = append(o.Allocations, a)
+}
+
+// Report is the result of allocating an Infracost output to owners. Owners are
+// sorted by monthly cost in descending order. Any cost that could not be
+// allocated, either because no owner was found or because a shared resource's
+// percentages did not add up to 100, is reported in Unallocated.
+type Report struct {
+	Currency         string          `json:"currency"`
+	TotalMonthlyCost decimal.Decimal `json:"totalMonthlyCost"`
+	Owners           []OwnerCost     `json:"owners"`
+	Unallocated      OwnerCost       `json:"unallocated"`
+}
+
+// Allocate assigns the monthly cost of every resource in root to its owners.
+func Allocate(root output.Root, opts Options) (*Report, error) {
+	report := &Report{
+		Currency: root.Currency,
+	}
+
+	owners := map[string]*OwnerCost{}
+
+	for _, project := range root.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range project.Breakdown.Resources {
+			cost := decimal.Zero
+			if r.MonthlyCost != nil {
+				cost = *r.MonthlyCost
+			}
+			report.TotalMonthlyCost = report.TotalMonthlyCost.Add(cost)
+
+			p := resourcePath(project, r)
+			shares, source, rule, err := resolveShares(r, p, opts)
+			if err != nil {
+				return nil, fmt.Errorf("could not allocate resource %s in project %s: %w", r.Name, project.Name, err)
+			}
+
+			allocated := decimal.Zero
+			for _, s := range shares {
+				a := Allocation{
+					Project:      project.Name,
+					Name:         r.Name,
+					ResourceType: r.ResourceType,
+					Path:         p,
+					Source:       source,
+					Rule:         rule,
+					Percent:      s.Percent,
+					MonthlyCost:  cost.Mul(s.Percent).Div(hundred),
+				}
+				allocated = allocated.Add(s.Percent)
+
+				o, ok := owners[s.Owner]
+				if !ok {
+					o = &OwnerCost{Owner: s.Owner}
+					owners[s.Owner] = o
+				}
+				o.add(a)
+			}
+
+			if remaining := hundred.Sub(allocated); remaining.IsPositive() {
+				report.Unallocated.add(Allocation{
+					Project:      project.Name,
+					Name:         r.Name,
+					ResourceType: r.ResourceType,
+					Path:         p,
+					Source:       source,
+					Rule:         rule,
+					Percent:      remaining,
+					MonthlyCost:  cost.Mul(remaining).Div(hundred),
+				})
+			}
+		}
+	}
+
+	report.Owners = make([]OwnerCost, 0, len(owners))
+	for _, o := range owners {
+		report.Owners = append(report.Owners, *o)
+	}
+
+	sort.Slice(report.Owners, func(i, j int) bool {
+		a, b := report.Owners[i], report.Owners[j]
+		if a.MonthlyCost.Equal(b.MonthlyCost) {
+			return a.Owner < b.Owner
+		}
+		return a.MonthlyCost.GreaterThan(b.MonthlyCost)
+	})
+
+	return report, nil
+}
+
+// resolveShares finds the owners of a resource. Tags take precedence over path
+// ownership rules, which take precedence over the fallback owner.
+func resolveShares(r output.Resource, p string, opts Options) ([]Share, string, string, error) {
+	if r.Tags != nil {
+		for _, key := range opts.TagKeys {
+			v, ok := (*r.Tags)[key]
+			if !ok || v == "" {
+				continue
+			}
+
+			shares, err := ParseShares(v)
+			if err != nil {
+				return nil, "", "", fmt.Errorf("invalid %s tag: %w", key, err)
+			}
+
+			return shares, SourceTag, key, nil
+		}
+	}
+
+	if p != "" {
+		if rule := opts.Ownership.Match(p); rule != nil {
+			return rule.Shares, SourcePath, rule.Pattern, nil
+		}
+	}
+
+	if opts.Fallback != "" {
+		return []Share{{Owner: opts.Fallback, Percent: hundred}}, SourceFallback, "", nil
+	}
+
+	return nil, "", "", nil
+}
+
+// resourcePath returns the path used to match a resource against the ownership
+// rules. This is the file the resource was defined in if known, otherwise the
+// path of the project. Paths are relative to the root of the repo so that the
+// ownership rules match no matter how the project was loaded.
+func resourcePath(project output.Project, r output.Resource) string {
+	if f, ok := r.Metadata["filename"].(string); ok && f != "" {
+		return repoFilePath(project, f)
+	}
+
+	if project.Metadata == nil {
+		return ""
+	}
+
+	if project.Metadata.VCSSubPath != "" {
+		return normalizePath(project.Metadata.VCSSubPath)
+	}
+
+	if project.Metadata.Path != "" {
+		return normalizePath(project.Metadata.Path)
+	}
+
+	return ""
+}
+
+// repoFilePath returns the filename of a resource relative to the root of the
+// repo. Depending on how the project was loaded the filename can be absolute,
+// relative to the working directory or relative to the project, so it is first
+// made relative to the project path and then joined with the project's
+// subpath in the repo.
+func repoFilePath(project output.Project, filename string) string {
+	if project.Metadata == nil || project.Metadata.Path == "" {
+		return normalizePath(filename)
+	}
+
+	projectPath := project.Metadata.Path
+	if filepath.IsAbs(filename) && !filepath.IsAbs(projectPath) {
+		abs, err := filepath.Abs(projectPath)
+		if err != nil {
+			return normalizePath(filename)
+		}
+		projectPath = abs
+	}
+
+	rel, err := filepath.Rel(projectPath, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		if filepath.IsAbs(filename) {
+			return normalizePath(filename)
+		}
+
+		// A relative filename that isn't under the project path is relative to
+		// the project itself.
+		rel = filename
+	}
+
+	return normalizePath(path.Join(project.Metadata.VCSSubPath, filepath.ToSlash(rel)))
+}
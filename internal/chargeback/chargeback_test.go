@@ -0,0 +1,98 @@
+package chargeback
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestResourcePath(t *testing.T) {
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+
+	tests := []struct {
+		name       string
+		path       string
+		vcsSubPath string
+		filename   string
+		expected   string
+	}{
+		{name: "project relative", path: "apps/payments", vcsSubPath: "apps/payments", filename: "main.tf", expected: "apps/payments/main.tf"},
+		{name: "working directory relative", path: "apps/payments", vcsSubPath: "apps/payments", filename: "apps/payments/modules/db/main.tf", expected: "apps/payments/modules/db/main.tf"},
+		{name: "absolute", path: filepath.Join(repoRoot, "apps", "payments"), vcsSubPath: "apps/payments", filename: filepath.Join(repoRoot, "apps", "payments", "main.tf"), expected: "apps/payments/main.tf"},
+		{name: "absolute with relative project path", path: ".", vcsSubPath: "apps/payments", filename: mustAbs(t, "main.tf"), expected: "apps/payments/main.tf"},
+		{name: "absolute outside project", path: filepath.Join(repoRoot, "apps", "payments"), filename: "/modules/db/main.tf", expected: "modules/db/main.tf"},
+		{name: "no subpath", path: ".", filename: "./apps/main.tf", expected: "apps/main.tf"},
+		{name: "no filename", path: "apps/payments", vcsSubPath: "apps/payments", expected: "apps/payments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := output.Project{
+				Metadata: &schema.ProjectMetadata{
+					Path:       tt.path,
+					VCSSubPath: tt.vcsSubPath,
+				},
+			}
+
+			r := output.Resource{Metadata: map[string]interface{}{}}
+			if tt.filename != "" {
+				r.Metadata["filename"] = tt.filename
+			}
+
+			assert.Equal(t, tt.expected, resourcePath(project, r))
+		})
+	}
+}
+
+func TestAllocateAbsoluteFilename(t *testing.T) {
+	o := &Ownership{}
+	r, err := NewRule("/apps/payments/", "team-payments")
+	assert.NoError(t, err)
+	o.Rules = append(o.Rules, r)
+
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	cost := decimal.NewFromInt(10)
+	root := output.Root{
+		Projects: []output.Project{
+			{
+				Name: "payments",
+				Metadata: &schema.ProjectMetadata{
+					Path:       filepath.Join(repoRoot, "apps", "payments"),
+					VCSSubPath: "apps/payments",
+				},
+				Breakdown: &output.Breakdown{
+					Resources: []output.Resource{
+						{
+							Name:        "aws_instance.web",
+							MonthlyCost: &cost,
+							Metadata: map[string]interface{}{
+								"filename": filepath.Join(repoRoot, "apps", "payments", "main.tf"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	report, err := Allocate(root, Options{Ownership: o})
+	assert.NoError(t, err)
+	if assert.Len(t, report.Owners, 1) {
+		assert.Equal(t, "team-payments", report.Owners[0].Owner)
+		assert.Equal(t, "apps/payments/main.tf", report.Owners[0].Allocations[0].Path)
+	}
+	assert.True(t, report.Unallocated.MonthlyCost.IsZero())
+}
+
+func mustAbs(t *testing.T, p string) string {
+	t.Helper()
+
+	abs, err := filepath.Abs(p)
+	assert.NoError(t, err)
+	return abs
+}
@@ -0,0 +1,119 @@
+package chargeback
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+// UnallocatedLabel is the owner name used for unallocated costs in table and
+// CSV output.
+const UnallocatedLabel = "(unallocated)"
+
+// Format renders the report in the given format: table, csv or json.
+func Format(format string, r *Report) ([]byte, error) {
+	switch format {
+	case "", "table":
+		return ToTable(r), nil
+	case "csv":
+		return ToCSV(r)
+	case "json":
+		return json.MarshalIndent(r, "", "  ")
+	}
+
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
+
+// ToTable renders a per-owner summary table of the report.
+func ToTable(r *Report) []byte {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+	t.Style().Format.Footer = text.FormatDefault
+	t.AppendHeader(table.Row{"Owner", "Resources", "Monthly cost", "Share"})
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Owner", WidthMin: 30},
+		{Name: "Resources", Align: text.AlignRight, AlignFooter: text.AlignRight},
+		{Name: "Monthly cost", Align: text.AlignRight, AlignFooter: text.AlignRight},
+		{Name: "Share", Align: text.AlignRight, AlignFooter: text.AlignRight},
+	})
+
+	for _, o := range r.Owners {
+		t.AppendRow(ownerRow(r, o.Owner, o))
+	}
+	t.AppendRow(ownerRow(r, UnallocatedLabel, r.Unallocated))
+
+	t.AppendFooter(table.Row{"Total", "", output.FormatCost2DP(r.Currency, &r.TotalMonthlyCost), ""})
+
+	return []byte(t.Render())
+}
+
+func ownerRow(r *Report, label string, o OwnerCost) table.Row {
+	return table.Row{
+		label,
+		len(o.Allocations),
+		output.FormatCost2DP(r.Currency, &o.MonthlyCost),
+		formatPercent(o.MonthlyCost, r.TotalMonthlyCost),
+	}
+}
+
+// ToCSV renders one row per resource allocation, including the unallocated
+// remainder of each resource.
+func ToCSV(r *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	err := w.Write([]string{"owner", "project", "resource", "resource_type", "path", "source", "rule", "percent", "monthly_cost", "currency"})
+	if err != nil {
+		return nil, err
+	}
+
+	write := func(owner string, allocs []Allocation) error {
+		for _, a := range allocs {
+			err := w.Write([]string{
+				owner,
+				a.Project,
+				a.Name,
+				a.ResourceType,
+				a.Path,
+				a.Source,
+				a.Rule,
+				a.Percent.String(),
+				a.MonthlyCost.StringFixed(2),
+				r.Currency,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, o := range r.Owners {
+		if err := write(o.Owner, o.Allocations); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := write(UnallocatedLabel, r.Unallocated.Allocations); err != nil {
+		return nil, err
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func formatPercent(d, total decimal.Decimal) string {
+	if total.IsZero() {
+		return "-"
+	}
+
+	return d.Mul(hundred).Div(total).StringFixed(1) + "%"
+}
@@ -0,0 +1,241 @@
+package chargeback
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Share is the portion of a resource's cost that is allocated to a single owner.
+// Percent is a value between 0 and 100.
+type Share struct {
+	Owner   string
+	Percent decimal.Decimal
+}
+
+// ParseShares parses an owner specification into a list of shares. The spec is
+// a comma or whitespace separated list of owners, each optionally suffixed with
+// a percentage, e.g:
+//
+//	team-a
+//	team-a:60,team-b:40
+//	team-a:50 team-b:25
+//
+// If no percentages are given the cost is split evenly between the owners.
+// Percentages must either be given for every owner or for none of them and
+// must not add up to more than 100. Any remainder is left unallocated.
+func ParseShares(spec string) ([]Share, error) {
+	fields := strings.FieldsFunc(spec, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no owners specified")
+	}
+
+	shares := make([]Share, 0, len(fields))
+	withPercent := 0
+	total := decimal.Zero
+
+	for _, f := range fields {
+		owner, percent, hasPercent := strings.Cut(f, ":")
+		owner = strings.TrimSpace(owner)
+		if owner == "" {
+			return nil, fmt.Errorf("invalid owner %q", f)
+		}
+
+		share := Share{Owner: owner}
+		if hasPercent {
+			p, err := decimal.NewFromString(strings.TrimSuffix(percent, "%"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid percentage for owner %q: %w", owner, err)
+			}
+			if p.IsNegative() || p.GreaterThan(hundred) {
+				return nil, fmt.Errorf("percentage for owner %q must be between 0 and 100", owner)
+			}
+
+			share.Percent = p
+			total = total.Add(p)
+			withPercent++
+		}
+
+		shares = append(shares, share)
+	}
+
+	if withPercent == 0 {
+		even := hundred.Div(decimal.NewFromInt(int64(len(shares))))
+		for i := range shares {
+			shares[i].Percent = even
+		}
+
+		return shares, nil
+	}
+
+	if withPercent != len(shares) {
+		return nil, fmt.Errorf("percentages must be given for all owners in %q", spec)
+	}
+
+	if total.GreaterThan(hundred) {
+		return nil, fmt.Errorf("percentages in %q add up to more than 100", spec)
+	}
+
+	return shares, nil
+}
+
+// Rule maps a CODEOWNERS style path pattern to the owners of the matching paths.
+type Rule struct {
+	Pattern string
+	Shares  []Share
+	Line    int
+
+	re *regexp.Regexp
+}
+
+// Match returns true if the slash separated path p matches the rule pattern.
+func (r Rule) Match(p string) bool {
+	return r.re.MatchString(normalizePath(p))
+}
+
+// Ownership is an ordered list of path ownership rules. As with CODEOWNERS
+// files the last matching rule takes precedence.
+type Ownership struct {
+	Rules []Rule
+}
+
+// LoadOwnership reads an ownership file from the given path.
+func LoadOwnership(filename string) (*Ownership, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not open ownership file %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	o := &Ownership{}
+	scanner := bufio.NewScanner(f)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: no owners specified for pattern %q", filename, line, fields[0])
+		}
+
+		rule, err := NewRule(fields[0], strings.Join(fields[1:], " "))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+		rule.Line = line
+
+		o.Rules = append(o.Rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read ownership file %s: %w", filename, err)
+	}
+
+	return o, nil
+}
+
+// NewRule builds a Rule from a path pattern and an owner specification in the
+// format accepted by ParseShares.
+func NewRule(pattern, spec string) (Rule, error) {
+	shares, err := ParseShares(spec)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	re, err := patternToRegexp(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	return Rule{
+		Pattern: pattern,
+		Shares:  shares,
+		re:      re,
+	}, nil
+}
+
+// Match returns the last rule that matches the given path, or nil if there are
+// no matching rules.
+func (o *Ownership) Match(p string) *Rule {
+	if o == nil {
+		return nil
+	}
+
+	for i := len(o.Rules) - 1; i >= 0; i-- {
+		if o.Rules[i].Match(p) {
+			return &o.Rules[i]
+		}
+	}
+
+	return nil
+}
+
+// patternToRegexp converts a gitignore style glob into a regular expression.
+// Patterns that start with a slash or contain a slash are anchored to the root
+// of the repository, other patterns can match at any directory depth. A match
+// on a directory also matches everything below it.
+func patternToRegexp(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	if p == "" || p == "*" || p == "**" {
+		return regexp.Compile(`^.*$`)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+					continue
+				}
+				b.WriteString(".*")
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("(?:/.*)?$")
+
+	return regexp.Compile(b.String())
+}
+
+func normalizePath(p string) string {
+	p = filepath.ToSlash(p)
+	p = path.Clean(p)
+	p = strings.TrimPrefix(p, "./")
+	return strings.TrimPrefix(p, "/")
+}
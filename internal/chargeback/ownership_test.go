@@ -0,0 +1,80 @@
+package chargeback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShares(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected map[string]string
+		err      bool
+	}{
+		{spec: "team-a", expected: map[string]string{"team-a": "100"}},
+		{spec: "team-a team-b", expected: map[string]string{"team-a": "50", "team-b": "50"}},
+		{spec: "team-a:60,team-b:40", expected: map[string]string{"team-a": "60", "team-b": "40"}},
+		{spec: "team-a:25% team-b:25%", expected: map[string]string{"team-a": "25", "team-b": "25"}},
+		{spec: "team-a:60,team-b", err: true},
+		{spec: "team-a:60,team-b:60", err: true},
+		{spec: "team-a:abc", err: true},
+		{spec: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			shares, err := ParseShares(tt.spec)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			actual := map[string]string{}
+			for _, s := range shares {
+				actual[s.Owner] = s.Percent.String()
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestOwnershipMatch(t *testing.T) {
+	o := &Ownership{}
+	for _, p := range []string{"*", "/apps/", "apps/search/", "*.sql", "/infra/**/prod/", "docs"} {
+		r, err := NewRule(p, "team-a")
+		require.NoError(t, err)
+		o.Rules = append(o.Rules, r)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "main.tf", expected: "*"},
+		{path: "apps/main.tf", expected: "/apps/"},
+		{path: "./apps/payments/main.tf", expected: "/apps/"},
+		{path: "apps/search/main.tf", expected: "apps/search/"},
+		{path: "nested/apps/search/main.tf", expected: "*"},
+		{path: "db/schema.sql", expected: "*.sql"},
+		{path: "infra/prod/main.tf", expected: "/infra/**/prod/"},
+		{path: "infra/eu/prod/main.tf", expected: "/infra/**/prod/"},
+		{path: "infra/eu/dev/main.tf", expected: "*"},
+		{path: "a/docs/main.tf", expected: "docs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r := o.Match(tt.path)
+			require.NotNil(t, r)
+			assert.Equal(t, tt.expected, r.Pattern)
+		})
+	}
+}
+
+func TestOwnershipMatchNil(t *testing.T) {
+	var o *Ownership
+	assert.Nil(t, o.Match("main.tf"))
+}
@@ -64,6 +64,24 @@ type Project struct {
 	TerraformVarFiles []string `yaml:"terraform_var_files,omitempty"`
 	// TerraformVars is a slice of input vars that are to be used with the project.
 	TerraformVars map[string]string `yaml:"terraform_vars,omitempty"`
+	// CloudFormationParameterFiles is any parameter files that are to be used with a CloudFormation template.
+	CloudFormationParameterFiles []string `yaml:"cloudformation_parameter_files,omitempty"`
+	// CloudFormationParameters is a map of parameter values that are to be used with a CloudFormation template.
+	CloudFormationParameters map[string]string `yaml:"cloudformation_parameters,omitempty"`
+	// ARMParameterFiles is any parameter files that are to be used with an ARM template or Bicep file.
+	ARMParameterFiles []string `yaml:"arm_parameter_files,omitempty"`
+	// ARMParameters is a map of parameter values that are to be used with an ARM template or Bicep file.
+	ARMParameters map[string]string `yaml:"arm_parameters,omitempty"`
+	// KubernetesNodePool is the node pool that the workloads of Kubernetes manifests are priced against.
+	KubernetesNodePool *KubernetesNodePool `yaml:"kubernetes_node_pool,omitempty" ignored:"true"`
+	// HelmValuesFiles is any values files that are to be used when rendering a Helm chart.
+	HelmValuesFiles []string `yaml:"helm_values_files,omitempty"`
+	// TerraformDataMocksFile is the path to a file with values for Terraform data sources
+	// and remote state outputs that would otherwise be mocked when evaluating HCL.
+	TerraformDataMocksFile string `yaml:"terraform_data_mocks_file,omitempty" ignored:"true"`
+	// TerraformRemoteStates resolves terraform_remote_state data sources from local state files or from
+	// other projects in the config file, so that their outputs don't need to be mocked.
+	TerraformRemoteStates []TerraformRemoteState `yaml:"terraform_remote_states,omitempty" ignored:"true"`
 	// TerraformForceCLI will run a project by calling out to the terraform/terragrunt binary to generate a plan JSON file.
 	TerraformForceCLI bool `yaml:"terraform_force_cli,omitempty"`
 	// TerraformPlanFlags are flags to pass to terraform plan with Terraform directory paths
@@ -89,6 +107,45 @@ type Project struct {
 	Env               map[string]string `yaml:"env,omitempty" ignored:"true"`
 }
 
+// TerraformRemoteState maps the backend config of terraform_remote_state data sources
+// to where their outputs can be read from. Exactly one of StateFile or Path must be set.
+type TerraformRemoteState struct {
+	// Backend is the backend type of the data sources, e.g. s3. Leave empty to match any backend.
+	Backend string `yaml:"backend,omitempty"`
+	// Config holds the backend config values that the data sources must have, e.g. bucket and key.
+	Config map[string]string `yaml:"config,omitempty"`
+	// StateFile is the path to a state file or `terraform output -json` file, relative to the project.
+	StateFile string `yaml:"state_file,omitempty"`
+	// Path is the path of another project whose evaluated outputs are used. Like the project path,
+	// this is relative to the directory Infracost is run from.
+	Path string `yaml:"path,omitempty"`
+}
+
+// KubernetesNodePool describes the nodes that Kubernetes workloads run on.
+// The nodes are priced as the Terraform node pool resource with the given
+// type, so the estimate matches the cluster's aws_eks_node_group,
+// azurerm_kubernetes_cluster_node_pool or google_container_node_pool. If
+// TerraformPath is set, the node pool is read from the cluster's Terraform
+// project and the other fields override its values.
+type KubernetesNodePool struct {
+	// TerraformPath is the path to the Terraform project of the cluster that has the node pool resource.
+	TerraformPath string `yaml:"terraform_path,omitempty"`
+	// Address is the address of the node pool resource in the Terraform project. Required if the project has more than one node pool.
+	Address string `yaml:"address,omitempty"`
+	// ResourceType is the Terraform resource type of the node pool, e.g. aws_eks_node_group.
+	ResourceType string `yaml:"resource_type,omitempty"`
+	// InstanceType is the instance type, VM size or machine type of the nodes.
+	InstanceType string `yaml:"instance_type,omitempty"`
+	// Region is the region or location of the cluster.
+	Region string `yaml:"region,omitempty"`
+	// CPU is the allocatable CPU of a node, e.g. 1930m. Defaults to the vCPUs of the instance type.
+	CPU string `yaml:"cpu,omitempty"`
+	// Memory is the allocatable memory of a node, e.g. 7Gi. Defaults to the memory of the instance type.
+	Memory string `yaml:"memory,omitempty"`
+	// MinNodes is the minimum number of nodes in the node pool.
+	MinNodes int64 `yaml:"min_nodes,omitempty"`
+}
+
 type Config struct {
 	Credentials   Credentials
 	Configuration Configuration
@@ -118,6 +175,10 @@ type Config struct {
 	EnableCloud               *bool `yaml:"enable_cloud,omitempty" envconfig:"ENABLE_CLOUD"`
 	EnableCloudUpload         *bool `yaml:"enable_cloud_upload,omitempty" envconfig:"ENABLE_CLOUD_UPLOAD"`
 	DisableHCLParsing         bool  `yaml:"disable_hcl_parsing,omitempty" envconfig:"DISABLE_HCL_PARSING"`
+	// HCLEvaluationCache enables caching the evaluated Terraform directories so
+	// that projects that haven't changed since the last run aren't evaluated
+	// again. See the terraform package hcl_cache.go for more information.
+	HCLEvaluationCache bool `yaml:"hcl_evaluation_cache,omitempty" envconfig:"HCL_EVALUATION_CACHE"`
 
 	TLSInsecureSkipVerify *bool  `envconfig:"TLS_INSECURE_SKIP_VERIFY"`
 	TLSCACertFile         string `envconfig:"TLS_CA_CERT_FILE"`
@@ -143,6 +204,13 @@ type Config struct {
 	Fields          []string   `yaml:"fields,omitempty" ignored:"true"`
 	CompareTo       string
 	GitDiffTarget   *string
+	// Recommendations enables the local cost optimisation recommendation rules.
+	Recommendations bool `yaml:"recommendations,omitempty" ignored:"true"`
+	// Carbon enables the estimation of carbon emissions alongside costs.
+	Carbon bool `yaml:"carbon,omitempty" ignored:"true"`
+	// PromptMissingVars prompts the user for the values of the missing
+	// variables of Terraform projects when running interactively.
+	PromptMissingVars bool `yaml:"prompt_missing_vars,omitempty" ignored:"true"`
 
 	// Base configuration settings
 	// RootPath defines the raw value of the `--path` flag provided by the user
@@ -0,0 +1,301 @@
+// Package explain describes how the cost of a single resource was calculated,
+// including the price lookups and usage values that were used.
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+const (
+	UsageSourceUsageFile         = "usage_file"
+	UsageSourceResourceTypeUsage = "resource_type_default_usage"
+	UsageSourceCloudEstimate     = "cloud_estimate"
+	UsageSourceDefault           = "default"
+)
+
+// Usage is a usage value that was applied to the resource.
+type Usage struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+	// Source is where the usage value came from, one of the UsageSource* values.
+	Source string `json:"source"`
+	// UsageKey is the key in the usage file that provided the value, if any.
+	UsageKey string `json:"usageKey,omitempty"`
+}
+
+// CostComponent describes how the cost of a single cost component was
+// calculated.
+type CostComponent struct {
+	Resource            string                `json:"resource"`
+	Name                string                `json:"name"`
+	Unit                string                `json:"unit"`
+	UnitMultiplier      decimal.Decimal       `json:"unitMultiplier"`
+	ProductFilter       *schema.ProductFilter `json:"productFilter,omitempty"`
+	PriceFilter         *schema.PriceFilter   `json:"priceFilter,omitempty"`
+	ProductHash         string                `json:"productHash,omitempty"`
+	ProductAttributes   map[string]string     `json:"productAttributes,omitempty"`
+	PriceHash           string                `json:"priceHash,omitempty"`
+	CustomPrice         bool                  `json:"customPrice,omitempty"`
+	Price               decimal.Decimal       `json:"price"`
+	HourlyQuantity      *decimal.Decimal      `json:"hourlyQuantity"`
+	MonthlyQuantity     *decimal.Decimal      `json:"monthlyQuantity"`
+	MonthlyDiscountPerc float64               `json:"monthlyDiscountPerc,omitempty"`
+	HourlyCost          *decimal.Decimal      `json:"hourlyCost"`
+	MonthlyCost         *decimal.Decimal      `json:"monthlyCost"`
+	Calculation         string                `json:"calculation"`
+}
+
+// Explanation describes how the cost of a resource was calculated.
+type Explanation struct {
+	Address        string           `json:"address"`
+	ResourceType   string           `json:"resourceType"`
+	Project        string           `json:"project"`
+	Currency       string           `json:"currency"`
+	Usage          []Usage          `json:"usage"`
+	CostComponents []CostComponent  `json:"costComponents"`
+	HourlyCost     *decimal.Decimal `json:"hourlyCost"`
+	MonthlyCost    *decimal.Decimal `json:"monthlyCost"`
+	SkipMessage    string           `json:"skipMessage,omitempty"`
+}
+
+// New builds an Explanation for the resource r that was built from partial. The
+// usage map loaded from the usage file is used to find which usage file key
+// provided each usage value.
+func New(projectName string, partial *schema.PartialResource, r *schema.Resource, usageFile schema.UsageMap, currency string) *Explanation {
+	e := &Explanation{
+		Address:      partial.Address,
+		ResourceType: partial.Type,
+		Project:      projectName,
+		Currency:     currency,
+		HourlyCost:   r.HourlyCost,
+		MonthlyCost:  r.MonthlyCost,
+		Usage:        []Usage{},
+	}
+
+	if r.IsSkipped {
+		e.SkipMessage = r.SkipMessage
+	}
+
+	e.Usage = explainUsage(partial, r, usageFile)
+
+	e.CostComponents = []CostComponent{}
+	addCostComponents(e, r, r.Name)
+
+	return e
+}
+
+func explainUsage(partial *schema.PartialResource, r *schema.Resource, usageFile schema.UsageMap) []Usage {
+	usage := []Usage{}
+	seen := map[string]bool{}
+
+	if partial.UsageData != nil {
+		for k, v := range partial.UsageData.Attributes {
+			u := Usage{
+				Key:      k,
+				Value:    v.Value(),
+				Source:   UsageSourceUsageFile,
+				UsageKey: usageFile.KeySource(partial.Address, k),
+			}
+
+			if u.UsageKey == partial.Type {
+				u.Source = UsageSourceResourceTypeUsage
+			}
+
+			usage = append(usage, u)
+			seen[k] = true
+		}
+	}
+
+	if partial.FetchedUsageData != nil {
+		for k, v := range partial.FetchedUsageData.Attributes {
+			if seen[k] {
+				continue
+			}
+
+			usage = append(usage, Usage{
+				Key:    k,
+				Value:  v.Value(),
+				Source: UsageSourceCloudEstimate,
+			})
+			seen[k] = true
+		}
+	}
+
+	for _, item := range r.UsageSchema {
+		if seen[item.Key] || item.DefaultValue == nil {
+			continue
+		}
+
+		usage = append(usage, Usage{
+			Key:    item.Key,
+			Value:  item.DefaultValue,
+			Source: UsageSourceDefault,
+		})
+		seen[item.Key] = true
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Key < usage[j].Key
+	})
+
+	return usage
+}
+
+func addCostComponents(e *Explanation, r *schema.Resource, name string) {
+	for _, c := range r.CostComponents {
+		e.CostComponents = append(e.CostComponents, CostComponent{
+			Resource:            name,
+			Name:                c.Name,
+			Unit:                c.Unit,
+			UnitMultiplier:      c.UnitMultiplier,
+			ProductFilter:       c.ProductFilter,
+			PriceFilter:         c.PriceFilter,
+			ProductHash:         c.ProductHash(),
+			ProductAttributes:   c.ProductAttributes(),
+			PriceHash:           c.PriceHash(),
+			CustomPrice:         c.CustomPrice() != nil,
+			Price:               c.Price(),
+			HourlyQuantity:      c.HourlyQuantity,
+			MonthlyQuantity:     c.MonthlyQuantity,
+			MonthlyDiscountPerc: c.MonthlyDiscountPerc,
+			HourlyCost:          c.HourlyCost,
+			MonthlyCost:         c.MonthlyCost,
+			Calculation:         calculation(c),
+		})
+	}
+
+	for _, s := range r.SubResources {
+		addCostComponents(e, s, name+" / "+s.Name)
+	}
+}
+
+// calculation returns the arithmetic used to calculate the monthly cost of the
+// cost component, mirroring schema.CostComponent.CalculateCosts.
+func calculation(c *schema.CostComponent) string {
+	if c.MonthlyQuantity == nil || c.MonthlyCost == nil {
+		return "no monthly quantity, cost not calculated"
+	}
+
+	// Show the price and quantity in the unit of the cost component, e.g. per
+	// 1M requests, as they're shown in the breakdown.
+	price, quantity := c.Price(), *c.MonthlyQuantity
+	if !c.UnitMultiplier.IsZero() {
+		price = c.UnitMultiplierPrice()
+		quantity = *c.UnitMultiplierMonthlyQuantity()
+	}
+
+	s := fmt.Sprintf("%s × %s %s", price.String(), quantity.String(), c.Unit)
+	if c.MonthlyDiscountPerc != 0 {
+		s += fmt.Sprintf(" × (1 - %s)", decimal.NewFromFloat(c.MonthlyDiscountPerc).String())
+	}
+
+	return fmt.Sprintf("%s = %s", s, c.MonthlyCost.String())
+}
+
+// ToJSON renders the explanation as indented JSON.
+func ToJSON(e *Explanation) ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// ToText renders the explanation in a human readable format.
+func ToText(e *Explanation) ([]byte, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s (%s)\n", ui.BoldString("Resource:"), e.Address, e.ResourceType)
+	fmt.Fprintf(&b, "%s %s\n", ui.BoldString("Project:"), e.Project)
+
+	if e.SkipMessage != "" {
+		fmt.Fprintf(&b, "\nThis resource was skipped: %s\n", e.SkipMessage)
+		return []byte(b.String()), nil
+	}
+
+	b.WriteString("\n" + ui.BoldString("Usage") + "\n")
+	if len(e.Usage) == 0 {
+		b.WriteString("  No usage values applied\n")
+	}
+
+	for _, u := range e.Usage {
+		source := u.Source
+		if u.UsageKey != "" {
+			source = fmt.Sprintf("%s (%s)", source, u.UsageKey)
+		}
+
+		v, err := json.Marshal(u.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&b, "  %s = %s, from %s\n", u.Key, string(v), source)
+	}
+
+	b.WriteString("\n" + ui.BoldString("Cost components") + "\n")
+	if len(e.CostComponents) == 0 {
+		b.WriteString("  No cost components\n")
+	}
+
+	for _, c := range e.CostComponents {
+		fmt.Fprintf(&b, "\n  %s\n", ui.BoldString(c.Name))
+		if c.Resource != e.Address {
+			fmt.Fprintf(&b, "    Resource:         %s\n", c.Resource)
+		}
+
+		if err := writeJSONField(&b, "Product filter:", c.ProductFilter); err != nil {
+			return nil, err
+		}
+		if err := writeJSONField(&b, "Price filter:", c.PriceFilter); err != nil {
+			return nil, err
+		}
+
+		if c.ProductHash != "" {
+			fmt.Fprintf(&b, "    Product hash:     %s\n", c.ProductHash)
+		}
+
+		if len(c.ProductAttributes) > 0 {
+			b.WriteString("    Product attributes:\n")
+
+			keys := make([]string, 0, len(c.ProductAttributes))
+			for k := range c.ProductAttributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				fmt.Fprintf(&b, "      %s: %s\n", k, c.ProductAttributes[k])
+			}
+		}
+
+		price := fmt.Sprintf("%s %s", c.Price.String(), e.Currency)
+		if c.CustomPrice {
+			price += " (custom price)"
+		} else if c.PriceHash != "" {
+			price += fmt.Sprintf(" (price hash %s)", c.PriceHash)
+		}
+
+		fmt.Fprintf(&b, "    Price:            %s\n", price)
+		fmt.Fprintf(&b, "    Monthly cost:     %s\n", c.Calculation)
+	}
+
+	if e.MonthlyCost != nil {
+		fmt.Fprintf(&b, "\n%s %s %s\n", ui.BoldString("Total monthly cost:"), e.MonthlyCost.String(), e.Currency)
+	}
+
+	return []byte(b.String()), nil
+}
+
+func writeJSONField(b *strings.Builder, label string, v interface{}) error {
+	j, err := json.MarshalIndent(v, "    ", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(b, "    %-17s %s\n", label, string(j))
+	return nil
+}
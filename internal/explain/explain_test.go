@@ -0,0 +1,93 @@
+package explain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestNew(t *testing.T) {
+	address := "aws_lambda_function.api"
+	usageFile := schema.NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function": map[string]interface{}{
+			"request_duration_ms": 250,
+		},
+		address: map[string]interface{}{
+			"monthly_requests": 1000000,
+		},
+	})
+
+	partial := &schema.PartialResource{
+		Type:      "aws_lambda_function",
+		Address:   address,
+		UsageData: usageFile.Get(address),
+		FetchedUsageData: schema.NewUsageData(address, map[string]gjson.Result{
+			"monthly_requests": gjson.Parse("5"),
+			"architecture":     gjson.Parse(`"arm64"`),
+		}),
+	}
+
+	c := &schema.CostComponent{
+		Name:                "Requests",
+		Unit:                "1M requests",
+		UnitMultiplier:      decimal.NewFromInt(1000000),
+		MonthlyQuantity:     testutil.DecimalPtr(decimal.NewFromInt(1000000)),
+		MonthlyDiscountPerc: 0.5,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: testutil.StrPtr("aws"),
+			Service:    testutil.StrPtr("AWSLambda"),
+		},
+	}
+	c.SetPrice(decimal.RequireFromString("0.0000002"))
+	c.SetPriceHash("abc123")
+	c.SetProductHash("def456")
+	c.SetProductAttributes(map[string]string{"group": "AWS-Lambda-Requests"})
+
+	r := &schema.Resource{
+		Name:           address,
+		CostComponents: []*schema.CostComponent{c},
+		UsageSchema: []*schema.UsageItem{
+			{Key: "monthly_requests", DefaultValue: 0, ValueType: schema.Int64},
+			{Key: "storage_gb", DefaultValue: 1, ValueType: schema.Float64},
+		},
+	}
+	r.CalculateCosts()
+
+	e := New("my-project", partial, r, usageFile, "USD")
+
+	assert.Equal(t, []Usage{
+		{Key: "architecture", Value: "arm64", Source: UsageSourceCloudEstimate},
+		{Key: "monthly_requests", Value: float64(1000000), Source: UsageSourceUsageFile, UsageKey: address},
+		{Key: "request_duration_ms", Value: float64(250), Source: UsageSourceResourceTypeUsage, UsageKey: "aws_lambda_function"},
+		{Key: "storage_gb", Value: 1, Source: UsageSourceDefault},
+	}, e.Usage)
+
+	require.Len(t, e.CostComponents, 1)
+	cc := e.CostComponents[0]
+	assert.Equal(t, "abc123", cc.PriceHash)
+	assert.Equal(t, "def456", cc.ProductHash)
+	assert.Equal(t, map[string]string{"group": "AWS-Lambda-Requests"}, cc.ProductAttributes)
+	assert.Equal(t, "0.2 × 1 1M requests × (1 - 0.5) = 0.1", cc.Calculation)
+
+	text, err := ToText(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "monthly_requests = 1000000, from usage_file (aws_lambda_function.api)")
+	assert.Contains(t, string(text), "request_duration_ms = 250, from resource_type_default_usage (aws_lambda_function)")
+}
+
+func TestNewSkipped(t *testing.T) {
+	partial := &schema.PartialResource{Type: "aws_foo", Address: "aws_foo.bar"}
+	r := &schema.Resource{Name: "aws_foo.bar", IsSkipped: true, SkipMessage: "This resource is not currently supported"}
+
+	e := New("my-project", partial, r, schema.NewUsageMap(nil), "USD")
+
+	text, err := ToText(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "This resource was skipped: This resource is not currently supported")
+}
@@ -20,6 +20,10 @@ var (
 	invalidFunctionArgumentDiagnostic = "Invalid function argument"
 )
 
+// mockSuffix is appended to the name of an attribute to build the value that
+// is used in its place when it can't be evaluated.
+const mockSuffix = "-mock"
+
 // Attribute provides a wrapper struct around hcl.Attribute it provides
 // helper methods and functionality for common interactions with hcl.Attribute.
 //
@@ -48,6 +52,9 @@ type Attribute struct {
 	// newMock generates a mock value for the attribute if it's value is missing.
 	newMock       func(attr *Attribute) cty.Value
 	previousValue cty.Value
+	// mocked is true if the expression couldn't be evaluated when Value was
+	// last called, so the value is, or is built from, mocked values.
+	mocked bool
 }
 
 // IsIterable returns if the attribute can be ranged over.
@@ -171,10 +178,15 @@ func (attr *Attribute) value(retry int) (ctyVal cty.Value) {
 		}
 	}()
 
+	if retry == 0 {
+		attr.mocked = false
+	}
+
 	var diag hcl.Diagnostics
 	ctyVal, diag = attr.HCLAttr.Expr.Value(attr.Ctx.Inner())
 	if diag.HasErrors() {
-		mockedVal := cty.StringVal(fmt.Sprintf("%s-mock", attr.Name()))
+		attr.mocked = true
+		mockedVal := cty.StringVal(attr.Name() + mockSuffix)
 		if attr.newMock != nil {
 			mockedVal = attr.newMock(attr)
 		}
@@ -343,10 +355,13 @@ func (attr *Attribute) graphValue() (ctyVal cty.Value) {
 		}
 	}()
 
+	attr.mocked = false
+
 	var diag hcl.Diagnostics
 	ctyVal, diag = attr.HCLAttr.Expr.Value(attr.Ctx.Inner())
 	if diag.HasErrors() {
-		mockedVal := cty.StringVal(fmt.Sprintf("%s-mock", attr.Name()))
+		attr.mocked = true
+		mockedVal := cty.StringVal(attr.Name() + mockSuffix)
 		if attr.newMock != nil {
 			mockedVal = attr.newMock(attr)
 		}
@@ -53,6 +53,23 @@ var (
 				Type:       "data",
 				LabelNames: []string{"type", "name"},
 			},
+			{
+				Type:       "ephemeral",
+				LabelNames: []string{"type", "name"},
+			},
+			{
+				Type:       "check",
+				LabelNames: []string{"name"},
+			},
+			{
+				Type: "moved",
+			},
+			{
+				Type: "import",
+			},
+			{
+				Type: "removed",
+			},
 		},
 	}
 	terraformAndProviderBlocks = &hcl.BodySchema{
@@ -284,8 +301,12 @@ type Block struct {
 	verbose    bool
 	logger     zerolog.Logger
 	newMock    func(attr *Attribute) cty.Value
+	dataMocks  *DataMocks
 	attributes []*Attribute
 	reference  *Reference
+	// instanceKey is the for_each key of a provider instance. See
+	// Block.ProviderInstances for more information.
+	instanceKey *string
 
 	Filename  string
 	StartLine int
@@ -295,6 +316,7 @@ type Block struct {
 // BlockBuilder handles generating new Blocks as part of the parsing and evaluation process.
 type BlockBuilder struct {
 	MockFunc      func(a *Attribute) cty.Value
+	DataMocks     *DataMocks
 	SetAttributes []SetAttributesFunc
 	Logger        zerolog.Logger
 	HCLParser     *modules.SharedHCLParser
@@ -320,6 +342,7 @@ func (b BlockBuilder) NewBlock(filename string, rootPath string, hclBlock *hcl.B
 			childBlocks: make(Blocks, len(body.Blocks)),
 			verbose:     isLoggingVerbose,
 			newMock:     b.MockFunc,
+			dataMocks:   b.DataMocks,
 			parent:      parent,
 		}
 
@@ -353,6 +376,7 @@ func (b BlockBuilder) NewBlock(filename string, rootPath string, hclBlock *hcl.B
 			rootPath:    rootPath,
 			verbose:     isLoggingVerbose,
 			newMock:     b.MockFunc,
+			dataMocks:   b.DataMocks,
 		}
 		block.setLogger(b.Logger)
 
@@ -371,6 +395,7 @@ func (b BlockBuilder) NewBlock(filename string, rootPath string, hclBlock *hcl.B
 		childBlocks: make(Blocks, len(content.Blocks)),
 		verbose:     isLoggingVerbose,
 		newMock:     b.MockFunc,
+		dataMocks:   b.DataMocks,
 	}
 
 	for i, hb := range content.Blocks {
@@ -463,7 +488,7 @@ type SetAttributesFunc func(b *Block)
 // on the block.
 func SetUUIDAttributes(b *Block) {
 	t := b.Type()
-	if t != "resource" && t != "data" {
+	if t != "resource" && t != "data" && t != "ephemeral" {
 		return
 	}
 
@@ -598,7 +623,7 @@ func (b *Block) ShouldExpand() bool {
 		return false
 	}
 
-	validType := b.Type() == "resource" || b.Type() == "module" || b.Type() == "data"
+	validType := b.Type() == "resource" || b.Type() == "module" || b.Type() == "data" || b.Type() == "ephemeral"
 	if !validType {
 		return false
 	}
@@ -622,6 +647,71 @@ func (b *Block) SetContext(ctx *Context) {
 	}
 }
 
+// ProviderInstances returns the instances of a provider Block. OpenTofu
+// supports for_each on aliased providers, which creates an instance of the
+// provider for each element, e.g:
+//
+//	provider "aws" {
+//	  alias    = "by_region"
+//	  for_each = toset(["us-east-1", "eu-west-1"])
+//	  region   = each.value
+//	}
+//
+// Resources then reference an instance with aws.by_region["us-east-1"]. Each
+// returned Block evaluates its attributes with each.key and each.value set.
+// Provider Blocks without for_each return the Block itself.
+func (b *Block) ProviderInstances() Blocks {
+	forEachAttr := b.GetAttribute("for_each")
+	if b.Type() != "provider" || forEachAttr == nil || b.GetAttribute("alias") == nil {
+		return Blocks{b}
+	}
+
+	value := forEachAttr.Value()
+	if value.IsNull() || !value.IsKnown() || !forEachAttr.IsIterable() {
+		b.logger.Debug().Msgf("provider %s has a for_each value that is unknown, skipping instances", b.FullName())
+		return nil
+	}
+
+	var instances Blocks
+	value.ForEachElement(func(key cty.Value, val cty.Value) bool {
+		var keyStr string
+		err := gocty.FromCtyValue(key, &keyStr)
+		if err != nil {
+			b.logger.Debug().Err(err).Msgf("could not marshal gocty key %s to string", key)
+			return false
+		}
+
+		ctx := b.context.NewChild()
+		ctx.SetByDot(key, "each.key")
+		ctx.SetByDot(val, "each.value")
+
+		instance := b.withContext(ctx, b.parent)
+		instance.instanceKey = &keyStr
+		instances = append(instances, instance)
+
+		return false
+	})
+
+	return instances
+}
+
+// withContext returns a copy of the Block and its child Blocks that
+// evaluates its attributes with ctx.
+func (b *Block) withContext(ctx *Context, parent *Block) *Block {
+	c := *b
+	c.context = ctx
+	c.parent = parent
+	c.attributes = nil
+	c.reference = nil
+
+	c.childBlocks = make(Blocks, len(b.childBlocks))
+	for i, child := range b.childBlocks {
+		c.childBlocks[i] = child.withContext(ctx.NewChild(), &c)
+	}
+
+	return &c
+}
+
 // HasModuleBlock returns is the Block as a module associated with it. If it doesn't this means
 // that this Block is part of the root Module.
 func (b *Block) HasModuleBlock() bool {
@@ -1013,11 +1103,20 @@ func (b *Block) getHCLAttributes() hcl.Attributes {
 //
 // Would evaluate to a cty.Value of type Object with the instance_type Attribute holding the value "t3.medium".
 func (b *Block) Values() cty.Value {
+	var values cty.Value
 	if f, ok := blockValueFuncs[fmt.Sprintf("%s.%s", b.Type(), b.TypeLabel())]; ok {
-		return f(b)
+		values = f(b)
+	} else {
+		values = b.values()
+	}
+
+	// user supplied data source values take precedence over the values
+	// that we'd otherwise have to compute or mock.
+	if mocked, ok := b.dataMocks.values(b); ok && isValidCtyObject(mocked) {
+		return mergeObjects(values, mocked)
 	}
 
-	return b.values()
+	return values
 }
 
 func (b *Block) values() cty.Value {
@@ -1063,6 +1162,10 @@ func (b *Block) values() cty.Value {
 			configKey = configKey + "." + alias
 		}
 
+		if b.instanceKey != nil {
+			configKey = fmt.Sprintf("%s[%q]", configKey, *b.instanceKey)
+		}
+
 		if b.ModuleAddress() != "" {
 			configKey = b.ModuleAddress() + ":" + configKey
 		}
@@ -1301,6 +1404,7 @@ var (
 		"data.aws_region":             awsCurrentRegion,
 		"data.aws_default_tags":       awsDefaultTagValues,
 		"resource.random_shuffle":     randomShuffleValues,
+		"data.terraform_remote_state": terraformRemoteStateValues,
 	}
 )
 
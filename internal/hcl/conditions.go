@@ -0,0 +1,108 @@
+package hcl
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// failedConditions evaluates the validation blocks of the variables of the
+// module and the precondition and postcondition blocks of its resources, data
+// sources and outputs. It returns a message for each condition that is false.
+//
+// Conditions that reference values which are unknown or were mocked, e.g. a
+// missing variable or the attributes of a resource that are only known after
+// apply, are skipped as we can't tell if they would fail.
+func (e *Evaluator) failedConditions() []string {
+	var failed []string
+
+	for _, b := range configBlocks(&e.module).OfType("variable") {
+		if e.isMissingVariable(b) {
+			continue
+		}
+
+		for _, validation := range b.GetChildBlocks("validation") {
+			if msg, ok := failedCondition(validation); ok {
+				failed = append(failed, fmt.Sprintf("%s: %s", b.FullName(), msg))
+			}
+		}
+	}
+
+	for _, b := range e.module.Blocks {
+		var conditions Blocks
+		switch b.Type() {
+		case "resource", "data":
+			for _, lifecycle := range b.GetChildBlocks("lifecycle") {
+				conditions = append(conditions, lifecycle.GetChildBlocks("precondition")...)
+				conditions = append(conditions, lifecycle.GetChildBlocks("postcondition")...)
+			}
+		case "output":
+			conditions = b.GetChildBlocks("precondition")
+		default:
+			continue
+		}
+
+		for _, condition := range conditions {
+			if msg, ok := failedCondition(condition); ok {
+				failed = append(failed, fmt.Sprintf("%s: %s", b.FullName(), msg))
+			}
+		}
+	}
+
+	return failed
+}
+
+// failedCondition returns the error message of the validation, precondition or
+// postcondition block b and true if its condition is false.
+func failedCondition(b *Block) (string, bool) {
+	condition := b.GetAttribute("condition")
+	if condition == nil {
+		return "", false
+	}
+
+	val, ok := knownValue(condition)
+	if !ok || val.Type() != cty.Bool || val.True() {
+		return "", false
+	}
+
+	msg := fmt.Sprintf("condition %s failed", exprSource(condition.HCLAttr.Expr))
+	if attr := b.GetAttribute("error_message"); attr != nil {
+		if v, ok := knownValue(attr); ok && v.Type() == cty.String {
+			msg = v.AsString()
+		}
+	}
+
+	return msg, true
+}
+
+// knownValue evaluates the attribute without mocking any of the values it
+// references, unlike Attribute.Value. It returns false if the value can't be
+// evaluated, or if it references values that are unknown or were mocked.
+func knownValue(attr *Attribute) (val cty.Value, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			attr.Logger.Debug().Msgf("could not evaluate value for attr: %s. Err: %s", attr.Name(), err)
+			val, ok = cty.NilVal, false
+		}
+	}()
+
+	if attr.Ctx == nil {
+		return cty.NilVal, false
+	}
+
+	ctx := attr.Ctx.Inner()
+	for _, traversal := range attr.HCLAttr.Expr.Variables() {
+		v, diags := traversal.TraverseAbs(ctx)
+		if diags.HasErrors() || !v.IsWhollyKnown() || isMockedValue(v) {
+			return cty.NilVal, false
+		}
+	}
+
+	val, diags := attr.HCLAttr.Expr.Value(ctx)
+	if diags.HasErrors() || val.IsNull() || !val.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+
+	val, _ = val.Unmark()
+	return val, true
+}
@@ -0,0 +1,212 @@
+package hcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+)
+
+// DataMocks holds user supplied values for data sources. Data sources are
+// read from the cloud provider at plan time so the Evaluator can't know their
+// values and would otherwise mock any of their attributes that are referenced.
+// DataMocks are loaded from a YAML file, e.g:
+//
+//	data:
+//	  # a data source address, the module prefix and instance keys are optional.
+//	  data.aws_ami.ubuntu:
+//	    id: ami-0c55b159cbfafe1f0
+//	  # a data source type, used for all data sources of this type that
+//	  # don't have their address listed.
+//	  aws_ssm_parameter:
+//	    value: t3.large
+//	remote_state:
+//	  - backend: s3
+//	    config:
+//	      bucket: acme-terraform-state
+//	      key: network/terraform.tfstate
+//	    outputs:
+//	      vpc_id: vpc-0a1b2c3d
+type DataMocks struct {
+	data         map[string]cty.Value
+	remoteStates []*remoteStateOutputs
+}
+
+type dataMocksFile struct {
+	Data        map[string]map[string]interface{} `yaml:"data"`
+	RemoteState []struct {
+		Backend string                 `yaml:"backend"`
+		Config  map[string]interface{} `yaml:"config"`
+		Outputs map[string]interface{} `yaml:"outputs"`
+	} `yaml:"remote_state"`
+}
+
+// LoadDataMocks reads the DataMocks from the YAML file at path.
+func LoadDataMocks(path string) (*DataMocks, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read data mocks file %s: %w", path, err)
+	}
+
+	var f dataMocksFile
+	err = yaml.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse data mocks file %s: %w", path, err)
+	}
+
+	mocks := &DataMocks{data: make(map[string]cty.Value, len(f.Data))}
+	for key, attrs := range f.Data {
+		val, err := toCtyValue(attrs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid values for data source %s: %w", key, err)
+		}
+
+		mocks.data[key] = val
+	}
+
+	for i, rs := range f.RemoteState {
+		outputs, err := toCtyValue(rs.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outputs for remote state %d: %w", i, err)
+		}
+
+		config := make(map[string]string, len(rs.Config))
+		for k, v := range rs.Config {
+			config[k] = fmt.Sprint(v)
+		}
+
+		mocks.addRemoteStates(RemoteState{
+			Backend: rs.Backend,
+			Config:  config,
+			Outputs: func() (cty.Value, error) {
+				return outputs, nil
+			},
+		})
+	}
+
+	return mocks, nil
+}
+
+// values returns the user supplied values for the data block b. An exact
+// address match takes precedence over an address without instance keys, which
+// in turn takes precedence over the data source type.
+func (m *DataMocks) values(b *Block) (cty.Value, bool) {
+	if m == nil || b.Type() != "data" {
+		return cty.NilVal, false
+	}
+
+	if b.TypeLabel() == "terraform_remote_state" {
+		for _, rs := range m.remoteStates {
+			if !rs.matches(b) {
+				continue
+			}
+
+			outputs, err := rs.value()
+			if err != nil {
+				b.logger.Debug().Err(err).Msgf("could not load outputs for remote state %s", b.FullName())
+				continue
+			}
+
+			return cty.ObjectVal(map[string]cty.Value{"outputs": outputs}), true
+		}
+	}
+
+	address := b.FullName()
+	for _, key := range []string{address, modArrayPartReplace.ReplaceAllString(address, ""), b.TypeLabel()} {
+		if v, ok := m.data[key]; ok {
+			return v, true
+		}
+	}
+
+	return cty.NilVal, false
+}
+
+// DataSources returns the addresses of the data sources in the Module and its
+// child modules. supplied are the data sources that had values provided in
+// DataMocks or resolved from a remote state, mocked are those whose attributes
+// are mocked by the Evaluator.
+// Data sources that are computed by Infracost, e.g. aws_region, are in neither.
+func (m *Module) DataSources() (supplied []string, mocked []string) {
+	seen := map[string]struct{}{}
+	m.collectDataSources(seen, &supplied, &mocked)
+
+	sort.Strings(supplied)
+	sort.Strings(mocked)
+
+	return supplied, mocked
+}
+
+func (m *Module) collectDataSources(seen map[string]struct{}, supplied *[]string, mocked *[]string) {
+	for _, b := range m.Blocks.OfType("data") {
+		address := b.FullName()
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+
+		if isSuppliedDataSource(b) {
+			*supplied = append(*supplied, address)
+			continue
+		}
+
+		if isComputedDataSource(b) {
+			continue
+		}
+
+		*mocked = append(*mocked, address)
+	}
+
+	for _, child := range m.Modules {
+		child.collectDataSources(seen, supplied, mocked)
+	}
+}
+
+// isSuppliedDataSource returns true if the values of the data block b were
+// provided by the user, either in DataMocks or as a remote state.
+func isSuppliedDataSource(b *Block) bool {
+	if _, ok := b.dataMocks.values(b); ok {
+		return true
+	}
+
+	if b.TypeLabel() == "terraform_remote_state" {
+		_, err := localRemoteStateOutputs(b)
+		return err == nil
+	}
+
+	return false
+}
+
+// isComputedDataSource returns true if Infracost computes the values of the
+// data block b, e.g. the zones of aws_availability_zones.
+func isComputedDataSource(b *Block) bool {
+	switch b.TypeLabel() {
+	case "local_file":
+		return true
+	case "terraform_remote_state":
+		// remote state can only be read if it's supplied, see isSuppliedDataSource.
+		return false
+	}
+
+	_, ok := blockValueFuncs[fmt.Sprintf("%s.%s", b.Type(), b.TypeLabel())]
+	return ok
+}
+
+// toCtyValue converts a value decoded from YAML into a cty.Value with the
+// type implied by its JSON representation.
+func toCtyValue(v interface{}) (cty.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	ty, err := ctyJson.ImpliedType(b)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyJson.Unmarshal(b, ty)
+}
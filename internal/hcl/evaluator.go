@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
@@ -35,10 +37,11 @@ var (
 	nestedModReplace    = regexp.MustCompile(`\.module\.`)
 	modArrayPartReplace = regexp.MustCompile(`\[[^[]*\]`)
 	validBlocksToExpand = map[string]struct{}{
-		"resource": {},
-		"module":   {},
-		"dynamic":  {},
-		"data":     {},
+		"resource":  {},
+		"module":    {},
+		"dynamic":   {},
+		"data":      {},
+		"ephemeral": {},
 	}
 
 	sensitiveRegxp = regexp.MustCompile(strings.Join([]string{
@@ -96,6 +99,9 @@ type Evaluator struct {
 	newSpinner     ui.SpinnerFunc
 	logger         zerolog.Logger
 	filteredBlocks []*Block
+	// mu guards the Context and the filtered blocks of the Evaluator when
+	// the Graph visits vertices of different modules concurrently.
+	mu sync.Mutex
 }
 
 // NewEvaluator returns an Evaluator with Context initialised with top level variables.
@@ -142,6 +148,8 @@ func NewEvaluator(
 		b.SetContext(ctx.NewChild())
 	}
 
+	module.inputVars = inputVars
+
 	moduleName := module.Name
 	if moduleName == "" {
 		moduleName = "root"
@@ -180,38 +188,81 @@ func (e *Evaluator) AddFilteredBlocks(blocks ...*Block) {
 	}
 }
 
+// sortFilteredBlocks sorts the filtered blocks into the order of the blocks
+// they were expanded from in the module, so that it doesn't depend on the order
+// that the Graph visited them in. Blocks expanded from the same block keep
+// their order.
+func (e *Evaluator) sortFilteredBlocks() {
+	indexes := make(map[*hcl.Block]int, len(e.module.Blocks))
+	for i, b := range e.module.Blocks {
+		indexes[b.HCLBlock] = i
+	}
+
+	// blocks with dynamic blocks are copied when they're expanded, but the
+	// copy keeps the hcl.Block of the block it was copied from.
+	index := func(b *Block) int {
+		for ; b != nil; b = b.original {
+			if i, ok := indexes[b.HCLBlock]; ok {
+				return i
+			}
+		}
+
+		return len(indexes)
+	}
+
+	sort.SliceStable(e.filteredBlocks, func(i, j int) bool {
+		return index(e.filteredBlocks[i]) < index(e.filteredBlocks[j])
+	})
+}
+
+// addRefactoringBlocks adds the moved, import and removed blocks to the
+// filtered blocks. These aren't evaluated as part of the graph but are needed
+// once evaluation is complete to annotate the resources they refer to.
+func (e *Evaluator) addRefactoringBlocks() {
+	for _, t := range refactoringBlockTypes {
+		e.AddFilteredBlocks(e.module.Blocks.OfType(t)...)
+	}
+}
+
 // MissingVars returns a list of names of the variable blocks with missing input values.
 func (e *Evaluator) MissingVars() []string {
 	var missing []string
 
 	blocks := e.module.Blocks.OfType("variable")
 	for _, block := range blocks {
-		name := block.Label()
-
-		var sensitive bool
-		value := block.GetAttribute("sensitive").Value()
-		if !value.IsNull() {
-			err := gocty.FromCtyValue(value, &sensitive)
-			if err != nil {
-				e.logger.Debug().Msgf("could not convert 'sensitive' attribute for variable.%s err: %s", name, err)
-			}
+		if e.isMissingVariable(block) {
+			missing = append(missing, fmt.Sprintf("variable.%s", block.Label()))
 		}
+	}
 
-		if sensitive {
-			continue
-		}
+	return missing
+}
 
-		if sensitiveRegxp.MatchString(strings.ToLower(name)) {
-			continue
-		}
+// isMissingVariable returns true if the variable block doesn't have an input
+// value or a default. Sensitive variables are never missing since we don't
+// want to ask for their values.
+func (e *Evaluator) isMissingVariable(block *Block) bool {
+	name := block.Label()
 
-		_, v := e.evaluateVariable(block, e.inputVars)
-		if v == errorNoVarValue {
-			missing = append(missing, fmt.Sprintf("variable.%s", name))
+	var sensitive bool
+	value := block.GetAttribute("sensitive").Value()
+	if !value.IsNull() {
+		err := gocty.FromCtyValue(value, &sensitive)
+		if err != nil {
+			e.logger.Debug().Msgf("could not convert 'sensitive' attribute for variable.%s err: %s", name, err)
 		}
 	}
 
-	return missing
+	if sensitive {
+		return false
+	}
+
+	if sensitiveRegxp.MatchString(strings.ToLower(name)) {
+		return false
+	}
+
+	_, v := e.evaluateVariable(block, e.inputVars)
+	return v == errorNoVarValue
 }
 
 // Run builds the Evaluator Context using all the provided Blocks. It will build up the Context to hold
@@ -246,14 +297,32 @@ func (e *Evaluator) Run() (*Module, error) {
 
 func (e *Evaluator) collectModules() *Module {
 	root := e.module
-	for _, definition := range e.moduleCalls {
-		root.Modules = append(root.Modules, definition.Module)
+
+	// sort the module calls so the order of the modules doesn't depend on
+	// the order of the map.
+	names := make([]string, 0, len(e.moduleCalls))
+	for name := range e.moduleCalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		root.Modules = append(root.Modules, e.moduleCalls[name].Module)
 	}
 
 	if v := e.MissingVars(); len(v) > 0 {
 		root.Warnings = append(root.Warnings, schema.NewDiagMissingVars(v...))
 	}
 
+	root.FailedConditions = e.failedConditions()
+	for _, m := range root.Modules {
+		root.FailedConditions = append(root.FailedConditions, m.FailedConditions...)
+	}
+
+	if len(root.FailedConditions) > 0 {
+		root.Warnings = append(root.Warnings, schema.NewDiagFailedConditions(root.FailedConditions...))
+	}
+
 	return &root
 }
 
@@ -299,6 +368,7 @@ func (e *Evaluator) evaluateStep(i int) {
 
 	e.ctx.Set(e.getValuesByBlockType("variable"), "var")
 	e.ctx.Set(e.getValuesByBlockType("data"), "data")
+	e.ctx.Set(e.getValuesByBlockType("ephemeral"), "ephemeral")
 	e.ctx.Set(e.getValuesByBlockType("locals"), "local")
 
 	resources := e.getValuesByBlockType("resource")
@@ -464,6 +534,7 @@ func (e *Evaluator) expandDynamicBlock(b *Block) *Block {
 		verbose:     b.verbose,
 		logger:      b.logger,
 		newMock:     b.newMock,
+		dataMocks:   b.dataMocks,
 		attributes:  b.attributes,
 		reference:   b.reference,
 		Filename:    b.Filename,
@@ -853,7 +924,7 @@ func (e *Evaluator) getValuesByBlockType(blockType string) cty.Value {
 
 			e.logger.Debug().Msgf("adding %s %s to the evaluation context", b.Type(), b.Label())
 			values[b.Label()] = b.Values()
-		case "resource", "data":
+		case "resource", "data", "ephemeral":
 			if len(b.Labels()) < 2 {
 				continue
 			}
@@ -885,9 +956,20 @@ func (e *Evaluator) evaluateProvider(b *Block, values map[string]cty.Value) cty.
 		return cty.ObjectVal(values)
 	}
 
+	aliasVal := b.Values()
+	if b.GetAttribute("for_each") != nil {
+		// providers with for_each are referenced by their instance key, e.g.
+		// aws.by_region["us-east-1"].
+		instances := make(map[string]cty.Value)
+		for _, instance := range b.ProviderInstances() {
+			instances[*instance.instanceKey] = instance.Values()
+		}
+		aliasVal = cty.ObjectVal(instances)
+	}
+
 	if !exists {
 		return cty.ObjectVal(map[string]cty.Value{
-			str: b.Values(),
+			str: aliasVal,
 		})
 	}
 
@@ -895,7 +977,7 @@ func (e *Evaluator) evaluateProvider(b *Block, values map[string]cty.Value) cty.
 	if ob == nil {
 		ob = make(map[string]cty.Value)
 	}
-	ob[str] = b.Values()
+	ob[str] = aliasVal
 	return cty.ObjectVal(ob)
 }
 
@@ -1110,9 +1192,11 @@ func (e *Evaluator) loadModules(lastContext hcl.EvalContext) {
 // ExpFunctions returns the set of functions that should be used to when evaluating
 // expressions in the receiving scope.
 func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Function {
-	return map[string]function.Function{
+	fns := map[string]function.Function{
 		"abs":              stdlib.AbsoluteFunc,
 		"abspath":          funcs.AbsPathFunc,
+		"alltrue":          funcs.AllTrueFunc,
+		"anytrue":          funcs.AnyTrueFunc,
 		"basename":         funcs.BasenameFunc,
 		"base64decode":     funcs.Base64DecodeFunc,
 		"base64encode":     funcs.Base64EncodeFunc,
@@ -1137,6 +1221,7 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"distinct":         stdlib.DistinctFunc,
 		"element":          stdlib.ElementFunc,
 		"endswith":         funcs.EndsWithFunc,
+		"ephemeralasnull":  funcs.EphemeralAsNullFunc,
 		"chunklist":        stdlib.ChunklistFunc,
 		"file":             funcs.MakeFileFunc(baseDir, false),
 		"fileexists":       funcs.MakeFileExistsFunc(baseDir),
@@ -1154,6 +1239,7 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"formatdate":       stdlib.FormatDateFunc,
 		"formatlist":       stdlib.FormatListFunc,
 		"indent":           stdlib.IndentFunc,
+		"issensitive":      funcs.IsSensitiveFunc,
 		"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
 		"join":             stdlib.JoinFunc,
 		"jsondecode":       funcs.JSONDecodeFunc,
@@ -1170,8 +1256,11 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"md5":              funcs.Md5Func,
 		"merge":            funcs.MergeFunc,
 		"min":              stdlib.MinFunc,
+		"nonsensitive":     funcs.NonsensitiveFunc,
+		"one":              funcs.OneFunc,
 		"parseint":         stdlib.ParseIntFunc,
 		"pathexpand":       funcs.PathExpandFunc,
+		"plantimestamp":    funcs.MockTimestampFunc, // We want to return a deterministic value each time
 		"infracostlog":     funcs.LogArgs(logger),
 		"infracostprint":   funcs.PrintArgs,
 		"pow":              stdlib.PowFunc,
@@ -1181,6 +1270,7 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"replace":          funcs.ReplaceFunc,
 		"reverse":          stdlib.ReverseListFunc,
 		"rsadecrypt":       funcs.RsaDecryptFunc,
+		"sensitive":        funcs.SensitiveFunc,
 		"setintersection":  stdlib.SetIntersectionFunc,
 		"setproduct":       stdlib.SetProductFunc,
 		"setsubtract":      stdlib.SetSubtractFunc,
@@ -1196,8 +1286,12 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"strcontains":      funcs.StrContainsFunc,
 		"strrev":           stdlib.ReverseFunc,
 		"substr":           stdlib.SubstrFunc,
+		"sum":              funcs.SumFunc,
+		"textdecodebase64": funcs.TextDecodeBase64Func,
+		"textencodebase64": funcs.TextEncodeBase64Func,
 		"timestamp":        funcs.MockTimestampFunc, // We want to return a deterministic value each time
 		"timeadd":          stdlib.TimeAddFunc,
+		"timecmp":          funcs.TimeCmpFunc,
 		"title":            stdlib.TitleFunc,
 		"tostring":         funcs.MakeToFunc(cty.String),
 		"tonumber":         funcs.MakeToFunc(cty.Number),
@@ -1219,6 +1313,43 @@ func ExpFunctions(baseDir string, logger zerolog.Logger) map[string]function.Fun
 		"yamldecode":       funcs.YAMLDecodeFunc,
 		"yamlencode":       yaml.YAMLEncodeFunc,
 		"zipmap":           stdlib.ZipmapFunc,
+
+		"provider::terraform::decode_tfvars": funcs.DecodeTfvarsFunc,
+		"provider::terraform::encode_expr":   funcs.EncodeExprFunc,
+		"provider::terraform::encode_tfvars": funcs.EncodeTfvarsFunc,
+
+		"provider::aws::arn_build":          funcs.ARNBuildFunc,
+		"provider::aws::arn_parse":          funcs.ARNParseFunc,
+		"provider::aws::trim_iam_role_path": funcs.TrimIAMRolePathFunc,
+
+		"provider::azurerm::normalise_resource_id": funcs.NormaliseResourceIDFunc,
+		"provider::azurerm::parse_resource_id":     funcs.ParseResourceIDFunc,
+
+		"provider::google::location_from_id": funcs.LocationFromIDFunc,
+		"provider::google::name_from_id":     funcs.NameFromIDFunc,
+		"provider::google::project_from_id":  funcs.ProjectFromIDFunc,
+		"provider::google::region_from_id":   funcs.RegionFromIDFunc,
+		"provider::google::region_from_zone": funcs.RegionFromZoneFunc,
+		"provider::google::zone_from_id":     funcs.ZoneFromIDFunc,
+	}
+
+	// the template functions can call any of the other functions so they need
+	// to be added once the rest are.
+	fns["templatefile"] = funcs.MakeTemplateFileFunc(baseDir, func() map[string]function.Function { return fns })
+	fns["templatestring"] = funcs.MakeTemplateStringFunc(func() map[string]function.Function { return fns })
+
+	// Terraform allows the built-in functions to be called with the core::
+	// prefix so that they can't be confused with provider functions.
+	core := make(map[string]function.Function, len(fns))
+	for name, fn := range fns {
+		if !strings.Contains(name, "::") && !strings.HasPrefix(name, "infracost") {
+			core["core::"+name] = fn
+		}
+	}
+
+	for name, fn := range core {
+		fns[name] = fn
 	}
 
+	return fns
 }
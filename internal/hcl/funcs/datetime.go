@@ -44,6 +44,40 @@ var TimeAddFunc = function.New(&function.Spec{
 	},
 })
 
+// TimeCmpFunc constructs a function that compares two timestamps.
+var TimeCmpFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "timestamp_a",
+			Type: cty.String,
+		},
+		{
+			Name: "timestamp_b",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Number),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		tsA, err := time.Parse(time.RFC3339, args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Number), function.NewArgError(0, err)
+		}
+		tsB, err := time.Parse(time.RFC3339, args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Number), function.NewArgError(1, err)
+		}
+
+		switch {
+		case tsA.Equal(tsB):
+			return cty.NumberIntVal(0), nil
+		case tsA.Before(tsB):
+			return cty.NumberIntVal(-1), nil
+		default:
+			return cty.NumberIntVal(1), nil
+		}
+	},
+})
+
 // Timestamp returns a string representation of a static timestamp.
 //
 // In the Terraform language, timestamps are conventionally represented as
@@ -69,3 +103,12 @@ func Timestamp() (cty.Value, error) {
 func TimeAdd(timestamp cty.Value, duration cty.Value) (cty.Value, error) {
 	return TimeAddFunc.Call([]cty.Value{timestamp, duration})
 }
+
+// TimeCmp compares two timestamps, returning -1 if the first is before the
+// second, 0 if they're the same moment and 1 if the first is after the second.
+//
+// Both timestamps must be strings in RFC 3339 format. Timestamps with different
+// UTC offsets are compared by the moment in time they represent.
+func TimeCmp(timestampA, timestampB cty.Value) (cty.Value, error) {
+	return TimeCmpFunc.Call([]cty.Value{timestampA, timestampB})
+}
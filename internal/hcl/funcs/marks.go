@@ -43,6 +43,10 @@ func Contains(val cty.Value, mark valueMark) bool {
 // Terraform.
 var MarkedSensitive = valueMark("sensitive")
 
+// MarkedEphemeral indicates that this value is ephemeral in the context of
+// Terraform, so it must not be persisted in the plan or state.
+var MarkedEphemeral = valueMark("ephemeral")
+
 // MarkedRaw is used to indicate to the repl that the value should be written without
 // any formatting.
 var MarkedRaw = valueMark("raw")
@@ -0,0 +1,127 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// The functions in this file are the ones provided by the AWS provider, and are
+// called with the provider::aws:: prefix.
+
+var arnObjectType = cty.Object(map[string]cty.Type{
+	"partition":  cty.String,
+	"service":    cty.String,
+	"region":     cty.String,
+	"account_id": cty.String,
+	"resource":   cty.String,
+})
+
+// ARNParseFunc constructs a function that parses an ARN into its parts.
+var ARNParseFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "arn",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(arnObjectType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		a, err := arn.Parse(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+
+		return cty.ObjectVal(map[string]cty.Value{
+			"partition":  cty.StringVal(a.Partition),
+			"service":    cty.StringVal(a.Service),
+			"region":     cty.StringVal(a.Region),
+			"account_id": cty.StringVal(a.AccountID),
+			"resource":   cty.StringVal(a.Resource),
+		}), nil
+	},
+})
+
+// ARNBuildFunc constructs a function that builds an ARN from its parts.
+var ARNBuildFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "partition",
+			Type: cty.String,
+		},
+		{
+			Name: "service",
+			Type: cty.String,
+		},
+		{
+			Name: "region",
+			Type: cty.String,
+		},
+		{
+			Name: "account_id",
+			Type: cty.String,
+		},
+		{
+			Name: "resource",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		a := arn.ARN{
+			Partition: args[0].AsString(),
+			Service:   args[1].AsString(),
+			Region:    args[2].AsString(),
+			AccountID: args[3].AsString(),
+			Resource:  args[4].AsString(),
+		}
+
+		return cty.StringVal(a.String()), nil
+	},
+})
+
+// TrimIAMRolePathFunc constructs a function that removes the path from the ARN
+// of an IAM role, e.g. arn:aws:iam::444455556666:role/path/example becomes
+// arn:aws:iam::444455556666:role/example.
+var TrimIAMRolePathFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "arn",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		a, err := arn.Parse(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(0, err)
+		}
+
+		if a.Service != "iam" || !strings.HasPrefix(a.Resource, "role/") {
+			return cty.UnknownVal(retType), function.NewArgError(0, fmt.Errorf("%q is not the ARN of an IAM role", args[0].AsString()))
+		}
+
+		parts := strings.Split(a.Resource, "/")
+		a.Resource = "role/" + parts[len(parts)-1]
+
+		return cty.StringVal(a.String()), nil
+	},
+})
+
+// ARNParse returns the parts of the ARN.
+func ARNParse(a cty.Value) (cty.Value, error) {
+	return ARNParseFunc.Call([]cty.Value{a})
+}
+
+// ARNBuild returns the ARN built from the parts.
+func ARNBuild(partition, service, region, accountID, resource cty.Value) (cty.Value, error) {
+	return ARNBuildFunc.Call([]cty.Value{partition, service, region, accountID, resource})
+}
+
+// TrimIAMRolePath returns the ARN of the IAM role without its path.
+func TrimIAMRolePath(a cty.Value) (cty.Value, error) {
+	return TrimIAMRolePathFunc.Call([]cty.Value{a})
+}
@@ -0,0 +1,152 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// The functions in this file are the ones provided by the AzureRM provider, and
+// are called with the provider::azurerm:: prefix.
+
+var azureResourceIDType = cty.Object(map[string]cty.Type{
+	"full_resource_type":  cty.String,
+	"parent_resources":    cty.Map(cty.String),
+	"resource_group_name": cty.String,
+	"resource_name":       cty.String,
+	"resource_provider":   cty.String,
+	"resource_scope":      cty.String,
+	"resource_type":       cty.String,
+	"subscription_id":     cty.String,
+})
+
+// azureResourceIDKeys are the static segments of Azure resource ids, keyed by
+// their lower case name, in the casing that the AzureRM provider uses.
+var azureResourceIDKeys = map[string]string{
+	"subscriptions":  "subscriptions",
+	"resourcegroups": "resourceGroups",
+	"providers":      "providers",
+}
+
+// ParseResourceIDFunc constructs a function that parses an Azure resource id
+// into its parts.
+var ParseResourceIDFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "id",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(azureResourceIDType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		id := args[0].AsString()
+
+		segments := strings.Split(strings.Trim(id, "/"), "/")
+
+		// the resource is the one of the last providers segment, anything
+		// before that is the scope of an extension resource or the
+		// subscription and resource group of the resource.
+		providers := -1
+		for i := len(segments) - 1; i >= 0; i-- {
+			if strings.EqualFold(segments[i], "providers") {
+				providers = i
+				break
+			}
+		}
+
+		if providers < 0 || providers+2 >= len(segments) || (len(segments)-providers)%2 != 0 {
+			return cty.UnknownVal(retType), function.NewArgError(0, fmt.Errorf("%q is not a valid Azure resource id", id))
+		}
+		types := segments[providers+2:]
+
+		scope := segments[:providers]
+		var subscriptionID, resourceGroupName, resourceScope string
+		for i := 0; i+1 < len(scope); i += 2 {
+			switch strings.ToLower(scope[i]) {
+			case "subscriptions":
+				subscriptionID = scope[i+1]
+			case "resourcegroups":
+				resourceGroupName = scope[i+1]
+			case "providers":
+				resourceScope = "/" + strings.Join(scope, "/")
+			}
+		}
+
+		namespace := segments[providers+1]
+		fullType := []string{namespace}
+		parents := make(map[string]cty.Value)
+		for i := 0; i < len(types); i += 2 {
+			fullType = append(fullType, types[i])
+			if i+2 < len(types) {
+				parents[types[i]] = cty.StringVal(types[i+1])
+			}
+		}
+
+		parentResources := cty.MapValEmpty(cty.String)
+		if len(parents) > 0 {
+			parentResources = cty.MapVal(parents)
+		}
+
+		return cty.ObjectVal(map[string]cty.Value{
+			"full_resource_type":  cty.StringVal(strings.Join(fullType, "/")),
+			"parent_resources":    parentResources,
+			"resource_group_name": cty.StringVal(resourceGroupName),
+			"resource_name":       cty.StringVal(types[len(types)-1]),
+			"resource_provider":   cty.StringVal(namespace),
+			"resource_scope":      cty.StringVal(resourceScope),
+			"resource_type":       cty.StringVal(types[len(types)-2]),
+			"subscription_id":     cty.StringVal(subscriptionID),
+		}), nil
+	},
+})
+
+// NormaliseResourceIDFunc constructs a function that normalises the casing of
+// the static segments of an Azure resource id, e.g. resourcegroups becomes
+// resourceGroups. The AzureRM provider also normalises the casing of the
+// resource types, which we don't do since that needs the id formats of every
+// resource type.
+var NormaliseResourceIDFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "id",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		id := args[0].AsString()
+		if !strings.HasPrefix(id, "/") {
+			return cty.UnknownVal(retType), function.NewArgError(0, fmt.Errorf("%q is not a valid Azure resource id", id))
+		}
+
+		segments := strings.Split(strings.Trim(id, "/"), "/")
+		for i := 0; i < len(segments); i += 2 {
+			key, ok := azureResourceIDKeys[strings.ToLower(segments[i])]
+			if !ok {
+				continue
+			}
+
+			segments[i] = key
+			if key == "providers" {
+				// the provider namespace isn't followed by a value so the
+				// next key is the segment after it.
+				i--
+			}
+		}
+
+		return cty.StringVal("/" + strings.Join(segments, "/")), nil
+	},
+})
+
+// ParseResourceID returns the parts of the Azure resource id.
+func ParseResourceID(id cty.Value) (cty.Value, error) {
+	return ParseResourceIDFunc.Call([]cty.Value{id})
+}
+
+// NormaliseResourceID returns the Azure resource id with the casing of its
+// static segments normalised.
+func NormaliseResourceID(id cty.Value) (cty.Value, error) {
+	return NormaliseResourceIDFunc.Call([]cty.Value{id})
+}
@@ -0,0 +1,79 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestParseResourceID(t *testing.T) {
+	tests := []struct {
+		ID   cty.Value
+		Want map[string]cty.Value
+		Err  bool
+	}{
+		{
+			cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Storage/storageAccounts/account1"),
+			map[string]cty.Value{
+				"full_resource_type":  cty.StringVal("Microsoft.Storage/storageAccounts"),
+				"parent_resources":    cty.MapValEmpty(cty.String),
+				"resource_group_name": cty.StringVal("resGroup1"),
+				"resource_name":       cty.StringVal("account1"),
+				"resource_provider":   cty.StringVal("Microsoft.Storage"),
+				"resource_scope":      cty.StringVal(""),
+				"resource_type":       cty.StringVal("storageAccounts"),
+				"subscription_id":     cty.StringVal("00000000-0000-0000-0000-000000000000"),
+			},
+			false,
+		},
+		{ // extension resources are scoped to another resource
+			cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Compute/virtualMachines/vm1/providers/Microsoft.Authorization/locks/lock1"),
+			map[string]cty.Value{
+				"full_resource_type":  cty.StringVal("Microsoft.Authorization/locks"),
+				"parent_resources":    cty.MapValEmpty(cty.String),
+				"resource_group_name": cty.StringVal("resGroup1"),
+				"resource_name":       cty.StringVal("lock1"),
+				"resource_provider":   cty.StringVal("Microsoft.Authorization"),
+				"resource_scope":      cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Compute/virtualMachines/vm1"),
+				"resource_type":       cty.StringVal("locks"),
+				"subscription_id":     cty.StringVal("00000000-0000-0000-0000-000000000000"),
+			},
+			false,
+		},
+		{ // resource groups aren't provider resources
+			cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1"),
+			nil,
+			true,
+		},
+		{ // the resource doesn't have a name
+			cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Storage/storageAccounts"),
+			nil,
+			true,
+		},
+		{
+			cty.StringVal("/subscriptions/00000000-0000-0000-0000-000000000000/providers"),
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("parse_resource_id(%#v)", test.ID), func(t *testing.T) {
+			got, err := ParseResourceID(test.ID)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if want := cty.ObjectVal(test.Want); !got.RawEquals(want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
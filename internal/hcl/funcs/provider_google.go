@@ -0,0 +1,107 @@
+package funcs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// The functions in this file are the ones provided by the Google provider, and
+// are called with the provider::google:: prefix. The ids they take can be
+// resource ids, e.g. projects/my-project/zones/us-central1-c/instances/my-vm,
+// self links or full resource names.
+
+var (
+	// ProjectFromIDFunc constructs a function that returns the project of a
+	// resource id.
+	ProjectFromIDFunc = makeGoogleIDPartFunc(regexp.MustCompile(`projects/([^/]+)/`), "projects/{project}/")
+	// RegionFromIDFunc constructs a function that returns the region of a
+	// resource id.
+	RegionFromIDFunc = makeGoogleIDPartFunc(regexp.MustCompile(`regions/([^/]+)/`), "regions/{region}/")
+	// ZoneFromIDFunc constructs a function that returns the zone of a resource
+	// id.
+	ZoneFromIDFunc = makeGoogleIDPartFunc(regexp.MustCompile(`zones/([^/]+)/`), "zones/{zone}/")
+	// LocationFromIDFunc constructs a function that returns the location of a
+	// resource id.
+	LocationFromIDFunc = makeGoogleIDPartFunc(regexp.MustCompile(`locations/([^/]+)/`), "locations/{location}/")
+	// NameFromIDFunc constructs a function that returns the short name of a
+	// resource id, which is its last part.
+	NameFromIDFunc = makeGoogleIDPartFunc(regexp.MustCompile(`/([^/]+)$`), "/{name}")
+)
+
+func makeGoogleIDPartFunc(re *regexp.Regexp, pattern string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "id",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			id := args[0].AsString()
+
+			matches := re.FindStringSubmatch(id)
+			if matches == nil {
+				return cty.UnknownVal(retType), function.NewArgError(0, fmt.Errorf("the id %q doesn't contain the expected pattern %q", id, pattern))
+			}
+
+			return cty.StringVal(matches[1]), nil
+		},
+	})
+}
+
+// RegionFromZoneFunc constructs a function that returns the region of a zone,
+// e.g. us-central1 for us-central1-a.
+var RegionFromZoneFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "zone",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		zone := args[0].AsString()
+
+		i := strings.LastIndex(zone, "-")
+		if i <= 0 || i == len(zone)-1 {
+			return cty.UnknownVal(retType), function.NewArgError(0, fmt.Errorf("%q is not a valid zone", zone))
+		}
+
+		return cty.StringVal(zone[:i]), nil
+	},
+})
+
+// ProjectFromID returns the project of the resource id.
+func ProjectFromID(id cty.Value) (cty.Value, error) {
+	return ProjectFromIDFunc.Call([]cty.Value{id})
+}
+
+// RegionFromID returns the region of the resource id.
+func RegionFromID(id cty.Value) (cty.Value, error) {
+	return RegionFromIDFunc.Call([]cty.Value{id})
+}
+
+// ZoneFromID returns the zone of the resource id.
+func ZoneFromID(id cty.Value) (cty.Value, error) {
+	return ZoneFromIDFunc.Call([]cty.Value{id})
+}
+
+// LocationFromID returns the location of the resource id.
+func LocationFromID(id cty.Value) (cty.Value, error) {
+	return LocationFromIDFunc.Call([]cty.Value{id})
+}
+
+// NameFromID returns the short name of the resource id.
+func NameFromID(id cty.Value) (cty.Value, error) {
+	return NameFromIDFunc.Call([]cty.Value{id})
+}
+
+// RegionFromZone returns the region of the zone.
+func RegionFromZone(zone cty.Value) (cty.Value, error) {
+	return RegionFromZoneFunc.Call([]cty.Value{zone})
+}
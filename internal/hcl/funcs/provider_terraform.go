@@ -0,0 +1,136 @@
+package funcs
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// The functions in this file are the ones provided by Terraform's built-in
+// terraform provider, and are called with the provider::terraform:: prefix.
+
+// EncodeTfvarsFunc constructs a function that takes an object and returns a
+// string containing the attributes of the object in the tfvars file syntax.
+var EncodeTfvarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		ty := v.Type()
+
+		if v.IsNull() {
+			return cty.NilVal, function.NewArgErrorf(0, "cannot encode a null value in tfvars syntax")
+		}
+		if !v.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+		if !(ty.IsObjectType() || ty.IsMapType()) {
+			return cty.NilVal, function.NewArgErrorf(0, "invalid value to encode: must be an object whose attribute names will become the encoded variable names")
+		}
+
+		var names []string
+		for it := v.ElementIterator(); it.Next(); {
+			k, _ := it.Element()
+			name := k.AsString()
+			if !hclsyntax.ValidIdentifier(name) {
+				return cty.NilVal, function.NewArgErrorf(0, "invalid variable name %q: must be a valid identifier, per Terraform's rules for input variable declarations", name)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		f := hclwrite.NewEmptyFile()
+		for _, name := range names {
+			f.Body().SetAttributeValue(name, v.GetAttr(name))
+		}
+
+		return cty.StringVal(string(f.Bytes())), nil
+	},
+})
+
+// DecodeTfvarsFunc constructs a function that takes a string in the tfvars file
+// syntax and returns an object with an attribute for each of the variables.
+var DecodeTfvarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		src := []byte(args[0].AsString())
+
+		f, diags := hclsyntax.ParseConfig(src, "<decode_tfvars argument>", hcl.InitialPos)
+		if diags.HasErrors() {
+			return cty.NilVal, function.NewArgErrorf(0, "invalid tfvars syntax: %s", diags.Error())
+		}
+
+		attrs, diags := f.Body.JustAttributes()
+		if diags.HasErrors() {
+			return cty.NilVal, function.NewArgErrorf(0, "invalid tfvars content: %s", diags.Error())
+		}
+
+		vals := make(map[string]cty.Value, len(attrs))
+		for name, attr := range attrs {
+			// tfvars files can only contain constant values, so there is no
+			// context to evaluate the expressions in.
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return cty.NilVal, function.NewArgErrorf(0, "invalid expression for variable %q: %s", name, diags.Error())
+			}
+			vals[name] = val
+		}
+
+		return cty.ObjectVal(vals), nil
+	},
+})
+
+// EncodeExprFunc constructs a function that takes a value and returns a string
+// containing the HCL expression that would produce the value.
+var EncodeExprFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+			AllowUnknown:     true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		if !v.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		return cty.StringVal(string(hclwrite.TokensForValue(v).Bytes())), nil
+	},
+})
+
+// EncodeTfvars returns the attributes of the object value in tfvars syntax.
+func EncodeTfvars(v cty.Value) (cty.Value, error) {
+	return EncodeTfvarsFunc.Call([]cty.Value{v})
+}
+
+// DecodeTfvars returns an object with the variables set by the tfvars source.
+func DecodeTfvars(src cty.Value) (cty.Value, error) {
+	return DecodeTfvarsFunc.Call([]cty.Value{src})
+}
+
+// EncodeExpr returns the HCL expression that would produce the value.
+func EncodeExpr(v cty.Value) (cty.Value, error) {
+	return EncodeExprFunc.Call([]cty.Value{v})
+}
@@ -176,3 +176,84 @@ func TestNonsensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  bool
+	}{
+		{
+			cty.StringVal("a").Mark(MarkedSensitive),
+			true,
+		},
+		{
+			cty.UnknownVal(cty.String).Mark(MarkedSensitive),
+			true,
+		},
+		{
+			cty.StringVal("a"),
+			false,
+		},
+		{
+			// only the top-level value is checked, as in Terraform
+			cty.ListVal([]cty.Value{cty.StringVal("a").Mark(MarkedSensitive)}),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("issensitive(%#v)", test.Input), func(t *testing.T) {
+			got, err := IsSensitive(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got.True() != test.Want {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestEphemeralAsNull(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+	}{
+		{
+			cty.StringVal("a"),
+			cty.StringVal("a"),
+		},
+		{
+			cty.StringVal("a").Mark(MarkedEphemeral),
+			cty.NullVal(cty.String),
+		},
+		{
+			cty.StringVal("a").WithMarks(cty.NewValueMarks(MarkedEphemeral, MarkedSensitive)),
+			cty.NullVal(cty.String).Mark(MarkedSensitive),
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.NumberIntVal(1).Mark(MarkedEphemeral),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.NullVal(cty.Number),
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("ephemeralasnull(%#v)", test.Input), func(t *testing.T) {
+			got, err := EphemeralAsNull(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// MakeTemplateStringFunc constructs a function that takes a string and an
+// arbitrary object of named values and attempts to render the string as a
+// template using HCL template syntax. It works in the same way as
+// MakeTemplateFileFunc, except that the template is given directly rather
+// than read from a file.
+func MakeTemplateStringFunc(funcsCb func() map[string]function.Function) function.Function {
+	params := []function.Parameter{
+		{
+			Name: "template",
+			Type: cty.String,
+		},
+		{
+			Name: "vars",
+			Type: cty.DynamicPseudoType,
+		},
+	}
+
+	loadTmpl := func(tmpl string) (hcl.Expression, error) {
+		expr, diags := hclsyntax.ParseTemplate([]byte(tmpl), "<templatestring argument>", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		return expr, nil
+	}
+
+	return function.New(&function.Spec{
+		Params: params,
+		Type: func(args []cty.Value) (cty.Type, error) {
+			if !(args[0].IsKnown() && args[1].IsKnown()) {
+				return cty.DynamicPseudoType, nil
+			}
+
+			expr, err := loadTmpl(args[0].AsString())
+			if err != nil {
+				return cty.DynamicPseudoType, function.NewArgError(0, err)
+			}
+
+			val, err := renderTemplate("templatestring", expr, args[1], funcsCb())
+			return val.Type(), err
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			expr, err := loadTmpl(args[0].AsString())
+			if err != nil {
+				return cty.DynamicVal, function.NewArgError(0, err)
+			}
+
+			return renderTemplate("templatestring", expr, args[1], funcsCb())
+		},
+	})
+}
+
+// renderTemplate evaluates the template expression using varsVal as the
+// variables and the given functions, except for the template functions which
+// are stubbed out to prevent a template including itself indefinitely. caller
+// is the name of the template function that's rendering the template.
+func renderTemplate(caller string, expr hcl.Expression, varsVal cty.Value, givenFuncs map[string]function.Function) (cty.Value, error) {
+	if varsTy := varsVal.Type(); !(varsTy.IsMapType() || varsTy.IsObjectType()) {
+		return cty.DynamicVal, function.NewArgErrorf(1, "invalid vars value: must be a map") // or an object, but we don't strongly distinguish these most of the time
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: varsVal.AsValueMap(),
+	}
+
+	// We require all of the variables to be valid HCL identifiers, because
+	// otherwise there would be no way to refer to them in the template
+	// anyway. Rejecting this here gives better feedback to the user
+	// than a syntax error somewhere in the template itself.
+	for n := range ctx.Variables {
+		if !hclsyntax.ValidIdentifier(n) {
+			// This error message intentionally doesn't describe _all_ of
+			// the different permutations that are technically valid as an
+			// HCL identifier, but rather focuses on what we might
+			// consider to be an "idiomatic" variable name.
+			return cty.DynamicVal, function.NewArgErrorf(1, "invalid template variable name %q: must start with a letter, followed by zero or more letters, digits, and underscores", n)
+		}
+	}
+
+	// We'll pre-check references in the template here so we can give a
+	// more specialized error message than HCL would by default, so it's
+	// clearer that this problem is coming from a template function call.
+	for _, traversal := range expr.Variables() {
+		root := traversal.RootName()
+		if _, ok := ctx.Variables[root]; !ok {
+			return cty.DynamicVal, function.NewArgErrorf(1, "vars map does not contain key %q, referenced at %s", root, traversal[0].SourceRange())
+		}
+	}
+
+	funcs := make(map[string]function.Function, len(givenFuncs))
+	for name, fn := range givenFuncs {
+		if n := strings.TrimPrefix(name, "core::"); n == "templatefile" || n == "templatestring" {
+			// We stub these out to prevent recursive calls.
+			name := name
+			funcs[name] = function.New(&function.Spec{
+				VarParam: &function.Parameter{
+					Name: "args",
+					Type: cty.DynamicPseudoType,
+				},
+				Type: func(args []cty.Value) (cty.Type, error) {
+					return cty.NilType, fmt.Errorf("cannot recursively call %s from inside %s call", name, caller)
+				},
+			})
+			continue
+		}
+		funcs[name] = fn
+	}
+	ctx.Functions = funcs
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	return val, nil
+}
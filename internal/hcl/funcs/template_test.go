@@ -0,0 +1,84 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestTemplateString(t *testing.T) {
+	tests := []struct {
+		Template cty.Value
+		Vars     cty.Value
+		Want     cty.Value
+		Err      string
+	}{
+		{
+			cty.StringVal("Hello, ${name}!"),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("Jodie"),
+			}),
+			cty.StringVal("Hello, Jodie!"),
+			``,
+		},
+		{
+			cty.StringVal("${join(\", \", names)}"),
+			cty.ObjectVal(map[string]cty.Value{
+				"names": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			}),
+			cty.StringVal("a, b"),
+			``,
+		},
+		{
+			cty.StringVal("${val}"),
+			cty.ObjectVal(map[string]cty.Value{
+				"val": cty.True,
+			}),
+			cty.True, // since this template contains only an interpolation, its true value shines through
+			``,
+		},
+		{
+			cty.StringVal("Hello, ${name}!"),
+			cty.EmptyObjectVal,
+			cty.NilVal,
+			`vars map does not contain key "name", referenced at <templatestring argument>:1,10-14`,
+		},
+		{
+			cty.StringVal("${templatestring(\"a\", {})}"),
+			cty.EmptyObjectVal,
+			cty.NilVal,
+			`<templatestring argument>:1,3-18: Error in function call; Call to function "templatestring" failed: cannot recursively call templatestring from inside templatestring call.`,
+		},
+	}
+
+	var fns map[string]function.Function
+	fns = map[string]function.Function{
+		"join":           stdlib.JoinFunc,
+		"templatestring": MakeTemplateStringFunc(func() map[string]function.Function { return fns }),
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("TemplateString(%#v, %#v)", test.Template, test.Vars), func(t *testing.T) {
+			got, err := fns["templatestring"].Call([]cty.Value{test.Template, test.Vars})
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package hcl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+var functionsTestdataDir = filepath.Join("testdata", "functions")
+
+// functionsWithoutConformance are the Terraform functions that don't have a
+// conformance case, since their results can't be compared with Terraform's.
+var functionsWithoutConformance = map[string]string{
+	"base64gzip":    "the compressed output depends on the Go version",
+	"bcrypt":        "the output is random",
+	"plantimestamp": "we return a static timestamp",
+	"rsadecrypt":    "it needs a private key",
+	"timestamp":     "we return a static timestamp",
+	"type":          "it's only available in terraform console",
+	"uuid":          "the output is random",
+}
+
+// functionsNotInTerraform are the functions that we support that Terraform
+// doesn't, or no longer does.
+var functionsNotInTerraform = map[string]string{
+	"infracostlog":   "it's used for debugging",
+	"infracostprint": "it's used for debugging",
+	"list":           "it was removed in Terraform 0.15 but configs may still use it",
+	"map":            "it was removed in Terraform 0.15 but configs may still use it",
+}
+
+type functionConformanceCase struct {
+	name    string
+	expr    hcl.Expression
+	want    cty.Value
+	wantErr bool
+}
+
+func loadFunctionConformanceCases(t *testing.T) ([]functionConformanceCase, cty.Value) {
+	t.Helper()
+
+	path := filepath.Join(functionsTestdataDir, "conformance.hcl")
+	src, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	f, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	content, diags := f.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "locals"},
+			{Type: "case", LabelNames: []string{"function"}},
+		},
+	})
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	locals := make(map[string]cty.Value)
+	var cases []functionConformanceCase
+	for _, block := range content.Blocks {
+		if block.Type == "locals" {
+			attrs, diags := block.Body.JustAttributes()
+			require.False(t, diags.HasErrors(), diags.Error())
+
+			for name, attr := range attrs {
+				val, diags := attr.Expr.Value(nil)
+				require.False(t, diags.HasErrors(), diags.Error())
+				locals[name] = val
+			}
+
+			continue
+		}
+
+		body, diags := block.Body.Content(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{
+				{Name: "expr", Required: true},
+				{Name: "want"},
+				{Name: "error"},
+			},
+		})
+		require.False(t, diags.HasErrors(), diags.Error())
+
+		c := functionConformanceCase{
+			name: block.Labels[0],
+			expr: body.Attributes["expr"].Expr,
+		}
+
+		if attr, ok := body.Attributes["want"]; ok {
+			c.want, diags = attr.Expr.Value(nil)
+			require.False(t, diags.HasErrors(), diags.Error())
+		}
+
+		if attr, ok := body.Attributes["error"]; ok {
+			val, diags := attr.Expr.Value(nil)
+			require.False(t, diags.HasErrors(), diags.Error())
+			c.wantErr = val.True()
+		}
+
+		cases = append(cases, c)
+	}
+
+	return cases, cty.ObjectVal(locals)
+}
+
+func loadTerraformFunctionNames(t *testing.T) []string {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(functionsTestdataDir, "terraform_functions.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		names = append(names, line)
+	}
+	require.NoError(t, scanner.Err())
+
+	return names
+}
+
+// functionResultJSON returns the value as JSON so that values which Terraform
+// shows in the same way, e.g. sets and tuples, can be compared.
+func functionResultJSON(t *testing.T, val cty.Value) string {
+	t.Helper()
+
+	val, _ = val.UnmarkDeep()
+	b, err := json.Marshal(ctyjson.SimpleJSONValue{Value: val})
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func Test_FunctionConformance(t *testing.T) {
+	cases, locals := loadFunctionConformanceCases(t)
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{"local": locals},
+		Functions: ExpFunctions(functionsTestdataDir, newDiscardLogger()),
+	}
+
+	for i, c := range cases {
+		c := c
+		t.Run(fmt.Sprintf("%s/%d", c.name, i), func(t *testing.T) {
+			got, diags := c.expr.Value(ctx)
+			if c.wantErr {
+				assert.True(t, diags.HasErrors(), "expected an error, got %s", got.GoString())
+				return
+			}
+
+			require.False(t, diags.HasErrors(), diags.Error())
+			assert.Equal(t, functionResultJSON(t, c.want), functionResultJSON(t, got))
+		})
+	}
+}
+
+func Test_FunctionsMatchTerraform(t *testing.T) {
+	terraformNames := loadTerraformFunctionNames(t)
+	fns := ExpFunctions(functionsTestdataDir, newDiscardLogger())
+
+	cases, _ := loadFunctionConformanceCases(t)
+	covered := make(map[string]bool)
+	for _, c := range cases {
+		covered[c.name] = true
+	}
+
+	inTerraform := make(map[string]bool)
+	for _, name := range terraformNames {
+		inTerraform[name] = true
+
+		if name == "type" {
+			continue
+		}
+
+		assert.Contains(t, fns, name, "Terraform function %s isn't supported", name)
+		if _, ok := functionsWithoutConformance[name]; !ok {
+			assert.True(t, covered[name], "function %s doesn't have a conformance case", name)
+		}
+
+		if !strings.Contains(name, "::") {
+			assert.Contains(t, fns, "core::"+name, "Terraform function %s can't be called with the core:: prefix", name)
+		}
+	}
+
+	for name := range fns {
+		if strings.HasPrefix(name, "core::") {
+			continue
+		}
+
+		if _, ok := functionsNotInTerraform[name]; !ok {
+			assert.True(t, inTerraform[name], "function %s isn't in the list of Terraform functions", name)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/heimdalr/dag"
 	"github.com/rs/zerolog"
@@ -56,14 +57,27 @@ type Graph struct {
 	dag           *dag.DAG
 	logger        zerolog.Logger
 	rootVertex    Vertex
-	vertexMutex   *sync.Mutex
 	moduleConfigs *ModuleConfigs
+	// parallelism is the maximum number of vertices that are visited at the
+	// same time when the graph is walked.
+	parallelism int
+
+	// results holds the outcome of visiting each vertex of the graph, keyed
+	// by the vertex ID. It's used to debug the evaluation of the graph.
+	results   map[string]vertexResult
+	resultsMu sync.Mutex
+}
+
+// vertexResult is the outcome of visiting a vertex when walking the graph.
+type vertexResult struct {
+	duration time.Duration
+	err      error
 }
 
 type Vertex interface {
 	ID() string
 	ModuleAddress() string
-	Visit(mutex *sync.Mutex) error
+	Visit() error
 	References() []VertexReference
 }
 
@@ -73,15 +87,20 @@ type VertexReference struct {
 	Key           string
 }
 
-func NewGraphWithRoot(logger zerolog.Logger, vertexMutex *sync.Mutex) (*Graph, error) {
-	if vertexMutex == nil {
-		vertexMutex = &sync.Mutex{}
+// NewGraphWithRoot returns a Graph with a root vertex that visits at most
+// parallelism vertices at the same time. If parallelism is less than 1 the
+// vertices are visited one at a time.
+func NewGraphWithRoot(logger zerolog.Logger, parallelism int) (*Graph, error) {
+	if parallelism < 1 {
+		parallelism = 1
 	}
+
 	g := &Graph{
 		dag:           dag.NewDAG(),
 		logger:        logger,
 		moduleConfigs: NewModuleConfigs(),
-		vertexMutex:   vertexMutex,
+		parallelism:   parallelism,
+		results:       make(map[string]vertexResult),
 	}
 
 	g.rootVertex = &VertexRoot{}
@@ -158,6 +177,17 @@ func (g *Graph) Populate(evaluator *Evaluator) error {
 				moduleConfigs: g.moduleConfigs,
 				block:         block,
 			})
+		case "ephemeral":
+			vertexes = append(vertexes, &VertexEphemeral{
+				logger:        g.logger,
+				moduleConfigs: g.moduleConfigs,
+				block:         block,
+			})
+		case "check":
+			vertexes = append(vertexes, &VertexCheck{
+				logger: g.logger,
+				block:  block,
+			})
 		}
 	}
 
@@ -217,9 +247,9 @@ func (g *Graph) Populate(evaluator *Evaluator) error {
 			parts := strings.Split(ref.Key, ".")
 			idx := len(parts)
 
-			// data references should always have a length of 3
+			// data and ephemeral references should always have a length of 3
 			// provider references might have a length of 3 (if using an alias) or 2 (if not).
-			if (strings.HasPrefix(ref.Key, "data.") || strings.HasPrefix(ref.Key, "provider.")) && len(parts) >= 3 {
+			if (strings.HasPrefix(ref.Key, "data.") || strings.HasPrefix(ref.Key, "ephemeral.") || strings.HasPrefix(ref.Key, "provider.")) && len(parts) >= 3 {
 				// Source ID is the first 3 parts or less if the length of parts is less than 3
 				idx = 3
 			} else if len(parts) >= 2 {
@@ -332,6 +362,7 @@ func (g *Graph) Populate(evaluator *Evaluator) error {
 
 	evaluator.ctx.Set(cty.ObjectVal(map[string]cty.Value{}), "var")
 	evaluator.ctx.Set(cty.ObjectVal(map[string]cty.Value{}), "data")
+	evaluator.ctx.Set(cty.ObjectVal(map[string]cty.Value{}), "ephemeral")
 	evaluator.ctx.Set(cty.ObjectVal(map[string]cty.Value{}), "local")
 	evaluator.ctx.Set(cty.ObjectVal(map[string]cty.Value{}), "output")
 
@@ -340,6 +371,7 @@ func (g *Graph) Populate(evaluator *Evaluator) error {
 	// added when we walk the graph since locals are evaluated per attribute
 	// and not per block, so we need to make sure this is done here.
 	evaluator.AddFilteredBlocks(evaluator.module.Blocks.OfType("locals")...)
+	evaluator.addRefactoringBlocks()
 
 	return nil
 }
@@ -348,13 +380,25 @@ func (g *Graph) AsJSON() ([]byte, error) {
 	return g.dag.MarshalJSON()
 }
 
+// Walk visits the vertices of the graph once all the vertices they depend on
+// have been visited. Vertices that don't depend on each other, e.g. the
+// vertices of sibling modules, are visited concurrently, up to the parallelism
+// of the graph. Vertices lock the evaluators of the module instances they
+// evaluate, see lockModuleInstances, so the result of the walk is the same
+// whatever order the vertices are visited in.
 func (g *Graph) Walk() {
-	v := NewGraphVisitor(g.logger, g.vertexMutex)
+	v := NewGraphVisitor(g.logger)
+	sem := make(chan struct{}, g.parallelism)
 
 	flowCallback := func(d *dag.DAG, id string, parentResults []dag.FlowResult) (interface{}, error) {
 		vertex, _ := d.GetVertex(id)
 
-		v.Visit(id, vertex)
+		sem <- struct{}{}
+		defer func() { <-sem }()
+
+		start := time.Now()
+		err := v.Visit(id, vertex)
+		g.recordResult(id, vertexResult{duration: time.Since(start), err: err})
 
 		return vertex, nil
 	}
@@ -362,6 +406,21 @@ func (g *Graph) Walk() {
 	_, _ = g.dag.DescendantsFlow(g.rootVertex.ID(), nil, flowCallback)
 }
 
+func (g *Graph) recordResult(id string, result vertexResult) {
+	g.resultsMu.Lock()
+	defer g.resultsMu.Unlock()
+
+	g.results[id] = result
+}
+
+func (g *Graph) result(id string) (vertexResult, bool) {
+	g.resultsMu.Lock()
+	defer g.resultsMu.Unlock()
+
+	result, ok := g.results[id]
+	return result, ok
+}
+
 func (g *Graph) Run(evaluator *Evaluator) (*Module, error) {
 	err := g.Populate(evaluator)
 	if err != nil {
@@ -371,6 +430,7 @@ func (g *Graph) Run(evaluator *Evaluator) (*Module, error) {
 	g.ReduceTransitively()
 	g.Walk()
 
+	evaluator.sortFilteredBlocks()
 	evaluator.module.Blocks = evaluator.filteredBlocks
 	evaluator.module = *evaluator.collectModules()
 
@@ -378,25 +438,44 @@ func (g *Graph) Run(evaluator *Evaluator) (*Module, error) {
 }
 
 type GraphVisitor struct {
-	logger      zerolog.Logger
-	vertexMutex *sync.Mutex
+	logger zerolog.Logger
 }
 
-func NewGraphVisitor(logger zerolog.Logger, vertexMutex *sync.Mutex) *GraphVisitor {
+func NewGraphVisitor(logger zerolog.Logger) *GraphVisitor {
 	return &GraphVisitor{
-		logger:      logger,
-		vertexMutex: vertexMutex,
+		logger: logger,
 	}
 }
 
-func (v *GraphVisitor) Visit(id string, vertex interface{}) {
+// Visit evaluates the vertex. Errors are logged and returned so the outcome
+// of the vertex can be recorded, but they don't stop the graph being walked.
+func (v *GraphVisitor) Visit(id string, vertex interface{}) error {
 	v.logger.Debug().Msgf("visiting vertex %q", id)
 
 	vert := vertex.(Vertex)
-	err := vert.Visit(v.vertexMutex)
+	err := vert.Visit()
 	if err != nil {
 		v.logger.Debug().Err(err).Msgf("ignoring vertex %q because an error was encountered", id)
 	}
+
+	return err
+}
+
+// lockModuleInstances locks the evaluators of the module instances so that a
+// vertex can evaluate its blocks while vertices in other modules are being
+// visited. It returns a func that unlocks them. A vertex that also needs the
+// evaluator of the parent module, e.g. to set the value of an output, must only
+// lock it while the module instances are locked, never the other way around.
+func lockModuleInstances(instances []ModuleConfig) func() {
+	for _, instance := range instances {
+		instance.evaluator.mu.Lock()
+	}
+
+	return func() {
+		for i := len(instances) - 1; i >= 0; i-- {
+			instances[i].evaluator.mu.Unlock()
+		}
+	}
 }
 
 func (g *Graph) loadAllBlocks(evaluator *Evaluator) ([]*Block, error) {
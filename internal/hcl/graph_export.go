@@ -0,0 +1,409 @@
+package hcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VertexStatus is the outcome of evaluating a vertex of the Graph.
+type VertexStatus string
+
+const (
+	// VertexStatusEvaluated is used for vertices that were evaluated without
+	// any errors and with known values.
+	VertexStatusEvaluated VertexStatus = "evaluated"
+	// VertexStatusMocked is used for vertices that were evaluated but whose
+	// values were mocked or are unknown, e.g. because they reference a
+	// missing variable or a data source that can't be read.
+	VertexStatusMocked VertexStatus = "mocked"
+	// VertexStatusErrored is used for vertices that returned an error when
+	// they were evaluated.
+	VertexStatusErrored VertexStatus = "errored"
+	// VertexStatusSkipped is used for vertices that weren't visited when the
+	// graph was walked.
+	VertexStatusSkipped VertexStatus = "skipped"
+)
+
+// maxExportLabelValueLength is the maximum length of a value shown in the
+// vertex labels of the DOT and Mermaid outputs. The full value is in the JSON
+// output.
+const maxExportLabelValueLength = 60
+
+// GraphExport is a snapshot of a Graph after it has been walked, with the
+// outcome of evaluating each vertex. It's used to debug the evaluation of a
+// project.
+type GraphExport struct {
+	Vertices []ExportedVertex `json:"vertices"`
+	Edges    []ExportedEdge   `json:"edges"`
+}
+
+// ExportedVertex is a vertex of a GraphExport.
+type ExportedVertex struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Status   VertexStatus `json:"status"`
+	Duration string       `json:"duration,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	// Value is the value the vertex added to the evaluation context. If the
+	// vertex is in a module that has multiple instances, e.g. because it uses
+	// count or for_each, this is an object of the values keyed by the module
+	// instance name.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ExportedEdge is an edge of a GraphExport, pointing from a vertex to a vertex
+// that depends on it.
+type ExportedEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Export returns a GraphExport of the vertices and edges of the graph. It
+// should be called after the graph has been walked, otherwise all the vertices
+// are skipped.
+func (g *Graph) Export() *GraphExport {
+	vertices := g.dag.GetVertices()
+
+	ids := make([]string, 0, len(vertices))
+	for id := range vertices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	export := &GraphExport{
+		Vertices: make([]ExportedVertex, 0, len(ids)),
+		Edges:    []ExportedEdge{},
+	}
+
+	for _, id := range ids {
+		vertex, ok := vertices[id].(Vertex)
+		if !ok {
+			continue
+		}
+
+		export.Vertices = append(export.Vertices, g.exportVertex(id, vertex))
+
+		children, err := g.dag.GetChildren(id)
+		if err != nil {
+			continue
+		}
+
+		childIDs := make([]string, 0, len(children))
+		for childID := range children {
+			childIDs = append(childIDs, childID)
+		}
+		sort.Strings(childIDs)
+
+		for _, childID := range childIDs {
+			export.Edges = append(export.Edges, ExportedEdge{From: id, To: childID})
+		}
+	}
+
+	return export
+}
+
+func (g *Graph) exportVertex(id string, vertex Vertex) ExportedVertex {
+	ev := ExportedVertex{
+		ID:     id,
+		Type:   vertexType(vertex),
+		Status: VertexStatusSkipped,
+	}
+
+	result, ok := g.result(id)
+	if !ok {
+		return ev
+	}
+
+	ev.Duration = result.duration.Round(time.Microsecond).String()
+	if result.err != nil {
+		ev.Status = VertexStatusErrored
+		ev.Error = result.err.Error()
+		return ev
+	}
+
+	ev.Status = VertexStatusEvaluated
+
+	path := vertexValuePath(vertex)
+	if len(path) == 0 {
+		return ev
+	}
+
+	instances := g.moduleConfigs.Get(vertex.ModuleAddress())
+	values := make(map[string]interface{}, len(instances))
+	var value interface{}
+	for _, instance := range instances {
+		val := lookupContextValue(instance.evaluator.ctx, path)
+		if val == cty.NilVal {
+			continue
+		}
+
+		val, _ = val.UnmarkDeep()
+		if isMockedValue(val) || !val.IsWhollyKnown() {
+			ev.Status = VertexStatusMocked
+		}
+
+		value = exportValue(val)
+		values[instance.name] = value
+	}
+
+	if len(values) == 1 {
+		ev.Value = value
+	} else if len(values) > 1 {
+		ev.Value = values
+	}
+
+	return ev
+}
+
+// vertexType returns the name of the type of block the vertex is for.
+func vertexType(vertex Vertex) string {
+	switch vertex.(type) {
+	case *VertexRoot:
+		return "root"
+	case *VertexLocal:
+		return "local"
+	case *VertexVariable:
+		return "variable"
+	case *VertexOutput:
+		return "output"
+	case *VertexModuleCall:
+		return "module_call"
+	case *VertexModuleExit:
+		return "module_exit"
+	case *VertexProvider:
+		return "provider"
+	case *VertexResource:
+		return "resource"
+	case *VertexData:
+		return "data"
+	case *VertexEphemeral:
+		return "ephemeral"
+	case *VertexCheck:
+		return "check"
+	}
+
+	return "unknown"
+}
+
+// vertexValuePath returns the path in the evaluation context of the module
+// instance that the vertex sets when it's visited. Vertices that don't set a
+// single value in the context return nil.
+func vertexValuePath(vertex Vertex) []string {
+	switch v := vertex.(type) {
+	case *VertexLocal:
+		return []string{"local", v.attr.Name()}
+	case *VertexVariable:
+		return []string{"var", v.block.Label()}
+	case *VertexOutput:
+		return []string{"output", v.block.Label()}
+	case *VertexResource:
+		return []string{v.block.TypeLabel(), v.block.NameLabel()}
+	case *VertexData:
+		return []string{"data", v.block.TypeLabel(), v.block.NameLabel()}
+	case *VertexEphemeral:
+		return []string{"ephemeral", v.block.TypeLabel(), v.block.NameLabel()}
+	}
+
+	return nil
+}
+
+// lookupContextValue returns the value at path in the context, or cty.NilVal
+// if there is no value at the path. Unlike Context.Get it is safe to call with
+// paths that go through null or unknown values.
+func lookupContextValue(ctx *Context, path []string) cty.Value {
+	val := ctx.Get(path[0])
+
+	for _, part := range path[1:] {
+		if val == cty.NilVal || val.IsNull() || !val.IsKnown() {
+			return cty.NilVal
+		}
+
+		ty := val.Type()
+		switch {
+		case ty.IsObjectType():
+			if !ty.HasAttribute(part) {
+				return cty.NilVal
+			}
+			val = val.GetAttr(part)
+		case ty.IsMapType():
+			key := cty.StringVal(part)
+			if !val.HasIndex(key).True() {
+				return cty.NilVal
+			}
+			val = val.Index(key)
+		default:
+			return cty.NilVal
+		}
+	}
+
+	return val
+}
+
+// exportValue converts the value to a Go value that can be marshalled to JSON.
+// Unknown values are shown as "(known after apply)" like in a Terraform plan.
+func exportValue(val cty.Value) interface{} {
+	if val == cty.NilVal || val.IsNull() {
+		return nil
+	}
+
+	if !val.IsKnown() {
+		return "(known after apply)"
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString()
+	case ty == cty.Number:
+		return json.Number(val.AsBigFloat().Text('f', -1))
+	case ty == cty.Bool:
+		return val.True()
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		l := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			l = append(l, exportValue(v))
+		}
+		return l
+	case ty.IsMapType() || ty.IsObjectType():
+		m := make(map[string]interface{}, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			m[k.AsString()] = exportValue(v)
+		}
+		return m
+	}
+
+	return nil
+}
+
+// JSON returns the graph as indented JSON.
+func (e *GraphExport) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// DOT returns the graph in the Graphviz DOT language. Vertices are colored by
+// their status and labelled with their timing and value.
+func (e *GraphExport) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph {\n")
+	b.WriteString("  rankdir = \"LR\"\n")
+	b.WriteString("  node [shape = \"box\", style = \"rounded,filled\"]\n")
+
+	for _, v := range e.Vertices {
+		b.WriteString(fmt.Sprintf("  %s [label = %s, fillcolor = %s]\n",
+			strconv.Quote(v.ID),
+			strconv.Quote(strings.Join(v.labelLines(), "\n")),
+			strconv.Quote(v.Status.color()),
+		))
+	}
+
+	for _, edge := range e.Edges {
+		b.WriteString(fmt.Sprintf("  %s -> %s\n", strconv.Quote(edge.From), strconv.Quote(edge.To)))
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Vertices are styled by
+// their status and labelled with their timing and value.
+func (e *GraphExport) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	nodeIDs := make(map[string]string, len(e.Vertices))
+	byStatus := map[VertexStatus][]string{}
+
+	for i, v := range e.Vertices {
+		nodeID := fmt.Sprintf("v%d", i)
+		nodeIDs[v.ID] = nodeID
+		byStatus[v.Status] = append(byStatus[v.Status], nodeID)
+
+		lines := v.labelLines()
+		for j, line := range lines {
+			lines[j] = escapeMermaid(line)
+		}
+
+		b.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", nodeID, strings.Join(lines, "<br/>")))
+	}
+
+	for _, edge := range e.Edges {
+		b.WriteString(fmt.Sprintf("  %s --> %s\n", nodeIDs[edge.From], nodeIDs[edge.To]))
+	}
+
+	for _, status := range []VertexStatus{VertexStatusEvaluated, VertexStatusMocked, VertexStatusErrored, VertexStatusSkipped} {
+		b.WriteString(fmt.Sprintf("  classDef %s fill:%s\n", status, status.color()))
+
+		if nodes := byStatus[status]; len(nodes) > 0 {
+			b.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(nodes, ","), status))
+		}
+	}
+
+	return b.String()
+}
+
+// labelLines returns the lines used to label the vertex in the DOT and Mermaid
+// outputs.
+func (v ExportedVertex) labelLines() []string {
+	status := string(v.Status)
+	if v.Duration != "" {
+		status = fmt.Sprintf("%s, took %s", status, v.Duration)
+	}
+
+	lines := []string{v.ID, status}
+
+	if v.Error != "" {
+		lines = append(lines, truncateLabel(v.Error))
+	}
+
+	if v.Value != nil {
+		b, err := json.Marshal(v.Value)
+		if err == nil {
+			lines = append(lines, truncateLabel("= "+string(b)))
+		}
+	}
+
+	return lines
+}
+
+func (s VertexStatus) color() string {
+	switch s {
+	case VertexStatusEvaluated:
+		return "#d4edda"
+	case VertexStatusMocked:
+		return "#fff3cd"
+	case VertexStatusErrored:
+		return "#f8d7da"
+	}
+
+	return "#e2e3e5"
+}
+
+func truncateLabel(s string) string {
+	r := []rune(s)
+	if len(r) <= maxExportLabelValueLength {
+		return s
+	}
+
+	return string(r[:maxExportLabelValueLength-3]) + "..."
+}
+
+// escapeMermaid escapes the characters that can't be used in a quoted Mermaid
+// label.
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+	).Replace(s)
+}
@@ -0,0 +1,56 @@
+package hcl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// VertexCheck is a check block. Checks only report on the infrastructure so
+// nothing can depend on them. They're added to the graph so their references
+// are ordered correctly, but they are never added to the evaluated module.
+// This also keeps the data sources scoped to the check from leaking into the
+// module alongside the regular data blocks.
+type VertexCheck struct {
+	logger zerolog.Logger
+	block  *Block
+}
+
+func (v *VertexCheck) ID() string {
+	return v.block.FullName()
+}
+
+func (v *VertexCheck) ModuleAddress() string {
+	return v.block.ModuleAddress()
+}
+
+func (v *VertexCheck) References() []VertexReference {
+	var refs []VertexReference
+
+	// References to the check's scoped data sources resolve within the
+	// check, so they shouldn't add edges to module level data blocks.
+	scoped := make(map[string]struct{})
+	for _, b := range v.block.GetChildBlocks("data") {
+		scoped[fmt.Sprintf("data.%s.%s", b.TypeLabel(), b.NameLabel())] = struct{}{}
+	}
+
+	for _, ref := range v.block.VerticesReferenced() {
+		parts := strings.SplitN(ref.Key, ".", 4)
+		if len(parts) >= 3 {
+			if _, ok := scoped[strings.Join(parts[:3], ".")]; ok {
+				continue
+			}
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+func (v *VertexCheck) Visit() error {
+	v.logger.Debug().Msgf("skipping check %s since checks don't affect costs", v.ID())
+
+	return nil
+}
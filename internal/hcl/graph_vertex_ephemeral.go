@@ -0,0 +1,94 @@
+package hcl
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VertexEphemeral is an ephemeral resource. Ephemeral resources are never
+// persisted to the plan or state, so their values are only made available for
+// other blocks to reference and they are never priced.
+type VertexEphemeral struct {
+	logger        zerolog.Logger
+	moduleConfigs *ModuleConfigs
+	block         *Block
+}
+
+func (v *VertexEphemeral) ID() string {
+	return v.block.FullName()
+}
+
+func (v *VertexEphemeral) ModuleAddress() string {
+	return v.block.ModuleAddress()
+}
+
+func (v *VertexEphemeral) References() []VertexReference {
+	return v.block.VerticesReferenced()
+}
+
+func (v *VertexEphemeral) Visit() error {
+	moduleInstances := v.moduleConfigs.Get(v.block.ModuleAddress())
+	if len(moduleInstances) == 0 {
+		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
+	}
+
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
+	for _, moduleInstance := range moduleInstances {
+		e := moduleInstance.evaluator
+		blockInstance := e.module.Blocks.FindLocalName(v.block.LocalName())
+
+		if blockInstance == nil {
+			return fmt.Errorf("could not find block %q in module %q", v.block.FullName(), moduleInstance.name)
+		}
+
+		err := v.evaluate(e, blockInstance)
+		if err != nil {
+			return fmt.Errorf("could not evaluate ephemeral block %q", v.ID())
+		}
+
+		expanded, err := v.expand(e, blockInstance)
+		if err != nil {
+			return fmt.Errorf("could not expand ephemeral block %q", v.ID())
+		}
+
+		e.AddFilteredBlocks(expanded...)
+	}
+
+	return nil
+}
+
+func (v *VertexEphemeral) evaluate(e *Evaluator, b *Block) error {
+	if len(b.Labels()) < 2 {
+		return fmt.Errorf("ephemeral block %s has no label", v.ID())
+	}
+
+	var existingVals map[string]cty.Value
+	existingCtx := e.ctx.Get("ephemeral")
+	if !existingCtx.IsNull() {
+		existingVals = existingCtx.AsValueMap()
+	} else {
+		existingVals = make(map[string]cty.Value)
+	}
+
+	val := e.evaluateResource(b, existingVals)
+
+	v.logger.Debug().Msgf("adding ephemeral %s to the evaluation context", v.ID())
+	e.ctx.SetByDot(val, fmt.Sprintf("ephemeral.%s", b.TypeLabel()))
+
+	return nil
+}
+
+func (v *VertexEphemeral) expand(e *Evaluator, b *Block) ([]*Block, error) {
+	expanded := []*Block{b}
+	expanded = e.expandBlockCounts(expanded)
+	expanded = e.expandBlockForEaches(expanded)
+	expanded = e.expandDynamicBlocks(expanded...)
+
+	b.expanded = true
+
+	return expanded, nil
+}
@@ -2,7 +2,6 @@ package hcl
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/rs/zerolog"
 )
@@ -26,15 +25,15 @@ func (v *VertexLocal) References() []VertexReference {
 	return v.attr.VerticesReferenced(v.block)
 }
 
-func (v *VertexLocal) Visit(mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexLocal) Visit() error {
 	moduleInstances := v.moduleConfigs.Get(v.block.ModuleAddress())
 	if len(moduleInstances) == 0 {
 		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
 	}
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
 
@@ -3,7 +3,6 @@ package hcl
 import (
 	"fmt"
 	"strings"
-	"sync"
 
 	"github.com/rs/zerolog"
 	"github.com/zclconf/go-cty/cty"
@@ -47,12 +46,15 @@ func (v *VertexModuleCall) References() []VertexReference {
 	return v.block.VerticesReferenced()
 }
 
-func (v *VertexModuleCall) Visit(mutex *sync.Mutex) error {
+func (v *VertexModuleCall) Visit() error {
 	moduleInstances := v.moduleConfigs.Get(v.block.ModuleAddress())
 	if len(moduleInstances) == 0 {
 		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
 	}
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
 		blockInstance := e.module.Blocks.FindLocalName(v.block.LocalName())
@@ -61,12 +63,12 @@ func (v *VertexModuleCall) Visit(mutex *sync.Mutex) error {
 			return fmt.Errorf("could not find block %q in module %q", v.ID(), moduleInstance.name)
 		}
 
-		err := v.evaluate(e, blockInstance, mutex)
+		err := v.evaluate(e, blockInstance)
 		if err != nil {
 			return fmt.Errorf("could not evaluate module %q", v.block.FullName())
 		}
 
-		expanded, err := v.expand(e, blockInstance, mutex)
+		expanded, err := v.expand(e, blockInstance)
 		if err != nil {
 			return fmt.Errorf("could not expand module %q", v.block.FullName())
 		}
@@ -77,10 +79,7 @@ func (v *VertexModuleCall) Visit(mutex *sync.Mutex) error {
 	return nil
 }
 
-func (v *VertexModuleCall) evaluate(e *Evaluator, b *Block, mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexModuleCall) evaluate(e *Evaluator, b *Block) error {
 	if b.Label() == "" {
 		return fmt.Errorf("module block %s has no label", b.FullName())
 	}
@@ -91,9 +90,7 @@ func (v *VertexModuleCall) evaluate(e *Evaluator, b *Block, mutex *sync.Mutex) e
 	return nil
 }
 
-func (v *VertexModuleCall) expand(e *Evaluator, b *Block, mutex *sync.Mutex) ([]*Block, error) {
-	mutex.Lock()
-	defer mutex.Unlock()
+func (v *VertexModuleCall) expand(e *Evaluator, b *Block) ([]*Block, error) {
 	expanded := []*Block{b}
 	expanded = e.expandBlockForEaches(expanded)
 	expanded = e.expandBlockCounts(expanded)
@@ -128,6 +125,7 @@ func (v *VertexModuleCall) expand(e *Evaluator, b *Block, mutex *sync.Mutex) ([]
 			nil,
 			e.logger,
 		)
+		moduleEvaluator.addRefactoringBlocks()
 
 		v.moduleConfigs.Add(unexpandedName, ModuleConfig{
 			name:            name,
@@ -1,8 +1,6 @@
 package hcl
 
 import (
-	"sync"
-
 	"github.com/rs/zerolog"
 )
 
@@ -24,14 +22,15 @@ func (v *VertexModuleExit) References() []VertexReference {
 	return []VertexReference{}
 }
 
-func (v *VertexModuleExit) Visit(mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexModuleExit) Visit() error {
 	moduleInstances := v.moduleConfigs.Get(v.block.FullName())
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
+		e.sortFilteredBlocks()
 		e.module.Blocks = e.filteredBlocks
 		e.module = *e.collectModules()
 
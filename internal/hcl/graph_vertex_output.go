@@ -2,7 +2,6 @@ package hcl
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/rs/zerolog"
 )
@@ -28,15 +27,15 @@ func (v *VertexOutput) References() []VertexReference {
 	return v.block.VerticesReferenced()
 }
 
-func (v *VertexOutput) Visit(mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexOutput) Visit() error {
 	moduleInstances := v.moduleConfigs.Get(v.block.ModuleAddress())
 	if len(moduleInstances) == 0 {
 		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
 	}
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
 		blockInstance := e.module.Blocks.FindLocalName(v.block.LocalName())
@@ -69,7 +68,11 @@ func (v *VertexOutput) Visit(mutex *sync.Mutex) error {
 
 			parentKeyParts = append(parentKeyParts, blockInstance.Label())
 
+			// the parent module is evaluated by its own vertices, which can
+			// be visited at the same time as this one.
+			parentEvaluator.mu.Lock()
 			parentEvaluator.ctx.Set(val, parentKeyParts...)
+			parentEvaluator.mu.Unlock()
 		}
 
 		e.AddFilteredBlocks(blockInstance)
@@ -2,7 +2,6 @@ package hcl
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/rs/zerolog"
 	"github.com/zclconf/go-cty/cty"
@@ -32,10 +31,7 @@ func (v *VertexProvider) References() []VertexReference {
 	return v.block.VerticesReferenced()
 }
 
-func (v *VertexProvider) Visit(mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexProvider) Visit() error {
 	provider := v.block.Label()
 	if provider == "" {
 		return fmt.Errorf("provider block %s has no label", v.ID())
@@ -46,6 +42,9 @@ func (v *VertexProvider) Visit(mutex *sync.Mutex) error {
 		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
 	}
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
 
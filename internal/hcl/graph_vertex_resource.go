@@ -2,7 +2,6 @@ package hcl
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/rs/zerolog"
 	"github.com/zclconf/go-cty/cty"
@@ -26,15 +25,15 @@ func (v *VertexResource) References() []VertexReference {
 	return v.block.VerticesReferenced()
 }
 
-func (v *VertexResource) Visit(mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexResource) Visit() error {
 	moduleInstances := v.moduleConfigs.Get(v.block.ModuleAddress())
 	if len(moduleInstances) == 0 {
 		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
 	}
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
 		blockInstance := e.module.Blocks.FindLocalName(v.block.LocalName())
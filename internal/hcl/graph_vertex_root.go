@@ -1,7 +1,5 @@
 package hcl
 
-import "sync"
-
 type VertexRoot struct{}
 
 func (v *VertexRoot) ID() string {
@@ -16,6 +14,6 @@ func (v *VertexRoot) References() []VertexReference {
 	return []VertexReference{}
 }
 
-func (v *VertexRoot) Visit(mutex *sync.Mutex) error {
+func (v *VertexRoot) Visit() error {
 	return nil
 }
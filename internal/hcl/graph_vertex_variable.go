@@ -2,7 +2,6 @@ package hcl
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/rs/zerolog"
 	"github.com/zclconf/go-cty/cty"
@@ -26,15 +25,15 @@ func (v *VertexVariable) References() []VertexReference {
 	return v.block.VerticesReferenced()
 }
 
-func (v *VertexVariable) Visit(mutex *sync.Mutex) error {
-	mutex.Lock()
-	defer mutex.Unlock()
-
+func (v *VertexVariable) Visit() error {
 	moduleInstances := v.moduleConfigs.Get(v.block.ModuleAddress())
 	if len(moduleInstances) == 0 {
 		return fmt.Errorf("no module instances found for module address %q", v.block.ModuleAddress())
 	}
 
+	unlock := lockModuleInstances(moduleInstances)
+	defer unlock()
+
 	for _, moduleInstance := range moduleInstances {
 		e := moduleInstance.evaluator
 		blockInstance := e.module.Blocks.FindLocalName(v.block.LocalName())
@@ -51,9 +50,14 @@ func (v *VertexVariable) Visit(mutex *sync.Mutex) error {
 			attrName := v.block.TypeLabel()
 			attr, ok := moduleInstance.moduleCall.Definition.AttributesAsMap()[attrName]
 			if ok {
+				// the module call is evaluated in the context of the parent
+				// module, which can be changed by other vertices at the
+				// same time as this one.
+				moduleInstance.parentEvaluator.mu.Lock()
 				inputVars = map[string]cty.Value{
 					attrName: attr.Value(),
 				}
+				moduleInstance.parentEvaluator.mu.Unlock()
 			}
 		}
 
@@ -0,0 +1,403 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// PromptedVarFileName is the name of the var file, in the .infracost directory
+// of a Terraform project, that holds the values the user gave when they were
+// prompted for missing variables. It's kept out of the project directory so
+// that Terraform and the project auto-detection don't pick it up.
+const PromptedVarFileName = "prompted.tfvars"
+
+// PromptedVarFilePath returns the path of the prompted var file of the
+// Terraform project at path.
+func PromptedVarFilePath(path string) string {
+	return filepath.Join(path, config.InfracostDir, PromptedVarFileName)
+}
+
+// MissingVariable is an input variable of the root module that the user didn't
+// give a value for and that doesn't have a default.
+type MissingVariable struct {
+	Name        string
+	Description string
+	// Type is the type constraint of the variable, e.g. list(string). It's
+	// empty if the variable doesn't have a type.
+	Type cty.Type
+	// Validations are the conditions of the validation blocks of the variable
+	// with their error messages.
+	Validations []string
+	// CostRelevant is true if the value of the variable is used by a resource,
+	// either directly or through locals, module inputs or module outputs.
+	CostRelevant bool
+}
+
+// TypeString returns the type constraint of the variable as it would be
+// written in the config, or "any" if it doesn't have one.
+func (v MissingVariable) TypeString() string {
+	if v.Type == cty.NilType {
+		return "any"
+	}
+
+	return typeexpr.TypeString(v.Type)
+}
+
+// Parse converts the string that the user gave for the variable into a value
+// of the type of the variable. Strings can be given without quotes, any other
+// type is parsed as an HCL expression, e.g. ["a", "b"] for a list(string).
+func (v MissingVariable) Parse(input string) (cty.Value, error) {
+	if v.Type == cty.String {
+		return cty.StringVal(input), nil
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(input), v.Name, hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.New(diags[0].Summary)
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.New(diags[0].Summary)
+	}
+
+	if v.Type == cty.NilType || v.Type == cty.DynamicPseudoType {
+		return val, nil
+	}
+
+	val, err := convert.Convert(val, v.Type)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("value must be of type %s: %w", v.TypeString(), err)
+	}
+
+	return val, nil
+}
+
+// placeholder returns an empty value of the type of the variable, used in the
+// tfvars template.
+func (v MissingVariable) placeholder() cty.Value {
+	switch {
+	case v.Type == cty.String:
+		return cty.StringVal("")
+	case v.Type == cty.Number:
+		return cty.Zero
+	case v.Type == cty.Bool:
+		return cty.False
+	case v.Type.IsListType(), v.Type.IsSetType(), v.Type.IsTupleType():
+		return cty.EmptyTupleVal
+	case v.Type.IsMapType(), v.Type.IsObjectType():
+		return cty.EmptyObjectVal
+	}
+
+	return cty.NullVal(cty.DynamicPseudoType)
+}
+
+// MissingVariables returns the variable blocks with missing input values.
+// Unlike MissingVars, the variables have the information the user needs to
+// give them a value. Sensitive variables are skipped.
+func (e *Evaluator) MissingVariables() []MissingVariable {
+	var missing []MissingVariable
+
+	for _, block := range configBlocks(&e.module).OfType("variable") {
+		if !e.isMissingVariable(block) {
+			continue
+		}
+
+		v := MissingVariable{Name: block.Label()}
+
+		if attr := block.GetAttribute("description"); attr != nil {
+			v.Description = attr.AsString()
+		}
+
+		if attr := block.GetAttribute("type"); attr != nil {
+			ty, diags := typeexpr.TypeConstraint(attr.HCLAttr.Expr)
+			if !diags.HasErrors() {
+				v.Type = ty
+			}
+		}
+
+		for _, validation := range block.GetChildBlocks("validation") {
+			condition := validation.GetAttribute("condition")
+			if condition == nil {
+				continue
+			}
+
+			hint := exprSource(condition.HCLAttr.Expr)
+			if msg := validation.GetAttribute("error_message"); msg != nil {
+				hint = fmt.Sprintf("%s (%s)", hint, msg.AsString())
+			}
+
+			v.Validations = append(v.Validations, hint)
+		}
+
+		missing = append(missing, v)
+	}
+
+	return missing
+}
+
+// exprSource returns the config that the expression was parsed from.
+func exprSource(expr hcl.Expression) string {
+	r := expr.Range()
+
+	b, err := os.ReadFile(r.Filename)
+	if err != nil || r.End.Byte > len(b) || r.Start.Byte > r.End.Byte {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(string(b[r.Start.Byte:r.End.Byte])), " ")
+}
+
+// markCostRelevantVariables sets CostRelevant on the MissingVariables of the
+// root Module m.
+func (m *Module) markCostRelevantVariables() {
+	if len(m.MissingVariables) == 0 {
+		return
+	}
+
+	r := costRelevanceResolver{visiting: make(map[*Attribute]struct{})}
+	relevant := r.module(m)
+
+	for i, v := range m.MissingVariables {
+		_, ok := relevant[v.Name]
+		m.MissingVariables[i].CostRelevant = ok
+	}
+}
+
+// costRelevanceResolver finds the variables of a Module whose values are used
+// by resources. It follows references in the same way as the
+// provenanceResolver.
+type costRelevanceResolver struct {
+	// visiting guards against following circular references forever.
+	visiting map[*Attribute]struct{}
+}
+
+// module returns the names of the variables of m that are used by the
+// resources of m or the resources of the modules that m calls.
+func (r costRelevanceResolver) module(m *Module) map[string]struct{} {
+	vars := make(map[string]struct{})
+
+	for _, b := range configBlocks(m) {
+		switch b.Type() {
+		case "resource":
+			r.block(m, b, vars)
+		case "module":
+			// if the module wasn't evaluated, e.g. because its count is
+			// unknown, we can't tell which inputs it uses so all of them
+			// are relevant.
+			var childVars map[string]struct{}
+			if child := childModule(m, b); child != nil {
+				childVars = r.module(child)
+			}
+
+			// the inputs of the module call are relevant if the variables
+			// they set are, as are count and for_each since they change
+			// the number of resources.
+			for _, attr := range b.GetAttributes() {
+				_, ok := childVars[attr.Name()]
+				if ok || childVars == nil || attr.Name() == "count" || attr.Name() == "for_each" {
+					r.attribute(m, attr, vars)
+				}
+			}
+		}
+	}
+
+	return vars
+}
+
+func (r costRelevanceResolver) block(m *Module, b *Block, vars map[string]struct{}) {
+	for _, attr := range b.GetAttributes() {
+		r.attribute(m, attr, vars)
+	}
+
+	for _, child := range b.Children() {
+		r.block(m, child, vars)
+	}
+}
+
+func (r costRelevanceResolver) attribute(m *Module, attr *Attribute, vars map[string]struct{}) {
+	if _, ok := r.visiting[attr]; ok {
+		return
+	}
+	r.visiting[attr] = struct{}{}
+	defer delete(r.visiting, attr)
+
+	for _, traversal := range attr.HCLAttr.Expr.Variables() {
+		switch traversal.RootName() {
+		case "var":
+			vars[traversalAttrName(traversal, 1)] = struct{}{}
+		case "local":
+			for _, b := range configBlocks(m).OfType("locals") {
+				if local := b.GetAttribute(traversalAttrName(traversal, 1)); local != nil {
+					r.attribute(m, local, vars)
+				}
+			}
+		case "module":
+			r.moduleOutput(m, traversal, vars)
+		}
+	}
+}
+
+// moduleOutput adds the variables of m that are passed to the module call
+// that the traversal references the output of, if the output uses them.
+func (r costRelevanceResolver) moduleOutput(m *Module, traversal hcl.Traversal, vars map[string]struct{}) {
+	name := traversalAttrName(traversal, 1)
+
+	// the output name comes after the instance key of modules with count
+	// or for_each.
+	var output string
+	for _, step := range traversal[2:] {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			output = attr.Name
+			break
+		}
+	}
+
+	for _, b := range configBlocks(m).OfType("module") {
+		if b.TypeLabel() != name {
+			continue
+		}
+
+		child := childModule(m, b)
+		if child == nil {
+			continue
+		}
+
+		childVars := make(map[string]struct{})
+		for _, ob := range configBlocks(child).OfType("output") {
+			if output != "" && ob.Label() != output {
+				continue
+			}
+
+			if value := ob.GetAttribute("value"); value != nil {
+				r.attribute(child, value, childVars)
+			}
+		}
+
+		for childVar := range childVars {
+			if attr := b.GetAttribute(childVar); attr != nil {
+				r.attribute(m, attr, vars)
+			}
+		}
+	}
+}
+
+// childModule returns the Module called by the module block b of m. If the
+// module has multiple instances the first is returned since they all have the
+// same config.
+func childModule(m *Module, b *Block) *Module {
+	name := modArrayPartReplace.ReplaceAllString(b.FullName(), "")
+
+	for _, child := range m.Modules {
+		for _, cb := range child.Blocks {
+			if cb.moduleBlock != nil {
+				if modArrayPartReplace.ReplaceAllString(cb.moduleBlock.FullName(), "") == name {
+					return child
+				}
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// configBlocks returns the Blocks of the module as they're written in the
+// config, before count and for_each are expanded. This means that resources
+// whose count is unknown, and so have no instances, are still included.
+func configBlocks(m *Module) Blocks {
+	if len(m.RawBlocks) > 0 {
+		return m.RawBlocks
+	}
+
+	return m.Blocks
+}
+
+// TFVarsTemplate returns a tfvars file with a commented out assignment for
+// each of the variables, along with their description, type and validations,
+// for the user to fill in.
+func TFVarsTemplate(vars []MissingVariable) []byte {
+	sorted := make([]MissingVariable, len(vars))
+	copy(sorted, vars)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var b strings.Builder
+	b.WriteString("# Values for the Terraform variables that Infracost couldn't find a value for.\n")
+	b.WriteString("# Uncomment and fill in the variables, then pass this file to Infracost with\n")
+	b.WriteString("# the --terraform-var-file flag.\n")
+
+	for _, v := range sorted {
+		b.WriteString("\n")
+
+		if v.Description != "" {
+			for _, line := range strings.Split(strings.TrimSpace(v.Description), "\n") {
+				b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+			}
+		}
+
+		b.WriteString(fmt.Sprintf("# Type: %s\n", v.TypeString()))
+
+		for _, validation := range v.Validations {
+			b.WriteString(fmt.Sprintf("# Validation: %s\n", validation))
+		}
+
+		f := hclwrite.NewEmptyFile()
+		f.Body().SetAttributeValue(v.Name, v.placeholder())
+		for _, line := range strings.Split(strings.TrimSpace(string(f.Bytes())), "\n") {
+			b.WriteString("# " + line + "\n")
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// WriteVarFile sets the values of the variables in the var file at path,
+// creating it if it doesn't exist. Any other variables and comments in the file
+// are kept.
+func WriteVarFile(path string, values map[string]cty.Value) error {
+	f := hclwrite.NewEmptyFile()
+
+	b, err := os.ReadFile(path)
+	if err == nil {
+		var diags hcl.Diagnostics
+		f, diags = hclwrite.ParseConfig(b, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("could not parse var file %s: %w", path, diags)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f.Body().SetAttributeValue(name, values[name])
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, f.Bytes(), 0600)
+}
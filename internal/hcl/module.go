@@ -47,6 +47,19 @@ type Module struct {
 	// SourceURL is the discovered remote url for the module. This will only be
 	// filled if the module is a remote module.
 	SourceURL string
+
+	// MissingVariables are the variables of the module that don't have a
+	// value. This is only set for root modules.
+	MissingVariables []MissingVariable
+
+	// FailedConditions are the messages of the variable validations and the
+	// preconditions and postconditions of the module, and the modules it
+	// calls, that failed.
+	FailedConditions []string
+
+	// inputVars are the input variable values that the module was evaluated
+	// with. See Module.Provenance for more information.
+	inputVars map[string]cty.Value
 }
 
 // Index returns the count index of the Module using the name.
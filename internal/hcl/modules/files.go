@@ -0,0 +1,73 @@
+package modules
+
+import (
+	"os"
+	"strings"
+)
+
+var configFileSuffixes = []string{".tf", ".tf.json", ".tofu", ".tofu.json"}
+
+// IsConfigFile returns true if the file name is a Terraform or OpenTofu
+// configuration file.
+func IsConfigFile(name string) bool {
+	for _, suffix := range configFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsJSONConfigFile returns true if the file name is a Terraform or OpenTofu
+// configuration file in the JSON syntax.
+func IsJSONConfigFile(name string) bool {
+	return strings.HasSuffix(name, ".tf.json") || strings.HasSuffix(name, ".tofu.json")
+}
+
+// ConfigFiles returns the names of the configuration files in the directory
+// entries that Terraform or OpenTofu would load. OpenTofu ignores a .tf or
+// .tf.json file if the directory has a .tofu or .tofu.json file with the same
+// name, which lets modules support both tools with OpenTofu-specific
+// features, including override files such as main_override.tofu. See
+// https://opentofu.org/docs/language/files/#file-extension
+func ConfigFiles(entries []os.DirEntry) []string {
+	names := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names[e.Name()] = struct{}{}
+		}
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !IsConfigFile(name) {
+			continue
+		}
+
+		if tofuName, ok := tofuFileName(name); ok {
+			if _, exists := names[tofuName]; exists {
+				continue
+			}
+		}
+
+		files = append(files, name)
+	}
+
+	return files
+}
+
+// tofuFileName returns the name of the OpenTofu file that takes precedence
+// over the Terraform file.
+func tofuFileName(name string) (string, bool) {
+	if strings.HasSuffix(name, ".tf.json") {
+		return strings.TrimSuffix(name, ".tf.json") + ".tofu.json", true
+	}
+
+	if strings.HasSuffix(name, ".tf") {
+		return strings.TrimSuffix(name, ".tf") + ".tofu", true
+	}
+
+	return "", false
+}
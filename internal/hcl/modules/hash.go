@@ -0,0 +1,193 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HashDir returns a hash of the files in dir that can change how the module in
+// dir is evaluated. This is its config files and any other files it can read
+// with functions like file or templatefile. Subdirectories are included,
+// apart from hidden directories, e.g. .terraform or .infracost, and
+// directories that contain config files. These are other modules that are
+// hashed separately if they're used.
+func HashDir(dir string) (string, error) {
+	h := sha256.New()
+
+	err := hashDir(h, dir, "")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func hashDir(h hash.Hash, root string, rel string) error {
+	entries, err := os.ReadDir(filepath.Join(root, rel))
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	for _, e := range entries {
+		name := e.Name()
+		path := filepath.Join(rel, name)
+
+		if e.IsDir() {
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+
+			subEntries, err := os.ReadDir(filepath.Join(root, path))
+			if err != nil {
+				return err
+			}
+
+			if len(ConfigFiles(subEntries)) > 0 {
+				continue
+			}
+
+			err = hashDir(h, root, path)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(root, path))
+		if err != nil {
+			return err
+		}
+
+		fh := sha256.New()
+		_, err = io.Copy(fh, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(h, "%s:%x\n", filepath.ToSlash(path), fh.Sum(nil))
+	}
+
+	return nil
+}
+
+// Hash returns a hash of the module manifest of the project at path, the
+// directories of the modules in the manifest and the directories of the local
+// modules that the project uses, so that it changes if the source, version or
+// content of any module that the project uses changes. The hash is empty if the
+// modules of the project haven't been loaded before, since there's no manifest
+// to read.
+func (m *ModuleLoader) Hash(path string) (string, error) {
+	manifestFilePath := m.manifestFilePath(path)
+	baseDir := m.cachePath
+
+	_, err := os.Stat(manifestFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		// Load uses the Terraform module manifest if there isn't an
+		// Infracost one, in which case the module dirs are relative to
+		// the project path.
+		manifestFilePath = m.tfManifestFilePath(path)
+		baseDir = path
+
+		_, err = os.Stat(manifestFilePath)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := readManifest(manifestFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "version:%s\n", manifest.Version)
+
+	modules := make([]*ManifestModule, len(manifest.Modules))
+	copy(modules, manifest.Modules)
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Key < modules[j].Key
+	})
+
+	for _, module := range modules {
+		dirHash, err := HashDir(filepath.Join(baseDir, module.Dir))
+		if err != nil {
+			return "", fmt.Errorf("could not hash module %s: %w", module.Key, err)
+		}
+
+		fmt.Fprintf(h, "module:%s:%s:%s:%s\n", module.Key, module.Source, module.Version, dirHash)
+	}
+
+	// local modules aren't included in the manifest, so find them from the
+	// module calls of the project and its local modules.
+	localDirs := map[string]struct{}{}
+	err = m.collectLocalModuleDirs(path, localDirs)
+	if err != nil {
+		return "", err
+	}
+
+	dirs := make([]string, 0, len(localDirs))
+	for dir := range localDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		dirHash, err := HashDir(dir)
+		if err != nil {
+			return "", fmt.Errorf("could not hash local module %s: %w", dir, err)
+		}
+
+		rel, _ := filepath.Rel(path, dir)
+		fmt.Fprintf(h, "local_module:%s:%s\n", filepath.ToSlash(rel), dirHash)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// collectLocalModuleDirs adds the directories of the local modules called by
+// the module in dir, and the local modules that they call, to dirs.
+func (m *ModuleLoader) collectLocalModuleDirs(dir string, dirs map[string]struct{}) error {
+	module, _, err := m.loadModuleFromPath(dir, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, call := range module.ModuleCalls {
+		if !isLocalModule(call.Source) {
+			continue
+		}
+
+		moduleDir := filepath.Clean(filepath.Join(dir, call.Source))
+		if _, ok := dirs[moduleDir]; ok {
+			continue
+		}
+
+		dirs[moduleDir] = struct{}{}
+		err := m.collectLocalModuleDirs(moduleDir, dirs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	"github.com/rs/zerolog"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/infracost/infracost/internal/config"
@@ -34,6 +36,10 @@ var (
 	tfManifestPath = ".terraform/modules/modules.json"
 
 	supportedManifestVersion = "2.0"
+
+	// invalidProviderReferenceSummary is the summary of the tfconfig
+	// diagnostic for provider references it can't decode.
+	invalidProviderReferenceSummary = "Invalid provider reference"
 )
 
 // ModuleLoader handles the loading of Terraform modules. It supports local, registry and other remote modules.
@@ -107,10 +113,39 @@ func (m *ModuleLoader) tfManifestFilePath(path string) string {
 	return filepath.Join(path, tfManifestPath)
 }
 
+// LoadOption configures how the modules are loaded.
+type LoadOption func(o *loadOptions)
+
+type loadOptions struct {
+	inputVars map[string]cty.Value
+	funcs     map[string]function.Function
+}
+
+// LoadWithInputVars sets the input variables of the root module, which are
+// used to evaluate module sources and versions that reference variables.
+func LoadWithInputVars(vars map[string]cty.Value) LoadOption {
+	return func(o *loadOptions) {
+		o.inputVars = vars
+	}
+}
+
+// LoadWithFunctions sets the functions that can be used in module sources
+// and versions.
+func LoadWithFunctions(funcs map[string]function.Function) LoadOption {
+	return func(o *loadOptions) {
+		o.funcs = funcs
+	}
+}
+
 // Load loads the modules from the given path.
 // For each module it checks if the module has already been downloaded, by checking if iut exists in the manifest
 // If not then it downloads the module from the registry or from a remote source and updates the module manifest with the latest metadata.
-func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
+func (m *ModuleLoader) Load(path string, opts ...LoadOption) (man *Manifest, err error) {
+	options := &loadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	defer func() {
 		if man != nil {
 			man.cachePath = m.cachePath
@@ -164,7 +199,7 @@ func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
 	}
 	m.cache.loadFromManifest(manifest)
 
-	metadatas, err := m.loadModules(path, "")
+	metadatas, err := m.loadModules(path, "", options.inputVars, options.funcs)
 	if err != nil {
 		return nil, err
 	}
@@ -181,11 +216,12 @@ func (m *ModuleLoader) Load(path string) (man *Manifest, err error) {
 	return manifest, nil
 }
 
-// loadModules recursively loads the modules from the given path.
-func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModule, error) {
+// loadModules recursively loads the modules from the given path. The inputs
+// are the values of the module's variables that are known statically.
+func (m *ModuleLoader) loadModules(path string, prefix string, inputs map[string]cty.Value, funcs map[string]function.Function) ([]*ManifestModule, error) {
 	manifestModules := make([]*ManifestModule, 0)
 
-	module, err := m.loadModuleFromPath(path)
+	module, staticCalls, err := m.loadModuleFromPath(path, inputs, funcs)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +239,12 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 	for i := 0; i < getProcessCount(); i++ {
 		errGroup.Go(func() error {
 			for moduleCall := range jobs {
-				metadata, err := m.loadModule(moduleCall, path, prefix)
+				var childInputs map[string]cty.Value
+				if call, ok := staticCalls[moduleCall.Name]; ok {
+					childInputs = call.inputs
+				}
+
+				metadata, err := m.loadModule(moduleCall, path, prefix, childInputs, funcs)
 				if err != nil {
 					return err
 				}
@@ -216,7 +257,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 				}
 
 				moduleDir := filepath.Join(m.cachePath, metadata.Dir)
-				nestedManifestModules, err := m.loadModules(moduleDir, metadata.Key+".")
+				nestedManifestModules, err := m.loadModules(moduleDir, metadata.Key+".", childInputs, funcs)
 				if err != nil {
 					return err
 				}
@@ -244,7 +285,7 @@ func (m *ModuleLoader) loadModules(path string, prefix string) ([]*ManifestModul
 // 2. Checks if the module is a local module.
 // 3. Checks if the module is a registry module and downloads it.
 // 4. Checks if the module is a remote module and downloads it.
-func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath string, prefix string) (*ManifestModule, error) {
+func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath string, prefix string, inputs map[string]cty.Value, funcs map[string]function.Function) (*ManifestModule, error) {
 	key := prefix + moduleCall.Name
 	source := moduleCall.Source
 	version := moduleCall.Version
@@ -271,7 +312,7 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 		// Test if we can actually load the module. If not, then we should try re-loading it.
 		// This can happen if the directory the module was downloaded to has been deleted and moved
 		// so the existing manifest.json is out-of-date.
-		_, loadModErr := m.loadModuleFromPath(path.Join(m.cachePath, manifestModule.Dir))
+		_, _, loadModErr := m.loadModuleFromPath(path.Join(m.cachePath, manifestModule.Dir), inputs, funcs)
 		if loadModErr == nil {
 			return manifestModule, nil
 		}
@@ -323,50 +364,76 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 	return manifestModule, nil
 }
 
-func (m *ModuleLoader) loadModuleFromPath(fullPath string) (*tfconfig.Module, error) {
+// loadModuleFromPath loads the module in the directory. Module sources and
+// versions can reference variables and locals, as supported by OpenTofu, so
+// they are evaluated with the static values of the module's variables.
+func (m *ModuleLoader) loadModuleFromPath(fullPath string, inputs map[string]cty.Value, funcs map[string]function.Function) (*tfconfig.Module, map[string]*staticModuleCall, error) {
 	mod := tfconfig.NewModule(fullPath)
 
 	fileInfos, err := os.ReadDir(fullPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	for _, info := range fileInfos {
-		if info.IsDir() {
-			continue
-		}
-
-		var parseFunc func(filename string) (*hcl.File, hcl.Diagnostics)
-		if strings.HasSuffix(info.Name(), ".tf") {
-			parseFunc = m.hclParser.ParseHCLFile
-		}
-
-		if strings.HasSuffix(info.Name(), ".tf.json") {
+	var files []*hcl.File
+	for _, name := range ConfigFiles(fileInfos) {
+		parseFunc := m.hclParser.ParseHCLFile
+		if IsJSONConfigFile(name) {
 			parseFunc = m.hclParser.ParseJSONFile
 		}
 
-		// this is not a file we can parse:
-		if parseFunc == nil {
-			continue
-		}
-
-		path := filepath.Join(fullPath, info.Name())
+		path := filepath.Join(fullPath, name)
 		f, fileDiag := parseFunc(path)
 		if fileDiag != nil && fileDiag.HasErrors() {
-			return nil, fmt.Errorf("failed to parse file %s diag: %w", path, fileDiag)
+			return nil, nil, fmt.Errorf("failed to parse file %s diag: %w", path, fileDiag)
 		}
 
 		if f == nil {
 			continue
 		}
 
+		files = append(files, f)
+	}
+
+	blocks := StaticBlocks(files)
+	staticCalls, err := staticModuleCalls(blocks, NewStaticContext(blocks, inputs, funcs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var staticRanges []hcl.Range
+	for _, call := range staticCalls {
+		staticRanges = append(staticRanges, call.ranges...)
+	}
+
+	for _, f := range files {
 		contentDiag := tfconfig.LoadModuleFromFile(f, mod)
-		if contentDiag != nil && contentDiag.HasErrors() {
-			return nil, fmt.Errorf("failed to load module from file %s diag: %w", path, contentDiag)
+		for _, diag := range contentDiag {
+			// tfconfig can only decode static module sources and versions,
+			// these have been evaluated with the module's variables instead.
+			if diag.Severity != hcl.DiagError || withinRanges(diag.Subject, staticRanges) {
+				continue
+			}
+
+			// tfconfig doesn't support references to provider instances, e.g.
+			// aws.by_region[each.key], but the module loader doesn't use the
+			// resources' providers.
+			if diag.Summary == invalidProviderReferenceSummary {
+				continue
+			}
+
+			return nil, nil, fmt.Errorf("failed to load module from file %s diag: %w", f.Body.MissingItemRange().Filename, contentDiag)
+		}
+	}
+
+	for name, call := range mod.ModuleCalls {
+		if static, ok := staticCalls[name]; ok {
+			call.Source = static.source
+			call.Version = static.version
 		}
 	}
 
-	return mod, nil
+	return mod, staticCalls, nil
 }
 
 func (m *ModuleLoader) loadRegistryModule(key string, source string, version string) (*ManifestModule, error) {
@@ -0,0 +1,195 @@
+package modules
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+var staticSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "locals"},
+		{Type: "module", LabelNames: []string{"name"}},
+	},
+}
+
+var variableDefaultSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "default"}},
+}
+
+// maxStaticLocalPasses is the maximum number of passes made over the locals,
+// each pass evaluates the locals that depend on locals evaluated in the
+// previous pass.
+const maxStaticLocalPasses = 10
+
+// StaticBlocks returns the variable, locals and module blocks of the files.
+func StaticBlocks(files []*hcl.File) hcl.Blocks {
+	var blocks hcl.Blocks
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+
+		content, _, _ := f.Body.PartialContent(staticSchema)
+		if content != nil {
+			blocks = append(blocks, content.Blocks...)
+		}
+	}
+
+	return blocks
+}
+
+// NewStaticContext returns the context for evaluating the expressions that
+// OpenTofu evaluates before the configuration is planned, such as module
+// sources and backend configuration. These expressions can reference
+// variables and locals that don't depend on any resources. Variables are set
+// from the inputs, falling back to their defaults, and locals that can't be
+// evaluated statically are unknown. See
+// https://opentofu.org/docs/language/values/#early-evaluation
+func NewStaticContext(blocks hcl.Blocks, inputs map[string]cty.Value, funcs map[string]function.Function) *hcl.EvalContext {
+	vars := map[string]cty.Value{}
+	for _, b := range blocks {
+		if b.Type != "variable" || len(b.Labels) == 0 {
+			continue
+		}
+
+		name := b.Labels[0]
+		if v, ok := inputs[name]; ok {
+			vars[name] = v
+			continue
+		}
+
+		vars[name] = cty.DynamicVal
+
+		content, _, _ := b.Body.PartialContent(variableDefaultSchema)
+		if attr, ok := content.Attributes["default"]; ok {
+			v, diags := attr.Expr.Value(nil)
+			if !diags.HasErrors() {
+				vars[name] = v
+			}
+		}
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(vars),
+			"local": cty.EmptyObjectVal,
+		},
+		Functions: funcs,
+	}
+
+	var pending hcl.Attributes = map[string]*hcl.Attribute{}
+	for _, b := range blocks {
+		if b.Type != "locals" {
+			continue
+		}
+
+		attrs, _ := b.Body.JustAttributes()
+		for k, v := range attrs {
+			pending[k] = v
+		}
+	}
+
+	locals := map[string]cty.Value{}
+	for i := 0; i < maxStaticLocalPasses && len(pending) > 0; i++ {
+		evaluated := false
+		for name, attr := range pending {
+			v, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() || !v.IsWhollyKnown() {
+				continue
+			}
+
+			locals[name] = v
+			delete(pending, name)
+			evaluated = true
+		}
+
+		if !evaluated {
+			break
+		}
+
+		ctx.Variables["local"] = cty.ObjectVal(locals)
+	}
+
+	for name := range pending {
+		locals[name] = cty.DynamicVal
+	}
+	ctx.Variables["local"] = cty.ObjectVal(locals)
+
+	return ctx
+}
+
+// staticModuleCall is a module call with its source, version and inputs
+// evaluated statically.
+type staticModuleCall struct {
+	source  string
+	version string
+	inputs  map[string]cty.Value
+	// ranges are the ranges of the source and version expressions that were
+	// evaluated statically.
+	ranges []hcl.Range
+}
+
+// staticModuleCalls evaluates the source, version and inputs of the module
+// calls with the static context. Inputs that can't be evaluated statically
+// are omitted.
+func staticModuleCalls(blocks hcl.Blocks, ctx *hcl.EvalContext) (map[string]*staticModuleCall, error) {
+	calls := map[string]*staticModuleCall{}
+
+	for _, b := range blocks {
+		if b.Type != "module" || len(b.Labels) == 0 {
+			continue
+		}
+
+		name := b.Labels[0]
+		call := &staticModuleCall{inputs: map[string]cty.Value{}}
+
+		attrs, _ := b.Body.JustAttributes()
+		for k, attr := range attrs {
+			v, diags := attr.Expr.Value(ctx)
+
+			switch k {
+			case "source", "version":
+				if diags.HasErrors() || !v.IsWhollyKnown() || v.IsNull() || v.Type() != cty.String {
+					return nil, fmt.Errorf("module %s %s must be known before the configuration is applied, it can only reference variables and locals that don't depend on resources", name, k)
+				}
+
+				if k == "source" {
+					call.source = v.AsString()
+				} else {
+					call.version = v.AsString()
+				}
+
+				call.ranges = append(call.ranges, attr.Expr.Range())
+			case "count", "for_each", "providers", "depends_on":
+				continue
+			default:
+				if !diags.HasErrors() && v.IsWhollyKnown() {
+					call.inputs[k] = v
+				}
+			}
+		}
+
+		calls[name] = call
+	}
+
+	return calls, nil
+}
+
+// withinRanges returns true if the range is within one of the ranges.
+func withinRanges(r *hcl.Range, ranges []hcl.Range) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, outer := range ranges {
+		if r.Filename == outer.Filename && r.Start.Byte >= outer.Start.Byte && r.End.Byte <= outer.End.Byte {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,6 +1,7 @@
 package hcl
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/extclient"
@@ -227,6 +229,14 @@ func OptionWithBlockBuilder(blockBuilder BlockBuilder) Option {
 	}
 }
 
+// OptionWithDataMocks sets the user supplied data source values that the
+// Evaluator uses in place of mocked values. See DataMocks for more information.
+func OptionWithDataMocks(mocks *DataMocks) Option {
+	return func(p *Parser) {
+		p.blockBuilder.DataMocks = mocks
+	}
+}
+
 // OptionWithTerraformWorkspace informs the Parser to use the provided name as the workspace for context evaluation.
 // The Parser exposes this workspace in the evaluation context under the variable named `terraform.workspace`.
 // This is commonly used by users to specify different capacity/configuration in their Terraform, e.g:
@@ -243,6 +253,14 @@ func OptionWithTerraformWorkspace(name string) Option {
 	}
 }
 
+// OptionWithGraphParallelism sets the maximum number of vertices that the Graph
+// evaluates at the same time. Vertices are evaluated one at a time by default.
+func OptionWithGraphParallelism(parallelism int) Option {
+	return func(p *Parser) {
+		p.graphParallelism = parallelism
+	}
+}
+
 // OptionWithSpinner sets a SpinnerFunc onto the Parser. With this option enabled
 // the Parser will send progress to the Spinner. This is disabled by default as
 // we run the Parser concurrently underneath DirProvider and don't want to mess with its output.
@@ -280,6 +298,9 @@ type Parser struct {
 	hasChanges            bool
 	moduleSuffix          string
 	envMatcher            *EnvFileMatcher
+	graph                 *Graph
+	graphParallelism      int
+	usesRemoteVariables   bool
 }
 
 // NewParser creates a new parser for the given RootPath.
@@ -386,13 +407,17 @@ func (p *Parser) ParseDirectory() (m *Module, err error) {
 	}
 
 	p.logger.Debug().Msg("Loading TFVars...")
-	inputVars, err := p.loadVars(blocks, p.tfvarsPaths)
+	inputVars, err := p.loadVars(blocks, p.varFilePaths())
 	if err != nil {
 		return m, err
 	}
 
 	// load the modules. This downloads any remote modules to the local file system
-	modulesManifest, err := p.moduleLoader.Load(p.initialPath)
+	modulesManifest, err := p.moduleLoader.Load(
+		p.initialPath,
+		modules.LoadWithInputVars(inputVars),
+		modules.LoadWithFunctions(ExpFunctions(p.initialPath, p.logger)),
+	)
 	if err != nil {
 		return m, fmt.Errorf("Error loading Terraform modules: %w", err)
 	}
@@ -430,11 +455,13 @@ func (p *Parser) ParseDirectory() (m *Module, err error) {
 		// we use the base zerolog log here so that it's consistent with the spinner logs
 		log.Info().Msgf("Building project with experimental graph runner")
 
-		g, err := NewGraphWithRoot(p.logger, nil)
+		g, err := NewGraphWithRoot(p.logger, p.graphParallelism)
 		if err != nil {
 			return m, err
 		}
 
+		p.graph = g
+
 		root, err = g.Run(evaluator)
 		if err != nil {
 			return m, err
@@ -450,9 +477,107 @@ func (p *Parser) ParseDirectory() (m *Module, err error) {
 	root.HasChanges = p.hasChanges
 	root.TerraformVarsPaths = p.tfvarsPaths
 	root.ModuleSuffix = p.moduleSuffix
+	root.MissingVariables = evaluator.MissingVariables()
+	root.markCostRelevantVariables()
 	return root, nil
 }
 
+// varFilePaths returns the paths of the var files to load the input variables
+// from, in order of precedence. The prompted var file, see
+// PromptedVarFilePath, comes first so that it has the lowest precedence and is
+// only used for variables that aren't set anywhere else.
+func (p *Parser) varFilePaths() []string {
+	prompted := PromptedVarFilePath(p.initialPath)
+	if _, err := os.Stat(prompted); err != nil {
+		return p.tfvarsPaths
+	}
+
+	return append([]string{prompted}, p.tfvarsPaths...)
+}
+
+// Graph returns the evaluation graph built by the last call to ParseDirectory.
+// It's nil if the graph evaluator isn't enabled.
+func (p *Parser) Graph() *Graph {
+	return p.graph
+}
+
+// HasChanges returns true if the project has changes in the current VCS diff.
+func (p *Parser) HasChanges() bool {
+	return p.hasChanges
+}
+
+// UsesRemoteVariables returns true if the last call to ParseDirectory loaded
+// variables from a Terraform Cloud/Enterprise workspace. These can change
+// without any change to the project files.
+func (p *Parser) UsesRemoteVariables() bool {
+	return p.usesRemoteVariables
+}
+
+// Hash returns a hash of the inputs that the project is evaluated with, so it
+// can be used to key a cache of the evaluated project. This covers the files in
+// the project directory, the var files, the input and env variables, the
+// workspace, the module suffix and the modules in the module manifest of the
+// project. The hash is empty if the modules of the project haven't been loaded
+// before, as then the modules that the project uses aren't known until it's
+// parsed.
+func (p *Parser) Hash() (string, error) {
+	modulesHash, err := p.moduleLoader.Hash(p.initialPath)
+	if err != nil || modulesHash == "" {
+		return "", err
+	}
+
+	dirHash, err := modules.HashDir(p.initialPath)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "dir:%s\n", dirHash)
+	fmt.Fprintf(h, "modules:%s\n", modulesHash)
+	fmt.Fprintf(h, "workspace:%s\n", p.workspaceName)
+	fmt.Fprintf(h, "module_suffix:%s\n", p.moduleSuffix)
+
+	for _, filename := range p.varFilePaths() {
+		b, err := os.ReadFile(filename)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+
+		fmt.Fprintf(h, "var_file:%s:%x\n", filename, sha256.Sum256(b))
+	}
+
+	for _, vars := range []struct {
+		name   string
+		values map[string]cty.Value
+	}{
+		{name: "env_var", values: p.tfEnvVars},
+		{name: "input_var", values: p.inputVars},
+	} {
+		keys := make([]string, 0, len(vars.values))
+		for k := range vars.values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s:%s:%s\n", vars.name, k, hashableValue(vars.values[k]))
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// hashableValue returns a string representation of the value that includes its
+// type, so that values of different types don't have the same representation.
+func hashableValue(val cty.Value) string {
+	b, err := ctyJson.Marshal(val, cty.DynamicPseudoType)
+	if err != nil {
+		return val.GoString()
+	}
+
+	return string(b)
+}
+
 // Path returns the full path that the parser runs within.
 func (p *Parser) Path() string {
 	return p.initialPath
@@ -528,12 +653,38 @@ func (p *Parser) parseDirectoryFiles(files []file) (Blocks, error) {
 }
 
 func (p *Parser) loadVars(blocks Blocks, filenames []string) (map[string]cty.Value, error) {
-	combinedVars := p.tfEnvVars
-	if combinedVars == nil {
-		combinedVars = make(map[string]cty.Value)
+	// copy the env vars so that loading the vars doesn't change the inputs
+	// of the Parser.
+	combinedVars := make(map[string]cty.Value, len(p.tfEnvVars))
+	for k, v := range p.tfEnvVars {
+		combinedVars[k] = v
+	}
+
+	// Variables from var files and inputs take precedence over remote
+	// variables. They're loaded first so that they can be used to configure
+	// the backend that the remote variables are loaded from.
+	localVars := make(map[string]cty.Value)
+	for _, filename := range filenames {
+		err := p.loadAndCombineVars(filename, localVars)
+		if err != nil {
+			return combinedVars, err
+		}
+	}
+
+	for k, v := range p.inputVars {
+		localVars[k] = v
 	}
 
 	if p.remoteVariablesLoader != nil {
+		staticVars := make(map[string]cty.Value, len(combinedVars)+len(localVars))
+		for k, v := range combinedVars {
+			staticVars[k] = v
+		}
+		for k, v := range localVars {
+			staticVars[k] = v
+		}
+		p.setStaticContext(blocks.OfType("terraform"), blocks, staticVars)
+
 		remoteVars, err := p.remoteVariablesLoader.Load(blocks)
 
 		if err != nil {
@@ -541,25 +692,36 @@ func (p *Parser) loadVars(blocks Blocks, filenames []string) (map[string]cty.Val
 			return combinedVars, err
 		}
 
+		p.usesRemoteVariables = len(remoteVars) > 0
 		for k, v := range remoteVars {
 			combinedVars[k] = v
 		}
 	}
 
-	for _, filename := range filenames {
-		err := p.loadAndCombineVars(filename, combinedVars)
-		if err != nil {
-			return combinedVars, err
-		}
-	}
-
-	for k, v := range p.inputVars {
+	for k, v := range localVars {
 		combinedVars[k] = v
 	}
 
 	return combinedVars, nil
 }
 
+// setStaticContext sets the context of the blocks to the static context of
+// the module, so that the backend and cloud configuration can reference
+// variables and locals as supported by OpenTofu.
+func (p *Parser) setStaticContext(targets Blocks, blocks Blocks, vars map[string]cty.Value) {
+	var hclBlocks hcl.Blocks
+	for _, b := range blocks {
+		if b.Type() == "variable" || b.Type() == "locals" {
+			hclBlocks = append(hclBlocks, b.HCLBlock)
+		}
+	}
+
+	ctx := modules.NewStaticContext(hclBlocks, vars, ExpFunctions(p.initialPath, p.logger))
+	for _, b := range targets {
+		b.SetContext(NewContext(ctx, nil, p.logger))
+	}
+}
+
 func (p *Parser) loadAndCombineVars(filename string, combinedVars map[string]cty.Value) error {
 	vars, err := p.loadVarFile(filename)
 	if err != nil {
@@ -647,26 +809,13 @@ func loadDirectory(hclParser *modules.SharedHCLParser, logger zerolog.Logger, fu
 
 	files := make([]file, 0)
 
-	for _, info := range fileInfos {
-		if info.IsDir() {
-			continue
-		}
-
-		var parseFunc func(filename string) (*hcl.File, hcl.Diagnostics)
-		if strings.HasSuffix(info.Name(), ".tf") {
-			parseFunc = hclParser.ParseHCLFile
-		}
-
-		if strings.HasSuffix(info.Name(), ".tf.json") {
+	for _, name := range modules.ConfigFiles(fileInfos) {
+		parseFunc := hclParser.ParseHCLFile
+		if modules.IsJSONConfigFile(name) {
 			parseFunc = hclParser.ParseJSONFile
 		}
 
-		// this is not a file we can parse:
-		if parseFunc == nil {
-			continue
-		}
-
-		path := filepath.Join(fullPath, info.Name())
+		path := filepath.Join(fullPath, name)
 		f, diag := parseFunc(path)
 		if diag != nil && diag.HasErrors() {
 			if stopOnHCLError {
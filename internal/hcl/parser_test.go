@@ -1,6 +1,7 @@
 package hcl
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/sync"
 )
 
@@ -1742,6 +1744,357 @@ resource "aws_instance" "example" {
 	)
 }
 
+func Test_GraphEvaluatesModulesInParallel(t *testing.T) {
+	t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+
+	path := createTestFileWithModule(`
+locals {
+  sizes = ["small", "medium", "large"]
+}
+
+module "a" {
+  source = "../mod"
+  name   = "a"
+  count  = 2
+}
+
+module "b" {
+  source   = "../mod"
+  for_each = toset(local.sizes)
+  name     = each.value
+}
+
+module "c" {
+  source = "../mod"
+  name   = module.a[1].name
+}
+
+module "d" {
+  source = "../mod"
+  name   = "${module.c.name}-${module.b["large"].name}"
+}
+
+resource "aws_instance" "root" {
+  count         = 2
+  instance_type = module.d.instance_types[count.index]
+}
+`,
+		`
+variable "name" {
+  type = string
+}
+
+locals {
+  prefix = "${var.name}-"
+}
+
+resource "aws_instance" "example" {
+  count         = 3
+  instance_type = "${local.prefix}${count.index}"
+
+  dynamic "ebs_block_device" {
+    for_each = [1, 2]
+    content {
+      volume_size = ebs_block_device.value
+    }
+  }
+}
+
+output "name" {
+  value = var.name
+}
+
+output "instance_types" {
+  value = aws_instance.example[*].instance_type
+}
+`,
+		"mod",
+	)
+
+	parse := func(parallelism int) []string {
+		logger := newDiscardLogger()
+		loader := modules.NewModuleLoader(filepath.Dir(path), modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+		parser := NewParser(
+			RootPath{Path: path},
+			CreateEnvFileMatcher([]string{}),
+			loader,
+			logger,
+			OptionWithGraphParallelism(parallelism),
+		)
+		module, err := parser.ParseDirectory()
+		require.NoError(t, err)
+
+		var blocks []string
+		var collect func(m *Module)
+		collect = func(m *Module) {
+			for _, b := range m.Blocks {
+				blocks = append(blocks, fmt.Sprintf("%s: %s", b.FullName(), b.Values().GoString()))
+			}
+
+			for _, child := range m.Modules {
+				collect(child)
+			}
+		}
+		collect(module)
+
+		return blocks
+	}
+
+	expected := parse(1)
+
+	var root string
+	for _, b := range expected {
+		if strings.HasPrefix(b, "aws_instance.root[1]: ") {
+			root = b
+		}
+	}
+	assert.Contains(t, root, `"instance_type":cty.StringVal("a-large-1")`)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, expected, parse(8))
+	}
+}
+
+func Test_MissingVariables(t *testing.T) {
+	path := createTestFileWithModule(`
+variable "instance_type" {
+  description = "The instance type."
+  type        = string
+
+  validation {
+    condition     = startswith(var.instance_type, "t3.")
+    error_message = "Only t3 instances are allowed."
+  }
+}
+
+variable "instance_count" {
+  type = number
+}
+
+variable "disk_sizes" {
+  type = list(number)
+}
+
+variable "owner" {}
+
+variable "ami" {
+  default = "ami-123"
+}
+
+locals {
+  sizes = var.disk_sizes
+}
+
+resource "aws_instance" "web" {
+  count         = var.instance_count
+  ami           = var.ami
+  instance_type = var.instance_type
+}
+
+module "disks" {
+  source = "../mod"
+  sizes  = local.sizes
+  owner  = var.owner
+}
+`,
+		`
+variable "sizes" {
+  type = list(number)
+}
+
+variable "owner" {}
+
+resource "aws_ebs_volume" "data" {
+  count = length(var.sizes)
+  size  = var.sizes[count.index]
+}
+
+output "owner" {
+  value = var.owner
+}
+`,
+		"mod",
+	)
+
+	parse := func() *Module {
+		logger := newDiscardLogger()
+		loader := modules.NewModuleLoader(filepath.Dir(path), modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+		parser := NewParser(
+			RootPath{Path: path},
+			CreateEnvFileMatcher([]string{}),
+			loader,
+			logger,
+		)
+		module, err := parser.ParseDirectory()
+		require.NoError(t, err)
+
+		return module
+	}
+
+	module := parse()
+	require.Len(t, module.MissingVariables, 4)
+
+	vars := make(map[string]MissingVariable)
+	for _, v := range module.MissingVariables {
+		vars[v.Name] = v
+	}
+
+	assert.Equal(t, "The instance type.", vars["instance_type"].Description)
+	assert.Equal(t, "string", vars["instance_type"].TypeString())
+	assert.Equal(t, []string{`startswith(var.instance_type, "t3.") (Only t3 instances are allowed.)`}, vars["instance_type"].Validations)
+	assert.True(t, vars["instance_type"].CostRelevant)
+	assert.True(t, vars["instance_count"].CostRelevant)
+	assert.Equal(t, "list(number)", vars["disk_sizes"].TypeString())
+	assert.True(t, vars["disk_sizes"].CostRelevant)
+	assert.Equal(t, "any", vars["owner"].TypeString())
+	assert.False(t, vars["owner"].CostRelevant)
+
+	_, err := vars["disk_sizes"].Parse(`["a"]`)
+	assert.Error(t, err)
+
+	sizes, err := vars["disk_sizes"].Parse(`[10, 20]`)
+	require.NoError(t, err)
+
+	err = WriteVarFile(PromptedVarFilePath(path), map[string]cty.Value{
+		"instance_type":  cty.StringVal("t3.micro"),
+		"instance_count": cty.NumberIntVal(2),
+		"disk_sizes":     sizes,
+	})
+	require.NoError(t, err)
+
+	module = parse()
+	require.Len(t, module.MissingVariables, 1)
+	assert.Equal(t, "owner", module.MissingVariables[0].Name)
+
+	var instances, volumes int
+	var collect func(m *Module)
+	collect = func(m *Module) {
+		for _, b := range m.Blocks {
+			switch b.TypeLabel() {
+			case "aws_instance":
+				instances++
+				assert.Equal(t, "t3.micro", b.GetAttribute("instance_type").AsString())
+			case "aws_ebs_volume":
+				volumes++
+			}
+		}
+
+		for _, child := range m.Modules {
+			collect(child)
+		}
+	}
+	collect(module)
+
+	assert.Equal(t, 2, instances)
+	assert.Equal(t, 2, volumes)
+}
+
+func Test_FailedConditions(t *testing.T) {
+	path := createTestFileWithModule(`
+variable "instance_type" {
+  type = string
+
+  validation {
+    condition     = startswith(var.instance_type, "t3.")
+    error_message = "Only t3 instances are allowed."
+  }
+}
+
+variable "region" {
+  type = string
+
+  validation {
+    condition     = contains(["us-east-1", "eu-west-1"], var.region)
+    error_message = "Unsupported region."
+  }
+}
+
+variable "env" {
+  type = string
+
+  validation {
+    condition     = var.env != ""
+    error_message = "The env must be set."
+  }
+}
+
+resource "aws_instance" "web" {
+  count         = 2
+  instance_type = var.instance_type
+
+  lifecycle {
+    precondition {
+      condition     = count.index < 1
+      error_message = "Only one instance is allowed."
+    }
+
+    postcondition {
+      condition     = self.public_ip != ""
+      error_message = "The instance must have a public IP."
+    }
+  }
+}
+
+module "disks" {
+  source = "../mod"
+  size   = 5000
+}
+`,
+		`
+variable "size" {
+  type = number
+
+  validation {
+    condition = var.size <= 1000
+  }
+}
+
+resource "aws_ebs_volume" "data" {
+  size = var.size
+}
+`,
+		"mod",
+	)
+
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			logger := newDiscardLogger()
+			loader := modules.NewModuleLoader(filepath.Dir(path), modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: path},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+				OptionWithInputVars(map[string]string{
+					"instance_type": "m5.large",
+					"region":        "us-east-1",
+				}),
+			)
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, []string{
+				"variable.instance_type: Only t3 instances are allowed.",
+				"aws_instance.web[1]: Only one instance is allowed.",
+				"module.disks.variable.size: condition var.size <= 1000 failed",
+			}, module.FailedConditions)
+
+			var diag *schema.ProjectDiag
+			for _, w := range module.Warnings {
+				if w.Message == "Failed Terraform conditions" {
+					diag = w
+				}
+			}
+			require.NotNil(t, diag)
+			assert.ElementsMatch(t, module.FailedConditions, diag.Data)
+		})
+	}
+}
+
 func Test_VariableLengthWhenMocked(t *testing.T) {
 	t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
 
@@ -1778,6 +2131,41 @@ resource "aws_instance" "example" {
 	)
 }
 
+func Test_ProviderFunctions(t *testing.T) {
+	path := createTestFile("main.tf", `
+locals {
+  role = provider::aws::arn_parse("arn:aws:iam::444455556666:role/with/path/example")
+}
+
+resource "aws_instance" "example" {
+  instance_type = templatestring("$${size}.large", { size = "m5" })
+  account_id    = local.role.account_id
+  role          = provider::aws::trim_iam_role_path("arn:aws:iam::444455556666:role/with/path/example")
+  zone          = core::upper(provider::google::region_from_zone("us-central1-b"))
+}
+`,
+	)
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(filepath.Dir(path), modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+	parser := NewParser(
+		RootPath{Path: filepath.Dir(path)},
+		CreateEnvFileMatcher([]string{}),
+		loader,
+		logger,
+	)
+	module, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	resource := module.Blocks.Matching(BlockMatcher{Label: "aws_instance.example"})
+	assertBlockEqualsJSON(
+		t,
+		`{"instance_type":"m5.large", "account_id":"444455556666", "role":"arn:aws:iam::444455556666:role/example", "zone":"US-CENTRAL1"}`,
+		resource.Values(),
+		"id", "arn", "self_link", "name",
+	)
+}
+
 func BenchmarkParserEvaluate(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		b.StopTimer()
@@ -1807,3 +2195,613 @@ func BenchmarkParserEvaluate(b *testing.B) {
 		b.StartTimer()
 	}
 }
+
+func Test_TofuFilesTakePrecedence(t *testing.T) {
+	path := createTestFile("main.tf", `
+resource "aws_instance" "web" {
+	instance_type = "t3.micro"
+}
+`)
+	dir := filepath.Dir(path)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tofu"), []byte(`
+resource "aws_instance" "web" {
+	instance_type = "t3.large"
+}
+`), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db.tofu"), []byte(`
+resource "aws_db_instance" "db" {
+	instance_class = "db.t3.micro"
+}
+`), os.ModePerm))
+
+	logger := newDiscardLogger()
+	loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+	parser := NewParser(
+		RootPath{Path: dir},
+		CreateEnvFileMatcher([]string{}),
+		loader,
+		logger,
+	)
+
+	module, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	resources := module.Blocks.OfType("resource")
+	require.Len(t, resources, 2)
+	assert.Equal(t, "aws_db_instance.db", resources[0].FullName())
+	assert.Equal(t, "aws_instance.web", resources[1].FullName())
+	assert.Equal(t, "t3.large", resources[1].GetAttribute("instance_type").Value().AsString())
+}
+
+func Test_ModuleSourceWithVariables(t *testing.T) {
+	path := createTestFileWithModule(`
+variable "module_dir" {
+	default = "unknown"
+}
+
+locals {
+	source = "../${var.module_dir}"
+}
+
+module "my-mod" {
+	source = local.source
+	input  = "ok"
+}
+
+output "result" {
+	value = module.my-mod.mod_result
+}
+`,
+		`
+variable "input" {}
+
+output "mod_result" {
+	value = var.input
+}
+`,
+		"module",
+	)
+
+	logger := newDiscardLogger()
+	dir := filepath.Dir(path)
+	loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+	parser := NewParser(
+		RootPath{Path: path},
+		CreateEnvFileMatcher([]string{}),
+		loader,
+		logger,
+		OptionWithInputVars(map[string]string{"module_dir": "module"}),
+	)
+
+	rootModule, err := parser.ParseDirectory()
+	require.NoError(t, err)
+	require.Len(t, rootModule.Modules, 1)
+
+	rootOutputs := rootModule.Blocks.OfType("output")
+	require.Len(t, rootOutputs, 1)
+	assert.Equal(t, "ok", rootOutputs[0].GetAttribute("value").Value().AsString())
+}
+
+func Test_ProviderForEach(t *testing.T) {
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			path := createTestFile("main.tofu", `
+variable "regions" {
+	default = ["us-east-1", "eu-west-1"]
+}
+
+provider "aws" {
+	region = "us-west-2"
+}
+
+provider "aws" {
+	alias    = "by_region"
+	for_each = toset(var.regions)
+	region   = each.value
+}
+
+resource "aws_instance" "web" {
+	for_each = toset(var.regions)
+	provider = aws.by_region[each.key]
+}
+`)
+
+			logger := newDiscardLogger()
+			dir := filepath.Dir(path)
+			loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: dir},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+			)
+
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			var instances []string
+			for _, b := range module.Blocks.OfType("provider") {
+				for _, instance := range b.ProviderInstances() {
+					instances = append(instances, instance.Values().GetAttr("config_key").AsString())
+				}
+			}
+			assert.ElementsMatch(t, []string{"aws", `aws.by_region["eu-west-1"]`, `aws.by_region["us-east-1"]`}, instances)
+
+			resources := module.Blocks.OfType("resource")
+			require.Len(t, resources, 2)
+			for _, r := range resources {
+				key := *r.Key()
+				assert.Equal(t, fmt.Sprintf("aws.by_region[%q]", key), r.ProviderConfigKey())
+				assert.Equal(t, key, getRegionFromProvider(r, r.Provider()))
+			}
+		})
+	}
+}
+
+func Test_Refactorings(t *testing.T) {
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			path := createTestFileWithModule(`
+moved {
+	from = aws_instance.old
+	to   = aws_instance.web
+}
+
+moved {
+	from = module.legacy
+	to   = module.app
+}
+
+import {
+	for_each = toset(["a", "b"])
+	to       = aws_instance.imported[each.key]
+	id       = each.key
+}
+
+removed {
+	from = aws_instance.kept
+
+	lifecycle {
+		destroy = false
+	}
+}
+
+removed {
+	from = aws_instance.deleted
+}
+
+resource "aws_instance" "web" {}
+
+resource "aws_instance" "imported" {
+	for_each = toset(["a", "b"])
+}
+
+module "app" {
+	source = "../module"
+}
+`,
+				`
+moved {
+	from = aws_instance.old
+	to   = aws_instance.app["x"]
+}
+
+resource "aws_instance" "app" {
+	for_each = toset(["x"])
+}
+`,
+				"module",
+			)
+
+			logger := newDiscardLogger()
+			dir := filepath.Dir(path)
+			loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: path},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+			)
+
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			r := module.Refactorings()
+			assert.Equal(t, "aws_instance.old", r.PreviousAddress("aws_instance.web"))
+			assert.Equal(t, "module.legacy.aws_instance.old", r.PreviousAddress(`module.app.aws_instance.app["x"]`))
+			assert.Equal(t, "", r.PreviousAddress(`aws_instance.imported["a"]`))
+			assert.True(t, r.IsImported(`aws_instance.imported["a"]`))
+			assert.False(t, r.IsImported("aws_instance.web"))
+			assert.Equal(t, []string{"aws_instance.kept"}, r.Forgotten())
+		})
+	}
+}
+
+func Test_EphemeralAndCheckBlocks(t *testing.T) {
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			path := createTestFile("main.tf", `
+ephemeral "random_password" "db" {
+	length = 16
+}
+
+ephemeral "aws_ssm_parameter" "config" {
+	for_each = toset(["a", "b"])
+	name     = "config-${each.key}"
+}
+
+resource "aws_db_instance" "db" {
+	instance_class      = "db.t3.micro"
+	password_wo         = ephemeral.random_password.db.result
+	password_wo_version = 1
+	engine              = ephemeral.aws_ssm_parameter.config["a"].name
+}
+
+check "health" {
+	data "http" "health" {
+		url = "https://example.com"
+	}
+
+	assert {
+		condition     = data.http.health.status_code == 200
+		error_message = "unhealthy"
+	}
+}
+`)
+
+			logger := newDiscardLogger()
+			dir := filepath.Dir(path)
+			loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: dir},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+			)
+
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			assert.Empty(t, module.Blocks.OfType("data"))
+			assert.Len(t, module.Blocks.OfType("ephemeral"), 3)
+
+			resources := module.Blocks.OfType("resource")
+			require.Len(t, resources, 1)
+			assert.Equal(t, "config-a", resources[0].GetAttribute("engine").Value().AsString())
+			assert.Equal(t, "db.t3.micro", resources[0].GetAttribute("instance_class").Value().AsString())
+		})
+	}
+}
+
+func Test_DataMocks(t *testing.T) {
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			path := createTestFile("main.tf", `
+variable "state_bucket" {
+	default = "acme-terraform-state"
+}
+
+data "aws_ami" "ubuntu" {
+	most_recent = true
+}
+
+data "aws_ssm_parameter" "instance_type" {
+	name = "/app/instance_type"
+}
+
+data "aws_ssm_parameter" "count" {
+	name = "/app/count"
+}
+
+data "aws_caller_identity" "current" {}
+
+data "terraform_remote_state" "network" {
+	backend = "s3"
+	config = {
+		bucket = var.state_bucket
+		key    = "network/terraform.tfstate"
+	}
+}
+
+resource "aws_instance" "web" {
+	count         = data.aws_ssm_parameter.count.value
+	ami           = data.aws_ami.ubuntu.id
+	instance_type = data.aws_ssm_parameter.instance_type.value
+	subnet_id     = data.terraform_remote_state.network.outputs.subnet_ids[0]
+}
+`)
+			dir := filepath.Dir(path)
+			mocksPath := filepath.Join(dir, "mocks.yml")
+			err := os.WriteFile(mocksPath, []byte(`
+data:
+  data.aws_ami.ubuntu:
+    id: ami-0c55b159cbfafe1f0
+  aws_ssm_parameter:
+    value: t3.large
+  data.aws_ssm_parameter.count:
+    value: 2
+remote_state:
+  - backend: s3
+    config:
+      bucket: other-state
+    outputs:
+      subnet_ids: [subnet-other]
+  - backend: s3
+    config:
+      bucket: acme-terraform-state
+      key: network/terraform.tfstate
+    outputs:
+      subnet_ids: [subnet-0a1b2c3d]
+`), os.ModePerm)
+			require.NoError(t, err)
+
+			mocks, err := LoadDataMocks(mocksPath)
+			require.NoError(t, err)
+
+			logger := newDiscardLogger()
+			loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: dir},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+				OptionWithDataMocks(mocks),
+			)
+
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			resources := module.Blocks.OfType("resource")
+			require.Len(t, resources, 2)
+			for _, r := range resources {
+				assert.Equal(t, "ami-0c55b159cbfafe1f0", r.GetAttribute("ami").Value().AsString())
+				assert.Equal(t, "t3.large", r.GetAttribute("instance_type").Value().AsString())
+				assert.Equal(t, "subnet-0a1b2c3d", r.GetAttribute("subnet_id").Value().AsString())
+			}
+
+			supplied, mocked := module.DataSources()
+			assert.Equal(t, []string{
+				"data.aws_ami.ubuntu",
+				"data.aws_ssm_parameter.count",
+				"data.aws_ssm_parameter.instance_type",
+				"data.terraform_remote_state.network",
+			}, supplied)
+			assert.Equal(t, []string{"data.aws_caller_identity.current"}, mocked)
+		})
+	}
+}
+
+func Test_RemoteStates(t *testing.T) {
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			path := createTestFile("main.tf", `
+data "terraform_remote_state" "network" {
+	backend = "local"
+	config = {
+		path = "network.tfstate"
+	}
+}
+
+data "terraform_remote_state" "cluster" {
+	backend = "s3"
+	config = {
+		bucket = "acme-terraform-state"
+		key    = "cluster/terraform.tfstate"
+	}
+}
+
+data "terraform_remote_state" "unknown" {
+	backend = "s3"
+	config = {
+		bucket = "acme-terraform-state"
+		key    = "unknown/terraform.tfstate"
+	}
+}
+
+resource "aws_eks_node_group" "workers" {
+	subnet_ids = data.terraform_remote_state.network.outputs.subnet_ids
+	scaling_config {
+		desired_size = data.terraform_remote_state.cluster.outputs.node_count
+	}
+}
+`)
+			dir := filepath.Dir(path)
+			err := os.WriteFile(filepath.Join(dir, "network.tfstate"), []byte(`{
+	"version": 4,
+	"outputs": {
+		"subnet_ids": {"value": ["subnet-a", "subnet-b"], "type": ["list", "string"]}
+	}
+}`), os.ModePerm)
+			require.NoError(t, err)
+
+			clusterOutputs := filepath.Join(dir, "cluster.json")
+			err = os.WriteFile(clusterOutputs, []byte(`{
+	"node_count": {"sensitive": false, "type": "number", "value": 5}
+}`), os.ModePerm)
+			require.NoError(t, err)
+
+			logger := newDiscardLogger()
+			loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: dir},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+				OptionWithRemoteStates(RemoteState{
+					Backend: "s3",
+					Config:  map[string]string{"key": "cluster/terraform.tfstate"},
+					Outputs: func() (cty.Value, error) {
+						return LoadStateOutputs(clusterOutputs)
+					},
+				}),
+			)
+
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			resources := module.Blocks.OfType("resource")
+			require.Len(t, resources, 1)
+			assert.Equal(t, cty.ListVal([]cty.Value{cty.StringVal("subnet-a"), cty.StringVal("subnet-b")}), resources[0].GetAttribute("subnet_ids").Value())
+
+			scaling := resources[0].GetChildBlock("scaling_config")
+			require.NotNil(t, scaling)
+			assert.Equal(t, int64(5), scaling.GetAttribute("desired_size").AsInt())
+
+			supplied, mocked := module.DataSources()
+			assert.Equal(t, []string{"data.terraform_remote_state.cluster", "data.terraform_remote_state.network"}, supplied)
+			assert.Equal(t, []string{"data.terraform_remote_state.unknown"}, mocked)
+		})
+	}
+}
+
+func Test_Provenance(t *testing.T) {
+	for _, graph := range []bool{false, true} {
+		t.Run(fmt.Sprintf("graph=%t", graph), func(t *testing.T) {
+			if graph {
+				t.Setenv("INFRACOST_GRAPH_EVALUATOR", "true")
+			}
+
+			path := createTestFileWithModule(`
+variable "instance_type" {}
+
+variable "volume_size" {
+	default = 50
+}
+
+data "aws_ami" "ubuntu" {}
+
+locals {
+	ami = data.aws_ami.ubuntu.id
+}
+
+module "sizes" {
+	source = "../module"
+	size   = var.volume_size
+	image  = local.ami
+}
+
+resource "aws_subnet" "main" {}
+
+resource "aws_instance" "web" {
+	ami           = local.ami
+	instance_type = var.instance_type
+	subnet_id     = aws_subnet.main.id
+	monitoring    = true
+	key_name      = "deploy-mock-key"
+	user_data     = module.sizes.image
+	tenancy       = "host-mock"
+
+	root_block_device {
+		volume_size = var.volume_size
+	}
+
+	ebs_block_device {
+		volume_size = module.sizes.size
+	}
+}
+`,
+				`
+variable "size" {}
+
+variable "image" {}
+
+resource "aws_ebs_volume" "data" {
+	size = var.size
+}
+
+output "size" {
+	value = var.size
+}
+
+output "image" {
+	value = var.image
+}
+`,
+				"module",
+			)
+
+			logger := newDiscardLogger()
+			dir := filepath.Dir(path)
+			loader := modules.NewModuleLoader(dir, modules.NewSharedHCLParser(), nil, config.TerraformSourceMap{}, logger, &sync.KeyMutex{})
+			parser := NewParser(
+				RootPath{Path: path},
+				CreateEnvFileMatcher([]string{}),
+				loader,
+				logger,
+				OptionWithInputVars(map[string]string{"instance_type": "t3.large"}),
+			)
+
+			module, err := parser.ParseDirectory()
+			require.NoError(t, err)
+
+			var web *Block
+			for _, b := range module.Blocks.OfType("resource") {
+				if b.TypeLabel() == "aws_instance" {
+					web = b
+				}
+			}
+			require.NotNil(t, web)
+
+			assert.Equal(t, map[string]schema.Provenance{
+				"ami":                             schema.ProvenanceMocked,
+				"instance_type":                   schema.ProvenanceVarFile,
+				"subnet_id":                       schema.ProvenanceMocked,
+				"root_block_device.0.volume_size": schema.ProvenanceDefault,
+				"ebs_block_device.0.volume_size":  schema.ProvenanceModuleOutput,
+				"user_data":                       schema.ProvenanceMocked,
+			}, module.Provenance(web))
+
+			require.Len(t, module.Modules, 1)
+			child := module.Modules[0]
+			volumes := child.Blocks.OfType("resource")
+			require.Len(t, volumes, 1)
+			assert.Equal(t, map[string]schema.Provenance{
+				"size": schema.ProvenanceDefault,
+			}, child.Provenance(volumes[0]))
+		})
+	}
+}
+
+func Test_isMockedValue(t *testing.T) {
+	tests := []struct {
+		value cty.Value
+		want  bool
+	}{
+		{cty.StringVal("ami-mock"), true},
+		{cty.StringVal("vpc_id-mock"), true},
+		{cty.StringVal("subnet_ids-0-mock"), true},
+		{cty.StringVal("my-mock-server"), false},
+		{cty.StringVal("ami-mockingbird"), false},
+		{cty.StringVal("-mock"), false},
+		{cty.StringVal("t3.medium"), false},
+		{cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("id-mock")}), true},
+		{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("db-mockup")}), false},
+		{cty.NullVal(cty.String), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value.GoString(), func(t *testing.T) {
+			assert.Equal(t, tt.want, isMockedValue(tt.value))
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/zclconf/go-cty/cty/gocty"
 
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl/modules"
 )
 
 var (
@@ -1221,6 +1222,11 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int) {
 		return
 	}
 
+	configFiles := make(map[string]struct{})
+	for _, name := range modules.ConfigFiles(fileInfos) {
+		configFiles[name] = struct{}{}
+	}
+
 	for _, info := range fileInfos {
 		if info.IsDir() {
 			continue
@@ -1229,12 +1235,11 @@ func (p *ProjectLocator) walkPaths(fullPath string, level int) {
 		var parseFunc func(filename string) (*hcl.File, hcl.Diagnostics)
 		name := info.Name()
 
-		if strings.HasSuffix(name, ".tf") {
+		if _, ok := configFiles[name]; ok {
 			parseFunc = hclParser.ParseHCLFile
-		}
-
-		if strings.HasSuffix(name, ".tf.json") {
-			parseFunc = hclParser.ParseJSONFile
+			if modules.IsJSONConfigFile(name) {
+				parseFunc = hclParser.ParseJSONFile
+			}
 		}
 
 		if p.isTerraformVarFile(name) {
@@ -1311,6 +1316,14 @@ func (p *ProjectLocator) shallowDecodeTerraformBlocks(fullPath string, files map
 	var hasProviderBlock bool
 	var hasTerraformBackendBlock bool
 
+	// Module sources can reference variables and locals with OpenTofu, so
+	// evaluate them with the default values of the variables.
+	hclFiles := make([]*hcl.File, 0, len(files))
+	for _, file := range files {
+		hclFiles = append(hclFiles, file)
+	}
+	staticCtx := modules.NewStaticContext(modules.StaticBlocks(hclFiles), nil, nil)
+
 	for _, file := range files {
 		body, content, diags := file.Body.PartialContent(terraformAndProviderBlocks)
 		if diags != nil && diags.HasErrors() {
@@ -1336,7 +1349,7 @@ func (p *ProjectLocator) shallowDecodeTerraformBlocks(fullPath string, files map
 		for _, module := range moduleBody.Blocks {
 			a, _ := module.Body.JustAttributes()
 			if src, ok := a["source"]; ok {
-				val, _ := src.Expr.Value(nil)
+				val, _ := src.Expr.Value(staticCtx)
 
 				if val.Type() != cty.String {
 					p.logger.Debug().Str("module", strings.Join(module.Labels, ".")).Msgf("got unexpected cty value for module source string in file %s", file)
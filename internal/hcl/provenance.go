@@ -0,0 +1,263 @@
+package hcl
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// provenanceSkipAttributes are meta-arguments that don't end up as values of
+// the resource.
+var provenanceSkipAttributes = map[string]struct{}{
+	"count":      {},
+	"for_each":   {},
+	"provider":   {},
+	"depends_on": {},
+}
+
+// Provenance returns where the values of the attributes of the Block b came
+// from, keyed by the attribute path, e.g. root_block_device.0.volume_size.
+// b must be one of the Module's Blocks. References to variables, locals and
+// resources are followed so that, for example, an attribute set from a local
+// that uses a mocked data source value is mocked. Attributes that are set
+// literally are omitted.
+func (m *Module) Provenance(b *Block) map[string]schema.Provenance {
+	r := provenanceResolver{visiting: make(map[*Attribute]struct{})}
+	out := make(map[string]schema.Provenance)
+	r.block(m, b, "", out)
+
+	return out
+}
+
+type provenanceResolver struct {
+	// visiting guards against following circular references forever.
+	visiting map[*Attribute]struct{}
+}
+
+func (r provenanceResolver) block(m *Module, b *Block, prefix string, out map[string]schema.Provenance) {
+	for _, attr := range b.GetAttributes() {
+		if _, ok := provenanceSkipAttributes[attr.Name()]; ok {
+			continue
+		}
+
+		p := r.attribute(m, attr)
+		if p != schema.ProvenanceLiteral {
+			out[prefix+attr.Name()] = p
+		}
+	}
+
+	indexes := map[string]int{}
+	for _, child := range b.Children() {
+		t := child.Type()
+		if t == "dynamic" || t == "lifecycle" {
+			continue
+		}
+
+		r.block(m, child, fmt.Sprintf("%s%s.%d.", prefix, t, indexes[t]), out)
+		indexes[t]++
+	}
+}
+
+func (r provenanceResolver) attribute(m *Module, attr *Attribute) schema.Provenance {
+	if _, ok := r.visiting[attr]; ok {
+		return schema.ProvenanceLiteral
+	}
+	r.visiting[attr] = struct{}{}
+	defer delete(r.visiting, attr)
+
+	val, _ := attr.Value().UnmarkDeep()
+	if !val.IsWhollyKnown() {
+		return schema.ProvenanceUnknown
+	}
+
+	if attr.mocked {
+		return schema.ProvenanceMocked
+	}
+
+	// a value without references is set literally, even if it looks like a
+	// mock value.
+	traversals := attr.HCLAttr.Expr.Variables()
+	if len(traversals) > 0 && isMockedValue(val) {
+		return schema.ProvenanceMocked
+	}
+
+	p := schema.ProvenanceLiteral
+	for _, traversal := range traversals {
+		p = p.LeastCertain(r.reference(m, traversal))
+	}
+
+	return p
+}
+
+func (r provenanceResolver) reference(m *Module, traversal hcl.Traversal) schema.Provenance {
+	switch traversal.RootName() {
+	case "var":
+		return r.variable(m, traversalAttrName(traversal, 1))
+	case "local":
+		return r.local(m, traversalAttrName(traversal, 1))
+	case "module":
+		return r.moduleOutput(m, traversalAttrName(traversal, 1), traversalAttrName(traversal, 2))
+	case "data":
+		b := findBlockByLocalName(m, fmt.Sprintf("data.%s.%s", traversalAttrName(traversal, 1), traversalAttrName(traversal, 2)))
+		if b == nil {
+			return schema.ProvenanceMocked
+		}
+
+		return dataSourceProvenance(b)
+	case "count", "each", "path", "terraform", "self":
+		return schema.ProvenanceLiteral
+	}
+
+	b := findBlockByLocalName(m, fmt.Sprintf("%s.%s", traversal.RootName(), traversalAttrName(traversal, 1)))
+	if b == nil {
+		return schema.ProvenanceUnknown
+	}
+
+	// attributes that aren't in the config, e.g. ids, are generated by the
+	// cloud provider so we only have placeholder values for them.
+	name := traversalAttrName(traversal, 2)
+	attr := b.GetAttribute(name)
+	if _, synthetic := b.UniqueAttrs[name]; attr == nil || synthetic {
+		return schema.ProvenanceMocked
+	}
+
+	return r.attribute(m, attr)
+}
+
+func (r provenanceResolver) variable(m *Module, name string) schema.Provenance {
+	for _, b := range m.Blocks.OfType("variable") {
+		if b.Label() != name {
+			continue
+		}
+
+		if _, ok := m.inputVars[name]; ok {
+			if m.Parent == nil || !b.HasModuleBlock() {
+				return schema.ProvenanceVarFile
+			}
+
+			// the input of a child module comes from the module call in
+			// the parent.
+			if attr := b.moduleBlock.GetAttribute(name); attr != nil {
+				return r.attribute(m.Parent, attr)
+			}
+
+			return schema.ProvenanceVarFile
+		}
+
+		if b.GetAttribute("default") != nil {
+			return schema.ProvenanceDefault
+		}
+
+		break
+	}
+
+	return schema.ProvenanceUnknown
+}
+
+func (r provenanceResolver) local(m *Module, name string) schema.Provenance {
+	for _, b := range m.Blocks.OfType("locals") {
+		if attr := b.GetAttribute(name); attr != nil {
+			return r.attribute(m, attr)
+		}
+	}
+
+	return schema.ProvenanceUnknown
+}
+
+// moduleOutput returns the provenance of the output of the module call, which
+// is the least certain of the module output and the provenance of the output's
+// value in the child module, e.g. so that an output that passes through a
+// mocked value is mocked.
+func (r provenanceResolver) moduleOutput(m *Module, name string, output string) schema.Provenance {
+	call := findBlockByLocalName(m, "module."+name)
+	if call == nil {
+		return schema.ProvenanceModuleOutput
+	}
+
+	for _, child := range m.Modules {
+		if child.Name != call.FullName() {
+			continue
+		}
+
+		for _, b := range child.Blocks.OfType("output") {
+			if b.Label() != output {
+				continue
+			}
+
+			if attr := b.GetAttribute("value"); attr != nil {
+				return schema.ProvenanceModuleOutput.LeastCertain(r.attribute(child, attr))
+			}
+		}
+	}
+
+	return schema.ProvenanceModuleOutput
+}
+
+// dataSourceProvenance returns the provenance of the values of the data
+// block b, which depends on whether they were supplied by the user or
+// computed rather than mocked.
+func dataSourceProvenance(b *Block) schema.Provenance {
+	if isSuppliedDataSource(b) || isComputedDataSource(b) {
+		return schema.ProvenanceDataSource
+	}
+
+	return schema.ProvenanceMocked
+}
+
+// findBlockByLocalName returns the first Block in the Module with the local
+// name, ignoring any count or for_each keys.
+func findBlockByLocalName(m *Module, name string) *Block {
+	for _, b := range m.Blocks {
+		if b.LocalName() == name || modArrayPartReplace.ReplaceAllString(b.LocalName(), "") == name {
+			return b
+		}
+	}
+
+	return nil
+}
+
+func traversalAttrName(traversal hcl.Traversal, i int) string {
+	if len(traversal) <= i {
+		return ""
+	}
+
+	if attr, ok := traversal[i].(hcl.TraverseAttr); ok {
+		return attr.Name
+	}
+
+	return ""
+}
+
+// mockValueRe matches the values that an Attribute is set to when it can't
+// be evaluated, which are the attribute name followed by mockSuffix, e.g.
+// ami-mock.
+var mockValueRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*` + regexp.QuoteMeta(mockSuffix) + `$`)
+
+// isMockedValue returns true if the value is, or contains, a value that was
+// mocked by an Attribute because it could not be evaluated. Strings must
+// match the mock value exactly, so literals that happen to contain the
+// mockSuffix aren't treated as mocked.
+func isMockedValue(val cty.Value) bool {
+	if val.IsNull() || !val.IsKnown() {
+		return false
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return mockValueRe.MatchString(val.AsString())
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType() || ty.IsMapType() || ty.IsObjectType():
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if isMockedValue(v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
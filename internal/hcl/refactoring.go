@@ -0,0 +1,182 @@
+package hcl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// refactoringBlockTypes are the block types that change how existing
+// resources map onto the configuration rather than describing resources.
+var refactoringBlockTypes = []string{"moved", "import", "removed"}
+
+// Move is an address change declared with a `moved` block.
+type Move struct {
+	From string
+	To   string
+}
+
+// Removal is declared with a `removed` block. Destroy is false when the
+// resource is only removed from state and continues to exist.
+type Removal struct {
+	From    string
+	Destroy bool
+}
+
+// Refactorings holds the `moved`, `import` and `removed` blocks declared in a
+// Module and its child modules. All addresses are absolute.
+type Refactorings struct {
+	Moves    []Move
+	Imports  []string
+	Removals []Removal
+}
+
+// PreviousAddress returns the address the resource at addr had before any
+// of the moves were applied, or "" if it has not been moved. Chained moves are
+// followed back to the original address, undoing the most specific move first
+// so that moves inside a module are undone before the module itself is moved.
+func (r Refactorings) PreviousAddress(addr string) string {
+	prev := addr
+
+	for i := 0; i < len(r.Moves); i++ {
+		var match *Move
+		for j, m := range r.Moves {
+			if schema.AddressWithin(prev, m.To) && (match == nil || len(m.To) > len(match.To)) {
+				match = &r.Moves[j]
+			}
+		}
+
+		if match == nil {
+			break
+		}
+
+		prev = match.From + prev[len(match.To):]
+	}
+
+	if prev == addr {
+		return ""
+	}
+
+	return prev
+}
+
+// IsImported returns true if the resource at addr is the target of an
+// `import` block.
+func (r Refactorings) IsImported(addr string) bool {
+	for _, to := range r.Imports {
+		if schema.AddressWithin(addr, to) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Forgotten returns the addresses of the `removed` blocks that remove
+// resources from state without destroying them.
+func (r Refactorings) Forgotten() []string {
+	var addrs []string
+	for _, removal := range r.Removals {
+		if !removal.Destroy {
+			addrs = append(addrs, removal.From)
+		}
+	}
+
+	return addrs
+}
+
+// Refactorings returns the `moved`, `import` and `removed` blocks declared in
+// the Module and all of its child modules.
+func (m *Module) Refactorings() Refactorings {
+	var r Refactorings
+	m.collectRefactorings(&r)
+
+	return r
+}
+
+func (m *Module) collectRefactorings(r *Refactorings) {
+	for _, b := range m.Blocks {
+		switch b.Type() {
+		case "moved":
+			from, fromOk := b.GetAttribute("from").AsAddress()
+			to, toOk := b.GetAttribute("to").AsAddress()
+			if fromOk && toOk {
+				r.Moves = append(r.Moves, Move{From: m.absoluteAddress(from), To: m.absoluteAddress(to)})
+			}
+		case "import":
+			if to, ok := b.GetAttribute("to").AsAddress(); ok {
+				r.Imports = append(r.Imports, m.absoluteAddress(to))
+			}
+		case "removed":
+			from, ok := b.GetAttribute("from").AsAddress()
+			if !ok {
+				continue
+			}
+
+			destroy := true
+			if attr := b.GetChildBlock("lifecycle").GetAttribute("destroy"); attr != nil {
+				v := attr.Value()
+				if v.IsKnown() && !v.IsNull() && v.Type() == cty.Bool {
+					destroy = v.True()
+				}
+			}
+
+			r.Removals = append(r.Removals, Removal{From: m.absoluteAddress(from), Destroy: destroy})
+		}
+	}
+
+	for _, child := range m.Modules {
+		child.collectRefactorings(r)
+	}
+}
+
+func (m *Module) absoluteAddress(addr string) string {
+	if m.Name == "" {
+		return addr
+	}
+
+	return m.Name + "." + addr
+}
+
+// AsAddress returns the attribute expression as a resource or module address,
+// e.g. `module.app.aws_instance.web["a"]`. This is used for the attributes of
+// `moved`, `import` and `removed` blocks which refer to objects rather than
+// their values. If the last index of the address is dynamic, e.g. when an
+// `import` block uses for_each, the address without the index is returned.
+func (attr *Attribute) AsAddress() (string, bool) {
+	if attr == nil {
+		return "", false
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(attr.HCLAttr.Expr)
+	if diags.HasErrors() {
+		index, ok := attr.HCLAttr.Expr.(*hclsyntax.IndexExpr)
+		if !ok {
+			return "", false
+		}
+
+		traversal, diags = hcl.AbsTraversalForExpr(index.Collection)
+		if diags.HasErrors() {
+			return "", false
+		}
+	}
+
+	var sb strings.Builder
+	for _, p := range traversal {
+		switch part := p.(type) {
+		case hcl.TraverseRoot:
+			sb.WriteString(part.Name)
+		case hcl.TraverseAttr:
+			sb.WriteString("." + part.Name)
+		case hcl.TraverseIndex:
+			sb.WriteString(fmt.Sprintf("[%s]", attr.getIndexValue(part)))
+		}
+	}
+
+	return sb.String(), true
+}
@@ -167,8 +167,18 @@ var TypeTerraform = Type{
 	name: "terraform",
 }
 
+var TypeEphemeral = Type{
+	name: "ephemeral",
+}
+
+var TypeCheck = Type{
+	name: "check",
+}
+
 var ValidTypes = []Type{
+	TypeCheck,
 	TypeData,
+	TypeEphemeral,
 	TypeLocal,
 	TypeModule,
 	TypeOutput,
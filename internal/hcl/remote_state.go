@@ -0,0 +1,206 @@
+package hcl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+)
+
+// RemoteState maps the backend configuration of terraform_remote_state data
+// sources to the outputs of the state that they read. This allows remote
+// state to be resolved offline, e.g. from a local copy of the state file.
+type RemoteState struct {
+	// Backend is the backend type, e.g. s3. An empty Backend matches any type.
+	Backend string
+	// Config holds the backend config values that a terraform_remote_state
+	// data source must have to match, e.g. the bucket and key.
+	Config map[string]string
+	// Outputs returns the outputs of the state. It is only called once the
+	// first matching data source is evaluated.
+	Outputs func() (cty.Value, error)
+}
+
+// remoteStateOutputs wraps a RemoteState so that its outputs are only loaded
+// once no matter how many times matching data sources are evaluated.
+type remoteStateOutputs struct {
+	RemoteState
+
+	once    sync.Once
+	outputs cty.Value
+	err     error
+}
+
+func (rs *remoteStateOutputs) value() (cty.Value, error) {
+	rs.once.Do(func() {
+		rs.outputs, rs.err = rs.Outputs()
+	})
+
+	return rs.outputs, rs.err
+}
+
+// matches returns true if the terraform_remote_state block b uses the
+// backend of the RemoteState and has all of its config values.
+func (rs *remoteStateOutputs) matches(b *Block) bool {
+	if rs.Backend != "" && b.GetAttribute("backend").AsString() != rs.Backend {
+		return false
+	}
+
+	values := remoteStateConfig(b)
+	for k, want := range rs.Config {
+		v, ok := values[k]
+		if !ok || !v.IsKnown() || v.IsNull() {
+			return false
+		}
+
+		str, err := convert.Convert(v, cty.String)
+		if err != nil || str.AsString() != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *DataMocks) addRemoteStates(states ...RemoteState) {
+	for _, rs := range states {
+		m.remoteStates = append(m.remoteStates, &remoteStateOutputs{RemoteState: rs})
+	}
+}
+
+// OptionWithRemoteStates sets the RemoteStates that are used to resolve the
+// outputs of terraform_remote_state data sources. Values for remote state in
+// the data mocks take precedence if OptionWithDataMocks is also used.
+func OptionWithRemoteStates(states ...RemoteState) Option {
+	return func(p *Parser) {
+		if len(states) == 0 {
+			return
+		}
+
+		if p.blockBuilder.DataMocks == nil {
+			p.blockBuilder.DataMocks = &DataMocks{}
+		}
+
+		p.blockBuilder.DataMocks.addRemoteStates(states...)
+	}
+}
+
+// LoadStateOutputs returns the outputs from the file at path. The file can
+// either be a Terraform state file or the output of `terraform output -json`.
+func LoadStateOutputs(path string) (cty.Value, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("could not read state file %s: %w", path, err)
+	}
+
+	var state struct {
+		Version *int                       `json:"version"`
+		Outputs map[string]json.RawMessage `json:"outputs"`
+	}
+	err = json.Unmarshal(b, &state)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("could not parse state file %s: %w", path, err)
+	}
+
+	outputs := state.Outputs
+	if state.Version == nil {
+		// `terraform output -json` has the outputs at the top level.
+		err = json.Unmarshal(b, &outputs)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("could not parse outputs file %s: %w", path, err)
+		}
+	}
+
+	values := make(map[string]cty.Value, len(outputs))
+	for name, raw := range outputs {
+		v, err := stateOutputValue(raw)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("could not parse output %s in %s: %w", name, path, err)
+		}
+
+		values[name] = v
+	}
+
+	return cty.ObjectVal(values), nil
+}
+
+func stateOutputValue(raw json.RawMessage) (cty.Value, error) {
+	var output struct {
+		Value json.RawMessage `json:"value"`
+		Type  json.RawMessage `json:"type"`
+	}
+	err := json.Unmarshal(raw, &output)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	if len(output.Value) == 0 {
+		return cty.NilVal, errors.New("missing value")
+	}
+
+	var ty cty.Type
+	if len(output.Type) > 0 {
+		ty, err = ctyJson.UnmarshalType(output.Type)
+	} else {
+		ty, err = ctyJson.ImpliedType(output.Value)
+	}
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyJson.Unmarshal(output.Value, ty)
+}
+
+// terraformRemoteStateValues returns the outputs of terraform_remote_state
+// data sources that use the local backend, as these can be read from the
+// filesystem without any credentials.
+func terraformRemoteStateValues(b *Block) cty.Value {
+	values := b.values()
+
+	outputs, err := localRemoteStateOutputs(b)
+	if err != nil {
+		b.logger.Debug().Err(err).Msgf("could not resolve remote state for %s", b.FullName())
+		return values
+	}
+
+	return mergeObjects(values, cty.ObjectVal(map[string]cty.Value{"outputs": outputs}))
+}
+
+func localRemoteStateOutputs(b *Block) (cty.Value, error) {
+	if b.GetAttribute("backend").AsString() != "local" {
+		return cty.NilVal, errors.New("remote state does not use the local backend")
+	}
+
+	path := "terraform.tfstate"
+	if v, ok := remoteStateConfig(b)["path"]; ok && v.IsKnown() && v.Type() == cty.String {
+		path = v.AsString()
+	}
+
+	// local backend paths are relative to the root module, which is where
+	// Terraform would be run from.
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(b.rootPath, path)
+	}
+
+	return LoadStateOutputs(path)
+}
+
+func remoteStateConfig(b *Block) map[string]cty.Value {
+	attr := b.GetAttribute("config")
+	if attr == nil {
+		return nil
+	}
+
+	config := attr.Value()
+	if !config.IsKnown() || config.IsNull() || !config.CanIterateElements() {
+		return nil
+	}
+
+	return config.AsValueMap()
+}
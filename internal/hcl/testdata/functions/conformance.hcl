@@ -0,0 +1,714 @@
+# Each case evaluates expr and compares the value with want, which is the result
+# that Terraform gives for the same expression in `terraform console`. Cases
+# with error = true must fail to evaluate, as they do in Terraform. The label is
+# the name of the function that the case covers. The locals can be referenced
+# in the expressions. Paths are relative to this directory.
+
+locals {
+  greeting = "Hello, $${name}!"
+}
+
+case "abs" {
+  expr = abs(-12.4)
+  want = 12.4
+}
+
+case "abspath" {
+  expr = abspath("/tmp/../hello.txt")
+  want = "/hello.txt"
+}
+
+case "alltrue" {
+  expr = alltrue(["true", true])
+  want = true
+}
+
+case "alltrue" {
+  expr = alltrue([true, false])
+  want = false
+}
+
+case "anytrue" {
+  expr = anytrue([false, true])
+  want = true
+}
+
+case "anytrue" {
+  expr = anytrue([])
+  want = false
+}
+
+case "base64decode" {
+  expr = base64decode("SGVsbG8gV29ybGQ=")
+  want = "Hello World"
+}
+
+case "base64encode" {
+  expr = base64encode("Hello World")
+  want = "SGVsbG8gV29ybGQ="
+}
+
+case "base64sha256" {
+  expr = base64sha256("hello world")
+  want = "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="
+}
+
+case "base64sha512" {
+  expr = base64sha512("hello world")
+  want = "MJ7MSJwS1utMxA9QyQLytNDtd+5RGnx6m808qG1M2G+YndNbxf9JlnDaNCVbRbDP2DDoH2Bdz33FVC6TrpzXbw=="
+}
+
+case "basename" {
+  expr = basename("foo/bar/baz.txt")
+  want = "baz.txt"
+}
+
+case "can" {
+  expr = can(tonumber("nope"))
+  want = false
+}
+
+case "ceil" {
+  expr = ceil(5.1)
+  want = 6
+}
+
+case "chomp" {
+  expr = chomp("hello\n")
+  want = "hello"
+}
+
+case "chunklist" {
+  expr = chunklist(["a", "b", "c", "d", "e"], 2)
+  want = [["a", "b"], ["c", "d"], ["e"]]
+}
+
+case "cidrhost" {
+  expr = cidrhost("10.12.112.0/20", 16)
+  want = "10.12.112.16"
+}
+
+case "cidrnetmask" {
+  expr = cidrnetmask("172.16.0.0/12")
+  want = "255.240.0.0"
+}
+
+case "cidrsubnet" {
+  expr = cidrsubnet("172.16.0.0/12", 4, 2)
+  want = "172.18.0.0/16"
+}
+
+case "cidrsubnets" {
+  expr = cidrsubnets("10.1.0.0/16", 4, 4, 8, 4)
+  want = ["10.1.0.0/20", "10.1.16.0/20", "10.1.32.0/24", "10.1.48.0/20"]
+}
+
+case "coalesce" {
+  expr = coalesce("", "b")
+  want = "b"
+}
+
+case "coalescelist" {
+  expr = coalescelist([], ["c"])
+  want = ["c"]
+}
+
+case "compact" {
+  expr = compact(["a", "", "b", null])
+  want = ["a", "b"]
+}
+
+case "concat" {
+  expr = concat(["a"], ["b", "c"])
+  want = ["a", "b", "c"]
+}
+
+case "contains" {
+  expr = contains(["a", "b"], "a")
+  want = true
+}
+
+case "csvdecode" {
+  expr = csvdecode("a,b\n1,2\n")
+  want = [{ a = "1", b = "2" }]
+}
+
+case "dirname" {
+  expr = dirname("foo/bar/baz.txt")
+  want = "foo/bar"
+}
+
+case "distinct" {
+  expr = distinct(["a", "b", "a"])
+  want = ["a", "b"]
+}
+
+case "element" {
+  expr = element(["a", "b", "c"], 3)
+  want = "a"
+}
+
+case "endswith" {
+  expr = endswith("hello world", "world")
+  want = true
+}
+
+case "ephemeralasnull" {
+  expr = ephemeralasnull({ a = "b" })
+  want = { a = "b" }
+}
+
+case "file" {
+  expr = file("hello.txt")
+  want = "Hello World"
+}
+
+case "filebase64" {
+  expr = filebase64("hello.txt")
+  want = "SGVsbG8gV29ybGQ="
+}
+
+case "filebase64sha256" {
+  expr = filebase64sha256("hello.txt")
+  want = "pZGm1Av0IEBKARczz7exkNYsZb8LzaMrV7J32a2fFG4="
+}
+
+case "filebase64sha512" {
+  expr = filebase64sha512("hello.txt")
+  want = "LHT9F+2v2A6ER7DUZ0HuJDt+t03SFJoKsbkkb7MDgvJ+hT2FhXGeDmfL2g2qj1FnEGRhXWRa4nrLFb+xRH9Fmw=="
+}
+
+case "fileexists" {
+  expr = fileexists("hello.txt")
+  want = true
+}
+
+case "filemd5" {
+  expr = filemd5("hello.txt")
+  want = "b10a8db164e0754105b7a99be72e3fe5"
+}
+
+case "fileset" {
+  expr = fileset(".", "*.txt")
+  want = ["hello.txt", "terraform_functions.txt"]
+}
+
+case "filesha1" {
+  expr = filesha1("hello.txt")
+  want = "0a4d55a8d778e5022fab701977c5d840bbc486d0"
+}
+
+case "filesha256" {
+  expr = filesha256("hello.txt")
+  want = "a591a6d40bf420404a011733cfb7b190d62c65bf0bcda32b57b277d9ad9f146e"
+}
+
+case "filesha512" {
+  expr = filesha512("hello.txt")
+  want = "2c74fd17edafd80e8447b0d46741ee243b7eb74dd2149a0ab1b9246fb30382f27e853d8585719e0e67cbda0daa8f51671064615d645ae27acb15bfb1447f459b"
+}
+
+case "flatten" {
+  expr = flatten([["a", "b"], [], ["c"]])
+  want = ["a", "b", "c"]
+}
+
+case "floor" {
+  expr = floor(4.9)
+  want = 4
+}
+
+case "format" {
+  expr = format("Hello, %s!", "Ander")
+  want = "Hello, Ander!"
+}
+
+case "formatdate" {
+  expr = formatdate("DD MMM YYYY hh:mm ZZZ", "2018-01-02T23:12:01Z")
+  want = "02 Jan 2018 23:12 UTC"
+}
+
+case "formatlist" {
+  expr = formatlist("Hello, %s!", ["Valentina", "Ander"])
+  want = ["Hello, Valentina!", "Hello, Ander!"]
+}
+
+case "indent" {
+  expr = indent(2, "a\nb")
+  want = "a\n  b"
+}
+
+case "index" {
+  expr = index(["a", "b", "c"], "b")
+  want = 1
+}
+
+case "issensitive" {
+  expr = issensitive(sensitive("secret"))
+  want = true
+}
+
+case "issensitive" {
+  expr = issensitive("public")
+  want = false
+}
+
+case "join" {
+  expr = join(", ", ["foo", "bar"])
+  want = "foo, bar"
+}
+
+case "jsondecode" {
+  expr = jsondecode("{\"hello\": \"world\"}")
+  want = { hello = "world" }
+}
+
+case "jsonencode" {
+  expr = jsonencode({ hello = "world" })
+  want = "{\"hello\":\"world\"}"
+}
+
+case "keys" {
+  expr = keys({ a = 1, c = 2, d = 3 })
+  want = ["a", "c", "d"]
+}
+
+case "length" {
+  expr = length("hello")
+  want = 5
+}
+
+case "length" {
+  expr = length({ a = "b" })
+  want = 1
+}
+
+case "log" {
+  expr = log(16, 2)
+  want = 4
+}
+
+case "lookup" {
+  expr = lookup({ a = "ay", b = "bee" }, "c", "what?")
+  want = "what?"
+}
+
+case "lower" {
+  expr = lower("HELLO")
+  want = "hello"
+}
+
+case "matchkeys" {
+  expr = matchkeys(["i-123", "i-abc", "i-def"], ["us-west", "us-east", "us-east"], ["us-east"])
+  want = ["i-abc", "i-def"]
+}
+
+case "max" {
+  expr = max(12, 54, 3)
+  want = 54
+}
+
+case "md5" {
+  expr = md5("hello world")
+  want = "5eb63bbbe01eeed093cb22bb8f5acdc3"
+}
+
+case "merge" {
+  expr = merge({ a = "b", c = "d" }, { e = "f", c = "z" })
+  want = { a = "b", c = "z", e = "f" }
+}
+
+case "min" {
+  expr = min(12, 54, 3)
+  want = 3
+}
+
+case "nonsensitive" {
+  expr = nonsensitive(sensitive("secret"))
+  want = "secret"
+}
+
+case "nonsensitive" {
+  expr  = nonsensitive("public")
+  error = true
+}
+
+case "one" {
+  expr = one(["hello"])
+  want = "hello"
+}
+
+case "one" {
+  expr = one([])
+  want = null
+}
+
+case "one" {
+  expr  = one(["hello", "goodbye"])
+  error = true
+}
+
+case "parseint" {
+  expr = parseint("FF", 16)
+  want = 255
+}
+
+case "pathexpand" {
+  expr = pathexpand("/etc/hosts")
+  want = "/etc/hosts"
+}
+
+case "pow" {
+  expr = pow(3, 2)
+  want = 9
+}
+
+case "range" {
+  expr = range(1, 4)
+  want = [1, 2, 3]
+}
+
+case "regex" {
+  expr = regex("[a-z]+", "53453453.345345aaabbbccc23454")
+  want = "aaabbbccc"
+}
+
+case "regexall" {
+  expr = regexall("[a-z]+", "1234abcd5678efgh9")
+  want = ["abcd", "efgh"]
+}
+
+case "replace" {
+  expr = replace("1 + 2 + 3", "+", "-")
+  want = "1 - 2 - 3"
+}
+
+case "replace" {
+  expr = replace("hello world", "/w.*d/", "everybody")
+  want = "hello everybody"
+}
+
+case "reverse" {
+  expr = reverse([1, 2, 3])
+  want = [3, 2, 1]
+}
+
+case "sensitive" {
+  expr = sensitive("secret")
+  want = "secret"
+}
+
+case "setintersection" {
+  expr = setintersection(["a", "b"], ["b", "c"], ["b", "d"])
+  want = ["b"]
+}
+
+case "setproduct" {
+  expr = setproduct(["development", "staging"], ["app1", "app2"])
+  want = [["development", "app1"], ["development", "app2"], ["staging", "app1"], ["staging", "app2"]]
+}
+
+case "setsubtract" {
+  expr = setsubtract(["a", "b", "c"], ["a", "c"])
+  want = ["b"]
+}
+
+case "setunion" {
+  expr = setunion(["a", "b"], ["b", "c"])
+  want = ["a", "b", "c"]
+}
+
+case "sha1" {
+  expr = sha1("hello world")
+  want = "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"
+}
+
+case "sha256" {
+  expr = sha256("hello world")
+  want = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+}
+
+case "sha512" {
+  expr = sha512("hello world")
+  want = "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f"
+}
+
+case "signum" {
+  expr = signum(-13)
+  want = -1
+}
+
+case "slice" {
+  expr = slice(["a", "b", "c", "d"], 1, 3)
+  want = ["b", "c"]
+}
+
+case "sort" {
+  expr = sort(["e", "d", "a", "x"])
+  want = ["a", "d", "e", "x"]
+}
+
+case "split" {
+  expr = split(",", "foo,bar,baz")
+  want = ["foo", "bar", "baz"]
+}
+
+case "startswith" {
+  expr = startswith("hello world", "hello")
+  want = true
+}
+
+case "strcontains" {
+  expr = strcontains("hello world", "wor")
+  want = true
+}
+
+case "strrev" {
+  expr = strrev("hello")
+  want = "olleh"
+}
+
+case "substr" {
+  expr = substr("hello world", 1, 4)
+  want = "ello"
+}
+
+case "sum" {
+  expr = sum([10, 13, 6, 4.5])
+  want = 33.5
+}
+
+case "templatefile" {
+  expr = templatefile("hello.tmpl", { name = "World" })
+  want = "Hello, World!"
+}
+
+case "templatestring" {
+  expr = templatestring(local.greeting, { name = "World" })
+  want = "Hello, World!"
+}
+
+case "textdecodebase64" {
+  expr = textdecodebase64("SABlAGwAbABvACAAVwBvAHIAbABkAA==", "UTF-16LE")
+  want = "Hello World"
+}
+
+case "textencodebase64" {
+  expr = textencodebase64("Hello World", "UTF-16LE")
+  want = "SABlAGwAbABvACAAVwBvAHIAbABkAA=="
+}
+
+case "timeadd" {
+  expr = timeadd("2017-11-22T00:00:00Z", "10m")
+  want = "2017-11-22T00:10:00Z"
+}
+
+case "timecmp" {
+  expr = timecmp("2017-11-22T01:00:00+01:00", "2017-11-22T00:00:00Z")
+  want = 0
+}
+
+case "timecmp" {
+  expr = timecmp("2017-11-22T00:00:00Z", "2017-11-22T00:00:01Z")
+  want = -1
+}
+
+case "timecmp" {
+  expr  = timecmp("2017-11-22", "2017-11-22T00:00:00Z")
+  error = true
+}
+
+case "title" {
+  expr = title("hello world")
+  want = "Hello World"
+}
+
+case "tobool" {
+  expr = tobool("true")
+  want = true
+}
+
+case "tolist" {
+  expr = tolist(["a", "b"])
+  want = ["a", "b"]
+}
+
+case "tomap" {
+  expr = tomap({ a = 1, b = 2 })
+  want = { a = 1, b = 2 }
+}
+
+case "tonumber" {
+  expr = tonumber("1")
+  want = 1
+}
+
+case "toset" {
+  expr = toset(["c", "b", "b"])
+  want = ["b", "c"]
+}
+
+case "tostring" {
+  expr = tostring(1)
+  want = "1"
+}
+
+case "transpose" {
+  expr = transpose({ a = ["1", "2"], b = ["2", "3"] })
+  want = { "1" = ["a"], "2" = ["a", "b"], "3" = ["b"] }
+}
+
+case "trim" {
+  expr = trim("?!hello?!", "!?")
+  want = "hello"
+}
+
+case "trimprefix" {
+  expr = trimprefix("helloworld", "hello")
+  want = "world"
+}
+
+case "trimspace" {
+  expr = trimspace("  hello\n\n")
+  want = "hello"
+}
+
+case "trimsuffix" {
+  expr = trimsuffix("helloworld", "world")
+  want = "hello"
+}
+
+case "try" {
+  expr = try(tonumber("nope"), "fallback")
+  want = "fallback"
+}
+
+case "upper" {
+  expr = upper("hello")
+  want = "HELLO"
+}
+
+case "upper" {
+  expr = core::upper("hello")
+  want = "HELLO"
+}
+
+case "urlencode" {
+  expr = urlencode("Hello World!")
+  want = "Hello+World%21"
+}
+
+case "uuidv5" {
+  expr = uuidv5("dns", "www.terraform.io")
+  want = "a5008fae-b28c-5ba5-96cd-82b4c53552d6"
+}
+
+case "values" {
+  expr = values({ a = 3, c = 2, d = 1 })
+  want = [3, 2, 1]
+}
+
+case "yamldecode" {
+  expr = yamldecode("hello: world")
+  want = { hello = "world" }
+}
+
+case "yamlencode" {
+  expr = yamlencode({ a = "b" })
+  want = "\"a\": \"b\"\n"
+}
+
+case "zipmap" {
+  expr = zipmap(["a", "b"], [1, 2])
+  want = { a = 1, b = 2 }
+}
+
+case "provider::terraform::decode_tfvars" {
+  expr = provider::terraform::decode_tfvars("example = \"Hello!\"")
+  want = { example = "Hello!" }
+}
+
+case "provider::terraform::encode_expr" {
+  expr = provider::terraform::encode_expr({ name = "Alice" })
+  want = "{\n  name = \"Alice\"\n}"
+}
+
+case "provider::terraform::encode_tfvars" {
+  expr = provider::terraform::encode_tfvars({ example = "Hello!" })
+  want = "example = \"Hello!\"\n"
+}
+
+case "provider::aws::arn_build" {
+  expr = provider::aws::arn_build("aws", "iam", "", "444455556666", "role/example")
+  want = "arn:aws:iam::444455556666:role/example"
+}
+
+case "provider::aws::arn_parse" {
+  expr = provider::aws::arn_parse("arn:aws:iam::444455556666:role/example")
+  want = {
+    partition  = "aws"
+    service    = "iam"
+    region     = ""
+    account_id = "444455556666"
+    resource   = "role/example"
+  }
+}
+
+case "provider::aws::arn_parse" {
+  expr  = provider::aws::arn_parse("not-an-arn")
+  error = true
+}
+
+case "provider::aws::trim_iam_role_path" {
+  expr = provider::aws::trim_iam_role_path("arn:aws:iam::444455556666:role/with/path/example")
+  want = "arn:aws:iam::444455556666:role/example"
+}
+
+case "provider::azurerm::normalise_resource_id" {
+  expr = provider::azurerm::normalise_resource_id("/Subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resGroup1/providers/Microsoft.Network/virtualNetworks/network1")
+  want = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/virtualNetworks/network1"
+}
+
+case "provider::azurerm::parse_resource_id" {
+  expr = provider::azurerm::parse_resource_id("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/virtualNetworks/network1/subnets/subnet1")
+  want = {
+    full_resource_type  = "Microsoft.Network/virtualNetworks/subnets"
+    parent_resources    = { virtualNetworks = "network1" }
+    resource_group_name = "resGroup1"
+    resource_name       = "subnet1"
+    resource_provider   = "Microsoft.Network"
+    resource_scope      = ""
+    resource_type       = "subnets"
+    subscription_id     = "00000000-0000-0000-0000-000000000000"
+  }
+}
+
+case "provider::google::location_from_id" {
+  expr = provider::google::location_from_id("projects/my-project/locations/us-central1/services/my-service")
+  want = "us-central1"
+}
+
+case "provider::google::name_from_id" {
+  expr = provider::google::name_from_id("projects/my-project/zones/us-central1-c/instances/my-instance")
+  want = "my-instance"
+}
+
+case "provider::google::project_from_id" {
+  expr = provider::google::project_from_id("https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-c/instances/my-instance")
+  want = "my-project"
+}
+
+case "provider::google::region_from_id" {
+  expr = provider::google::region_from_id("projects/my-project/regions/us-central1/subnetworks/my-subnetwork")
+  want = "us-central1"
+}
+
+case "provider::google::region_from_zone" {
+  expr = provider::google::region_from_zone("us-central1-b")
+  want = "us-central1"
+}
+
+case "provider::google::zone_from_id" {
+  expr = provider::google::zone_from_id("projects/my-project/zones/us-central1-c/instances/my-instance")
+  want = "us-central1-c"
+}
@@ -134,6 +134,7 @@ func CompareTo(c *config.Config, current, prior Root) (Root, error) {
 			if !p.Metadata.HasErrors() && !v.Metadata.HasErrors() {
 				scp.PastResources = v.Resources
 				scp.Metadata.PastPolicySha = v.Metadata.PolicySha
+				schema.MarkForgottenResources(scp.PastResources, scp.Metadata.RemovedResources)
 				scp.Diff = schema.CalculateDiff(scp.PastResources, scp.Resources)
 			}
 
@@ -243,6 +244,7 @@ func Combine(inputs []ReportInput) (Root, error) {
 	var pastTotalMonthlyCost *decimal.Decimal
 	var diffTotalHourlyCost *decimal.Decimal
 	var diffTotalMonthlyCost *decimal.Decimal
+	var totalMonthlyCO2e, pastTotalMonthlyCO2e, diffTotalMonthlyCO2e *decimal.Decimal
 
 	projects := make([]Project, 0)
 	summaries := make([]*Summary, 0, len(inputs))
@@ -310,6 +312,10 @@ func Combine(inputs []ReportInput) (Root, error) {
 			diffTotalHourlyCost = decimalPtr(diffTotalHourlyCost.Add(*input.Root.DiffTotalHourlyCost))
 		}
 
+		totalMonthlyCO2e = addDecimalPtrs(totalMonthlyCO2e, input.Root.TotalMonthlyCO2e)
+		pastTotalMonthlyCO2e = addDecimalPtrs(pastTotalMonthlyCO2e, input.Root.PastTotalMonthlyCO2e)
+		diffTotalMonthlyCO2e = addDecimalPtrs(diffTotalMonthlyCO2e, input.Root.DiffTotalMonthlyCO2e)
+
 		if i != 0 && metadata.VCSRepositoryURL != input.Root.Metadata.VCSRepositoryURL {
 			invalidMetadata = true
 		}
@@ -327,6 +333,9 @@ func Combine(inputs []ReportInput) (Root, error) {
 	combined.PastTotalMonthlyCost = pastTotalMonthlyCost
 	combined.DiffTotalHourlyCost = diffTotalHourlyCost
 	combined.DiffTotalMonthlyCost = diffTotalMonthlyCost
+	combined.TotalMonthlyCO2e = totalMonthlyCO2e
+	combined.PastTotalMonthlyCO2e = pastTotalMonthlyCO2e
+	combined.DiffTotalMonthlyCO2e = diffTotalMonthlyCO2e
 	combined.TimeGenerated = lastestGeneratedAt
 	combined.Summary = MergeSummaries(summaries)
 	combined.Metadata = metadata
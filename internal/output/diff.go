@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dustin/go-humanize"
@@ -10,6 +11,7 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/shopspring/decimal"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 )
 
@@ -47,6 +49,9 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 
 		for _, diffResource := range project.Diff.Resources {
 			oldResource := findResourceByName(project.PastBreakdown.Resources, diffResource.Name)
+			if prev := diffResource.previousAddress(); oldResource == nil && prev != "" {
+				oldResource = findResourceByName(project.PastBreakdown.Resources, prev)
+			}
 			newResource := findResourceByName(project.Breakdown.Resources, diffResource.Name)
 
 			s += resourceToDiff(out.Currency, diffResource, oldResource, newResource, true)
@@ -77,6 +82,21 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 			)
 		}
 
+		if project.Diff.TotalMonthlyCO2e != nil {
+			var oldCO2e, newCO2e *decimal.Decimal
+			if project.PastBreakdown != nil {
+				oldCO2e = project.PastBreakdown.TotalMonthlyCO2e
+			}
+			if project.Breakdown != nil {
+				newCO2e = project.Breakdown.TotalMonthlyCO2e
+			}
+
+			s += fmt.Sprintf("\nCarbon:  %s %s",
+				formatCO2eChange(project.Diff.TotalMonthlyCO2e),
+				ui.FaintStringf("(%s → %s)", formatCO2e(oldCO2e), formatCO2e(newCO2e)),
+			)
+		}
+
 		s += "\n\n"
 		s += "──────────────────────────────────\n"
 	}
@@ -200,20 +220,35 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		oldCost = oldResource.MonthlyCost
 	}
 
-	var newCost *decimal.Decimal
+	var newCost, oldCO2e, newCO2e *decimal.Decimal
 	if newResource != nil {
 		newCost = newResource.MonthlyCost
+		newCO2e = newResource.MonthlyCO2e
+	}
+
+	if oldResource != nil {
+		oldCO2e = oldResource.MonthlyCO2e
 	}
 
 	nameLabel := diffResource.Name
 	if isTopLevel {
 		nameLabel = ui.BoldString(nameLabel)
+
+		if prev := diffResource.previousAddress(); prev != "" && oldResource != nil && oldResource.Name == prev {
+			nameLabel += ui.FaintStringf(" (moved from %s)", prev)
+		}
+
+		nameLabel += diffResource.uncertainInputsLabel()
 	}
 
 	s += fmt.Sprintf("%s %s\n", opChar(op), nameLabel)
 
 	if isTopLevel {
-		if oldCost == nil && newCost == nil {
+		if diffResource.isImported() {
+			s += "  Newly managed by import, not new spend\n"
+		} else if diffResource.isForgotten() {
+			s += "  Removed from state without being destroyed, no savings\n"
+		} else if oldCost == nil && newCost == nil {
 			s += "  Monthly cost depends on usage\n"
 		} else {
 			s += fmt.Sprintf("  %s%s\n",
@@ -221,6 +256,13 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 				ui.FaintString(formatCostChangeDetails(currency, oldCost, newCost)),
 			)
 		}
+
+		if diffResource.MonthlyCO2e != nil {
+			s += fmt.Sprintf("  %s%s\n",
+				formatCO2eChange(diffResource.MonthlyCO2e),
+				ui.FaintString(formatCO2eChangeDetails(oldCO2e, newCO2e)),
+			)
+		}
 	}
 
 	for _, diffComponent := range diffResource.CostComponents {
@@ -266,19 +308,21 @@ func costComponentToDiff(currency string, diffComponent CostComponent, oldCompon
 		op = REMOVED
 	}
 
-	var oldCost, newCost, oldPrice, newPrice *decimal.Decimal
+	var oldCost, newCost, oldPrice, newPrice, oldCO2e, newCO2e *decimal.Decimal
 
 	if oldComponent != nil {
 		oldCost = oldComponent.MonthlyCost
 		oldPrice = &oldComponent.Price
+		oldCO2e = oldComponent.MonthlyCO2e
 	}
 
 	if newComponent != nil {
 		newCost = newComponent.MonthlyCost
 		newPrice = &newComponent.Price
+		newCO2e = newComponent.MonthlyCO2e
 	}
 
-	s += fmt.Sprintf("%s %s\n", opChar(op), colorizeDiffName(diffComponent.Name))
+	s += fmt.Sprintf("%s %s%s\n", opChar(op), colorizeDiffName(diffComponent.Name), diffComponent.uncertainInputsLabel())
 
 	if oldCost == nil && newCost == nil {
 		s += "  Monthly cost depends on usage\n"
@@ -294,6 +338,13 @@ func costComponentToDiff(currency string, diffComponent CostComponent, oldCompon
 		)
 	}
 
+	if diffComponent.MonthlyCO2e != nil {
+		s += fmt.Sprintf("  %s%s\n",
+			formatCO2eChange(diffComponent.MonthlyCO2e),
+			ui.FaintString(formatCO2eChangeDetails(oldCO2e, newCO2e)),
+		)
+	}
+
 	return s
 }
 
@@ -313,6 +364,60 @@ func opChar(op int) string {
 	}
 }
 
+// previousAddress returns the address the resource was moved from, if any.
+func (r Resource) previousAddress() string {
+	prev, _ := r.Metadata[schema.PreviousAddressMetadataKey].(string)
+	return prev
+}
+
+// isImported returns true if the resource is being imported rather than created.
+func (r Resource) isImported() bool {
+	imported, _ := r.Metadata[schema.ImportingMetadataKey].(bool)
+	return imported
+}
+
+// isForgotten returns true if the resource is being removed from state without
+// being destroyed.
+func (r Resource) isForgotten() bool {
+	forgotten, _ := r.Metadata[schema.ForgottenMetadataKey].(bool)
+	return forgotten
+}
+
+// uncertainInputs returns the attributes used to price the resource whose
+// values were mocked or unknown, sorted by name.
+func (r Resource) uncertainInputs() []string {
+	inputs, _ := r.Metadata[schema.UncertainInputsMetadataKey].(map[string]interface{})
+
+	keys := make([]string, 0, len(inputs))
+	for k := range inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// uncertainInputsLabel returns a label to show next to the resource name if
+// its cost was calculated using mocked or unknown values.
+func (r Resource) uncertainInputsLabel() string {
+	inputs := r.uncertainInputs()
+	if len(inputs) == 0 {
+		return ""
+	}
+
+	return ui.FaintStringf(" (uses mocked or unknown values: %s)", strings.Join(inputs, ", "))
+}
+
+// uncertainInputsLabel returns a label to show next to the cost component name
+// if it's priced from mocked values.
+func (c CostComponent) uncertainInputsLabel() string {
+	if len(c.UncertainInputs) == 0 {
+		return ""
+	}
+
+	return ui.FaintStringf(" (uses mocked values: %s)", strings.Join(c.UncertainInputs, ", "))
+}
+
 func findResourceByName(resources []Resource, name string) *Resource {
 	for _, r := range resources {
 		if r.Name == name {
@@ -360,6 +465,35 @@ func formatCostChangeDetails(currency string, oldCost *decimal.Decimal, newCost
 	return fmt.Sprintf(" (%s → %s)", formatCost(currency, oldCost), formatCost(currency, newCost))
 }
 
+func formatCO2eChange(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	abs := d.Abs()
+	return fmt.Sprintf("%s%s kgCO2e", getSym(*d), formatCO2e(&abs))
+}
+
+// diffDecimalPtrs returns current - past, treating nil values as zero.
+func diffDecimalPtrs(current, past *decimal.Decimal) *decimal.Decimal {
+	d := decimal.Zero
+	if current != nil {
+		d = d.Add(*current)
+	}
+	if past != nil {
+		d = d.Sub(*past)
+	}
+	return &d
+}
+
+func formatCO2eChangeDetails(oldCO2e *decimal.Decimal, newCO2e *decimal.Decimal) string {
+	if oldCO2e == nil || newCO2e == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s → %s)", formatCO2e(oldCO2e), formatCO2e(newCO2e))
+}
+
 func formatPriceChange(currency string, d decimal.Decimal) string {
 	abs := d.Abs()
 	return fmt.Sprintf("%s%s", getSym(d), formatPrice(currency, abs))
@@ -19,6 +19,15 @@ func formatQuantity(q *decimal.Decimal) string {
 	return humanize.CommafWithDigits(f, 4)
 }
 
+// formatCO2e formats an emissions estimate in kgCO2e.
+func formatCO2e(d *decimal.Decimal) string {
+	if d == nil {
+		return "-"
+	}
+	f, _ := d.Float64()
+	return humanize.CommafWithDigits(f, 2)
+}
+
 func formatCost(currency string, d *decimal.Decimal) string {
 	if d == nil {
 		return "-"
@@ -168,6 +168,18 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) (MarkdownO
 			}
 			return formatCost(out.Currency, d)
 		},
+		"hasRecommendations": out.HasRecommendations,
+		"hasCarbon":          out.HasCarbon,
+		"formatCO2eChange": func(pastCO2e, co2e *decimal.Decimal) string {
+			if pastCO2e == nil && co2e == nil {
+				return "-"
+			}
+
+			return formatCO2eChange(diffDecimalPtrs(co2e, pastCO2e))
+		},
+		"formatSaving": func(d *decimal.Decimal) string {
+			return formatCost(out.Currency, d)
+		},
 		"formatCostChange": func(pastCost, cost *decimal.Decimal) string {
 			return formatMarkdownCostChange(out.Currency, pastCost, cost, false)
 		},
@@ -35,6 +35,9 @@ type Root struct {
 	PastTotalMonthlyCost *decimal.Decimal `json:"pastTotalMonthlyCost"`
 	DiffTotalHourlyCost  *decimal.Decimal `json:"diffTotalHourlyCost"`
 	DiffTotalMonthlyCost *decimal.Decimal `json:"diffTotalMonthlyCost"`
+	TotalMonthlyCO2e     *decimal.Decimal `json:"totalMonthlyCo2e,omitempty"`
+	PastTotalMonthlyCO2e *decimal.Decimal `json:"pastTotalMonthlyCo2e,omitempty"`
+	DiffTotalMonthlyCO2e *decimal.Decimal `json:"diffTotalMonthlyCo2e,omitempty"`
 	TimeGenerated        time.Time        `json:"timeGenerated"`
 	Summary              *Summary         `json:"summary"`
 	FullSummary          *Summary         `json:"-"`
@@ -56,14 +59,32 @@ func (r *Root) HasUnsupportedResources() bool {
 	return *r.Summary.TotalUnsupportedResources > 0
 }
 
+// HasRecommendations returns if any of the projects have local cost optimisation
+// recommendations.
+func (r *Root) HasRecommendations() bool {
+	for _, p := range r.Projects {
+		if len(p.Recommendations) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasCarbon returns if any of the projects have carbon emissions estimates.
+func (r *Root) HasCarbon() bool {
+	return r.TotalMonthlyCO2e != nil || r.PastTotalMonthlyCO2e != nil
+}
+
 type Project struct {
-	Name          string                  `json:"name"`
-	Metadata      *schema.ProjectMetadata `json:"metadata"`
-	PastBreakdown *Breakdown              `json:"pastBreakdown"`
-	Breakdown     *Breakdown              `json:"breakdown"`
-	Diff          *Breakdown              `json:"diff"`
-	Summary       *Summary                `json:"summary"`
-	fullSummary   *Summary
+	Name            string                  `json:"name"`
+	Metadata        *schema.ProjectMetadata `json:"metadata"`
+	PastBreakdown   *Breakdown              `json:"pastBreakdown"`
+	Breakdown       *Breakdown              `json:"breakdown"`
+	Diff            *Breakdown              `json:"diff"`
+	Summary         *Summary                `json:"summary"`
+	Recommendations schema.Recommendations  `json:"recommendations,omitempty"`
+	fullSummary     *Summary
 }
 
 // ToSchemaProject generates a schema.Project from a Project. The created schema.Project is not suitable to be
@@ -89,10 +110,11 @@ func (p Project) ToSchemaProject() *schema.Project {
 	}
 
 	return &schema.Project{
-		Name:          p.Name,
-		Metadata:      clonedMetadata,
-		PastResources: pastResources,
-		Resources:     resources,
+		Name:            p.Name,
+		Metadata:        clonedMetadata,
+		PastResources:   pastResources,
+		Resources:       resources,
+		Recommendations: p.Recommendations,
 	}
 }
 
@@ -110,6 +132,7 @@ func convertOutputResources(outResources []Resource, skip bool) []*schema.Resour
 			Tags:           resource.Tags,
 			HourlyCost:     resource.HourlyCost,
 			MonthlyCost:    resource.MonthlyCost,
+			MonthlyCO2e:    resource.MonthlyCO2e,
 			ResourceType:   resource.ResourceType,
 		}
 	}
@@ -129,6 +152,8 @@ func convertCostComponents(outComponents []CostComponent) []*schema.CostComponen
 			MonthlyCost:     c.MonthlyCost,
 			HourlyQuantity:  c.HourlyQuantity,
 			MonthlyQuantity: c.MonthlyQuantity,
+			MonthlyCO2e:     c.MonthlyCO2e,
+			UncertainInputs: c.UncertainInputs,
 		}
 		sc.SetPrice(c.Price)
 
@@ -237,6 +262,7 @@ type Breakdown struct {
 	FreeResources    []Resource       `json:"freeResources,omitempty"`
 	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
 	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
+	TotalMonthlyCO2e *decimal.Decimal `json:"totalMonthlyCo2e,omitempty"`
 }
 
 // HasResources returns true if the breakdown has any resources or free resources.
@@ -253,6 +279,10 @@ type CostComponent struct {
 	Price           decimal.Decimal  `json:"price"`
 	HourlyCost      *decimal.Decimal `json:"hourlyCost"`
 	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	MonthlyCO2e     *decimal.Decimal `json:"monthlyCo2e,omitempty"`
+	// UncertainInputs are the attributes of the resource with mocked values
+	// that the cost component is priced from.
+	UncertainInputs []string `json:"uncertainInputs,omitempty"`
 }
 
 type ActualCosts struct {
@@ -269,6 +299,7 @@ type Resource struct {
 	Metadata       map[string]interface{} `json:"metadata"`
 	HourlyCost     *decimal.Decimal       `json:"hourlyCost,omitempty"`
 	MonthlyCost    *decimal.Decimal       `json:"monthlyCost,omitempty"`
+	MonthlyCO2e    *decimal.Decimal       `json:"monthlyCo2e,omitempty"`
 	CostComponents []CostComponent        `json:"costComponents,omitempty"`
 	ActualCosts    []ActualCosts          `json:"actualCosts,omitempty"`
 	SubResources   []Resource             `json:"subresources,omitempty"`
@@ -467,8 +498,34 @@ func outputBreakdown(c *config.Config, resources []*schema.Resource) *Breakdown
 		FreeResources:    freeResources,
 		TotalHourlyCost:  totalMonthlyCost,
 		TotalMonthlyCost: totalHourlyCost,
+		TotalMonthlyCO2e: calculateTotalCO2e(supportedResources),
+	}
+}
+
+// excludeForgottenCosts removes the costs of the resources that are being
+// removed from state without being destroyed from the breakdown totals. These
+// resources keep costing money, so the diff doesn't count them as savings and
+// the past totals shouldn't either.
+func excludeForgottenCosts(b *Breakdown) {
+	for _, r := range b.Resources {
+		if !r.isForgotten() {
+			continue
+		}
+
+		if b.TotalHourlyCost != nil && r.HourlyCost != nil {
+			b.TotalHourlyCost = decimalPtr(b.TotalHourlyCost.Sub(*r.HourlyCost))
+		}
+
+		if b.TotalMonthlyCost != nil && r.MonthlyCost != nil {
+			b.TotalMonthlyCost = decimalPtr(b.TotalMonthlyCost.Sub(*r.MonthlyCost))
+		}
+
+		if b.TotalMonthlyCO2e != nil && r.MonthlyCO2e != nil {
+			b.TotalMonthlyCO2e = decimalPtr(b.TotalMonthlyCO2e.Sub(*r.MonthlyCO2e))
+		}
 	}
 }
+
 func outputResource(r *schema.Resource) Resource {
 	comps := outputCostComponents(r.CostComponents)
 
@@ -495,6 +552,7 @@ func newResource(r *schema.Resource, comps []CostComponent, actualCosts []Actual
 		Tags:           r.Tags,
 		HourlyCost:     r.HourlyCost,
 		MonthlyCost:    r.MonthlyCost,
+		MonthlyCO2e:    r.MonthlyCO2e,
 		CostComponents: comps,
 		ActualCosts:    actualCosts,
 		SubResources:   subresources,
@@ -512,6 +570,8 @@ func outputCostComponents(costComponents []*schema.CostComponent) []CostComponen
 			Price:           c.UnitMultiplierPrice(),
 			HourlyCost:      c.HourlyCost,
 			MonthlyCost:     c.MonthlyCost,
+			MonthlyCO2e:     c.MonthlyCO2e,
+			UncertainInputs: c.UncertainInputs,
 		})
 	}
 	return comps
@@ -533,7 +593,8 @@ func outputActualCosts(actualCosts []*schema.ActualCosts) []ActualCosts {
 func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error) {
 	var totalMonthlyCost, totalHourlyCost,
 		pastTotalMonthlyCost, pastTotalHourlyCost,
-		diffTotalMonthlyCost, diffTotalHourlyCost *decimal.Decimal
+		diffTotalMonthlyCost, diffTotalHourlyCost,
+		totalMonthlyCO2e, pastTotalMonthlyCO2e, diffTotalMonthlyCO2e *decimal.Decimal
 
 	outProjects := make([]Project, 0, len(projects))
 	summaries := make([]*Summary, 0, len(projects))
@@ -558,10 +619,13 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 				}
 				totalMonthlyCost = decimalPtr(totalMonthlyCost.Add(*breakdown.TotalMonthlyCost))
 			}
+
+			totalMonthlyCO2e = addDecimalPtrs(totalMonthlyCO2e, breakdown.TotalMonthlyCO2e)
 		}
 
 		if project.HasDiff {
 			pastBreakdown = outputBreakdown(c, project.PastResources)
+			excludeForgottenCosts(pastBreakdown)
 			diff = outputBreakdown(c, project.Diff)
 
 			if pastBreakdown != nil {
@@ -578,6 +642,8 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 					}
 					pastTotalMonthlyCost = decimalPtr(pastTotalMonthlyCost.Add(*pastBreakdown.TotalMonthlyCost))
 				}
+
+				pastTotalMonthlyCO2e = addDecimalPtrs(pastTotalMonthlyCO2e, pastBreakdown.TotalMonthlyCO2e)
 			}
 
 			if diff != nil {
@@ -594,6 +660,8 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 					}
 					diffTotalMonthlyCost = decimalPtr(diffTotalMonthlyCost.Add(*diff.TotalMonthlyCost))
 				}
+
+				diffTotalMonthlyCO2e = addDecimalPtrs(diffTotalMonthlyCO2e, diff.TotalMonthlyCO2e)
 			}
 		}
 
@@ -620,13 +688,14 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 		fullSummaries = append(fullSummaries, fullSummary)
 
 		outProjects = append(outProjects, Project{
-			Name:          project.Name,
-			Metadata:      project.Metadata,
-			PastBreakdown: pastBreakdown,
-			Breakdown:     breakdown,
-			Diff:          diff,
-			Summary:       summary,
-			fullSummary:   fullSummary,
+			Name:            project.Name,
+			Metadata:        project.Metadata,
+			PastBreakdown:   pastBreakdown,
+			Breakdown:       breakdown,
+			Diff:            diff,
+			Summary:         summary,
+			Recommendations: project.Recommendations,
+			fullSummary:     fullSummary,
 		})
 	}
 
@@ -639,6 +708,9 @@ func ToOutputFormat(c *config.Config, projects []*schema.Project) (Root, error)
 		PastTotalMonthlyCost: pastTotalMonthlyCost,
 		DiffTotalHourlyCost:  diffTotalHourlyCost,
 		DiffTotalMonthlyCost: diffTotalMonthlyCost,
+		TotalMonthlyCO2e:     totalMonthlyCO2e,
+		PastTotalMonthlyCO2e: pastTotalMonthlyCO2e,
+		DiffTotalMonthlyCO2e: diffTotalMonthlyCO2e,
 		TimeGenerated:        time.Now().UTC(),
 		Summary:              MergeSummaries(summaries),
 		FullSummary:          MergeSummaries(fullSummaries),
@@ -762,8 +834,12 @@ func formatCounts(countMap *map[string]int) string {
 	return msg
 }
 
-func hasSupportedTerraformProvider(rType string) bool {
-	return strings.HasPrefix(rType, "aws_") || strings.HasPrefix(rType, "google_") || strings.HasPrefix(rType, "azurerm_")
+// hasSupportedProvider returns true if the resource type is from a supported
+// Terraform provider, or is a CloudFormation or ARM resource type. ARM resource
+// types are case-insensitive.
+func hasSupportedProvider(rType string) bool {
+	return strings.HasPrefix(rType, "aws_") || strings.HasPrefix(rType, "google_") || strings.HasPrefix(rType, "azurerm_") ||
+		strings.HasPrefix(rType, "AWS::") || strings.HasPrefix(strings.ToLower(rType), "microsoft.")
 }
 
 func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary, error) {
@@ -789,7 +865,7 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary,
 	}
 
 	for _, r := range resources {
-		if !opts.IncludeUnsupportedProviders && !hasSupportedTerraformProvider(r.ResourceType) {
+		if !opts.IncludeUnsupportedProviders && !hasSupportedProvider(r.ResourceType) {
 			continue
 		}
 
@@ -935,6 +1011,33 @@ func calculateTotalCosts(resources []Resource) (*decimal.Decimal, *decimal.Decim
 	return totalHourlyCost, totalMonthlyCost
 }
 
+// calculateTotalCO2e returns the total monthly emissions of the resources, or
+// nil if none of the resources have emissions estimates.
+func calculateTotalCO2e(resources []Resource) *decimal.Decimal {
+	var total *decimal.Decimal
+
+	for _, r := range resources {
+		if r.MonthlyCO2e != nil {
+			total = addDecimalPtrs(total, r.MonthlyCO2e)
+		}
+	}
+
+	return total
+}
+
+// addDecimalPtrs adds b to a, treating a nil a as zero. It returns a if b is nil.
+func addDecimalPtrs(a, b *decimal.Decimal) *decimal.Decimal {
+	if b == nil {
+		return a
+	}
+
+	if a == nil {
+		a = decimalPtr(decimal.Zero)
+	}
+
+	return decimalPtr(a.Add(*b))
+}
+
 func sortResources(resources []Resource, groupKey string) {
 	sort.Slice(resources, func(i, j int) bool {
 		// If an empty group key is passed just sort by name
@@ -12,6 +12,8 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+const co2eColumnHeader = "Monthly kgCO2e"
+
 func ToTable(out Root, opts Options) ([]byte, error) {
 	var tableLen int
 
@@ -21,6 +23,11 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 	// since we will show the overall total anyway
 	includeProjectTotals := len(out.Projects) != 1
 
+	fields := opts.Fields
+	if out.HasCarbon() {
+		fields = append(append([]string{}, opts.Fields...), "monthlyCO2e")
+	}
+
 	for i, project := range out.Projects {
 		if project.Breakdown == nil {
 			continue
@@ -40,7 +47,7 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 				s += "\n"
 			}
 		} else {
-			tableOut := tableForBreakdown(out.Currency, *project.Breakdown, opts.Fields, includeProjectTotals)
+			tableOut := tableForBreakdown(out.Currency, *project.Breakdown, fields, includeProjectTotals)
 
 			// Get the last table length so we can align the overall total with it
 			if i == len(out.Projects)-1 {
@@ -69,6 +76,14 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		padding = tableLen - (len(overallTitle) + 1)
 	}
 
+	if out.HasCarbon() {
+		// The emissions total is aligned with the last column, so the cost total
+		// needs to be shifted left by the width of that column
+		co2eWidth := len(co2eColumnHeader) + 2
+		totalOut = fmt.Sprintf("%*s %*s", padding-co2eWidth, totalOut, co2eWidth-1, formatCO2e(out.TotalMonthlyCO2e))
+		padding = 0
+	}
+
 	s += fmt.Sprintf("%s%s",
 		ui.BoldString(overallTitle),
 		fmt.Sprintf("%*s ", padding, totalOut), // pad based on the last line length
@@ -85,6 +100,11 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += breakdownSummaryTable(out, opts)
 	}
 
+	if out.HasRecommendations() {
+		s += "\n\n"
+		s += recommendationsTable(out)
+	}
+
 	return []byte(s), nil
 }
 
@@ -175,6 +195,15 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		})
 		i++
 	}
+	if contains(fields, "monthlyCO2e") {
+		headers = append(headers, ui.UnderlineString(co2eColumnHeader))
+		columns = append(columns, table.ColumnConfig{
+			Number:      i,
+			Align:       text.AlignRight,
+			AlignHeader: text.AlignRight,
+		})
+		i++
+	}
 
 	t.AppendRow(table.Row{""})
 
@@ -189,7 +218,7 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 			continue
 		}
 
-		t.AppendRow(table.Row{ui.BoldString(r.Name)})
+		t.AppendRow(table.Row{ui.BoldString(r.Name) + r.uncertainInputsLabel()})
 
 		buildCostComponentRows(t, currency, filteredComponents, "", len(r.SubResources) > 0, fields)
 		buildSubResourceRows(t, currency, filteredSubResources, "", fields)
@@ -202,10 +231,16 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		var totalCostRow table.Row
 		totalCostRow = append(totalCostRow, ui.BoldString(formatTitleWithCurrency("Project total", currency)))
 		numOfFields := i - 3
+		if contains(fields, "monthlyCO2e") {
+			numOfFields--
+		}
 		for q := 0; q < numOfFields; q++ {
 			totalCostRow = append(totalCostRow, "")
 		}
 		totalCostRow = append(totalCostRow, FormatCost2DP(currency, breakdown.TotalMonthlyCost))
+		if contains(fields, "monthlyCO2e") {
+			totalCostRow = append(totalCostRow, formatCO2e(breakdown.TotalMonthlyCO2e))
+		}
 		t.AppendRow(totalCostRow)
 	}
 
@@ -242,7 +277,7 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 			labelPrefix = prefix + "└─"
 		}
 
-		label := fmt.Sprintf("%s %s", ui.FaintString(labelPrefix), c.Name)
+		label := fmt.Sprintf("%s %s%s", ui.FaintString(labelPrefix), c.Name, c.uncertainInputsLabel())
 
 		if c.MonthlyCost == nil {
 			price := fmt.Sprintf("Monthly cost depends on usage: %s per %s",
@@ -276,6 +311,9 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 			if contains(fields, "monthlyCost") {
 				tableRow = append(tableRow, FormatCost2DP(currency, c.MonthlyCost))
 			}
+			if contains(fields, "monthlyCO2e") {
+				tableRow = append(tableRow, formatCO2e(c.MonthlyCO2e))
+			}
 
 			t.AppendRow(tableRow)
 		}
@@ -347,24 +385,66 @@ func breakdownSummaryTable(out Root, opts Options) string {
 	t := table.NewWriter()
 	t.SetStyle(table.StyleBold)
 	t.Style().Format.Header = text.FormatDefault
-	t.AppendHeader(table.Row{
+	headers := table.Row{
 		"Project",
 		"Monthly cost",
+	}
+	columns := []table.ColumnConfig{
+		{Name: "Project", WidthMin: 50},
+		{Name: "Monthly cost", WidthMin: 10},
+	}
+
+	if out.HasCarbon() {
+		headers = append(headers, co2eColumnHeader)
+		columns = append(columns, table.ColumnConfig{Name: co2eColumnHeader, WidthMin: 10})
+	}
+
+	t.AppendHeader(headers)
+	t.SetColumnConfigs(columns)
+
+	for _, project := range out.Projects {
+		row := table.Row{
+			truncateMiddle(project.Name, 64, "..."),
+			formatCost(out.Currency, project.Breakdown.TotalMonthlyCost),
+		}
+
+		if out.HasCarbon() {
+			row = append(row, formatCO2e(project.Breakdown.TotalMonthlyCO2e))
+		}
+
+		t.AppendRow(row)
+	}
+
+	return t.Render()
+}
+
+func recommendationsTable(out Root) string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+	t.SetTitle("Recommendations")
+	t.AppendHeader(table.Row{
+		"Resource",
+		"Recommendation",
+		"Suggested",
+		"Monthly saving",
 	})
 
 	t.SetColumnConfigs([]table.ColumnConfig{
-		{Name: "Project", WidthMin: 50},
-		{Name: "Monthly cost", WidthMin: 10},
+		{Name: "Monthly saving", Align: text.AlignRight},
 	})
 
 	for _, project := range out.Projects {
-		t.AppendRow(
-			table.Row{
-				truncateMiddle(project.Name, 64, "..."),
-				formatCost(out.Currency, project.Breakdown.TotalMonthlyCost),
-			},
-		)
-
+		for _, r := range project.Recommendations {
+			t.AppendRow(
+				table.Row{
+					truncateMiddle(r.Address, 64, "..."),
+					r.Title,
+					r.Suggested,
+					formatCost(out.Currency, r.MonthlySaving),
+				},
+			)
+		}
 	}
 
 	return t.Render()
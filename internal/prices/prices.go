@@ -162,6 +162,18 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 
 	c.SetPrice(p)
 	c.SetPriceHash(prices[0].Get("priceHash").String())
+
+	if productHash := productsWithPrices[0].Get("productHash"); productHash.Exists() {
+		c.SetProductHash(productHash.String())
+	}
+
+	if attributes := productsWithPrices[0].Get("attributes"); attributes.Exists() {
+		m := make(map[string]string)
+		for _, a := range attributes.Array() {
+			m[a.Get("key").String()] = a.Get("value").String()
+		}
+		c.SetProductAttributes(m)
+	}
 }
 
 func setResourceWarningEvent(ctx *config.RunContext, r *schema.Resource, msg string) {
@@ -0,0 +1,24 @@
+package azure
+
+import (
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetApplicationInsightsRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.Insights/components",
+		RFunc: NewApplicationInsights,
+	}
+}
+
+func NewApplicationInsights(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ApplicationInsights{
+		Address:         d.Address,
+		Region:          location(d),
+		RetentionInDays: d.GetInt64OrDefault("properties.RetentionInDays", 90),
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
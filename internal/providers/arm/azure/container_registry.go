@@ -0,0 +1,24 @@
+package azure
+
+import (
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetContainerRegistryRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.ContainerRegistry/registries",
+		RFunc: NewContainerRegistry,
+	}
+}
+
+func NewContainerRegistry(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ContainerRegistry{
+		Address: d.Address,
+		Region:  location(d),
+		SKU:     d.GetStringOrDefault("sku.name", "Basic"),
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
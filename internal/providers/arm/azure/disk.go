@@ -0,0 +1,32 @@
+package azure
+
+import (
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetDiskRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.Compute/disks",
+		RFunc: NewDisk,
+	}
+}
+
+func NewDisk(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	values := map[string]interface{}{
+		"location":             location(d),
+		"storage_account_type": d.GetStringOrDefault("sku.name", "Standard_LRS"),
+	}
+
+	if size := d.Get("properties.diskSizeGB"); size.Exists() {
+		values["disk_size_gb"] = size.Int()
+	}
+	if iops := d.Get("properties.diskIOPSReadWrite"); iops.Exists() {
+		values["disk_iops_read_write"] = iops.Int()
+	}
+	if mbps := d.Get("properties.diskMBpsReadWrite"); mbps.Exists() {
+		values["disk_mbps_read_write"] = mbps.Int()
+	}
+
+	return tfazure.NewAzureRMManagedDisk(terraformResourceData(d, "azurerm_managed_disk", d.Address, values), u)
+}
@@ -0,0 +1,98 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetPostgreSQLFlexibleServerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.DBforPostgreSQL/flexibleServers",
+		RFunc: NewPostgreSQLFlexibleServer,
+	}
+}
+
+func GetMySQLFlexibleServerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.DBforMySQL/flexibleServers",
+		RFunc: NewMySQLFlexibleServer,
+	}
+}
+
+var flexibleServerTiers = map[string]string{
+	"burstable":       "b",
+	"generalpurpose":  "gp",
+	"memoryoptimized": "mo",
+}
+
+// flexibleServerSKU returns the tier prefix, instance type and version of the
+// flexible server SKU. ARM templates set the tier separately from the SKU
+// name, e.g. GeneralPurpose and Standard_D4s_v3, while the core resources use
+// the Terraform format GP_Standard_D4s_v3.
+func flexibleServerSKU(d *schema.ResourceData) (string, string, string, string, bool) {
+	name := d.Get("sku.name").String()
+
+	tier, ok := flexibleServerTiers[strings.ToLower(d.Get("sku.tier").String())]
+	if !ok {
+		log.Warn().Msgf("Unrecognised flexible server tier for resource %s: %s", d.Address, d.Get("sku.tier").String())
+		return "", "", "", "", false
+	}
+
+	s := strings.Split(name, "_")
+	if len(s) < 2 || len(s) > 3 {
+		log.Warn().Msgf("Unrecognised flexible server SKU format for resource %s: %s", d.Address, name)
+		return "", "", "", "", false
+	}
+
+	version := ""
+	if len(s) > 2 {
+		version = s[2]
+	}
+
+	return strings.ToUpper(tier) + "_" + name, tier, s[1], version, true
+}
+
+func NewPostgreSQLFlexibleServer(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	sku, tier, size, version, ok := flexibleServerSKU(d)
+	if !ok {
+		return nil
+	}
+
+	r := &azure.PostgreSQLFlexibleServer{
+		Address:         d.Address,
+		Region:          location(d),
+		SKU:             sku,
+		Tier:            tier,
+		InstanceType:    size,
+		InstanceVersion: version,
+		Storage:         d.GetInt64OrDefault("properties.storage.storageSizeGB", 32) * 1024,
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
+
+func NewMySQLFlexibleServer(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	sku, tier, size, version, ok := flexibleServerSKU(d)
+	if !ok {
+		return nil
+	}
+
+	r := &azure.MySQLFlexibleServer{
+		Address:         d.Address,
+		Region:          location(d),
+		SKU:             sku,
+		Tier:            tier,
+		InstanceType:    size,
+		InstanceVersion: version,
+		Storage:         d.GetInt64OrDefault("properties.storage.storageSizeGB", 0),
+		IOPS:            d.GetInt64OrDefault("properties.storage.iops", 0),
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
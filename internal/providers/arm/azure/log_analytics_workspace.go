@@ -0,0 +1,23 @@
+package azure
+
+import (
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLogAnalyticsWorkspaceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "Microsoft.OperationalInsights/workspaces",
+		CoreRFunc: NewLogAnalyticsWorkspace,
+	}
+}
+
+func NewLogAnalyticsWorkspace(d *schema.ResourceData) schema.CoreResource {
+	return &azure.LogAnalyticsWorkspace{
+		Address:                       d.Address,
+		Region:                        location(d),
+		SKU:                           d.GetStringOrDefault("properties.sku.name", "PerGB2018"),
+		ReservationCapacityInGBPerDay: d.Get("properties.sku.capacityReservationLevel").Int(),
+		RetentionInDays:               d.GetInt64OrDefault("properties.retentionInDays", 30),
+	}
+}
@@ -0,0 +1,102 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetManagedClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.ContainerService/managedClusters",
+		RFunc: NewManagedCluster,
+	}
+}
+
+func GetManagedClusterAgentPoolRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.ContainerService/managedClusters/agentPools",
+		RFunc: NewManagedClusterAgentPool,
+	}
+}
+
+// NewManagedCluster prices the cluster like the equivalent Terraform resource.
+// The first system agent pool is the default node pool, the other agent pools
+// defined in the cluster are added as sub resources.
+func NewManagedCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	pools := d.Get("properties.agentPoolProfiles").Array()
+
+	defaultPool := 0
+	for i, p := range pools {
+		if strings.EqualFold(p.Get("mode").String(), "System") {
+			defaultPool = i
+			break
+		}
+	}
+
+	values := map[string]interface{}{
+		"location": location(d),
+		"sku_tier": d.GetStringOrDefault("sku.tier", "Free"),
+		"network_profile": []interface{}{
+			map[string]interface{}{"load_balancer_sku": d.GetStringOrDefault("properties.networkProfile.loadBalancerSku", "standard")},
+		},
+		"http_application_routing_enabled": d.Get("properties.addonProfiles.httpApplicationRouting.enabled").Bool(),
+	}
+	if len(pools) > 0 {
+		values["default_node_pool"] = []interface{}{nodePoolValues(pools[defaultPool])}
+	}
+
+	r := tfazure.NewAzureRMKubernetesCluster(terraformResourceData(d, "azurerm_kubernetes_cluster", d.Address, values), u)
+
+	for i, p := range pools {
+		if i == defaultPool {
+			continue
+		}
+
+		name := p.Get("name").String()
+		poolValues := nodePoolValues(p)
+		poolValues["location"] = location(d)
+
+		var poolUsage *schema.UsageData
+		if u != nil {
+			poolUsage = schema.NewUsageData(name, u.Get(name).Map())
+		}
+
+		r.SubResources = append(r.SubResources, tfazure.NewAzureRMKubernetesClusterNodePool(terraformResourceData(d, "azurerm_kubernetes_cluster_node_pool", name, poolValues), poolUsage))
+	}
+
+	return r
+}
+
+func NewManagedClusterAgentPool(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	values := nodePoolValues(d.Get("properties"))
+	values["location"] = location(d)
+
+	return tfazure.NewAzureRMKubernetesClusterNodePool(terraformResourceData(d, "azurerm_kubernetes_cluster_node_pool", d.Address, values), u)
+}
+
+// nodePoolValues returns the Terraform node pool attributes of an agent pool.
+func nodePoolValues(p gjson.Result) map[string]interface{} {
+	values := map[string]interface{}{
+		"vm_size": p.Get("vmSize").String(),
+	}
+
+	attrs := map[string]string{
+		"count":        "node_count",
+		"minCount":     "min_count",
+		"osType":       "os_type",
+		"osSKU":        "os_sku",
+		"osDiskType":   "os_disk_type",
+		"osDiskSizeGB": "os_disk_size_gb",
+	}
+	for k, attr := range attrs {
+		if v := p.Get(k); v.Exists() {
+			values[attr] = v.Value()
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,23 @@
+package azure
+
+import (
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetPublicIPAddressRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.Network/publicIPAddresses",
+		RFunc: NewPublicIPAddress,
+	}
+}
+
+func NewPublicIPAddress(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	values := map[string]interface{}{
+		"location":          location(d),
+		"allocation_method": d.GetStringOrDefault("properties.publicIPAllocationMethod", "Dynamic"),
+		"sku":               d.GetStringOrDefault("sku.name", "Basic"),
+	}
+
+	return tfazure.NewAzureRMPublicIP(terraformResourceData(d, "azurerm_public_ip", d.Address, values), u)
+}
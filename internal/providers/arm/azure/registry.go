@@ -0,0 +1,55 @@
+package azure
+
+import "github.com/infracost/infracost/internal/schema"
+
+var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
+	GetApplicationInsightsRegistryItem(),
+	GetContainerRegistryRegistryItem(),
+	GetDiskRegistryItem(),
+	GetLogAnalyticsWorkspaceRegistryItem(),
+	GetManagedClusterAgentPoolRegistryItem(),
+	GetManagedClusterRegistryItem(),
+	GetMySQLFlexibleServerRegistryItem(),
+	GetPostgreSQLFlexibleServerRegistryItem(),
+	GetPublicIPAddressRegistryItem(),
+	GetServerFarmRegistryItem(),
+	GetStorageAccountRegistryItem(),
+	GetVirtualMachineRegistryItem(),
+}
+
+// FreeResources grouped alphabetically
+var FreeResources = []string{
+	"Microsoft.Authorization/locks",
+	"Microsoft.Authorization/policyAssignments",
+	"Microsoft.Authorization/roleAssignments",
+	"Microsoft.Authorization/roleDefinitions",
+	"Microsoft.Compute/availabilitySets",
+	"Microsoft.Compute/virtualMachines/extensions",
+	"Microsoft.ContainerRegistry/registries/scopeMaps",
+	"Microsoft.DBforMySQL/flexibleServers/configurations",
+	"Microsoft.DBforMySQL/flexibleServers/databases",
+	"Microsoft.DBforMySQL/flexibleServers/firewallRules",
+	"Microsoft.DBforPostgreSQL/flexibleServers/configurations",
+	"Microsoft.DBforPostgreSQL/flexibleServers/databases",
+	"Microsoft.DBforPostgreSQL/flexibleServers/firewallRules",
+	"Microsoft.Insights/diagnosticSettings",
+	"Microsoft.KeyVault/vaults/accessPolicies",
+	"Microsoft.KeyVault/vaults/secrets",
+	"Microsoft.ManagedIdentity/userAssignedIdentities",
+	"Microsoft.Network/applicationSecurityGroups",
+	"Microsoft.Network/networkInterfaces",
+	"Microsoft.Network/networkSecurityGroups",
+	"Microsoft.Network/networkSecurityGroups/securityRules",
+	"Microsoft.Network/routeTables",
+	"Microsoft.Network/routeTables/routes",
+	"Microsoft.Network/virtualNetworks",
+	"Microsoft.Network/virtualNetworks/subnets",
+	"Microsoft.Resources/deployments",
+	"Microsoft.Resources/resourceGroups",
+	"Microsoft.Storage/storageAccounts/blobServices",
+	"Microsoft.Storage/storageAccounts/blobServices/containers",
+	"Microsoft.Storage/storageAccounts/fileServices",
+	"Microsoft.Storage/storageAccounts/queueServices",
+	"Microsoft.Storage/storageAccounts/tableServices",
+	"Microsoft.Web/sites/config",
+}
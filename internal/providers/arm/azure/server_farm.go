@@ -0,0 +1,36 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetServerFarmRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "Microsoft.Web/serverfarms",
+		CoreRFunc: NewServerFarm,
+	}
+}
+
+// NewServerFarm maps the App Service plan onto the core service plan resource.
+// Linux plans have the reserved property set and a kind containing linux.
+func NewServerFarm(d *schema.ResourceData) schema.CoreResource {
+	osType := "Windows"
+	kind := strings.ToLower(d.Get("kind").String())
+	switch {
+	case d.Get("properties.reserved").Bool() || strings.Contains(kind, "linux"):
+		osType = "Linux"
+	case d.Get("properties.hyperV").Bool() || strings.Contains(kind, "xenon"):
+		osType = "WindowsContainer"
+	}
+
+	return &azure.ServicePlan{
+		Address:     d.Address,
+		Region:      location(d),
+		SKUName:     d.Get("sku.name").String(),
+		WorkerCount: d.GetInt64OrDefault("sku.capacity", 1),
+		OSType:      osType,
+	}
+}
@@ -0,0 +1,38 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetStorageAccountRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "Microsoft.Storage/storageAccounts",
+		CoreRFunc: NewStorageAccount,
+	}
+}
+
+// NewStorageAccount maps the storage account onto the core resource. The SKU
+// name combines the account tier and replication type, e.g. Standard_RAGRS.
+func NewStorageAccount(d *schema.ResourceData) schema.CoreResource {
+	tier, replication, _ := strings.Cut(d.GetStringOrDefault("sku.name", "Standard_LRS"), "_")
+
+	switch strings.ToUpper(replication) {
+	case "RAGRS":
+		replication = "RA-GRS"
+	case "RAGZRS":
+		replication = "RA-GZRS"
+	}
+
+	return &azure.StorageAccount{
+		Address:                d.Address,
+		Region:                 location(d),
+		AccessTier:             d.GetStringOrDefault("properties.accessTier", "Hot"),
+		AccountKind:            d.GetStringOrDefault("kind", "StorageV2"),
+		AccountReplicationType: replication,
+		AccountTier:            tier,
+		NFSv3:                  d.Get("properties.isNfsV3Enabled").Bool(),
+	}
+}
@@ -0,0 +1,38 @@
+package azure
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// DefaultLocation is the location used for resources when neither the
+// resource nor the resource group location is known. ARM templates usually
+// deploy to the location of the resource group, which is chosen when the
+// template is deployed.
+const DefaultLocation = "eastus"
+
+var locationRe = regexp.MustCompile(`\s+`)
+
+// location returns the location of the resource in the format used by the
+// Azure CLI, e.g. westeurope for "West Europe".
+func location(d *schema.ResourceData) string {
+	l := d.Get("location").String()
+	if l == "" {
+		l = DefaultLocation
+	}
+
+	return strings.ToLower(locationRe.ReplaceAllString(l, ""))
+}
+
+// terraformResourceData returns resource data with the attributes of the
+// equivalent Terraform resource, so that resources without a core resource
+// can be priced with the Terraform resource functions.
+func terraformResourceData(d *schema.ResourceData, resourceType string, address string, values map[string]interface{}) *schema.ResourceData {
+	b, _ := json.Marshal(values)
+	return schema.NewResourceData(resourceType, "azurerm", address, d.Tags, gjson.ParseBytes(b))
+}
@@ -0,0 +1,94 @@
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetVirtualMachineRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "Microsoft.Compute/virtualMachines",
+		RFunc: NewVirtualMachine,
+		Notes: []string{
+			"Non-standard images such as RHEL are not supported.",
+			"Low priority, Spot and Reserved instances are not supported.",
+		},
+	}
+}
+
+// NewVirtualMachine prices the virtual machine and its OS disk like the
+// equivalent Terraform resource. Data disks that are created with the virtual
+// machine are added as sub resources, attached disks are priced as separate
+// Microsoft.Compute/disks resources.
+func NewVirtualMachine(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	osDisk := map[string]interface{}{
+		"storage_account_type": d.GetStringOrDefault("properties.storageProfile.osDisk.managedDisk.storageAccountType", "Standard_LRS"),
+	}
+	if size := d.Get("properties.storageProfile.osDisk.diskSizeGB"); size.Exists() {
+		osDisk["disk_size_gb"] = size.Int()
+	}
+
+	values := map[string]interface{}{
+		"location": location(d),
+		"size":     d.Get("properties.hardwareProfile.vmSize").String(),
+		"os_disk":  []interface{}{osDisk},
+		"additional_capabilities": []interface{}{
+			map[string]interface{}{"ultra_ssd_enabled": d.Get("properties.additionalCapabilities.ultraSSDEnabled").Bool()},
+		},
+	}
+
+	var r *schema.Resource
+	if isWindows(d) {
+		values["license_type"] = d.Get("properties.licenseType").String()
+		r = tfazure.NewAzureRMWindowsVirtualMachine(terraformResourceData(d, "azurerm_windows_virtual_machine", d.Address, values), u)
+	} else {
+		r = tfazure.NewAzureRMLinuxVirtualMachine(terraformResourceData(d, "azurerm_linux_virtual_machine", d.Address, values), u)
+	}
+
+	for i, disk := range d.Get("properties.storageProfile.dataDisks").Array() {
+		if strings.EqualFold(disk.Get("createOption").String(), "Attach") {
+			continue
+		}
+
+		diskValues := map[string]interface{}{
+			"location":             location(d),
+			"storage_account_type": disk.Get("managedDisk.storageAccountType").String(),
+		}
+		if diskValues["storage_account_type"] == "" {
+			diskValues["storage_account_type"] = "Standard_LRS"
+		}
+		if size := disk.Get("diskSizeGB"); size.Exists() {
+			diskValues["disk_size_gb"] = size.Int()
+		}
+
+		name := fmt.Sprintf("data_disk[%d]", i)
+		dataDisk := tfazure.NewAzureRMManagedDisk(terraformResourceData(d, "azurerm_managed_disk", name, diskValues), nil)
+		r.SubResources = append(r.SubResources, dataDisk)
+	}
+
+	return r
+}
+
+// isWindows returns true if the virtual machine runs Windows, based on its
+// OS profile, OS disk or image.
+func isWindows(d *schema.ResourceData) bool {
+	if d.Get("properties.osProfile.windowsConfiguration").Exists() {
+		return true
+	}
+
+	if strings.EqualFold(d.Get("properties.storageProfile.osDisk.osType").String(), "Windows") {
+		return true
+	}
+
+	if strings.HasPrefix(strings.ToLower(d.Get("properties.licenseType").String()), "windows") {
+		return true
+	}
+
+	publisher := d.Get("properties.storageProfile.imageReference.publisher")
+	return publisher.Type == gjson.String && strings.Contains(strings.ToLower(publisher.String()), "windows")
+}
@@ -0,0 +1,396 @@
+package arm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// environment holds the values of the deployment that the template functions
+// can access, e.g. resourceGroup().location. It is shared between the root
+// template and its nested deployments.
+type environment struct {
+	location       string
+	resourceGroup  string
+	subscriptionID string
+	tenantID       string
+	deploymentName string
+
+	// resources are the evaluated resources keyed by their lower cased
+	// resource ID, name and symbolic name so they can be used by reference().
+	resources map[string]map[string]interface{}
+}
+
+func newEnvironment(location string) *environment {
+	return &environment{
+		location:       location,
+		resourceGroup:  "infracost",
+		subscriptionID: "00000000-0000-0000-0000-000000000000",
+		tenantID:       "00000000-0000-0000-0000-000000000000",
+		deploymentName: "infracost",
+		resources:      map[string]map[string]interface{}{},
+	}
+}
+
+// templateScope holds the parameters and variables of a template.
+type templateScope struct {
+	env        *environment
+	parameters map[string]interface{}
+	variables  map[string]interface{}
+	values     map[string]interface{}
+	evaluating map[string]bool
+}
+
+// evalContext is the context that an expression is evaluated in. It extends
+// the template scope with the indexes of the copy loops and the variables of
+// the lambda functions that the expression is in.
+type evalContext struct {
+	scope       *templateScope
+	copyIndexes map[string]int
+	lambdaVars  map[string]interface{}
+}
+
+func newEvalContext(scope *templateScope) *evalContext {
+	return &evalContext{
+		scope:       scope,
+		copyIndexes: map[string]int{},
+		lambdaVars:  map[string]interface{}{},
+	}
+}
+
+// withCopyIndex returns a new context with the index of the named copy loop.
+// An empty name is used for the loop of the current resource.
+func (c *evalContext) withCopyIndex(name string, i int) *evalContext {
+	indexes := make(map[string]int, len(c.copyIndexes)+1)
+	for k, v := range c.copyIndexes {
+		indexes[k] = v
+	}
+	indexes[strings.ToLower(name)] = i
+
+	return &evalContext{scope: c.scope, copyIndexes: indexes, lambdaVars: c.lambdaVars}
+}
+
+func (c *evalContext) withLambdaVars(vars map[string]interface{}) *evalContext {
+	merged := make(map[string]interface{}, len(c.lambdaVars)+len(vars))
+	for k, v := range c.lambdaVars {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[strings.ToLower(k)] = v
+	}
+
+	return &evalContext{scope: c.scope, copyIndexes: c.copyIndexes, lambdaVars: merged}
+}
+
+// variable returns the value of the variable, evaluating it the first time
+// it is used.
+func (c *evalContext) variable(name string) (interface{}, error) {
+	s := c.scope
+	key, ok := lookupKey(s.variables, name)
+	if !ok {
+		return nil, fmt.Errorf("variable %q is not defined", name)
+	}
+
+	if v, ok := s.values[key]; ok {
+		return v, nil
+	}
+
+	if s.evaluating[key] {
+		return nil, fmt.Errorf("variable %q references itself", name)
+	}
+
+	s.evaluating[key] = true
+	defer delete(s.evaluating, key)
+
+	var v interface{}
+	var err error
+	if l, ok := s.variables[key].(variableLoop); ok {
+		out := map[string]interface{}{}
+		err = newEvalContext(s).expandPropertyLoops([]interface{}{l.loop}, out)
+		v = out[key]
+	} else {
+		v, err = newEvalContext(s).evaluate(s.variables[key])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating variable %q: %w", name, err)
+	}
+
+	s.values[key] = v
+
+	return v, nil
+}
+
+// evaluate evaluates all the expressions in the value. Objects with a copy
+// property are expanded into the arrays defined by the copy loops.
+func (c *evalContext) evaluate(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		return c.evaluateString(t)
+	case []interface{}:
+		out := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			e, err := c.evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, e)
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			if k == "copy" {
+				if loops, ok := item.([]interface{}); ok {
+					if err := c.expandPropertyLoops(loops, out); err != nil {
+						return nil, err
+					}
+					continue
+				}
+			}
+
+			e, err := c.evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = e
+		}
+		return out, nil
+	}
+
+	return v, nil
+}
+
+// expandPropertyLoops sets the properties defined by copy loops, e.g.
+// "copy": [{"name": "dataDisks", "count": 2, "input": {...}}].
+func (c *evalContext) expandPropertyLoops(loops []interface{}, out map[string]interface{}) error {
+	for _, l := range loops {
+		loop, ok := l.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid copy loop %v", l)
+		}
+
+		name, _ := loop["name"].(string)
+		count, err := c.copyCount(loop)
+		if err != nil {
+			return fmt.Errorf("error evaluating count of copy loop %q: %w", name, err)
+		}
+
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := c.withCopyIndex(name, i).evaluate(loop["input"])
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+
+		out[name] = items
+	}
+
+	return nil
+}
+
+// copyCount returns the number of iterations of a copy loop.
+func (c *evalContext) copyCount(loop map[string]interface{}) (int, error) {
+	v, err := c.evaluate(loop["count"])
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 {
+		return 0, fmt.Errorf("count can't be negative")
+	}
+
+	return int(n), nil
+}
+
+func (c *evalContext) evaluateString(s string) (interface{}, error) {
+	if !isExpression(s) {
+		return unescapeLiteral(s), nil
+	}
+
+	n, err := parseExpression(s[1 : len(s)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return c.eval(n)
+}
+
+func (c *evalContext) eval(n node) (interface{}, error) {
+	switch t := n.(type) {
+	case *literalNode:
+		return t.value, nil
+	case *propertyNode:
+		target, err := c.eval(t.target)
+		if err != nil {
+			return nil, err
+		}
+		return property(target, t.name)
+	case *indexNode:
+		target, err := c.eval(t.target)
+		if err != nil {
+			return nil, err
+		}
+		index, err := c.eval(t.index)
+		if err != nil {
+			return nil, err
+		}
+		return indexValue(target, index)
+	case *callNode:
+		return c.call(t)
+	}
+
+	return nil, fmt.Errorf("unexpected expression %T", n)
+}
+
+func (c *evalContext) call(n *callNode) (interface{}, error) {
+	// These functions don't evaluate all their arguments
+	switch n.name {
+	case "if":
+		if len(n.args) != 3 {
+			return nil, fmt.Errorf("if expects 3 arguments")
+		}
+		cond, err := c.eval(n.args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := toBool(cond)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return c.eval(n.args[1])
+		}
+		return c.eval(n.args[2])
+	case "lambda":
+		return c.lambda(n.args)
+	}
+
+	fn, ok := functions[n.name]
+	if !ok {
+		return nil, fmt.Errorf("function %q is not supported", n.name)
+	}
+
+	args := make([]interface{}, 0, len(n.args))
+	for _, a := range n.args {
+		v, err := c.eval(a)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+
+	return fn(c, args)
+}
+
+// lambdaValue is the function created by lambda('x', <expression>).
+type lambdaValue struct {
+	params []string
+	body   node
+	ctx    *evalContext
+}
+
+func (c *evalContext) lambda(args []node) (interface{}, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("lambda expects at least 1 argument")
+	}
+
+	l := &lambdaValue{body: args[len(args)-1], ctx: c}
+	for _, a := range args[:len(args)-1] {
+		v, err := c.eval(a)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("lambda parameter names must be strings")
+		}
+		l.params = append(l.params, name)
+	}
+
+	return l, nil
+}
+
+func (l *lambdaValue) apply(args ...interface{}) (interface{}, error) {
+	vars := map[string]interface{}{}
+	for i, p := range l.params {
+		if i < len(args) {
+			vars[p] = args[i]
+		}
+	}
+
+	return l.ctx.withLambdaVars(vars).eval(l.body)
+}
+
+// property returns the property of an object. ARM property names are case
+// insensitive. Missing properties return nil so that values which are only
+// known after deployment, e.g. the properties returned by reference(), don't
+// stop the rest of the template from being evaluated.
+func property(v interface{}, name string) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if k, ok := lookupKey(t, name); ok {
+			return t[k], nil
+		}
+		log.Debug().Msgf("Property %q not found, it may only be known after deployment", name)
+		return nil, nil
+	case nil:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("can't get property %q of %T", name, v)
+}
+
+func indexValue(v interface{}, index interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case []interface{}:
+		n, err := toNumber(index)
+		if err != nil {
+			return nil, err
+		}
+		i := int(n)
+		if i < 0 || i >= len(t) {
+			return nil, fmt.Errorf("index %d is out of range", i)
+		}
+		return t[i], nil
+	case map[string]interface{}:
+		name, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("object properties must be accessed with a string")
+		}
+		return property(t, name)
+	case nil:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("can't index %T", v)
+}
+
+// lookupKey returns the key in the map that matches the name, ignoring case.
+func lookupKey(m map[string]interface{}, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,269 @@
+package arm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ARM template expressions are strings wrapped in square brackets, e.g.
+// [concat(parameters('prefix'), '-vm')]. A string that starts with [[ is a
+// literal string starting with [.
+// See https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/template-expressions
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+	tokDot
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+type node interface{}
+
+type literalNode struct {
+	value interface{}
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+type propertyNode struct {
+	target node
+	name   string
+}
+
+type indexNode struct {
+	target node
+	index  node
+}
+
+// isExpression returns true if the string is an ARM template expression.
+func isExpression(s string) bool {
+	return len(s) >= 2 && s[0] == '[' && s[len(s)-1] == ']' && !strings.HasPrefix(s, "[[")
+}
+
+// unescapeLiteral returns the literal value of a string that isn't an
+// expression.
+func unescapeLiteral(s string) string {
+	if strings.HasPrefix(s, "[[") {
+		return s[1:]
+	}
+
+	return s
+}
+
+// parseExpression parses an expression without its surrounding brackets.
+func parseExpression(s string) (node, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &expressionParser{tokens: tokens}
+
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q in expression %q", p.peek().value, s)
+	}
+
+	return n, nil
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{tokLBracket, "["})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{tokRBracket, "]"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokComma, ","})
+			i++
+		case r == '.':
+			tokens = append(tokens, token{tokDot, "."})
+			i++
+		case r == '\'':
+			// Single quotes are escaped by doubling them
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string in expression %q", s)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						b.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{tokString, b.String()})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in expression %q", r, s)
+		}
+	}
+
+	return append(tokens, token{tokEOF, ""}), nil
+}
+
+type expressionParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *expressionParser) expect(kind tokenKind, value string) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("expected %q but got %q", value, t.value)
+	}
+
+	return nil
+}
+
+func (p *expressionParser) parseExpr() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokDot:
+			p.next()
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, fmt.Errorf("expected property name but got %q", t.value)
+			}
+			n = &propertyNode{target: n, name: t.value}
+		case tokLBracket:
+			p.next()
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokRBracket, "]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{target: n, index: index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *expressionParser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokString:
+		return &literalNode{value: t.value}, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &literalNode{value: f}, nil
+	case tokIdent:
+		if p.peek().kind != tokLParen {
+			switch strings.ToLower(t.value) {
+			case "true":
+				return &literalNode{value: true}, nil
+			case "false":
+				return &literalNode{value: false}, nil
+			case "null":
+				return &literalNode{value: nil}, nil
+			}
+			return nil, fmt.Errorf("unexpected identifier %q", t.value)
+		}
+
+		p.next()
+		call := &callNode{name: strings.ToLower(t.value)}
+		if p.peek().kind == tokRParen {
+			p.next()
+			return call, nil
+		}
+
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+
+			t := p.next()
+			if t.kind == tokRParen {
+				return call, nil
+			}
+			if t.kind != tokComma {
+				return nil, fmt.Errorf("expected \",\" or \")\" but got %q", t.value)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %q", t.value)
+}
@@ -0,0 +1,141 @@
+package arm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvalContext(t *testing.T) *evalContext {
+	t.Helper()
+
+	raw := map[string]interface{}{
+		"parameters": map[string]interface{}{
+			"prefix": map[string]interface{}{"type": "string", "defaultValue": "app"},
+			"count":  map[string]interface{}{"type": "int"},
+			"config": map[string]interface{}{"type": "object", "defaultValue": map[string]interface{}{"sku": "Standard", "Tier": "Hot"}},
+		},
+		"variables": map[string]interface{}{
+			"name":   "[concat(parameters('prefix'), '-', variables('suffix'))]",
+			"suffix": "web",
+			"items":  []interface{}{"a", "b", "c"},
+		},
+	}
+
+	scope, err := newTemplateScope(raw, map[string]interface{}{"count": "3"}, newEnvironment("westeurope"))
+	require.NoError(t, err)
+
+	return newEvalContext(scope)
+}
+
+func TestEvaluateExpressions(t *testing.T) {
+	ctx := testEvalContext(t)
+
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"plain", "plain"},
+		{"[[literal]", "[literal]"},
+		{"[parameters('prefix')]", "app"},
+		{"[parameters('count')]", 3.0},
+		{"[variables('name')]", "app-web"},
+		{"[variables('items')[1]]", "b"},
+		{"[parameters('config').sku]", "Standard"},
+		{"[parameters('config').tier]", "Hot"},
+		{"[parameters('config')['sku']]", "Standard"},
+		{"[resourceGroup().location]", "westeurope"},
+		{"[format('{0}-{1:D3}', 'vm', 7)]", "vm-007"},
+		{"[format('{{{0}}}', 'x')]", "{x}"},
+		{"[concat('a', 1, true())]", "a1True"},
+		{"[concat(createArray(1, 2), createArray(3))]", []interface{}{1.0, 2.0, 3.0}},
+		{"[if(equals(parameters('prefix'), 'app'), 'yes', 'no')]", "yes"},
+		{"[if(true(), 'yes', parameters('missing'))]", "yes"},
+		{"[and(true(), not(false()))]", true},
+		{"[or(false(), greater(2, 1))]", true},
+		{"[lessOrEquals('a', 'b')]", true},
+		{"[add(mul(2, 3), div(7, 2))]", 9.0},
+		{"[mod(7, 3)]", 1.0},
+		{"[length(variables('items'))]", 3.0},
+		{"[contains(variables('items'), 'c')]", true},
+		{"[contains(parameters('config'), 'SKU')]", true},
+		{"[empty('')]", true},
+		{"[first(variables('items'))]", "a"},
+		{"[last('xyz')]", "z"},
+		{"[split('a,b;c', createArray(',', ';'))]", []interface{}{"a", "b", "c"}},
+		{"[join(take(variables('items'), 2), '+')]", "a+b"},
+		{"[skip('abcdef', 4)]", "ef"},
+		{"[substring('abcdef', 1, 3)]", "bcd"},
+		{"[replace('a-b-c', '-', '')]", "abc"},
+		{"[toUpper(padLeft('7', 3, '0'))]", "007"},
+		{"[range(2, 3)]", []interface{}{2.0, 3.0, 4.0}},
+		{"[union(createObject('a', 1), createObject('b', 2))]", map[string]interface{}{"a": 1.0, "b": 2.0}},
+		{"[intersection(createArray(1, 2, 3), createArray(2, 3, 4))]", []interface{}{2.0, 3.0}},
+		{"[json('{\"a\": [1]}').a[0]]", 1.0},
+		{"[string(createObject('a', 'b'))]", `{"a":"b"}`},
+		{"[int('42')]", 42.0},
+		{"[min(createArray(4, 2, 8))]", 2.0},
+		{"[max(4, 2, 8)]", 8.0},
+		{"[coalesce(null(), 'x')]", "x"},
+		{"[resourceId('Microsoft.Network/virtualNetworks/subnets', 'vnet', 'default')]", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/infracost/providers/Microsoft.Network/virtualNetworks/vnet/subnets/default"},
+		{"[subscriptionResourceId('Microsoft.Authorization/roleDefinitions', 'abc')]", "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleDefinitions/abc"},
+		{"[length(uniqueString(resourceGroup().id))]", 13.0},
+		{"[filter(variables('items'), lambda('i', not(equals(lambdaVariables('i'), 'b'))))]", []interface{}{"a", "c"}},
+		{"[map(variables('items'), lambda('i', toUpper(lambdaVariables('i'))))]", []interface{}{"A", "B", "C"}},
+		{"[reduce(createArray(1, 2, 3), 0, lambda('acc', 'i', add(lambdaVariables('acc'), lambdaVariables('i'))))]", 6.0},
+		{"[items(createObject('b', 2, 'a', 1))[0].key]", "a"},
+		{"[reference('missing').properties.value]", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			v, err := ctx.evaluate(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestEvaluateExpressionErrors(t *testing.T) {
+	ctx := testEvalContext(t)
+
+	tests := []string{
+		"[parameters('missing')]",
+		"[variables('missing')]",
+		"[unknownFunction()]",
+		"[concat('a']",
+		"[copyIndex()]",
+		"[div(1, 0)]",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ctx.evaluate(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEvaluateCopyLoops(t *testing.T) {
+	ctx := testEvalContext(t)
+
+	v, err := ctx.evaluate(map[string]interface{}{
+		"copy": []interface{}{
+			map[string]interface{}{
+				"name":  "disks",
+				"count": "[parameters('count')]",
+				"input": map[string]interface{}{"lun": "[copyIndex('disks')]"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"disks": []interface{}{
+			map[string]interface{}{"lun": 0.0},
+			map[string]interface{}{"lun": 1.0},
+			map[string]interface{}{"lun": 2.0},
+		},
+	}, v)
+}
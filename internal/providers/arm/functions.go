@@ -0,0 +1,1586 @@
+package arm
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type function func(c *evalContext, args []interface{}) (interface{}, error)
+
+// functions are the supported ARM template functions, keyed by their lower
+// cased name. The functions that return values which are only known after
+// deployment return placeholder values.
+// See https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/template-functions
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		// Deployment
+		"parameters":  fnParameters,
+		"variables":   fnVariables,
+		"copyindex":   fnCopyIndex,
+		"deployment":  fnDeployment,
+		"environment": fnEnvironment,
+
+		// Scope
+		"resourcegroup": fnResourceGroup,
+		"subscription":  fnSubscription,
+		"tenant":        fnTenant,
+
+		// Resource
+		"resourceid":             fnResourceID,
+		"subscriptionresourceid": fnSubscriptionResourceID,
+		"tenantresourceid":       fnTenantResourceID,
+		"extensionresourceid":    fnExtensionResourceID,
+		"reference":              fnReference,
+		"pickzones":              fnPickZones,
+
+		// Logical
+		"and":   fnAnd,
+		"or":    fnOr,
+		"not":   fnNot,
+		"bool":  fnBool,
+		"true":  func(*evalContext, []interface{}) (interface{}, error) { return true, nil },
+		"false": func(*evalContext, []interface{}) (interface{}, error) { return false, nil },
+		"null":  func(*evalContext, []interface{}) (interface{}, error) { return nil, nil },
+
+		// Comparison
+		"coalesce":        fnCoalesce,
+		"equals":          fnEquals,
+		"less":            compareFn(func(c int) bool { return c < 0 }),
+		"lessorequals":    compareFn(func(c int) bool { return c <= 0 }),
+		"greater":         compareFn(func(c int) bool { return c > 0 }),
+		"greaterorequals": compareFn(func(c int) bool { return c >= 0 }),
+
+		// Numeric
+		"add":   arithmeticFn(func(a, b float64) float64 { return a + b }),
+		"sub":   arithmeticFn(func(a, b float64) float64 { return a - b }),
+		"mul":   arithmeticFn(func(a, b float64) float64 { return a * b }),
+		"div":   fnDiv,
+		"mod":   fnMod,
+		"int":   fnInt,
+		"float": fnFloat,
+		"min":   minMaxFn(func(a, b float64) bool { return a < b }),
+		"max":   minMaxFn(func(a, b float64) bool { return a > b }),
+
+		// String
+		"base64":               fnBase64,
+		"base64tostring":       fnBase64ToString,
+		"base64tojson":         fnBase64ToJSON,
+		"datauri":              fnDataURI,
+		"endswith":             fnEndsWith,
+		"format":               fnFormat,
+		"guid":                 fnGUID,
+		"indexof":              fnIndexOf,
+		"lastindexof":          fnLastIndexOf,
+		"newguid":              fnNewGUID,
+		"padleft":              fnPadLeft,
+		"replace":              fnReplace,
+		"split":                fnSplit,
+		"startswith":           fnStartsWith,
+		"string":               fnString,
+		"substring":            fnSubstring,
+		"tolower":              stringFn(strings.ToLower),
+		"toupper":              stringFn(strings.ToUpper),
+		"trim":                 stringFn(strings.TrimSpace),
+		"uniquestring":         fnUniqueString,
+		"uri":                  fnURI,
+		"uricomponent":         stringFn(url.QueryEscape),
+		"uricomponenttostring": fnURIComponentToString,
+		"utcnow":               fnUTCNow,
+		"datetimeadd":          fnDateTimeAdd,
+
+		// Array and object
+		"array":    fnArray,
+		"concat":   fnConcat,
+		"contains": fnContains,
+		"createarray": func(_ *evalContext, args []interface{}) (interface{}, error) {
+			return append([]interface{}{}, args...), nil
+		},
+		"createobject": fnCreateObject,
+		"empty":        fnEmpty,
+		"first":        fnFirst,
+		"last":         fnLast,
+		"flatten":      fnFlatten,
+		"intersection": fnIntersection,
+		"union":        fnUnion,
+		"items":        fnItems,
+		"join":         fnJoin,
+		"json":         fnJSON,
+		"length":       fnLength,
+		"range":        fnRange,
+		"skip":         fnSkip,
+		"take":         fnTake,
+		"objectkeys":   fnObjectKeys,
+		"tryget":       fnTryGet,
+
+		// Lambda
+		"lambdavariables": fnLambdaVariables,
+		"filter":          fnFilter,
+		"map":             fnMap,
+		"reduce":          fnReduce,
+		"sort":            fnSort,
+		"toobject":        fnToObject,
+	}
+}
+
+func expectArgs(name string, args []interface{}, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		if min == max {
+			return fmt.Errorf("%s expects %d arguments, got %d", name, min, len(args))
+		}
+		return fmt.Errorf("%s expects between %d and %d arguments, got %d", name, min, max, len(args))
+	}
+
+	return nil
+}
+
+func fnParameters(c *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("parameters", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	name := toString(args[0])
+	key, ok := lookupKey(c.scope.parameters, name)
+	if !ok {
+		return nil, fmt.Errorf("parameter %q is not defined", name)
+	}
+
+	return c.scope.parameters[key], nil
+}
+
+func fnVariables(c *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("variables", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return c.variable(toString(args[0]))
+}
+
+func fnCopyIndex(c *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("copyIndex", args, 0, 2); err != nil {
+		return nil, err
+	}
+
+	name := ""
+	offset := 0.0
+	for _, a := range args {
+		if s, ok := a.(string); ok {
+			name = s
+			continue
+		}
+		n, err := toNumber(a)
+		if err != nil {
+			return nil, err
+		}
+		offset = n
+	}
+
+	i, ok := c.copyIndexes[strings.ToLower(name)]
+	if !ok {
+		if name == "" {
+			return nil, fmt.Errorf("copyIndex can only be used in a copy loop")
+		}
+		return nil, fmt.Errorf("copy loop %q not found", name)
+	}
+
+	return float64(i) + offset, nil
+}
+
+func fnDeployment(c *evalContext, args []interface{}) (interface{}, error) {
+	return map[string]interface{}{
+		"name":       c.scope.env.deploymentName,
+		"location":   c.scope.env.location,
+		"properties": map[string]interface{}{},
+	}, nil
+}
+
+func fnEnvironment(c *evalContext, args []interface{}) (interface{}, error) {
+	return map[string]interface{}{
+		"name":                    "AzureCloud",
+		"gallery":                 "https://gallery.azure.com/",
+		"graph":                   "https://graph.windows.net/",
+		"portal":                  "https://portal.azure.com",
+		"resourceManager":         "https://management.azure.com/",
+		"activeDirectoryDataLake": "https://datalake.azure.net/",
+		"authentication": map[string]interface{}{
+			"loginEndpoint": "https://login.microsoftonline.com/",
+			"audiences":     []interface{}{"https://management.core.windows.net/", "https://management.azure.com/"},
+		},
+		"suffixes": map[string]interface{}{
+			"acrLoginServer":                      ".azurecr.io",
+			"azureDatalakeAnalyticsCatalogAndJob": "azuredatalakeanalytics.net",
+			"azureDatalakeStoreFileSystem":        "azuredatalakestore.net",
+			"keyvaultDns":                         ".vault.azure.net",
+			"sqlServerHostname":                   ".database.windows.net",
+			"storage":                             "core.windows.net",
+		},
+	}, nil
+}
+
+func fnResourceGroup(c *evalContext, args []interface{}) (interface{}, error) {
+	env := c.scope.env
+	return map[string]interface{}{
+		"id":         fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", env.subscriptionID, env.resourceGroup),
+		"name":       env.resourceGroup,
+		"type":       "Microsoft.Resources/resourceGroups",
+		"location":   env.location,
+		"tags":       map[string]interface{}{},
+		"properties": map[string]interface{}{"provisioningState": "Succeeded"},
+	}, nil
+}
+
+func fnSubscription(c *evalContext, args []interface{}) (interface{}, error) {
+	env := c.scope.env
+	return map[string]interface{}{
+		"id":             "/subscriptions/" + env.subscriptionID,
+		"subscriptionId": env.subscriptionID,
+		"tenantId":       env.tenantID,
+		"displayName":    "infracost",
+	}, nil
+}
+
+func fnTenant(c *evalContext, args []interface{}) (interface{}, error) {
+	env := c.scope.env
+	return map[string]interface{}{
+		"id":          "/tenants/" + env.tenantID,
+		"tenantId":    env.tenantID,
+		"countryCode": "US",
+		"displayName": "infracost",
+	}, nil
+}
+
+// resourceIDSegments returns the provider path of a resource ID from the
+// resource type and names, e.g. Microsoft.Network/virtualNetworks/vnet/subnets/default.
+func resourceIDSegments(resourceType string, names []interface{}) (string, error) {
+	typeParts := strings.Split(resourceType, "/")
+	if len(typeParts) < 2 {
+		return "", fmt.Errorf("invalid resource type %q", resourceType)
+	}
+
+	// The names can be given as separate arguments or as one string with
+	// segments separated by /
+	var nameParts []string
+	for _, n := range names {
+		nameParts = append(nameParts, strings.Split(toString(n), "/")...)
+	}
+
+	if len(nameParts) != len(typeParts)-1 {
+		return "", fmt.Errorf("resource type %q expects %d names, got %d", resourceType, len(typeParts)-1, len(nameParts))
+	}
+
+	segments := []string{typeParts[0], typeParts[1], nameParts[0]}
+	for i, t := range typeParts[2:] {
+		segments = append(segments, t, nameParts[i+1])
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+// splitScopeArgs splits the arguments of the resourceId functions into the
+// optional scope arguments and the resource type and names. The resource type
+// is the first argument that contains a /.
+func splitScopeArgs(args []interface{}) ([]string, string, []interface{}, error) {
+	for i, a := range args {
+		s := toString(a)
+		if strings.Contains(s, "/") && strings.Contains(s, ".") {
+			scope := make([]string, 0, i)
+			for _, p := range args[:i] {
+				scope = append(scope, toString(p))
+			}
+			return scope, s, args[i+1:], nil
+		}
+	}
+
+	return nil, "", nil, fmt.Errorf("resource type not found in arguments")
+}
+
+func fnResourceID(c *evalContext, args []interface{}) (interface{}, error) {
+	scope, resourceType, names, err := splitScopeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	env := c.scope.env
+	subscriptionID := env.subscriptionID
+	resourceGroup := env.resourceGroup
+
+	switch len(scope) {
+	case 1:
+		resourceGroup = scope[0]
+	case 2:
+		subscriptionID = scope[0]
+		resourceGroup = scope[1]
+	}
+
+	path, err := resourceIDSegments(resourceType, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s", subscriptionID, resourceGroup, path), nil
+}
+
+func fnSubscriptionResourceID(c *evalContext, args []interface{}) (interface{}, error) {
+	scope, resourceType, names, err := splitScopeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionID := c.scope.env.subscriptionID
+	if len(scope) > 0 {
+		subscriptionID = scope[0]
+	}
+
+	path, err := resourceIDSegments(resourceType, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("/subscriptions/%s/providers/%s", subscriptionID, path), nil
+}
+
+func fnTenantResourceID(c *evalContext, args []interface{}) (interface{}, error) {
+	_, resourceType, names, err := splitScopeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := resourceIDSegments(resourceType, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return "/providers/" + path, nil
+}
+
+func fnExtensionResourceID(c *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("extensionResourceId", args, 3, -1); err != nil {
+		return nil, err
+	}
+
+	path, err := resourceIDSegments(toString(args[1]), args[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("%s/providers/%s", toString(args[0]), path), nil
+}
+
+// fnReference returns the properties of a resource in the template. Most
+// properties are only known after deployment, so only the properties set in
+// the template and the outputs of nested deployments are returned.
+func fnReference(c *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("reference", args, 1, 3); err != nil {
+		return nil, err
+	}
+
+	r, ok := c.scope.env.resources[strings.ToLower(toString(args[0]))]
+	if !ok {
+		log.Debug().Msgf("Resource %s not found in template, its properties are only known after deployment", toString(args[0]))
+		return map[string]interface{}{}, nil
+	}
+
+	if len(args) == 3 && strings.EqualFold(toString(args[2]), "full") {
+		return r, nil
+	}
+
+	props, _ := r["properties"].(map[string]interface{})
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+
+	return props, nil
+}
+
+func fnPickZones(c *evalContext, args []interface{}) (interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func fnAnd(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("and", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	for _, a := range args {
+		b, err := toBool(a)
+		if err != nil {
+			return nil, err
+		}
+		if !b {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func fnOr(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("or", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	for _, a := range args {
+		b, err := toBool(a)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func fnNot(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("not", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	b, err := toBool(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return !b, nil
+}
+
+func fnBool(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("bool", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return toBool(args[0])
+}
+
+func fnCoalesce(_ *evalContext, args []interface{}) (interface{}, error) {
+	for _, a := range args {
+		if a != nil {
+			return a, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func fnEquals(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("equals", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	return reflect.DeepEqual(args[0], args[1]), nil
+}
+
+func compareFn(cmp func(int) bool) function {
+	return func(_ *evalContext, args []interface{}) (interface{}, error) {
+		if err := expectArgs("comparison", args, 2, 2); err != nil {
+			return nil, err
+		}
+
+		if a, ok := args[0].(string); ok {
+			b, ok := args[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("can't compare string with %T", args[1])
+			}
+			return cmp(strings.Compare(a, b)), nil
+		}
+
+		a, err := toNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := toNumber(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case a < b:
+			return cmp(-1), nil
+		case a > b:
+			return cmp(1), nil
+		}
+
+		return cmp(0), nil
+	}
+}
+
+func numberArgs(name string, args []interface{}, n int) ([]float64, error) {
+	if err := expectArgs(name, args, n, n); err != nil {
+		return nil, err
+	}
+
+	nums := make([]float64, 0, len(args))
+	for _, a := range args {
+		f, err := toNumber(a)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, f)
+	}
+
+	return nums, nil
+}
+
+func arithmeticFn(op func(a, b float64) float64) function {
+	return func(_ *evalContext, args []interface{}) (interface{}, error) {
+		nums, err := numberArgs("arithmetic", args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		return op(nums[0], nums[1]), nil
+	}
+}
+
+func fnDiv(_ *evalContext, args []interface{}) (interface{}, error) {
+	nums, err := numberArgs("div", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if nums[1] == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+
+	// ARM only supports integer division
+	return math.Trunc(nums[0] / nums[1]), nil
+}
+
+func fnMod(_ *evalContext, args []interface{}) (interface{}, error) {
+	nums, err := numberArgs("mod", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if nums[1] == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+
+	return math.Mod(nums[0], nums[1]), nil
+}
+
+func fnInt(_ *evalContext, args []interface{}) (interface{}, error) {
+	nums, err := numberArgs("int", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return math.Trunc(nums[0]), nil
+}
+
+func fnFloat(_ *evalContext, args []interface{}) (interface{}, error) {
+	nums, err := numberArgs("float", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return nums[0], nil
+}
+
+func minMaxFn(better func(a, b float64) bool) function {
+	return func(_ *evalContext, args []interface{}) (interface{}, error) {
+		values := args
+		if len(args) == 1 {
+			if arr, ok := args[0].([]interface{}); ok {
+				values = arr
+			}
+		}
+
+		if len(values) == 0 {
+			return nil, fmt.Errorf("expected at least 1 value")
+		}
+
+		var result float64
+		for i, v := range values {
+			n, err := toNumber(v)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 || better(n, result) {
+				result = n
+			}
+		}
+
+		return result, nil
+	}
+}
+
+func stringFn(fn func(string) string) function {
+	return func(_ *evalContext, args []interface{}) (interface{}, error) {
+		if err := expectArgs("string function", args, 1, 1); err != nil {
+			return nil, err
+		}
+
+		return fn(toString(args[0])), nil
+	}
+}
+
+func fnBase64(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("base64", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(toString(args[0]))), nil
+}
+
+func fnBase64ToString(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("base64ToString", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(toString(args[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func fnBase64ToJSON(c *evalContext, args []interface{}) (interface{}, error) {
+	s, err := fnBase64ToString(c, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return fnJSON(c, []interface{}{s})
+}
+
+func fnDataURI(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("dataUri", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return "data:text/plain;charset=utf8;base64," + base64.StdEncoding.EncodeToString([]byte(toString(args[0]))), nil
+}
+
+func fnEndsWith(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("endsWith", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	return strings.HasSuffix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1]))), nil
+}
+
+func fnStartsWith(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("startsWith", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	return strings.HasPrefix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1]))), nil
+}
+
+var formatItemRe = regexp.MustCompile(`\{\{|\}\}|\{(\d+)(?::([^}]*))?\}`)
+
+// fnFormat implements the .NET composite formatting used by format(), e.g.
+// format('{0}-{1:D3}', 'vm', 1). Only the D format specifier is supported,
+// other specifiers are ignored.
+func fnFormat(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("format", args, 1, -1); err != nil {
+		return nil, err
+	}
+
+	var err error
+	result := formatItemRe.ReplaceAllStringFunc(toString(args[0]), func(m string) string {
+		switch m {
+		case "{{":
+			return "{"
+		case "}}":
+			return "}"
+		}
+
+		parts := formatItemRe.FindStringSubmatch(m)
+		i, _ := strconv.Atoi(parts[1])
+		if i+1 >= len(args) {
+			err = fmt.Errorf("format index %d is out of range", i)
+			return m
+		}
+
+		v := args[i+1]
+		spec := parts[2]
+		if len(spec) > 0 && (spec[0] == 'D' || spec[0] == 'd') {
+			if n, nErr := toNumber(v); nErr == nil {
+				width, _ := strconv.Atoi(spec[1:])
+				return fmt.Sprintf("%0*d", width, int64(n))
+			}
+		}
+
+		return toString(v)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// fnGUID returns a deterministic GUID from the arguments.
+func fnGUID(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("guid", args, 1, -1); err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, 0, len(args))
+	for _, a := range args {
+		parts = append(parts, toString(a))
+	}
+
+	h := sha1.Sum([]byte(strings.Join(parts, "-"))) // nolint:gosec
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16]), nil
+}
+
+func fnNewGUID(_ *evalContext, args []interface{}) (interface{}, error) {
+	return fnGUID(nil, []interface{}{time.Now().String()})
+}
+
+// fnUniqueString returns a deterministic 13 character string from the
+// arguments. The value differs from the one Azure generates.
+func fnUniqueString(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("uniqueString", args, 1, -1); err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, 0, len(args))
+	for _, a := range args {
+		parts = append(parts, toString(a))
+	}
+
+	h := sha256.Sum256([]byte(strings.Join(parts, "-")))
+	return strings.ToLower(base32.StdEncoding.EncodeToString(h[:]))[:13], nil
+}
+
+func fnIndexOf(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("indexOf", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	if arr, ok := args[0].([]interface{}); ok {
+		for i, v := range arr {
+			if reflect.DeepEqual(v, args[1]) {
+				return float64(i), nil
+			}
+		}
+		return float64(-1), nil
+	}
+
+	return float64(strings.Index(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))), nil
+}
+
+func fnLastIndexOf(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("lastIndexOf", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	if arr, ok := args[0].([]interface{}); ok {
+		for i := len(arr) - 1; i >= 0; i-- {
+			if reflect.DeepEqual(arr[i], args[1]) {
+				return float64(i), nil
+			}
+		}
+		return float64(-1), nil
+	}
+
+	return float64(strings.LastIndex(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1])))), nil
+}
+
+func fnPadLeft(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("padLeft", args, 2, 3); err != nil {
+		return nil, err
+	}
+
+	s := toString(args[0])
+	total, err := toNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	pad := " "
+	if len(args) == 3 {
+		pad = toString(args[2])
+	}
+
+	for len(s) < int(total) && pad != "" {
+		s = pad[:1] + s
+	}
+
+	return s, nil
+}
+
+func fnReplace(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("replace", args, 3, 3); err != nil {
+		return nil, err
+	}
+
+	return strings.ReplaceAll(toString(args[0]), toString(args[1]), toString(args[2])), nil
+}
+
+func fnSplit(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("split", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	var delimiters []string
+	if arr, ok := args[1].([]interface{}); ok {
+		for _, d := range arr {
+			delimiters = append(delimiters, toString(d))
+		}
+	} else {
+		delimiters = []string{toString(args[1])}
+	}
+
+	parts := []string{toString(args[0])}
+	for _, d := range delimiters {
+		var next []string
+		for _, p := range parts {
+			next = append(next, strings.Split(p, d)...)
+		}
+		parts = next
+	}
+
+	out := make([]interface{}, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, p)
+	}
+
+	return out, nil
+}
+
+func fnString(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("string", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return toString(args[0]), nil
+}
+
+func fnSubstring(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("substring", args, 1, 3); err != nil {
+		return nil, err
+	}
+
+	s := toString(args[0])
+	start := 0
+	length := len(s)
+
+	if len(args) > 1 {
+		n, err := toNumber(args[1])
+		if err != nil {
+			return nil, err
+		}
+		start = int(n)
+		length = len(s) - start
+	}
+
+	if len(args) > 2 {
+		n, err := toNumber(args[2])
+		if err != nil {
+			return nil, err
+		}
+		length = int(n)
+	}
+
+	if start < 0 || length < 0 || start+length > len(s) {
+		return nil, fmt.Errorf("substring index is out of range")
+	}
+
+	return s[start : start+length], nil
+}
+
+func fnURI(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("uri", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(toString(args[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := url.Parse(toString(args[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	return base.ResolveReference(rel).String(), nil
+}
+
+func fnURIComponentToString(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("uriComponentToString", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return url.QueryUnescape(toString(args[0]))
+}
+
+func fnUTCNow(_ *evalContext, args []interface{}) (interface{}, error) {
+	return time.Now().UTC().Format("20060102T150405Z"), nil
+}
+
+func fnDateTimeAdd(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("dateTimeAdd", args, 2, 3); err != nil {
+		return nil, err
+	}
+
+	// The duration is ignored since the result is only used in values that
+	// don't affect the cost, e.g. expiry dates.
+	return toString(args[0]), nil
+}
+
+func fnArray(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("array", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	if arr, ok := args[0].([]interface{}); ok {
+		return arr, nil
+	}
+
+	return []interface{}{args[0]}, nil
+}
+
+func fnConcat(_ *evalContext, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		if _, ok := args[0].([]interface{}); ok {
+			out := []interface{}{}
+			for _, a := range args {
+				arr, ok := a.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("concat expects all arguments to be arrays")
+				}
+				out = append(out, arr...)
+			}
+			return out, nil
+		}
+	}
+
+	var b strings.Builder
+	for _, a := range args {
+		b.WriteString(toString(a))
+	}
+
+	return b.String(), nil
+}
+
+func fnContains(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("contains", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case []interface{}:
+		for _, v := range t {
+			if reflect.DeepEqual(v, args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		_, ok := lookupKey(t, toString(args[1]))
+		return ok, nil
+	}
+
+	return strings.Contains(toString(args[0]), toString(args[1])), nil
+}
+
+func fnCreateObject(_ *evalContext, args []interface{}) (interface{}, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("createObject expects an even number of arguments")
+	}
+
+	out := map[string]interface{}{}
+	for i := 0; i < len(args); i += 2 {
+		out[toString(args[i])] = args[i+1]
+	}
+
+	return out, nil
+}
+
+func fnEmpty(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("empty", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case nil:
+		return true, nil
+	case string:
+		return t == "", nil
+	case []interface{}:
+		return len(t) == 0, nil
+	case map[string]interface{}:
+		return len(t) == 0, nil
+	}
+
+	return false, nil
+}
+
+func fnFirst(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("first", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case []interface{}:
+		if len(t) == 0 {
+			return nil, nil
+		}
+		return t[0], nil
+	case string:
+		if t == "" {
+			return "", nil
+		}
+		return t[:1], nil
+	}
+
+	return nil, fmt.Errorf("first expects an array or string")
+}
+
+func fnLast(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("last", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case []interface{}:
+		if len(t) == 0 {
+			return nil, nil
+		}
+		return t[len(t)-1], nil
+	case string:
+		if t == "" {
+			return "", nil
+		}
+		return t[len(t)-1:], nil
+	}
+
+	return nil, fmt.Errorf("last expects an array or string")
+}
+
+func fnFlatten(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("flatten", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("flatten expects an array")
+	}
+
+	out := []interface{}{}
+	for _, v := range arr {
+		inner, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("flatten expects an array of arrays")
+		}
+		out = append(out, inner...)
+	}
+
+	return out, nil
+}
+
+func fnIntersection(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("intersection", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	switch first := args[0].(type) {
+	case []interface{}:
+		out := []interface{}{}
+		for _, v := range first {
+			inAll := true
+			for _, other := range args[1:] {
+				arr, _ := other.([]interface{})
+				if !containsValue(arr, v) {
+					inAll = false
+					break
+				}
+			}
+			if inAll && !containsValue(out, v) {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for k, v := range first {
+			inAll := true
+			for _, other := range args[1:] {
+				obj, _ := other.(map[string]interface{})
+				if ov, ok := obj[k]; !ok || !reflect.DeepEqual(ov, v) {
+					inAll = false
+					break
+				}
+			}
+			if inAll {
+				out[k] = v
+			}
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("intersection expects arrays or objects")
+}
+
+func fnUnion(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("union", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	if _, ok := args[0].([]interface{}); ok {
+		out := []interface{}{}
+		for _, a := range args {
+			arr, _ := a.([]interface{})
+			for _, v := range arr {
+				if !containsValue(out, v) {
+					out = append(out, v)
+				}
+			}
+		}
+		return out, nil
+	}
+
+	out := map[string]interface{}{}
+	for _, a := range args {
+		obj, ok := a.(map[string]interface{})
+		if !ok && a != nil {
+			return nil, fmt.Errorf("union expects arrays or objects")
+		}
+		for k, v := range obj {
+			out[k] = v
+		}
+	}
+
+	return out, nil
+}
+
+func fnItems(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("items", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	obj, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("items expects an object")
+	}
+
+	out := []interface{}{}
+	for _, k := range sortedKeys(obj) {
+		out = append(out, map[string]interface{}{"key": k, "value": obj[k]})
+	}
+
+	return out, nil
+}
+
+func fnJoin(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("join", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("join expects an array")
+	}
+
+	parts := make([]string, 0, len(arr))
+	for _, v := range arr {
+		parts = append(parts, toString(v))
+	}
+
+	return strings.Join(parts, toString(args[1])), nil
+}
+
+func fnJSON(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("json", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(toString(args[0])), &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func fnLength(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("length", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case string:
+		return float64(len(t)), nil
+	case []interface{}:
+		return float64(len(t)), nil
+	case map[string]interface{}:
+		return float64(len(t)), nil
+	case nil:
+		return float64(0), nil
+	}
+
+	return nil, fmt.Errorf("length expects a string, array or object")
+}
+
+func fnRange(_ *evalContext, args []interface{}) (interface{}, error) {
+	nums, err := numberArgs("range", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, 0, int(nums[1]))
+	for i := 0; i < int(nums[1]); i++ {
+		out = append(out, nums[0]+float64(i))
+	}
+
+	return out, nil
+}
+
+func fnSkip(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("skip", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	n, err := toNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case []interface{}:
+		i := clamp(int(n), len(t))
+		return t[i:], nil
+	case string:
+		i := clamp(int(n), len(t))
+		return t[i:], nil
+	}
+
+	return nil, fmt.Errorf("skip expects an array or string")
+}
+
+func fnTake(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("take", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	n, err := toNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := args[0].(type) {
+	case []interface{}:
+		i := clamp(int(n), len(t))
+		return t[:i], nil
+	case string:
+		i := clamp(int(n), len(t))
+		return t[:i], nil
+	}
+
+	return nil, fmt.Errorf("take expects an array or string")
+}
+
+func fnObjectKeys(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("objectKeys", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	obj, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("objectKeys expects an object")
+	}
+
+	out := []interface{}{}
+	for _, k := range sortedKeys(obj) {
+		out = append(out, k)
+	}
+
+	return out, nil
+}
+
+func fnTryGet(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("tryGet", args, 2, -1); err != nil {
+		return nil, err
+	}
+
+	v := args[0]
+	for _, k := range args[1:] {
+		next, err := indexValue(v, k)
+		if err != nil {
+			return nil, nil
+		}
+		v = next
+	}
+
+	return v, nil
+}
+
+func fnLambdaVariables(c *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("lambdaVariables", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	v, ok := c.lambdaVars[strings.ToLower(toString(args[0]))]
+	if !ok {
+		return nil, fmt.Errorf("lambda variable %q is not defined", toString(args[0]))
+	}
+
+	return v, nil
+}
+
+func lambdaArgs(name string, args []interface{}, n int) ([]interface{}, *lambdaValue, error) {
+	if err := expectArgs(name, args, n, n); err != nil {
+		return nil, nil, err
+	}
+
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects an array", name)
+	}
+
+	l, ok := args[len(args)-1].(*lambdaValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects a lambda function", name)
+	}
+
+	return arr, l, nil
+}
+
+func fnFilter(_ *evalContext, args []interface{}) (interface{}, error) {
+	arr, l, err := lambdaArgs("filter", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []interface{}{}
+	for _, v := range arr {
+		keep, err := l.apply(v)
+		if err != nil {
+			return nil, err
+		}
+		if b, _ := toBool(keep); b {
+			out = append(out, v)
+		}
+	}
+
+	return out, nil
+}
+
+func fnMap(_ *evalContext, args []interface{}) (interface{}, error) {
+	arr, l, err := lambdaArgs("map", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, 0, len(arr))
+	for i, v := range arr {
+		mapped, err := l.apply(v, float64(i))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mapped)
+	}
+
+	return out, nil
+}
+
+func fnReduce(_ *evalContext, args []interface{}) (interface{}, error) {
+	arr, l, err := lambdaArgs("reduce", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := args[1]
+	for i, v := range arr {
+		acc, err = l.apply(acc, v, float64(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return acc, nil
+}
+
+func fnSort(_ *evalContext, args []interface{}) (interface{}, error) {
+	arr, l, err := lambdaArgs("sort", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	out := append([]interface{}{}, arr...)
+	var sortErr error
+	sort.SliceStable(out, func(i, j int) bool {
+		less, err := l.apply(out[i], out[j])
+		if err != nil {
+			sortErr = err
+			return false
+		}
+		b, _ := toBool(less)
+		return b
+	})
+
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	return out, nil
+}
+
+func fnToObject(_ *evalContext, args []interface{}) (interface{}, error) {
+	if err := expectArgs("toObject", args, 2, 3); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("toObject expects an array")
+	}
+
+	keyFn, ok := args[1].(*lambdaValue)
+	if !ok {
+		return nil, fmt.Errorf("toObject expects a lambda function")
+	}
+
+	var valueFn *lambdaValue
+	if len(args) == 3 {
+		valueFn, ok = args[2].(*lambdaValue)
+		if !ok {
+			return nil, fmt.Errorf("toObject expects a lambda function")
+		}
+	}
+
+	out := map[string]interface{}{}
+	for _, v := range arr {
+		k, err := keyFn.apply(v)
+		if err != nil {
+			return nil, err
+		}
+
+		value := v
+		if valueFn != nil {
+			value, err = valueFn.apply(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		out[toString(k)] = value
+	}
+
+	return out, nil
+}
+
+func containsValue(arr []interface{}, v interface{}) bool {
+	for _, item := range arr {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func clamp(i, max int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > max {
+		return max
+	}
+
+	return i
+}
+
+// toString converts a value to a string the way ARM does when it is used in
+// a string function.
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		if t {
+			return "True"
+		}
+		return "False"
+	case float64:
+		if t == math.Trunc(t) {
+			return strconv.FormatInt(int64(t), 10)
+		}
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+func toNumber(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case int:
+		return float64(t), nil
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return 0, fmt.Errorf("can't convert %q to a number", t)
+		}
+		return f, nil
+	}
+
+	return 0, fmt.Errorf("can't convert %T to a number", v)
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case float64:
+		return t != 0, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(t))
+		if err != nil {
+			return false, fmt.Errorf("can't convert %q to a bool", t)
+		}
+		return b, nil
+	case nil:
+		return false, nil
+	}
+
+	return false, fmt.Errorf("can't convert %T to a bool", v)
+}
@@ -0,0 +1,88 @@
+package arm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// loadParameters returns the parameter values from the parameter files and
+// the overrides, with the overrides taking precedence. Relative parameter file
+// paths are relative to the directory of the template.
+func loadParameters(templatePath string, files []string, overrides map[string]string) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+
+	dir := filepath.Dir(templatePath)
+
+	for _, f := range files {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(dir, f)
+		}
+
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading ARM parameter file")
+		}
+
+		fileParams, err := parseParameterFile(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing ARM parameter file %s", f)
+		}
+
+		for k, v := range fileParams {
+			params[k] = v
+		}
+	}
+
+	for k, v := range overrides {
+		params[k] = v
+	}
+
+	return params, nil
+}
+
+// parseParameterFile parses an ARM deployment parameter file, which maps the
+// parameter names to an object with the value. Parameters that reference a
+// Key Vault secret are skipped since secrets don't affect the cost. An object
+// mapping the parameter names directly to their values is also supported.
+func parseParameterFile(b []byte) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	values := raw
+	if p, ok := raw["parameters"].(map[string]interface{}); ok {
+		values = p
+	}
+
+	params := map[string]interface{}{}
+	for k, v := range values {
+		if k == "$schema" || k == "contentVersion" {
+			continue
+		}
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			params[k] = v
+			continue
+		}
+
+		if value, ok := m["value"]; ok {
+			params[k] = value
+			continue
+		}
+
+		if _, ok := m["reference"]; ok {
+			log.Debug().Msgf("Skipping parameter %s as it references a Key Vault secret", k)
+			continue
+		}
+
+		params[k] = v
+	}
+
+	return params, nil
+}
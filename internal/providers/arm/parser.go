@@ -0,0 +1,101 @@
+package arm
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type Parser struct {
+	ctx *config.ProjectContext
+}
+
+func NewParser(ctx *config.ProjectContext) *Parser {
+	return &Parser{ctx}
+}
+
+func (p *Parser) createPartialResource(d *schema.ResourceData, u *schema.UsageData) *schema.PartialResource {
+	registryMap := GetResourceRegistryMap()
+
+	d.UsageData = u
+
+	if registryItem, ok := (*registryMap)[strings.ToLower(d.Type)]; ok {
+		if registryItem.NoPrice {
+			return schema.NewPartialResource(d, &schema.Resource{
+				Name:        d.Address,
+				IsSkipped:   true,
+				NoPrice:     true,
+				SkipMessage: "Free resource.",
+			}, nil, nil)
+		}
+
+		if registryItem.CoreRFunc != nil {
+			if core := registryItem.CoreRFunc(d); core != nil {
+				return schema.NewPartialResource(d, nil, core, nil)
+			}
+		} else if res := registryItem.RFunc(d, u); res != nil {
+			if u != nil {
+				res.EstimationSummary = u.CalcEstimationSummary()
+			}
+
+			return schema.NewPartialResource(d, res, nil, nil)
+		}
+	}
+
+	return schema.NewPartialResource(d, &schema.Resource{
+		Name:        d.Address,
+		IsSkipped:   true,
+		SkipMessage: "This resource is not currently supported",
+	}, nil, nil)
+}
+
+// parseTemplate returns the partial resources of the evaluated template.
+// Resources are addressed by their type and name, e.g.
+// Microsoft.Storage/storageAccounts/mystorage, which is unique within the
+// resource group the template is deployed to. Usage can be keyed by the address
+// or the resource name.
+func (p *Parser) parseTemplate(t *template, location string, usage schema.UsageMap) ([]*schema.PartialResource, []*schema.PartialResource, error) {
+	var resources []*schema.PartialResource
+
+	for _, r := range t.resources {
+		values := make(map[string]interface{}, len(r.Values)+1)
+		for k, v := range r.Values {
+			values[k] = v
+		}
+		if l, _ := values["location"].(string); l == "" {
+			values["location"] = location
+		}
+
+		b, err := json.Marshal(values)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rawValues := gjson.ParseBytes(b)
+		tags := parseTags(rawValues)
+
+		address := r.ID()
+		usageData := usage.Get(address)
+		if usageData == nil {
+			usageData = usage.Get(r.Name)
+		}
+
+		resourceData := schema.NewResourceData(r.Type, "azurerm", address, &tags, rawValues)
+		resources = append(resources, p.createPartialResource(resourceData, usageData))
+	}
+
+	return resources, resources, nil
+}
+
+func parseTags(values gjson.Result) map[string]string {
+	tags := map[string]string{}
+	for k, v := range values.Get("tags").Map() {
+		tags[k] = v.String()
+	}
+
+	return tags
+}
@@ -0,0 +1,47 @@
+package arm
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/infracost/infracost/internal/providers/arm/azure"
+)
+
+// ResourceRegistryMap maps the lower cased ARM resource types to their
+// registry items, since resource types are case insensitive.
+type ResourceRegistryMap map[string]*schema.RegistryItem
+
+var (
+	resourceRegistryMap ResourceRegistryMap
+	once                sync.Once
+)
+
+func GetResourceRegistryMap() *ResourceRegistryMap {
+	once.Do(func() {
+		resourceRegistryMap = make(ResourceRegistryMap)
+
+		// Merge all resource registries
+		for _, registryItem := range azure.ResourceRegistry {
+			resourceRegistryMap[strings.ToLower(registryItem.Name)] = registryItem
+		}
+		for _, registryItem := range createFreeResources(azure.FreeResources) {
+			resourceRegistryMap[strings.ToLower(registryItem.Name)] = registryItem
+		}
+	})
+
+	return &resourceRegistryMap
+}
+
+func createFreeResources(l []string) []*schema.RegistryItem {
+	freeResources := make([]*schema.RegistryItem, 0)
+	for _, resourceName := range l {
+		freeResources = append(freeResources, &schema.RegistryItem{
+			Name:    resourceName,
+			NoPrice: true,
+			Notes:   []string{"Free resource."},
+		})
+	}
+	return freeResources
+}
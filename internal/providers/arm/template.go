@@ -0,0 +1,498 @@
+package arm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	deploymentsType = "Microsoft.Resources/deployments"
+
+	// maxNestedDeploymentDepth limits how deep nested deployments are
+	// evaluated, to avoid templates that link to themselves.
+	maxNestedDeploymentDepth = 5
+)
+
+// resource is an evaluated resource of the template. Resources in copy loops
+// are expanded into a resource per iteration and child resources are flattened
+// with their full type and name, e.g. Microsoft.Network/virtualNetworks/subnets
+// and vnet/default.
+type resource struct {
+	Type   string
+	Name   string
+	Values map[string]interface{}
+}
+
+// ID returns the provider path of the resource ID, which is unique within a
+// resource group, e.g. Microsoft.Storage/storageAccounts/mystorage.
+func (r *resource) ID() string {
+	path, err := resourceIDSegments(r.Type, []interface{}{r.Name})
+	if err != nil {
+		return r.Type + "/" + r.Name
+	}
+
+	return path
+}
+
+type template struct {
+	resources []*resource
+}
+
+// IsTemplate returns true if the file is an ARM template or a Bicep file.
+func IsTemplate(path string) bool {
+	if strings.EqualFold(filepath.Ext(path), ".bicep") {
+		return true
+	}
+
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var raw struct {
+		Schema    string      `json:"$schema"`
+		Resources interface{} `json:"resources"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(raw.Schema), "deploymenttemplate.json") && raw.Resources != nil
+}
+
+// readTemplate returns the JSON of the template. Bicep files are compiled to
+// an ARM template using the Bicep CLI, either standalone or through the
+// Azure CLI.
+func readTemplate(path string) ([]byte, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".bicep") {
+		return os.ReadFile(path)
+	}
+
+	var cmd *exec.Cmd
+	if bicep, err := exec.LookPath("bicep"); err == nil {
+		cmd = exec.Command(bicep, "build", path, "--stdout")
+	} else if az, err := exec.LookPath("az"); err == nil {
+		cmd = exec.Command(az, "bicep", "build", "--file", path, "--stdout")
+	} else {
+		return nil, errors.New("Bicep files need the Bicep CLI or Azure CLI to be installed, alternatively run 'bicep build' and use the generated ARM template")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error compiling Bicep file: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// loadTemplate reads the template and evaluates its resources using the
+// parameter values.
+func loadTemplate(path string, params map[string]interface{}, env *environment) (*template, error) {
+	b, err := readTemplate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	resources, _, err := evaluateTemplate(raw, params, env, filepath.Dir(path), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &template{resources: resources}, nil
+}
+
+// evaluateTemplate returns the evaluated resources and outputs of a template.
+// dir is used to resolve the relative paths of linked templates.
+func evaluateTemplate(raw map[string]interface{}, params map[string]interface{}, env *environment, dir string, depth int) ([]*resource, map[string]interface{}, error) {
+	scope, err := newTemplateScope(raw, params, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e := &templateEvaluator{dir: dir, depth: depth}
+	ctx := newEvalContext(scope)
+
+	for _, r := range templateResources(raw) {
+		if err := e.expandResource(ctx, r, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	outputs := map[string]interface{}{}
+	rawOutputs, _ := raw["outputs"].(map[string]interface{})
+	for name, o := range rawOutputs {
+		output, _ := o.(map[string]interface{})
+		v, err := ctx.evaluate(output["value"])
+		if err != nil {
+			log.Debug().Msgf("Could not evaluate output %s: %s", name, err)
+		}
+		outputs[name] = map[string]interface{}{"type": output["type"], "value": v}
+	}
+
+	return e.resources, outputs, nil
+}
+
+// templateResources returns the resources of the template. Templates using
+// languageVersion 2.0 define the resources as an object keyed by their
+// symbolic name, which is stored in the resource so it can be referenced.
+func templateResources(raw map[string]interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+
+	switch t := raw["resources"].(type) {
+	case []interface{}:
+		for _, r := range t {
+			if m, ok := r.(map[string]interface{}); ok {
+				out = append(out, m)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range sortedKeys(t) {
+			m, ok := t[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			withName := make(map[string]interface{}, len(m)+1)
+			for k, v := range m {
+				withName[k] = v
+			}
+			withName[symbolicNameKey] = name
+			out = append(out, withName)
+		}
+	}
+
+	return out
+}
+
+const symbolicNameKey = "__symbolicName"
+
+// variableLoop is a variable defined by a copy loop in the variables section.
+type variableLoop struct {
+	loop map[string]interface{}
+}
+
+func newTemplateScope(raw map[string]interface{}, params map[string]interface{}, env *environment) (*templateScope, error) {
+	scope := &templateScope{
+		env:        env,
+		parameters: map[string]interface{}{},
+		variables:  map[string]interface{}{},
+		values:     map[string]interface{}{},
+		evaluating: map[string]bool{},
+	}
+
+	rawVars, _ := raw["variables"].(map[string]interface{})
+	for k, v := range rawVars {
+		if k == "copy" {
+			loops, _ := v.([]interface{})
+			for _, l := range loops {
+				if loop, ok := l.(map[string]interface{}); ok {
+					name, _ := loop["name"].(string)
+					scope.variables[name] = variableLoop{loop: loop}
+				}
+			}
+			continue
+		}
+		scope.variables[k] = v
+	}
+
+	// Parameter default values can reference other parameters so they are
+	// evaluated after all the provided values are set.
+	defs, _ := raw["parameters"].(map[string]interface{})
+	var defaults []string
+	for _, name := range sortedKeys(defs) {
+		def, _ := defs[name].(map[string]interface{})
+		paramType, _ := def["type"].(string)
+
+		if key, ok := lookupKey(params, name); ok {
+			v, err := convertParameter(params[key], paramType)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for parameter %q: %w", name, err)
+			}
+			scope.parameters[name] = v
+			continue
+		}
+
+		defaults = append(defaults, name)
+	}
+
+	// Defaults that reference parameters which aren't set yet are retried
+	// until no more defaults can be evaluated.
+	ctx := newEvalContext(scope)
+	for len(defaults) > 0 {
+		var pending []string
+		var lastErr error
+
+		for _, name := range defaults {
+			def, _ := defs[name].(map[string]interface{})
+			v, ok := def["defaultValue"]
+			if !ok {
+				log.Warn().Msgf("No value found for parameter %s, set it with a parameter file or --parameter-overrides", name)
+				scope.parameters[name] = nil
+				continue
+			}
+
+			evaluated, err := ctx.evaluate(v)
+			if err != nil {
+				pending = append(pending, name)
+				lastErr = fmt.Errorf("error evaluating default value of parameter %q: %w", name, err)
+				continue
+			}
+			scope.parameters[name] = evaluated
+		}
+
+		if len(pending) == len(defaults) {
+			return nil, lastErr
+		}
+		defaults = pending
+	}
+
+	return scope, nil
+}
+
+// convertParameter converts the parameter values given as strings, e.g. with
+// --parameter-overrides, to the type of the parameter.
+func convertParameter(v interface{}, paramType string) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	switch strings.ToLower(paramType) {
+	case "int":
+		return toNumber(s)
+	case "bool":
+		return toBool(s)
+	case "array", "object":
+		var out interface{}
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	return s, nil
+}
+
+type templateEvaluator struct {
+	dir       string
+	depth     int
+	resources []*resource
+}
+
+type parentResource struct {
+	Type string
+	Name string
+}
+
+// expandResource evaluates a resource, or each iteration of a resource with a
+// copy loop.
+func (e *templateEvaluator) expandResource(ctx *evalContext, raw map[string]interface{}, parent *parentResource) error {
+	if existing, _ := raw["existing"].(bool); existing {
+		return nil
+	}
+
+	loop, ok := raw["copy"].(map[string]interface{})
+	if !ok {
+		return e.evaluateResource(ctx, raw, parent)
+	}
+
+	name, _ := loop["name"].(string)
+	count, err := ctx.copyCount(loop)
+	if err != nil {
+		return fmt.Errorf("error evaluating count of copy loop %q: %w", name, err)
+	}
+
+	for i := 0; i < count; i++ {
+		loopCtx := ctx.withCopyIndex("", i).withCopyIndex(name, i)
+		if err := e.evaluateResource(loopCtx, raw, parent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *templateEvaluator) evaluateResource(ctx *evalContext, raw map[string]interface{}, parent *parentResource) error {
+	if cond, ok := raw["condition"]; ok {
+		v, err := ctx.evaluate(cond)
+		if err != nil {
+			return fmt.Errorf("error evaluating condition: %w", err)
+		}
+		b, err := toBool(v)
+		if err != nil {
+			return fmt.Errorf("error evaluating condition: %w", err)
+		}
+		if !b {
+			return nil
+		}
+	}
+
+	resourceType, err := ctx.evaluate(raw["type"])
+	if err != nil {
+		return fmt.Errorf("error evaluating resource type: %w", err)
+	}
+
+	name, err := ctx.evaluate(raw["name"])
+	if err != nil {
+		return fmt.Errorf("error evaluating resource name: %w", err)
+	}
+
+	r := &resource{Type: toString(resourceType), Name: toString(name)}
+
+	// Child resources defined in the resources property of their parent use
+	// a type and name relative to the parent.
+	if parent != nil && strings.Count(r.Type, "/") == 0 {
+		r.Type = parent.Type + "/" + r.Type
+		r.Name = parent.Name + "/" + r.Name
+	}
+
+	r.Values = map[string]interface{}{}
+	for k, v := range raw {
+		switch k {
+		case "copy", "condition", "resources", "dependsOn", "type", "name", symbolicNameKey:
+			continue
+		case "properties":
+			if strings.EqualFold(r.Type, deploymentsType) {
+				continue
+			}
+		}
+
+		evaluated, err := ctx.evaluate(v)
+		if err != nil {
+			log.Debug().Msgf("Could not evaluate %s of resource %s: %s", k, r.ID(), err)
+			continue
+		}
+		r.Values[k] = evaluated
+	}
+	r.Values["type"] = r.Type
+	r.Values["name"] = r.Name
+
+	if strings.EqualFold(r.Type, deploymentsType) {
+		if err := e.evaluateDeployment(ctx, raw, r); err != nil {
+			return err
+		}
+	}
+
+	e.resources = append(e.resources, r)
+	ctx.scope.env.register(r, raw[symbolicNameKey])
+
+	children, _ := raw["resources"].([]interface{})
+	for _, c := range children {
+		child, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := e.expandResource(ctx, child, &parentResource{Type: r.Type, Name: r.Name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// register adds the resource so that it can be used by reference().
+func (env *environment) register(r *resource, symbolicName interface{}) {
+	id := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s", env.subscriptionID, env.resourceGroup, r.ID())
+	env.resources[strings.ToLower(id)] = r.Values
+	env.resources[strings.ToLower(r.Name)] = r.Values
+
+	if s, ok := symbolicName.(string); ok {
+		env.resources[strings.ToLower(s)] = r.Values
+	}
+}
+
+// evaluateDeployment evaluates the template of a nested deployment and adds
+// its resources. Inline templates are evaluated in the scope of the parent
+// template unless the deployment uses the inner expression evaluation scope,
+// which Bicep modules do. Linked templates are only supported when they use a
+// relative path.
+func (e *templateEvaluator) evaluateDeployment(ctx *evalContext, raw map[string]interface{}, r *resource) error {
+	if e.depth >= maxNestedDeploymentDepth {
+		log.Warn().Msgf("Skipping nested deployment %s as it exceeds the maximum depth of %d", r.Name, maxNestedDeploymentDepth)
+		return nil
+	}
+
+	props, _ := raw["properties"].(map[string]interface{})
+
+	innerScope := false
+	if opts, ok := props["expressionEvaluationOptions"].(map[string]interface{}); ok {
+		scope, _ := opts["scope"].(string)
+		innerScope = strings.EqualFold(scope, "inner")
+	}
+
+	nestedRaw, _ := props["template"].(map[string]interface{})
+	nestedDir := e.dir
+
+	if nestedRaw == nil {
+		link, _ := props["templateLink"].(map[string]interface{})
+		relativePath, err := ctx.evaluate(link["relativePath"])
+		if err != nil || toString(relativePath) == "" {
+			log.Warn().Msgf("Skipping nested deployment %s as only inline templates and templates linked with a relative path are supported", r.Name)
+			return nil
+		}
+
+		path := filepath.Join(e.dir, toString(relativePath))
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading linked template of deployment %s: %w", r.Name, err)
+		}
+		if err := json.Unmarshal(b, &nestedRaw); err != nil {
+			return fmt.Errorf("error parsing linked template of deployment %s: %w", r.Name, err)
+		}
+
+		nestedDir = filepath.Dir(path)
+		innerScope = true
+	}
+
+	if !innerScope {
+		for _, nested := range templateResources(nestedRaw) {
+			if err := e.expandResource(ctx, nested, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	params := map[string]interface{}{}
+	rawParams, _ := props["parameters"].(map[string]interface{})
+	for name, p := range rawParams {
+		param, _ := p.(map[string]interface{})
+		if _, ok := param["value"]; !ok {
+			continue
+		}
+		v, err := ctx.evaluate(param["value"])
+		if err != nil {
+			return fmt.Errorf("error evaluating parameter %q of deployment %s: %w", name, r.Name, err)
+		}
+		params[name] = v
+	}
+
+	resources, outputs, err := evaluateTemplate(nestedRaw, params, ctx.scope.env, nestedDir, e.depth+1)
+	if err != nil {
+		return fmt.Errorf("error evaluating deployment %s: %w", r.Name, err)
+	}
+
+	e.resources = append(e.resources, resources...)
+	r.Values["properties"] = map[string]interface{}{"outputs": outputs}
+
+	return nil
+}
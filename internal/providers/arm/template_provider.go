@@ -0,0 +1,79 @@
+package arm
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/arm/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type TemplateProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewTemplateProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &TemplateProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *TemplateProvider) Type() string {
+	return "arm"
+}
+
+func (p *TemplateProvider) DisplayType() string {
+	return "Azure Resource Manager"
+}
+
+func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	params, err := loadParameters(p.Path, p.ctx.ProjectConfig.ARMParameterFiles, p.ctx.ProjectConfig.ARMParameters)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	// Templates usually deploy to the location of the resource group, which
+	// isn't known until the template is deployed.
+	location := azure.DefaultLocation
+	if p.ctx.RunContext.Config.AzureOverrideRegion != "" {
+		location = p.ctx.RunContext.Config.AzureOverrideRegion
+	}
+
+	template, err := loadTemplate(p.Path, params, newEnvironment(location))
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading ARM template file")
+	}
+
+	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+
+	parser := NewParser(p.ctx)
+	pastResources, resources, err := parser.parseTemplate(template, location, usage)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error parsing ARM template file")
+	}
+
+	project.PartialPastResources = pastResources
+	project.PartialResources = resources
+
+	if !p.includePastResources {
+		project.PartialPastResources = nil
+	}
+
+	return []*schema.Project{project}, nil
+}
@@ -0,0 +1,68 @@
+package arm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestTemplateProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:              "testdata/azuredeploy.json",
+		ARMParameterFiles: []string{"azuredeploy.parameters.json"},
+		ARMParameters:     map[string]string{"location": "West Europe"},
+	}, nil)
+	usage := schema.NewUsageMapFromInterface(map[string]interface{}{
+		"shop-vm-01": map[string]interface{}{
+			"monthly_hrs": 100,
+		},
+	})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(usage)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "arm", projects[0].Metadata.Type)
+	schema.BuildResources(projects, nil)
+
+	byName := map[string]*schema.Resource{}
+	for _, r := range projects[0].Resources {
+		byName[r.Name] = r
+	}
+
+	vm := byName["Microsoft.Compute/virtualMachines/shop-vm-01"]
+	require.NotNil(t, vm)
+	assert.Equal(t, "Instance usage (Linux, pay as you go, Standard_B2s)", vm.CostComponents[0].Name)
+	assert.Equal(t, "100", vm.CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "westeurope", *vm.CostComponents[0].ProductFilter.Region)
+
+	subResources := []string{}
+	for _, s := range vm.SubResources {
+		subResources = append(subResources, s.Name)
+	}
+	assert.Equal(t, []string{"os_disk", "data_disk[0]", "data_disk[1]"}, subResources)
+
+	ip := byName["Microsoft.Network/publicIPAddresses/shop-pip"]
+	require.NotNil(t, ip)
+	assert.Equal(t, "IP address (static)", ip.CostComponents[0].Name)
+
+	db := byName["Microsoft.DBforPostgreSQL/flexibleServers/shop-psql"]
+	require.NotNil(t, db)
+	assert.False(t, db.IsSkipped)
+	assert.NotEmpty(t, db.CostComponents)
+
+	assert.False(t, byName["Microsoft.Web/serverfarms/shop-plan"].IsSkipped)
+	assert.False(t, byName["Microsoft.OperationalInsights/workspaces/shop-logs"].IsSkipped)
+	assert.True(t, byName["Microsoft.Network/virtualNetworks/shop-vnet"].NoPrice)
+	assert.True(t, byName["Microsoft.Resources/deployments/monitoring"].NoPrice)
+
+	for name, r := range byName {
+		if r.ResourceType == "Microsoft.Storage/storageAccounts" {
+			assert.False(t, r.IsSkipped, name)
+			assert.NotEmpty(t, r.CostComponents, name)
+		}
+	}
+}
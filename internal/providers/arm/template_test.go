@@ -0,0 +1,146 @@
+package arm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resourceIDs(t *template) []string {
+	ids := make([]string, 0, len(t.resources))
+	for _, r := range t.resources {
+		ids = append(ids, r.ID())
+	}
+
+	return ids
+}
+
+func findResource(t *template, id string) *resource {
+	for _, r := range t.resources {
+		if r.ID() == id {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func TestIsTemplate(t *testing.T) {
+	assert.True(t, IsTemplate("testdata/azuredeploy.json"))
+	assert.True(t, IsTemplate("testdata/main.bicep"))
+	assert.False(t, IsTemplate("testdata/azuredeploy.parameters.json"))
+	assert.False(t, IsTemplate("testdata"))
+}
+
+func TestLoadTemplateDefaults(t *testing.T) {
+	template, err := loadTemplate("testdata/azuredeploy.json", nil, newEnvironment("westeurope"))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		findStorage(t, template).ID(),
+		findStorage(t, template).ID() + "/blobServices/default",
+		"Microsoft.Network/virtualNetworks/app-vnet",
+		"Microsoft.Compute/virtualMachines/app-vm-01",
+		"Microsoft.Compute/virtualMachines/app-vm-02",
+		"Microsoft.Resources/deployments/monitoring",
+		"Microsoft.OperationalInsights/workspaces/app-logs",
+		"Microsoft.Resources/deployments/web",
+		"Microsoft.Web/serverfarms/app-plan",
+		"Microsoft.Resources/deployments/database",
+		"Microsoft.DBforPostgreSQL/flexibleServers/app-psql",
+	}, resourceIDs(template))
+
+	storage := findStorage(t, template)
+	assert.Equal(t, "westeurope", storage.Values["location"])
+	assert.Equal(t, map[string]interface{}{"name": "Standard_LRS"}, storage.Values["sku"])
+	assert.Equal(t, map[string]interface{}{"environment": "dev"}, storage.Values["tags"])
+
+	vnet := findResource(template, "Microsoft.Network/virtualNetworks/app-vnet")
+	subnets := vnet.Values["properties"].(map[string]interface{})["subnets"].([]interface{})
+	require.Len(t, subnets, 2)
+	assert.Equal(t, "subnet-1", subnets[1].(map[string]interface{})["name"])
+	assert.Equal(t, "10.0.1.0/24", subnets[1].(map[string]interface{})["properties"].(map[string]interface{})["addressPrefix"])
+
+	vm := findResource(template, "Microsoft.Compute/virtualMachines/app-vm-02")
+	disks := vm.Values["properties"].(map[string]interface{})["storageProfile"].(map[string]interface{})["dataDisks"].([]interface{})
+	require.Len(t, disks, 2)
+	assert.Equal(t, 256.0, disks[1].(map[string]interface{})["diskSizeGB"])
+
+	workspace := findResource(template, "Microsoft.OperationalInsights/workspaces/app-logs")
+	assert.Equal(t, "westeurope", workspace.Values["location"])
+
+	plan := findResource(template, "Microsoft.Web/serverfarms/app-plan")
+	assert.Equal(t, "B1", plan.Values["sku"].(map[string]interface{})["name"])
+
+	db := findResource(template, "Microsoft.DBforPostgreSQL/flexibleServers/app-psql")
+	assert.Equal(t, map[string]interface{}{"workspace": "app-logs"}, db.Values["tags"])
+}
+
+func TestLoadTemplateParameters(t *testing.T) {
+	params, err := loadParameters("testdata/azuredeploy.json", []string{"azuredeploy.parameters.json"}, map[string]string{
+		"vmCount": "1",
+		"vmSize":  "Standard_D4s_v5",
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, params, "adminPassword")
+
+	template, err := loadTemplate("testdata/azuredeploy.json", params, newEnvironment("eastus"))
+	require.NoError(t, err)
+
+	assert.NotNil(t, findResource(template, "Microsoft.Network/publicIPAddresses/shop-pip"))
+	assert.NotNil(t, findResource(template, "Microsoft.Compute/virtualMachines/shop-vm-01"))
+	assert.Nil(t, findResource(template, "Microsoft.Compute/virtualMachines/shop-vm-02"))
+
+	vm := findResource(template, "Microsoft.Compute/virtualMachines/shop-vm-01")
+	assert.Equal(t, "Standard_D4s_v5", vm.Values["properties"].(map[string]interface{})["hardwareProfile"].(map[string]interface{})["vmSize"])
+
+	storage := findStorage(t, template)
+	assert.Equal(t, map[string]interface{}{"name": "Standard_GRS"}, storage.Values["sku"])
+
+	plan := findResource(template, "Microsoft.Web/serverfarms/shop-plan")
+	assert.Equal(t, "P1v3", plan.Values["sku"].(map[string]interface{})["name"])
+}
+
+func TestLoadTemplateSymbolicNames(t *testing.T) {
+	raw := map[string]interface{}{
+		"languageVersion": "2.0",
+		"resources": map[string]interface{}{
+			"plan": map[string]interface{}{
+				"type":     "Microsoft.Web/serverfarms",
+				"name":     "plan",
+				"location": "uksouth",
+				"sku":      map[string]interface{}{"name": "S1"},
+			},
+			"existingVnet": map[string]interface{}{
+				"type":     "Microsoft.Network/virtualNetworks",
+				"name":     "vnet",
+				"existing": true,
+			},
+			"site": map[string]interface{}{
+				"type":     "Microsoft.Web/sites",
+				"name":     "site",
+				"location": "[reference('plan', '2022-03-01', 'Full').location]",
+			},
+		},
+	}
+
+	resources, _, err := evaluateTemplate(raw, nil, newEnvironment("eastus"), ".", 0)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "Microsoft.Web/serverfarms/plan", resources[0].ID())
+	assert.Equal(t, "uksouth", resources[1].Values["location"])
+}
+
+func findStorage(t *testing.T, template *template) *resource {
+	t.Helper()
+
+	for _, r := range template.resources {
+		if r.Type == "Microsoft.Storage/storageAccounts" {
+			return r
+		}
+	}
+
+	require.Fail(t, "storage account not found")
+	return nil
+}
@@ -0,0 +1,30 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/apigateway"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetAPIGatewayRestAPIRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ApiGateway::RestApi",
+		RFunc: NewAPIGatewayRestAPI,
+	}
+}
+
+func NewAPIGatewayRestAPI(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	if _, ok := d.CFResource.(*apigateway.RestApi); !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.APIGatewayRestAPI{
+		Address: d.Address,
+		Region:  region(d),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
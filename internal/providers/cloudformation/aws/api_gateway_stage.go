@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/awslabs/goformation/v4/cloudformation/apigateway"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetAPIGatewayStageRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ApiGateway::Stage",
+		RFunc: NewAPIGatewayStage,
+	}
+}
+
+func NewAPIGatewayStage(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*apigateway.Stage)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	// The cache size is a string in CloudFormation, e.g. "0.5" for 0.5GB
+	cacheClusterSize, _ := strconv.ParseFloat(cfr.CacheClusterSize, 64)
+
+	r := &aws.APIGatewayStage{
+		Address:          d.Address,
+		Region:           region(d),
+		CacheClusterSize: cacheClusterSize,
+		CacheEnabled:     cfr.CacheClusterEnabled,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
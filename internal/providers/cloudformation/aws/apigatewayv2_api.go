@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/apigatewayv2"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetAPIGatewayv2ApiRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ApiGatewayV2::Api",
+		RFunc: NewAPIGatewayV2API,
+	}
+}
+
+func NewAPIGatewayV2API(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*apigatewayv2.Api)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.APIGatewayV2API{
+		Address:      d.Address,
+		Region:       region(d),
+		ProtocolType: cfr.ProtocolType,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
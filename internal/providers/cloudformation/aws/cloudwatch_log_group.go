@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/logs"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetCloudwatchLogGroupItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Logs::LogGroup",
+		RFunc: NewCloudwatchLogGroup,
+	}
+}
+
+func NewCloudwatchLogGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	_, ok := d.CFResource.(*logs.LogGroup)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.CloudwatchLogGroup{
+		Address: d.Address,
+		Region:  region(d),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetDBInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "AWS::RDS::DBInstance",
+		CoreRFunc: NewDBInstance,
+	}
+}
+
+func NewDBInstance(d *schema.ResourceData) schema.CoreResource {
+	cfr, ok := d.CFResource.(*rds.DBInstance)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	// Instances in an Aurora cluster are priced as cluster instances, and the
+	// storage is priced on the cluster
+	if cfr.DBClusterIdentifier != "" {
+		return nil
+	}
+
+	storageType := cfr.StorageType
+	if storageType == "" {
+		storageType = "gp2"
+		if cfr.Iops > 0 {
+			storageType = "io1"
+		}
+	}
+
+	piEnabled := cfr.EnablePerformanceInsights
+
+	r := &aws.DBInstance{
+		Address:                              d.Address,
+		Region:                               region(d),
+		InstanceClass:                        cfr.DBInstanceClass,
+		Engine:                               cfr.Engine,
+		MultiAZ:                              cfr.MultiAZ,
+		LicenseModel:                         cfr.LicenseModel,
+		BackupRetentionPeriod:                int64(cfr.BackupRetentionPeriod),
+		IOPS:                                 float64(cfr.Iops),
+		StorageType:                          storageType,
+		PerformanceInsightsEnabled:           piEnabled,
+		PerformanceInsightsLongTermRetention: piEnabled && cfr.PerformanceInsightsRetentionPeriod > 7,
+	}
+
+	if v, err := strconv.ParseFloat(cfr.AllocatedStorage, 64); err == nil {
+		r.AllocatedStorageGB = floatPtr(v)
+	}
+
+	return r
+}
@@ -2,7 +2,6 @@ package aws
 
 import (
 	"github.com/awslabs/goformation/v4/cloudformation/dynamodb"
-	"github.com/rs/zerolog/log"
 
 	"github.com/infracost/infracost/internal/resources/aws"
 	"github.com/infracost/infracost/internal/schema"
@@ -21,11 +20,10 @@ func GetDynamoDBTableRegistryItem() *schema.RegistryItem {
 func NewDynamoDBTable(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
 	cfr, ok := d.CFResource.(*dynamodb.Table)
 	if !ok {
-		log.Warn().Msgf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		logUnexpectedType(d)
 		return nil
 	}
 
-	region := "us-east-1" // TODO figure out how to set region
 	billingMode := cfr.BillingMode
 	var readCapacity int64
 	if cfr.ProvisionedThroughput != nil {
@@ -38,7 +36,7 @@ func NewDynamoDBTable(d *schema.ResourceData, u *schema.UsageData) *schema.Resou
 
 	a := &aws.DynamoDBTable{
 		Address:        d.Address,
-		Region:         region,
+		Region:         region(d),
 		BillingMode:    billingMode,
 		WriteCapacity:  &writeCapacity,
 		ReadCapacity:   &readCapacity,
@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEBSVolumeRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::Volume",
+		RFunc: NewEBSVolume,
+	}
+}
+
+func NewEBSVolume(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.Volume)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.EBSVolume{
+		Address:    d.Address,
+		Region:     region(d),
+		Type:       cfr.VolumeType,
+		IOPS:       int64(cfr.Iops),
+		Throughput: int64(cfr.Throughput),
+	}
+
+	if cfr.Size > 0 {
+		r.Size = intPtr(int64(cfr.Size))
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
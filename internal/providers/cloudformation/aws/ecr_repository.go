@@ -0,0 +1,20 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetECRRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "AWS::ECR::Repository",
+		CoreRFunc: NewECRRepository,
+	}
+}
+
+func NewECRRepository(d *schema.ResourceData) schema.CoreResource {
+	return &aws.ECRRepository{
+		Address: d.Address,
+		Region:  region(d),
+	}
+}
@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/efs"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEFSFileSystemRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EFS::FileSystem",
+		RFunc: NewEFSFileSystem,
+	}
+}
+
+func NewEFSFileSystem(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*efs.FileSystem)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.EFSFileSystem{
+		Address:                     d.Address,
+		Region:                      region(d),
+		HasLifecyclePolicy:          len(cfr.LifecyclePolicies) > 0,
+		AvailabilityZoneName:        cfr.AvailabilityZoneName,
+		ProvisionedThroughputInMBps: cfr.ProvisionedThroughputInMibps,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
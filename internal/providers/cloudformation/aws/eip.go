@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEIPRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::EC2::EIP",
+		Notes: []string{
+			"Addresses associated using AWS::EC2::EIPAssociation or a NAT gateway are treated as unattached.",
+		},
+		RFunc: NewEIP,
+	}
+}
+
+func NewEIP(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.EIP)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.EIP{
+		Address:   d.Address,
+		Region:    region(d),
+		Allocated: cfr.InstanceId != "",
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticache"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetElastiCacheClusterItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElastiCache::CacheCluster",
+		RFunc: NewElastiCacheCluster,
+	}
+}
+
+func NewElastiCacheCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticache.CacheCluster)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.ElastiCacheCluster{
+		Address:                d.Address,
+		Region:                 region(d),
+		NodeType:               cfr.CacheNodeType,
+		Engine:                 cfr.Engine,
+		CacheNodes:             int64(cfr.NumCacheNodes),
+		SnapshotRetentionLimit: int64(cfr.SnapshotRetentionLimit),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// rootDeviceNames are the device names used for the root volume by the
+// common AMIs.
+var rootDeviceNames = map[string]bool{
+	"/dev/xvda":  true,
+	"/dev/sda1":  true,
+	"/dev/sda":   true,
+	"/dev/nvme0": true,
+}
+
+func GetInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::EC2::Instance",
+		Notes: []string{
+			"Costs associated with marketplace AMIs are not supported.",
+			"Launch templates are not yet supported.",
+		},
+		CoreRFunc: NewInstance,
+	}
+}
+
+func NewInstance(d *schema.ResourceData) schema.CoreResource {
+	cfr, ok := d.CFResource.(*ec2.Instance)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.Instance{
+		Address:          d.Address,
+		Region:           region(d),
+		Tenancy:          cfr.Tenancy,
+		PurchaseOption:   "on_demand",
+		AMI:              cfr.ImageId,
+		InstanceType:     cfr.InstanceType,
+		EBSOptimized:     cfr.EbsOptimized,
+		EnableMonitoring: cfr.Monitoring,
+		HasHost:          cfr.HostId != "",
+		RootBlockDevice: &aws.EBSVolume{
+			Address: "root_block_device",
+			Region:  region(d),
+		},
+	}
+
+	if cfr.CreditSpecification != nil {
+		r.CPUCredits = cfr.CreditSpecification.CPUCredits
+	}
+
+	if len(cfr.ElasticInferenceAccelerators) > 0 {
+		r.ElasticInferenceAcceleratorType = &cfr.ElasticInferenceAccelerators[0].Type
+	}
+
+	for i, m := range cfr.BlockDeviceMappings {
+		if m.Ebs == nil {
+			continue
+		}
+
+		v := &aws.EBSVolume{
+			Address: fmt.Sprintf("ebs_block_device[%d]", i),
+			Region:  region(d),
+			Type:    m.Ebs.VolumeType,
+			IOPS:    int64(m.Ebs.Iops),
+		}
+
+		if m.Ebs.VolumeSize > 0 {
+			v.Size = intPtr(int64(m.Ebs.VolumeSize))
+		}
+
+		if rootDeviceNames[m.DeviceName] {
+			v.Address = r.RootBlockDevice.Address
+			r.RootBlockDevice = v
+			continue
+		}
+
+		r.EBSBlockDevices = append(r.EBSBlockDevices, v)
+	}
+
+	return r
+}
@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/kms"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetNewKMSKeyRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::KMS::Key",
+		RFunc: NewKMSKey,
+	}
+}
+
+func NewKMSKey(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*kms.Key)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.KMSKey{
+		Address:               d.Address,
+		Region:                region(d),
+		CustomerMasterKeySpec: cfr.KeySpec,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
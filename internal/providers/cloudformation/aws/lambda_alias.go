@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLambdaAliasRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Lambda::Alias",
+		RFunc: NewLambdaAlias,
+		Notes: []string{
+			"Only aliases with provisioned concurrency are priced.",
+		},
+	}
+}
+
+// NewLambdaAlias prices the provisioned concurrency of the alias, since
+// CloudFormation configures provisioned concurrency on the alias rather than
+// as a separate resource.
+func NewLambdaAlias(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*lambda.Alias)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	if cfr.ProvisionedConcurrencyConfig == nil || cfr.ProvisionedConcurrencyConfig.ProvisionedConcurrentExecutions == 0 {
+		return &schema.Resource{
+			Name:        d.Address,
+			NoPrice:     true,
+			IsSkipped:   true,
+			SkipMessage: "Free resource.",
+		}
+	}
+
+	r := &aws.LambdaProvisionedConcurrencyConfig{
+		Address:                         d.Address,
+		Region:                          region(d),
+		Name:                            cfr.FunctionName,
+		ProvisionedConcurrentExecutions: int64(cfr.ProvisionedConcurrencyConfig.ProvisionedConcurrentExecutions),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLambdaFunctionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "AWS::Lambda::Function",
+		CoreRFunc: NewLambdaFunction,
+	}
+}
+
+func NewLambdaFunction(d *schema.ResourceData) schema.CoreResource {
+	cfr, ok := d.CFResource.(*lambda.Function)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	memorySize := int64(128)
+	if cfr.MemorySize > 0 {
+		memorySize = int64(cfr.MemorySize)
+	}
+
+	// goformation doesn't support the Architectures and EphemeralStorage
+	// properties yet so these are read from the raw values.
+	architecture := "x86_64"
+	if a := d.Get("Architectures.0").String(); a != "" {
+		architecture = a
+	}
+
+	storageSize := int64(512)
+	if s := d.Get("EphemeralStorage.Size").Int(); s > 0 {
+		storageSize = s
+	}
+
+	return &aws.LambdaFunction{
+		Address:      d.Address,
+		Region:       region(d),
+		Name:         cfr.FunctionName,
+		MemorySize:   memorySize,
+		Architecture: architecture,
+		StorageSize:  storageSize,
+	}
+}
@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLBRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElasticLoadBalancingV2::LoadBalancer",
+		RFunc: NewLB,
+	}
+}
+
+func NewLB(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticloadbalancingv2.LoadBalancer)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	loadBalancerType := cfr.Type
+	if loadBalancerType == "" {
+		loadBalancerType = "application"
+	}
+
+	r := &aws.LB{
+		Address:          d.Address,
+		Region:           region(d),
+		LoadBalancerType: loadBalancerType,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
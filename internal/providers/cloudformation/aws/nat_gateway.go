@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetNATGatewayRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::NatGateway",
+		RFunc: NewNATGateway,
+	}
+}
+
+func NewNATGateway(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	_, ok := d.CFResource.(*ec2.NatGateway)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.NATGateway{
+		Address: d.Address,
+		Region:  region(d),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
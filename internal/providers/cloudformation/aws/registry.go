@@ -3,16 +3,16 @@ package aws
 import "github.com/infracost/infracost/internal/schema"
 
 var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
-	// GetAPIGatewayRestAPIRegistryItem(),
-	// GetAPIGatewayStageRegistryItem(),
-	// GetAPIGatewayv2ApiRegistryItem(),
+	GetAPIGatewayRestAPIRegistryItem(),
+	GetAPIGatewayStageRegistryItem(),
+	GetAPIGatewayv2ApiRegistryItem(),
 	// GetAutoscalingGroupRegistryItem(),
 	// GetACMCertificate(),
 	// GetACMPCACertificateAuthorityRegistryItem(),
 	// GetCloudfrontDistributionRegistryItem(),
 	// GetCloudwatchDashboardRegistryItem(),
 	// GetCloudwatchEventBusItem(),
-	// GetCloudwatchLogGroupItem(),
+	GetCloudwatchLogGroupItem(),
 	// GetCloudwatchMetricAlarmRegistryItem(),
 	// GetCodebuildProjectRegistryItem(),
 	// GetConfigRuleItem(),
@@ -20,7 +20,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetConfigOrganizationCustomRuleItem(),
 	// GetConfigOrganizationManagedRuleItem(),
 	// getDataTransferRegistryItem(),
-	// GetDBInstanceRegistryItem(),
+	GetDBInstanceRegistryItem(),
 	// GetDMSRegistryItem(),
 	// GetDocDBClusterInstanceRegistryItem(),
 	// GetDocDBClusterRegistryItem(),
@@ -30,29 +30,30 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetDynamoDBTableRegistryItem(),
 	// GetEBSSnapshotCopyRegistryItem(),
 	// GetEBSSnapshotRegistryItem(),
-	// GetEBSVolumeRegistryItem(),
+	GetEBSVolumeRegistryItem(),
 	// GetEC2ClientVPNEndpointRegistryItem(),
 	// GetEC2ClientVPNNetworkAssociationRegistryItem(),
 	// GetEC2TrafficMirroSessionRegistryItem(),
 	// GetEC2TransitGatewayPeeringAttachmentRegistryItem(),
 	// GetEC2TransitGatewayVpcAttachmentRegistryItem(),
-	// GetECRRegistryItem(),
+	GetECRRegistryItem(),
 	// GetECSServiceRegistryItem(),
-	// GetEFSFileSystemRegistryItem(),
-	// GetEIPRegistryItem(),
-	// GetElastiCacheClusterItem(),
+	GetEFSFileSystemRegistryItem(),
+	GetEIPRegistryItem(),
+	GetElastiCacheClusterItem(),
 	// GetElastiCacheReplicationGroupItem(),
 	// GetElasticsearchDomainRegistryItem(),
 	// GetELBRegistryItem(),
 	// GetFSXWindowsFSRegistryItem(),
-	// GetInstanceRegistryItem(),
-	// GetLambdaFunctionRegistryItem(),
-	// GetLBRegistryItem(),
+	GetInstanceRegistryItem(),
+	GetLambdaAliasRegistryItem(),
+	GetLambdaFunctionRegistryItem(),
+	GetLBRegistryItem(),
 	// GetLightsailInstanceRegistryItem(),
 	// GetMSKClusterRegistryItem(),
 	// GetALBRegistryItem(),
 	// GetMQBrokerRegistryItem(),
-	// GetNATGatewayRegistryItem(),
+	GetNATGatewayRegistryItem(),
 	// GetRDSClusterRegistryItem(),
 	// GetRDSClusterInstanceRegistryItem(),
 	// GetRedshiftClusterRegistryItem(),
@@ -60,19 +61,19 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetRoute53ResolverEndpointRegistryItem(),
 	// GetRoute53RecordRegistryItem(),
 	// GetRoute53ZoneRegistryItem(),
-	// GetS3BucketRegistryItem(),
+	GetS3BucketRegistryItem(),
 	// GetS3BucketAnalyticsConfigurationRegistryItem(),
 	// GetS3BucketInventoryRegistryItem(),
-	// GetSecretsManagerSecret(),
+	GetSecretsManagerSecret(),
 	// GetSSMActivationRegistryItem(),
 	// GetSSMParameterRegistryItem(),
-	// GetSNSTopicRegistryItem(),
+	GetSNSTopicRegistryItem(),
 	// GetSNSTopicSubscriptionRegistryItem(),
-	// GetSQSQueueRegistryItem(),
+	GetSQSQueueRegistryItem(),
 	// GetNewEKSNodeGroupItem(),
 	// GetNewEKSFargateProfileItem(),
 	// GetNewEKSClusterItem(),
-	// GetNewKMSKeyRegistryItem(),
+	GetNewKMSKeyRegistryItem(),
 	// GetNewKMSExternalKeyRegistryItem(),
 	// GetVPNConnectionRegistryItem(),
 	// GetVpcEndpointRegistryItem(),
@@ -80,6 +81,51 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 
 // FreeResources grouped alphabetically
 var FreeResources = []string{
+	// CloudFormation resource types
+	"AWS::ApiGateway::Deployment",
+	"AWS::ApiGateway::Method",
+	"AWS::ApiGateway::Resource",
+	"AWS::ApiGatewayV2::Integration",
+	"AWS::ApiGatewayV2::Route",
+	"AWS::ApiGatewayV2::Stage",
+	"AWS::CDK::Metadata",
+	"AWS::CloudFormation::Stack",
+	"AWS::EC2::EIPAssociation",
+	"AWS::EC2::InternetGateway",
+	"AWS::EC2::Route",
+	"AWS::EC2::RouteTable",
+	"AWS::EC2::SecurityGroup",
+	"AWS::EC2::SecurityGroupEgress",
+	"AWS::EC2::SecurityGroupIngress",
+	"AWS::EC2::Subnet",
+	"AWS::EC2::SubnetRouteTableAssociation",
+	"AWS::EC2::VolumeAttachment",
+	"AWS::EC2::VPC",
+	"AWS::EC2::VPCGatewayAttachment",
+	"AWS::ElastiCache::ParameterGroup",
+	"AWS::ElastiCache::SubnetGroup",
+	"AWS::ElasticLoadBalancingV2::Listener",
+	"AWS::ElasticLoadBalancingV2::ListenerRule",
+	"AWS::ElasticLoadBalancingV2::TargetGroup",
+	"AWS::Events::Rule",
+	"AWS::IAM::InstanceProfile",
+	"AWS::IAM::ManagedPolicy",
+	"AWS::IAM::Policy",
+	"AWS::IAM::Role",
+	"AWS::IAM::User",
+	"AWS::KMS::Alias",
+	"AWS::Lambda::EventSourceMapping",
+	"AWS::Lambda::LayerVersion",
+	"AWS::Lambda::Permission",
+	"AWS::Lambda::Version",
+	"AWS::Logs::MetricFilter",
+	"AWS::Logs::SubscriptionFilter",
+	"AWS::RDS::DBParameterGroup",
+	"AWS::RDS::DBSubnetGroup",
+	"AWS::S3::BucketPolicy",
+	"AWS::SNS::TopicPolicy",
+	"AWS::SQS::QueuePolicy",
+
 	// AWS Certificate Manager
 	"aws_acm_certificate_validation",
 
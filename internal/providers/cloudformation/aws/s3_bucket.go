@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"sort"
+
+	"github.com/awslabs/goformation/v4/cloudformation/s3"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+var s3StorageClassNames = map[string]string{
+	"STANDARD":            "standard",
+	"INTELLIGENT_TIERING": "intelligent_tiering",
+	"STANDARD_IA":         "standard_infrequent_access",
+	"ONEZONE_IA":          "one_zone_infrequent_access",
+	"GLACIER":             "glacier_flexible_retrieval",
+	"DEEP_ARCHIVE":        "glacier_deep_archive",
+}
+
+func GetS3BucketRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "AWS::S3::Bucket",
+		CoreRFunc: NewS3Bucket,
+	}
+}
+
+func NewS3Bucket(d *schema.ResourceData) schema.CoreResource {
+	cfr, ok := d.CFResource.(*s3.Bucket)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	objTagsEnabled := false
+
+	// Always add the standard storage class
+	storageClasses := map[string]bool{
+		"standard": true,
+	}
+
+	if cfr.LifecycleConfiguration != nil {
+		for _, rule := range cfr.LifecycleConfiguration.Rules {
+			if rule.Status != "Enabled" {
+				continue
+			}
+
+			if len(rule.TagFilters) > 0 {
+				objTagsEnabled = true
+			}
+
+			transitions := rule.Transitions
+			if rule.Transition != nil {
+				transitions = append(transitions, *rule.Transition)
+			}
+			for _, t := range transitions {
+				if name, ok := s3StorageClassNames[t.StorageClass]; ok {
+					storageClasses[name] = true
+				}
+			}
+
+			noncurrentTransitions := rule.NoncurrentVersionTransitions
+			if rule.NoncurrentVersionTransition != nil {
+				noncurrentTransitions = append(noncurrentTransitions, *rule.NoncurrentVersionTransition)
+			}
+			for _, t := range noncurrentTransitions {
+				if name, ok := s3StorageClassNames[t.StorageClass]; ok {
+					storageClasses[name] = true
+				}
+			}
+		}
+	}
+
+	lifecycleStorageClasses := make([]string, 0, len(storageClasses))
+	for storageClass := range storageClasses {
+		lifecycleStorageClasses = append(lifecycleStorageClasses, storageClass)
+	}
+	sort.Strings(lifecycleStorageClasses)
+
+	return &aws.S3Bucket{
+		Address:                 d.Address,
+		Region:                  region(d),
+		Name:                    cfr.BucketName,
+		ObjectTagsEnabled:       objTagsEnabled,
+		LifecycleStorageClasses: lifecycleStorageClasses,
+	}
+}
@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/secretsmanager"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetSecretsManagerSecret() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SecretsManager::Secret",
+		RFunc: NewSecretsManagerSecret,
+	}
+}
+
+func NewSecretsManagerSecret(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	_, ok := d.CFResource.(*secretsmanager.Secret)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.SecretsManagerSecret{
+		Address: d.Address,
+		Region:  region(d),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/sns"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetSNSTopicRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SNS::Topic",
+		RFunc: NewSNSTopic,
+	}
+}
+
+func NewSNSTopic(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	_, ok := d.CFResource.(*sns.Topic)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.SNSTopic{
+		Address: d.Address,
+		Region:  region(d),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
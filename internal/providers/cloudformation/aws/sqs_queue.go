@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/sqs"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetSQSQueueRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SQS::Queue",
+		RFunc: NewSQSQueue,
+	}
+}
+
+func NewSQSQueue(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*sqs.Queue)
+	if !ok {
+		logUnexpectedType(d)
+		return nil
+	}
+
+	r := &aws.SQSQueue{
+		Address:   d.Address,
+		Region:    region(d),
+		FifoQueue: cfr.FifoQueue,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -2,8 +2,16 @@ package aws
 
 import (
 	"github.com/awslabs/goformation/v4/cloudformation/tags"
+	"github.com/rs/zerolog/log"
+
+	"github.com/infracost/infracost/internal/schema"
 )
 
+// DefaultRegion is the region used for resources when the template doesn't
+// specify one. CloudFormation templates are region agnostic, the region is
+// chosen when the stack is deployed.
+const DefaultRegion = "us-east-1"
+
 func mapTags(cfTags []tags.Tag) *map[string]string {
 	mapped := make(map[string]string)
 	for _, tag := range cfTags {
@@ -11,3 +19,26 @@ func mapTags(cfTags []tags.Tag) *map[string]string {
 	}
 	return &mapped
 }
+
+// region returns the region that the resource is deployed to.
+func region(d *schema.ResourceData) string {
+	if r := d.Get("region").String(); r != "" {
+		return r
+	}
+
+	return DefaultRegion
+}
+
+// logUnexpectedType logs a warning when the CloudFormation resource can't be
+// converted to the expected goformation type.
+func logUnexpectedType(d *schema.ResourceData) {
+	log.Warn().Msgf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+}
+
+func intPtr(i int64) *int64 {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,90 @@
+package cftest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/testutil"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+// GoldenFileResourceTests prices the CloudFormation template in
+// testdata/<testName>/<testName>.yml, using the usage file next to it if there
+// is one, and compares the output table with the golden file.
+func GoldenFileResourceTests(t *testing.T, testName string) {
+	t.Helper()
+
+	runCtx, err := config.NewRunContextFromEnv(context.Background())
+	require.NoError(t, err)
+
+	testutil.ConfigureTestToFailOnLogs(t, runCtx)
+
+	// Load the usage data, if any.
+	var usageData schema.UsageMap
+	usageFilePath := filepath.Join("testdata", testName, testName+".usage.yml")
+	if _, err := os.Stat(usageFilePath); err == nil || !os.IsNotExist(err) {
+		usageFile, err := usage.LoadUsageFile(usageFilePath)
+		require.NoError(t, err)
+		usageData = usageFile.ToUsageDataMap()
+	}
+
+	projectCtx := config.NewProjectContext(runCtx, &config.Project{
+		Path: filepath.Join("testdata", testName, testName+".yml"),
+		Name: t.Name(),
+	}, nil)
+
+	provider := cloudformation.NewTemplateProvider(projectCtx, false)
+	projects, err := provider.LoadResources(usageData)
+	require.NoError(t, err)
+
+	// build the resources the same way as the runner does
+	schema.BuildResources(projects, nil)
+
+	projects, err = RunCostCalculations(runCtx, projects)
+	require.NoError(t, err)
+
+	r, err := output.ToOutputFormat(runCtx.Config, projects)
+	require.NoError(t, err)
+	r.Currency = runCtx.Config.Currency
+
+	opts := output.Options{
+		ShowSkipped: true,
+		NoColor:     true,
+		Fields:      runCtx.Config.Fields,
+	}
+
+	actual, err := output.ToTable(r, opts)
+	require.NoError(t, err)
+
+	// strip the first line of output since it contains the project path
+	endOfFirstLine := bytes.Index(actual, []byte("\n"))
+	if endOfFirstLine > 0 {
+		actual = actual[endOfFirstLine+1:]
+	}
+
+	goldenFilePath := filepath.Join("testdata", testName, testName+".golden")
+	testutil.AssertGoldenFile(t, goldenFilePath, actual)
+}
+
+func RunCostCalculations(runCtx *config.RunContext, projects []*schema.Project) ([]*schema.Project, error) {
+	for _, project := range projects {
+		err := prices.PopulatePrices(runCtx, project)
+		if err != nil {
+			return projects, err
+		}
+
+		schema.CalculateCosts(project)
+	}
+
+	return projects, nil
+}
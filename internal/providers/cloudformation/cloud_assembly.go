@@ -0,0 +1,186 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Cloud assembly artifact types, see
+// https://github.com/aws/aws-cdk/tree/main/packages/aws-cdk-lib/cloud-assembly-schema
+const (
+	cdkStackArtifact          = "aws:cloudformation:stack"
+	cdkNestedAssemblyArtifact = "cdk:cloud-assembly"
+
+	cdkManifestFile = "manifest.json"
+	cdkOutDir       = "cdk.out"
+)
+
+type cloudAssemblyManifest struct {
+	Version   string                           `json:"version"`
+	Artifacts map[string]cloudAssemblyArtifact `json:"artifacts"`
+}
+
+type cloudAssemblyArtifact struct {
+	Type        string `json:"type"`
+	Environment string `json:"environment"`
+	DisplayName string `json:"displayName"`
+	Properties  struct {
+		TemplateFile  string            `json:"templateFile"`
+		StackName     string            `json:"stackName"`
+		Parameters    map[string]string `json:"parameters"`
+		DirectoryName string            `json:"directoryName"`
+	} `json:"properties"`
+}
+
+// CloudAssemblyStack is a CloudFormation stack synthesized by the AWS CDK.
+type CloudAssemblyStack struct {
+	// Name is the display name of the stack, which includes the path of the
+	// stage the stack is defined in.
+	Name         string
+	StackName    string
+	TemplatePath string
+	Region       string
+	AccountID    string
+	Parameters   map[string]string
+}
+
+// cloudAssemblyPath returns the directory of the CDK cloud assembly if path is
+// a cloud assembly or a CDK app directory containing a synthesized cdk.out.
+func cloudAssemblyPath(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+
+	for _, dir := range []string{path, filepath.Join(path, cdkOutDir)} {
+		if m, err := readCloudAssemblyManifest(dir); err == nil && m.Version != "" && len(m.Artifacts) > 0 {
+			return dir, true
+		}
+	}
+
+	return "", false
+}
+
+func readCloudAssemblyManifest(dir string) (*cloudAssemblyManifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, cdkManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var m cloudAssemblyManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// ReadCloudAssembly returns the stacks in the cloud assembly at dir, including
+// the stacks in nested assemblies created for CDK stages.
+func ReadCloudAssembly(dir string) ([]CloudAssemblyStack, error) {
+	m, err := readCloudAssemblyManifest(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading CDK cloud assembly manifest")
+	}
+
+	ids := make([]string, 0, len(m.Artifacts))
+	for id := range m.Artifacts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var stacks []CloudAssemblyStack
+
+	for _, id := range ids {
+		a := m.Artifacts[id]
+
+		switch a.Type {
+		case cdkStackArtifact:
+			if a.Properties.TemplateFile == "" {
+				continue
+			}
+
+			s := CloudAssemblyStack{
+				Name:         a.DisplayName,
+				StackName:    a.Properties.StackName,
+				TemplatePath: filepath.Join(dir, a.Properties.TemplateFile),
+				Parameters:   a.Properties.Parameters,
+			}
+			if s.Name == "" {
+				s.Name = id
+			}
+			if s.StackName == "" {
+				s.StackName = id
+			}
+			s.AccountID, s.Region = parseCDKEnvironment(a.Environment)
+
+			stacks = append(stacks, s)
+		case cdkNestedAssemblyArtifact:
+			if a.Properties.DirectoryName == "" {
+				continue
+			}
+
+			nested, err := ReadCloudAssembly(filepath.Join(dir, a.Properties.DirectoryName))
+			if err != nil {
+				return nil, err
+			}
+			stacks = append(stacks, nested...)
+		}
+	}
+
+	return stacks, nil
+}
+
+// parseCDKEnvironment returns the account and region from a cloud assembly
+// environment, e.g. aws://123456789012/us-east-1. Environment agnostic stacks
+// use unknown-account and unknown-region, which are returned as empty strings.
+func parseCDKEnvironment(env string) (string, string) {
+	account, region, _ := strings.Cut(strings.TrimPrefix(env, "aws://"), "/")
+
+	if strings.HasPrefix(account, "unknown-") {
+		account = ""
+	}
+
+	if strings.HasPrefix(region, "unknown-") {
+		region = ""
+	}
+
+	return account, region
+}
+
+// cdkResourceNames returns readable names for resources that have the
+// aws:cdk:path metadata added by the CDK, e.g. OrdersStack/Table for the
+// resource with the path OrdersStack/Table/Resource. Resources without the
+// metadata keep their logical ID.
+func cdkResourceNames(resources map[string]interface{}) map[string]string {
+	paths := map[string]string{}
+	for id, v := range resources {
+		if p, ok := mapValue(mapValue(v)["Metadata"])["aws:cdk:path"].(string); ok && p != "" {
+			paths[id] = p
+		}
+	}
+
+	counts := map[string]int{}
+	for _, p := range paths {
+		counts[strings.TrimSuffix(p, "/Resource")]++
+	}
+
+	names := make(map[string]string, len(paths))
+	for id, p := range paths {
+		name := strings.TrimSuffix(p, "/Resource")
+
+		// Keep the full path if removing the suffix makes it ambiguous
+		if counts[name] > 1 {
+			name = p
+		}
+
+		names[id] = name
+	}
+
+	return names
+}
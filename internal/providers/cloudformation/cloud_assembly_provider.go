@@ -0,0 +1,99 @@
+package cloudformation
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// CloudAssemblyProvider loads the stacks of a CDK cloud assembly, which is
+// synthesized to the cdk.out directory by cdk synth. Each stack is a separate
+// project.
+type CloudAssemblyProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewCloudAssemblyProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &CloudAssemblyProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *CloudAssemblyProvider) Type() string {
+	return "cdk"
+}
+
+func (p *CloudAssemblyProvider) DisplayType() string {
+	return "AWS CDK"
+}
+
+func (p *CloudAssemblyProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *CloudAssemblyProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	dir, ok := cloudAssemblyPath(p.Path)
+	if !ok {
+		return []*schema.Project{}, fmt.Errorf("No CDK cloud assembly found at %s, run cdk synth to create it", p.Path)
+	}
+
+	stacks, err := ReadCloudAssembly(dir)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	overrides, err := loadParameters(p.Path, p.ctx.ProjectConfig.CloudFormationParameterFiles, p.ctx.ProjectConfig.CloudFormationParameters)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	projects := make([]*schema.Project, 0, len(stacks))
+
+	for _, stack := range stacks {
+		params := map[string]string{
+			"AWS::StackName": stack.StackName,
+		}
+		if stack.Region != "" {
+			params["AWS::Region"] = stack.Region
+		}
+		if stack.AccountID != "" {
+			params["AWS::AccountId"] = stack.AccountID
+		}
+		for k, v := range stack.Parameters {
+			params[k] = v
+		}
+		for k, v := range overrides {
+			params[k] = v
+		}
+
+		template, err := loadTemplate(stack.TemplatePath, params)
+		if err != nil {
+			return projects, errors.Wrapf(err, "Error reading CloudFormation template for CDK stack %s", stack.Name)
+		}
+
+		metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+		metadata.Type = p.Type()
+		p.AddMetadata(metadata)
+
+		name := stack.Name
+		if p.ctx.ProjectConfig.Name != "" {
+			name = p.ctx.ProjectConfig.Name + "/" + stack.Name
+		}
+
+		project := schema.NewProject(name, metadata)
+		if err := parseProject(p.ctx, project, template, usage, p.includePastResources); err != nil {
+			return append(projects, project), err
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,120 @@
+package cloudformation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestReadCloudAssembly(t *testing.T) {
+	dir, ok := cloudAssemblyPath("testdata/cdk")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join("testdata/cdk", cdkOutDir), dir)
+
+	stacks, err := ReadCloudAssembly(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []CloudAssemblyStack{
+		{
+			Name:         "OrdersStack",
+			StackName:    "OrdersStack",
+			TemplatePath: filepath.Join(dir, "OrdersStack.template.json"),
+			Region:       "eu-west-1",
+		},
+		{
+			Name:         "Prod/QueueStack",
+			StackName:    "Prod-QueueStack",
+			TemplatePath: filepath.Join(dir, "assembly-Prod", "ProdQueueStack.template.json"),
+			Region:       "us-west-2",
+			AccountID:    "123456789012",
+		},
+	}, stacks)
+
+	assert.Equal(t, KindCDK, DetectTemplateKind("testdata/cdk"))
+	assert.Equal(t, KindCDK, DetectTemplateKind(dir))
+	assert.Equal(t, KindCloudFormation, DetectTemplateKind(filepath.Join(dir, "OrdersStack.template.json")))
+}
+
+func TestDetectTemplateKindCDKWithTerraform(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cdk.json"), []byte(`{"app": "npx ts-node bin/app.ts"}`), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "cdk.out"), 0700))
+	manifest := `{"version": "36.0.0", "artifacts": {"OrdersStack": {"type": "aws:cloudformation:stack", "properties": {"templateFile": "OrdersStack.template.json"}}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cdk.out", "manifest.json"), []byte(manifest), 0600))
+	assert.Equal(t, KindCDK, DetectTemplateKind(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tofu"), []byte("resource \"aws_sqs_queue\" \"orders\" {}\n"), 0600))
+	assert.Equal(t, "", DetectTemplateKind(dir))
+	assert.Equal(t, KindCDK, DetectTemplateKind(filepath.Join(dir, "cdk.out")))
+}
+
+func TestLoadTemplateNestedStack(t *testing.T) {
+	template, err := loadTemplate("testdata/cdk/cdk.out/OrdersStack.template.json", nil)
+	require.NoError(t, err)
+
+	nestedID := "StorageNestedStackStorageNestedStackResource9C8D7E6F/OrdersTable2C3D4E5F"
+	assert.ElementsMatch(t, []string{
+		"OrdersFunctionServiceRole1B2C3D4E",
+		"OrdersFunction5E6F7A8B",
+		"StorageNestedStackStorageNestedStackResource9C8D7E6F",
+		"CDKMetadata",
+		nestedID,
+	}, resourceNames(template.resources))
+
+	table := template.typed[nestedID].(*dynamodb.Table)
+	assert.Equal(t, int64(10), table.ProvisionedThroughput.ReadCapacityUnits)
+
+	assert.Equal(t, map[string]string{
+		"OrdersFunctionServiceRole1B2C3D4E":                    "OrdersStack/OrdersFunction/ServiceRole",
+		"OrdersFunction5E6F7A8B":                               "OrdersStack/OrdersFunction",
+		"StorageNestedStackStorageNestedStackResource9C8D7E6F": "OrdersStack/Storage.NestedStack/Storage.NestedStackResource",
+		"CDKMetadata": "OrdersStack/CDKMetadata/Default",
+		nestedID:      "OrdersStack/Storage/OrdersTable",
+	}, template.names)
+}
+
+func TestCloudAssemblyProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/cdk"}, nil)
+	usage := schema.NewUsageMapFromInterface(map[string]interface{}{
+		"OrdersStack/OrdersFunction": map[string]interface{}{
+			"monthly_requests": 2000000,
+		},
+	})
+
+	projects, err := NewCloudAssemblyProvider(ctx, false).LoadResources(usage)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	assert.Equal(t, "OrdersStack", projects[0].Name)
+	assert.Equal(t, "Prod/QueueStack", projects[1].Name)
+	schema.BuildResources(projects, nil)
+
+	byName := map[string]*schema.Resource{}
+	for _, r := range projects[0].Resources {
+		byName[r.Name] = r
+	}
+
+	fn := byName["OrdersStack/OrdersFunction"]
+	require.NotNil(t, fn)
+	assert.Equal(t, "2000000", fn.CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "eu-west-1", *fn.CostComponents[0].ProductFilter.Region)
+
+	table := byName["OrdersStack/Storage/OrdersTable"]
+	require.NotNil(t, table)
+	assert.False(t, table.IsSkipped)
+	assert.NotEmpty(t, table.CostComponents)
+
+	assert.True(t, byName["OrdersStack/CDKMetadata/Default"].NoPrice)
+	assert.True(t, byName["OrdersStack/Storage.NestedStack/Storage.NestedStackResource"].NoPrice)
+
+	queue := projects[1].Resources[0]
+	assert.Equal(t, "Prod/QueueStack/Queue", queue.Name)
+	assert.Equal(t, "us-west-2", *queue.CostComponents[0].ProductFilter.Region)
+}
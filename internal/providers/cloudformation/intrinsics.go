@@ -0,0 +1,567 @@
+package cloudformation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/cloudformation/aws"
+)
+
+// noValue is returned when a value resolves to AWS::NoValue, so the property
+// containing it can be removed.
+type noValue struct{}
+
+var subVariableRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// resolver resolves the intrinsic functions of a CloudFormation template
+// against the parameters, mappings and conditions in the template. Values that
+// are only known once the stack is deployed, such as resource attributes that
+// aren't set in the template, are replaced with placeholders.
+type resolver struct {
+	parameters map[string]interface{}
+	mappings   map[string]interface{}
+	conditions map[string]interface{}
+	resources  map[string]interface{}
+
+	region    string
+	accountID string
+	stackName string
+
+	evaluated  map[string]bool
+	evaluating map[string]bool
+	resolving  map[string]bool
+}
+
+// newResolver returns a resolver for the template. The overrides take
+// precedence over the parameter defaults in the template, and can also be
+// used to set the AWS::Region, AWS::AccountId and AWS::StackName pseudo
+// parameters.
+func newResolver(template map[string]interface{}, overrides map[string]string) *resolver {
+	r := &resolver{
+		parameters: map[string]interface{}{},
+		mappings:   mapValue(template["Mappings"]),
+		conditions: mapValue(template["Conditions"]),
+		resources:  mapValue(template["Resources"]),
+		region:     aws.DefaultRegion,
+		accountID:  "123456789012",
+		stackName:  "infracost",
+		evaluated:  map[string]bool{},
+		evaluating: map[string]bool{},
+		resolving:  map[string]bool{},
+	}
+
+	for name, v := range mapValue(template["Parameters"]) {
+		param := mapValue(v)
+		paramType, _ := param["Type"].(string)
+
+		value, ok := param["Default"]
+		if override, ok2 := overrides[name]; ok2 {
+			value, ok = override, true
+		}
+
+		if !ok {
+			logging.Logger.Debug().Msgf("CloudFormation parameter %s has no value", name)
+			continue
+		}
+
+		r.parameters[name] = parameterValue(paramType, value)
+	}
+
+	if v, ok := overrides["AWS::Region"]; ok && v != "" {
+		r.region = v
+	}
+
+	if v, ok := overrides["AWS::StackName"]; ok && v != "" {
+		r.stackName = v
+	}
+
+	if v, ok := overrides["AWS::AccountId"]; ok && v != "" {
+		r.accountID = v
+	}
+
+	for name := range overrides {
+		if _, ok := r.parameters[name]; !ok && !strings.HasPrefix(name, "AWS::") {
+			logging.Logger.Debug().Msgf("Ignoring value for CloudFormation parameter %s as it is not declared in the template", name)
+		}
+	}
+
+	return r
+}
+
+// parameterValue converts the parameter value to the type declared by the
+// parameter. List parameters are split into a list and number parameters are
+// converted to numbers so they can be used for numeric properties.
+func parameterValue(paramType string, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	switch {
+	case paramType == "Number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case paramType == "CommaDelimitedList" || strings.HasPrefix(paramType, "List<"):
+		items := []interface{}{}
+		for _, item := range strings.Split(s, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		return items
+	}
+
+	return s
+}
+
+// resolveTemplate resolves the intrinsic functions in the resources of the
+// template and removes any resources whose condition evaluates to false.
+func (r *resolver) resolveTemplate(template map[string]interface{}) {
+	resources := map[string]interface{}{}
+
+	for name, v := range r.resources {
+		resource := mapValue(v)
+
+		if c, ok := resource["Condition"].(string); ok && !r.evalCondition(c) {
+			logging.Logger.Debug().Msgf("Skipping CloudFormation resource %s as condition %s is false", name, c)
+			continue
+		}
+
+		resolved := map[string]interface{}{}
+		for k, v := range resource {
+			if k == "Properties" {
+				v = r.resolve(v)
+			}
+
+			if _, ok := v.(noValue); ok {
+				continue
+			}
+
+			resolved[k] = v
+		}
+
+		resources[name] = resolved
+	}
+
+	template["Resources"] = resources
+}
+
+// resolve returns the value with all the intrinsic functions in it resolved.
+func (r *resolver) resolve(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 1 {
+			for fn, args := range val {
+				if res, ok := r.resolveFunction(fn, args); ok {
+					return res
+				}
+			}
+		}
+
+		resolved := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res := r.resolve(item)
+			if _, ok := res.(noValue); ok {
+				continue
+			}
+			resolved[k] = res
+		}
+
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			res := r.resolve(item)
+			if _, ok := res.(noValue); ok {
+				continue
+			}
+			resolved = append(resolved, res)
+		}
+
+		return resolved
+	}
+
+	return v
+}
+
+// resolveFunction resolves the intrinsic function fn, returning false if fn is
+// not the name of an intrinsic function.
+func (r *resolver) resolveFunction(fn string, args interface{}) (interface{}, bool) {
+	switch fn {
+	case "Ref":
+		return r.ref(r.resolve(args)), true
+	case "Fn::Sub":
+		return r.sub(args), true
+	case "Fn::GetAtt":
+		return r.getAtt(r.resolve(args)), true
+	case "Fn::If":
+		return r.fnIf(args), true
+	case "Fn::FindInMap":
+		return r.findInMap(r.resolve(args)), true
+	case "Fn::Join":
+		return join(r.resolve(args)), true
+	case "Fn::Select":
+		return selectItem(r.resolve(args)), true
+	case "Fn::Split":
+		return split(r.resolve(args)), true
+	case "Fn::GetAZs":
+		region, _ := r.resolve(args).(string)
+		if region == "" {
+			region = r.region
+		}
+		return []interface{}{region + "a", region + "b", region + "c"}, true
+	case "Fn::Base64":
+		// The decoded value is more useful for the estimate than the encoded one
+		return r.resolve(args), true
+	case "Fn::Equals", "Fn::And", "Fn::Or", "Fn::Not":
+		return r.evalConditionFunction(fn, args), true
+	case "Fn::ImportValue", "Fn::Cidr", "Fn::Transform", "Fn::Length", "Fn::ToJsonString":
+		// These depend on other stacks or macros so they can't be resolved locally
+		return nil, true
+	}
+
+	return nil, false
+}
+
+func (r *resolver) ref(v interface{}) interface{} {
+	name, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	switch name {
+	case "AWS::Region":
+		return r.region
+	case "AWS::AccountId":
+		return r.accountID
+	case "AWS::Partition":
+		if strings.HasPrefix(r.region, "cn-") {
+			return "aws-cn"
+		}
+		return "aws"
+	case "AWS::URLSuffix":
+		if strings.HasPrefix(r.region, "cn-") {
+			return "amazonaws.com.cn"
+		}
+		return "amazonaws.com"
+	case "AWS::StackName":
+		return r.stackName
+	case "AWS::StackId":
+		return fmt.Sprintf("arn:aws:cloudformation:%s:%s:stack/%s/infracost", r.region, r.accountID, r.stackName)
+	case "AWS::NotificationARNs":
+		return []interface{}{}
+	case "AWS::NoValue":
+		return noValue{}
+	}
+
+	if v, ok := r.parameters[name]; ok {
+		return v
+	}
+
+	// The physical ID of a resource isn't known until it is created, so use the
+	// logical ID so properties that reference other resources are still set.
+	if _, ok := r.resources[name]; ok {
+		return name
+	}
+
+	return nil
+}
+
+// getAtt resolves an attribute of another resource. If the attribute is a
+// property of the resource in the template its value is used, otherwise a
+// placeholder is returned since the value is only known once it is deployed.
+func (r *resolver) getAtt(v interface{}) interface{} {
+	var resourceName, attr string
+
+	switch val := v.(type) {
+	case string:
+		parts := strings.SplitN(val, ".", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		resourceName, attr = parts[0], parts[1]
+	case []interface{}:
+		if len(val) != 2 {
+			return nil
+		}
+		resourceName, _ = val[0].(string)
+		attr, _ = val[1].(string)
+	}
+
+	resource, ok := r.resources[resourceName].(map[string]interface{})
+	if !ok || attr == "" {
+		return nil
+	}
+
+	key := resourceName + "." + attr
+	if r.resolving[key] {
+		logging.Logger.Debug().Msgf("Circular reference resolving Fn::GetAtt %s", key)
+		return nil
+	}
+
+	r.resolving[key] = true
+	defer delete(r.resolving, key)
+
+	var value interface{} = mapValue(resource["Properties"])
+	for _, part := range strings.Split(attr, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			value = nil
+			break
+		}
+		value = m[part]
+	}
+
+	if value != nil {
+		return r.resolve(value)
+	}
+
+	return key
+}
+
+// sub resolves the Fn::Sub function, which can either be a string or a list
+// of a string and a map of variables.
+func (r *resolver) sub(args interface{}) interface{} {
+	var s string
+	vars := map[string]interface{}{}
+
+	switch val := args.(type) {
+	case string:
+		s = val
+	case []interface{}:
+		if len(val) != 2 {
+			return nil
+		}
+		s, _ = val[0].(string)
+		vars = mapValue(r.resolve(val[1]))
+	default:
+		return nil
+	}
+
+	return subVariableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		name := strings.TrimSpace(match[2 : len(match)-1])
+
+		// ${!Literal} is written as ${Literal}
+		if strings.HasPrefix(name, "!") {
+			return "${" + name[1:] + "}"
+		}
+
+		var value interface{}
+		if v, ok := vars[name]; ok {
+			value = v
+		} else if strings.Contains(name, ".") && !strings.HasPrefix(name, "AWS::") {
+			value = r.getAtt(name)
+		} else {
+			value = r.ref(name)
+		}
+
+		return stringValue(value)
+	})
+}
+
+func (r *resolver) fnIf(args interface{}) interface{} {
+	list, ok := args.([]interface{})
+	if !ok || len(list) != 3 {
+		return nil
+	}
+
+	name, _ := list[0].(string)
+	if r.evalCondition(name) {
+		return r.resolve(list[1])
+	}
+
+	return r.resolve(list[2])
+}
+
+func (r *resolver) findInMap(args interface{}) interface{} {
+	list, ok := args.([]interface{})
+	if !ok || len(list) < 3 {
+		return nil
+	}
+
+	mapName, _ := list[0].(string)
+	topKey := stringValue(list[1])
+	secondKey := stringValue(list[2])
+
+	v, ok := mapValue(mapValue(r.mappings[mapName])[topKey])[secondKey]
+	if !ok {
+		// Fn::FindInMap supports a default value with the language extensions transform
+		if len(list) == 4 {
+			if d, ok := mapValue(list[3])["DefaultValue"]; ok {
+				return d
+			}
+		}
+
+		logging.Logger.Debug().Msgf("CloudFormation mapping %s.%s.%s not found", mapName, topKey, secondKey)
+		return nil
+	}
+
+	return v
+}
+
+// evalCondition returns the value of the named condition, caching the result
+// since conditions are often reused by multiple resources.
+func (r *resolver) evalCondition(name string) bool {
+	if v, ok := r.evaluated[name]; ok {
+		return v
+	}
+
+	expr, ok := r.conditions[name]
+	if !ok {
+		logging.Logger.Debug().Msgf("CloudFormation condition %s not found", name)
+		return false
+	}
+
+	if r.evaluating[name] {
+		logging.Logger.Debug().Msgf("Circular reference evaluating CloudFormation condition %s", name)
+		return false
+	}
+
+	r.evaluating[name] = true
+	v, _ := r.evalConditionExpr(expr).(bool)
+	delete(r.evaluating, name)
+
+	r.evaluated[name] = v
+
+	return v
+}
+
+func (r *resolver) evalConditionExpr(expr interface{}) interface{} {
+	if m, ok := expr.(map[string]interface{}); ok && len(m) == 1 {
+		if name, ok := m["Condition"].(string); ok {
+			return r.evalCondition(name)
+		}
+	}
+
+	return r.resolve(expr)
+}
+
+func (r *resolver) evalConditionFunction(fn string, args interface{}) interface{} {
+	list, ok := args.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		values = append(values, r.evalConditionExpr(item))
+	}
+
+	switch fn {
+	case "Fn::Equals":
+		if len(values) != 2 {
+			return nil
+		}
+		return stringValue(values[0]) == stringValue(values[1])
+	case "Fn::Not":
+		if len(values) != 1 {
+			return nil
+		}
+		b, _ := values[0].(bool)
+		return !b
+	case "Fn::And":
+		for _, v := range values {
+			if b, _ := v.(bool); !b {
+				return false
+			}
+		}
+		return true
+	case "Fn::Or":
+		for _, v := range values {
+			if b, _ := v.(bool); b {
+				return true
+			}
+		}
+		return false
+	}
+
+	return nil
+}
+
+func join(args interface{}) interface{} {
+	list, ok := args.([]interface{})
+	if !ok || len(list) != 2 {
+		return nil
+	}
+
+	delim, _ := list[0].(string)
+	items, ok := list[1].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	s := make([]string, 0, len(items))
+	for _, item := range items {
+		s = append(s, stringValue(item))
+	}
+
+	return strings.Join(s, delim)
+}
+
+func selectItem(args interface{}) interface{} {
+	list, ok := args.([]interface{})
+	if !ok || len(list) != 2 {
+		return nil
+	}
+
+	index, err := strconv.Atoi(stringValue(list[0]))
+	if err != nil {
+		return nil
+	}
+
+	items, ok := list[1].([]interface{})
+	if !ok || index < 0 || index >= len(items) {
+		return nil
+	}
+
+	return items[index]
+}
+
+func split(args interface{}) interface{} {
+	list, ok := args.([]interface{})
+	if !ok || len(list) != 2 {
+		return nil
+	}
+
+	delim, _ := list[0].(string)
+	s, ok := list[1].(string)
+	if !ok {
+		return nil
+	}
+
+	items := []interface{}{}
+	for _, item := range strings.Split(s, delim) {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func mapValue(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+
+	return map[string]interface{}{}
+}
+
+func stringValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil, noValue:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		s := make([]string, 0, len(val))
+		for _, item := range val {
+			s = append(s, stringValue(item))
+		}
+		return strings.Join(s, ",")
+	}
+
+	return fmt.Sprintf("%v", v)
+}
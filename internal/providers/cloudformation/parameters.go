@@ -0,0 +1,109 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type cliParameter struct {
+	ParameterKey   string `json:"ParameterKey"`
+	ParameterValue string `json:"ParameterValue"`
+}
+
+// loadParameters returns the parameter values from the parameter files and
+// the overrides, with the overrides taking precedence. Relative parameter file
+// paths are relative to the directory of the template.
+func loadParameters(templatePath string, files []string, overrides map[string]string) (map[string]string, error) {
+	params := map[string]string{}
+
+	dir := templatePath
+	if info, err := os.Stat(templatePath); err != nil || !info.IsDir() {
+		dir = filepath.Dir(templatePath)
+	}
+
+	for _, f := range files {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(dir, f)
+		}
+
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading CloudFormation parameter file")
+		}
+
+		fileParams, err := parseParameterFile(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing CloudFormation parameter file %s", f)
+		}
+
+		for k, v := range fileParams {
+			params[k] = v
+		}
+	}
+
+	for k, v := range overrides {
+		params[k] = v
+	}
+
+	return params, nil
+}
+
+// parseParameterFile parses the JSON parameter file formats that are used by
+// the AWS CLI and CodePipeline:
+//
+//   - a list of objects with ParameterKey and ParameterValue, as used by
+//     aws cloudformation create-stack --parameters file://params.json
+//   - a list of Key=Value strings, as used by
+//     aws cloudformation deploy --parameter-overrides file://params.json
+//   - an object with a Parameters map, as used by CodePipeline template
+//     configuration files
+//   - an object mapping parameter names to values
+func parseParameterFile(b []byte) (map[string]string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{}
+
+	switch v := raw.(type) {
+	case []interface{}:
+		for _, item := range v {
+			switch p := item.(type) {
+			case string:
+				key, value, ok := strings.Cut(p, "=")
+				if !ok {
+					return nil, fmt.Errorf("invalid parameter %q, expected Key=Value", p)
+				}
+				params[key] = value
+			case map[string]interface{}:
+				var c cliParameter
+				pb, _ := json.Marshal(p)
+				if err := json.Unmarshal(pb, &c); err != nil || c.ParameterKey == "" {
+					return nil, fmt.Errorf("invalid parameter, expected ParameterKey and ParameterValue")
+				}
+				params[c.ParameterKey] = c.ParameterValue
+			default:
+				return nil, fmt.Errorf("invalid parameter %v", item)
+			}
+		}
+	case map[string]interface{}:
+		values := v
+		if p, ok := v["Parameters"].(map[string]interface{}); ok {
+			values = p
+		}
+
+		for k, value := range values {
+			params[k] = stringValue(value)
+		}
+	default:
+		return nil, errors.New("expected a JSON list or object")
+	}
+
+	return params, nil
+}
@@ -1,14 +1,14 @@
 package cloudformation
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
-	"github.com/awslabs/goformation/v4/cloudformation"
-
 	"github.com/tidwall/gjson"
 
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/cloudformation/aws"
 	"github.com/infracost/infracost/internal/schema"
 )
 
@@ -20,76 +20,100 @@ func NewParser(ctx *config.ProjectContext) *Parser {
 	return &Parser{ctx}
 }
 
-func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+func (p *Parser) createPartialResource(d *schema.ResourceData, u *schema.UsageData) *schema.PartialResource {
 	registryMap := GetResourceRegistryMap()
 
 	if isAwsChina(d) {
 		p.ctx.ContextValues.SetValue("isAWSChina", true)
 	}
 
+	d.UsageData = u
+
 	if registryItem, ok := (*registryMap)[d.Type]; ok {
 		if registryItem.NoPrice {
-			return &schema.Resource{
-				Name:         d.Address,
-				ResourceType: d.Type,
-				Tags:         d.Tags,
-				IsSkipped:    true,
-				NoPrice:      true,
-				SkipMessage:  "Free resource.",
-			}
+			return schema.NewPartialResource(d, &schema.Resource{
+				Name:        d.Address,
+				IsSkipped:   true,
+				NoPrice:     true,
+				SkipMessage: "Free resource.",
+			}, nil, nil)
 		}
 
-		res := registryItem.RFunc(d, u)
-		if res != nil {
-			res.ResourceType = d.Type
-			// TODO: Figure out how to set tags.  For now, have the RFunc set them.
-			// res.Tags = d.Tags
+		// Use the CoreRFunc to generate a CoreResource if possible, so the
+		// resource is built with any usage fetched when building the project.
+		if registryItem.CoreRFunc != nil {
+			if core := registryItem.CoreRFunc(d); core != nil {
+				return schema.NewPartialResource(d, nil, core, nil)
+			}
+		} else if res := registryItem.RFunc(d, u); res != nil {
 			if u != nil {
 				res.EstimationSummary = u.CalcEstimationSummary()
 			}
-			return res
+
+			return schema.NewPartialResource(d, res, nil, nil)
 		}
 	}
 
-	return &schema.Resource{
-		Name:         d.Address,
-		ResourceType: d.Type,
-		Tags:         d.Tags,
-		IsSkipped:    true,
-		SkipMessage:  "This resource is not currently supported",
-	}
+	return schema.NewPartialResource(d, &schema.Resource{
+		Name:        d.Address,
+		IsSkipped:   true,
+		SkipMessage: "This resource is not currently supported",
+	}, nil, nil)
 }
 
-func (p *Parser) parseTemplate(t *cloudformation.Template, usage schema.UsageMap) ([]*schema.Resource, []*schema.Resource, error) {
+func (p *Parser) parseTemplate(t *template, usage schema.UsageMap) ([]*schema.PartialResource, []*schema.PartialResource, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
-	var resources []*schema.Resource
+	var resources []*schema.PartialResource
 	resources = append(resources, baseResources...)
 
-	for name, d := range t.Resources {
-		tags := map[string]string{} // TODO: Where do I get tags?
-		usageData := usage.Get(name)
+	region := t.region
+	if region == "" {
+		region = aws.DefaultRegion
+	}
+
+	for id, v := range t.resources {
+		raw := mapValue(v)
+		resourceType, _ := raw["Type"].(string)
+
+		props, err := json.Marshal(mapValue(raw["Properties"]))
+		if err != nil {
+			return nil, nil, err
+		}
 
-		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, &tags, d)
+		values := gjson.ParseBytes(props)
+		tags := parseTags(values)
 
-		if r := p.createResource(resourceData, usageData); r != nil {
-			resources = append(resources, r)
+		// Usage can be keyed by the resource name or its logical ID
+		name := id
+		if n, ok := t.names[id]; ok {
+			name = n
 		}
+		usageData := usage.Get(name)
+		if usageData == nil {
+			usageData = usage.Get(id)
+		}
+
+		// CFResource is nil for resource types that goformation doesn't support,
+		// these can still be priced using the raw values.
+		resourceData := schema.NewCFResourceData(resourceType, "aws", name, &tags, t.typed[id])
+		resourceData.RawValues = values
+		resourceData.Set("region", region)
+
+		resources = append(resources, p.createPartialResource(resourceData, usageData))
 	}
 
 	return resources, resources, nil
 }
 
-func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.Resource {
-	resources := make([]*schema.Resource, 0)
+func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.PartialResource {
+	resources := make([]*schema.PartialResource, 0)
 
 	for k, v := range u.Data() {
 		for _, t := range GetUsageOnlyResources() {
 			if strings.HasPrefix(k, fmt.Sprintf("%s.", t)) {
 				d := schema.NewResourceData(t, "global", k, nil, gjson.Result{})
-				if r := p.createResource(d, v); r != nil {
-					resources = append(resources, r)
-				}
+				resources = append(resources, p.createPartialResource(d, v))
 			}
 		}
 	}
@@ -97,6 +121,25 @@ func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.Resource {
 	return resources
 }
 
+// parseTags returns the tags from the Tags property of the resource. Most
+// resources use a list of key value pairs, but some use a map.
+func parseTags(props gjson.Result) map[string]string {
+	tags := map[string]string{}
+
+	v := props.Get("Tags")
+	if v.IsArray() {
+		for _, t := range v.Array() {
+			tags[t.Get("Key").String()] = t.Get("Value").String()
+		}
+	} else if v.IsObject() {
+		for k, t := range v.Map() {
+			tags[k] = t.String()
+		}
+	}
+
+	return tags
+}
+
 func isAwsChina(d *schema.ResourceData) bool {
 	return strings.HasPrefix(d.Type, "aws_") && strings.HasPrefix(d.Get("region").String(), "cn-")
 }
@@ -0,0 +1,74 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestParseTemplate(t *testing.T) {
+	template, err := loadTemplate("testdata/resources.yml", nil)
+	require.NoError(t, err)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, nil)
+	_, partials, err := NewParser(ctx).parseTemplate(template, schema.NewUsageMapFromInterface(map[string]interface{}{}))
+	require.NoError(t, err)
+	resources := buildResources(partials)
+
+	byName := map[string]*schema.Resource{}
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	supported := []string{
+		"WebServer", "DataVolume", "Database", "Function", "Bucket", "Queue", "Topic", "NatGateway",
+		"Address", "Cache", "FileSystem", "LoadBalancer", "Logs", "Key", "Secret", "Repository",
+	}
+	for _, name := range supported {
+		r, ok := byName[name]
+		require.True(t, ok, name)
+		assert.False(t, r.IsSkipped, name)
+		assert.True(t, len(r.CostComponents) > 0 || len(r.SubResources) > 0, name)
+	}
+
+	assert.True(t, byName["Role"].IsSkipped)
+	assert.True(t, byName["Role"].NoPrice)
+	assert.True(t, byName["Stream"].IsSkipped)
+	assert.False(t, byName["Stream"].NoPrice)
+
+	web := byName["WebServer"]
+	assert.Equal(t, "AWS::EC2::Instance", web.ResourceType)
+	assert.Equal(t, &map[string]string{"Environment": "prod"}, web.Tags)
+	assert.Equal(t, "Instance usage (Linux/UNIX, on-demand, m5.large)", web.CostComponents[0].Name)
+
+	require.Len(t, web.SubResources, 2)
+	assert.Equal(t, "root_block_device", web.SubResources[0].Name)
+	assert.Equal(t, "Storage (general purpose SSD, gp3)", web.SubResources[0].CostComponents[0].Name)
+	assert.Equal(t, "20", web.SubResources[0].CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "ebs_block_device[1]", web.SubResources[1].Name)
+
+	db := byName["Database"]
+	assert.Equal(t, "Database instance (on-demand, Multi-AZ, db.t3.medium)", db.CostComponents[0].Name)
+
+	for _, r := range resources {
+		for _, c := range r.CostComponents {
+			if c.ProductFilter != nil && c.ProductFilter.Region != nil {
+				assert.Equal(t, "us-east-1", *c.ProductFilter.Region, r.Name)
+			}
+		}
+	}
+}
+
+// buildResources builds the partial resources like Project.BuildResources.
+func buildResources(partials []*schema.PartialResource) []*schema.Resource {
+	resources := make([]*schema.Resource, 0, len(partials))
+	for _, p := range partials {
+		resources = append(resources, schema.BuildResource(p, nil))
+	}
+
+	return resources
+}
@@ -0,0 +1,524 @@
+package cloudformation
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// samGlobalTypes maps the sections of the SAM Globals section to the resource
+// types they apply to.
+var samGlobalTypes = map[string]string{
+	"Function":    "AWS::Serverless::Function",
+	"Api":         "AWS::Serverless::Api",
+	"HttpApi":     "AWS::Serverless::HttpApi",
+	"SimpleTable": "AWS::Serverless::SimpleTable",
+}
+
+// lambdaFunctionProperties are the properties of AWS::Serverless::Function
+// that are passed through to AWS::Lambda::Function unchanged.
+var lambdaFunctionProperties = []string{
+	"Architectures",
+	"CodeSigningConfigArn",
+	"Description",
+	"Environment",
+	"EphemeralStorage",
+	"FileSystemConfigs",
+	"FunctionName",
+	"Handler",
+	"ImageConfig",
+	"KmsKeyArn",
+	"Layers",
+	"LoggingConfig",
+	"MemorySize",
+	"PackageType",
+	"ReservedConcurrentExecutions",
+	"Runtime",
+	"RuntimeManagementConfig",
+	"SnapStart",
+	"Timeout",
+	"VpcConfig",
+}
+
+var logicalIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// applySAMGlobals merges the properties in the SAM Globals section into the
+// resources they apply to. Properties set on the resource take precedence,
+// maps are merged and lists are appended to, see
+// https://docs.aws.amazon.com/serverless-application-model/latest/developerguide/sam-specification-template-anatomy-globals.html
+func applySAMGlobals(raw map[string]interface{}) {
+	globals := mapValue(raw["Globals"])
+	if len(globals) == 0 {
+		return
+	}
+
+	for section, resourceType := range samGlobalTypes {
+		g := mapValue(globals[section])
+		if len(g) == 0 {
+			continue
+		}
+
+		for _, v := range mapValue(raw["Resources"]) {
+			resource := mapValue(v)
+			if resource["Type"] != resourceType {
+				continue
+			}
+
+			resource["Properties"] = mergeGlobal(g, mapValue(resource["Properties"]))
+		}
+	}
+}
+
+func mergeGlobal(global, local interface{}) interface{} {
+	switch g := global.(type) {
+	case map[string]interface{}:
+		l, ok := local.(map[string]interface{})
+		if !ok {
+			if local != nil {
+				return local
+			}
+			return g
+		}
+
+		merged := make(map[string]interface{}, len(g)+len(l))
+		for k, v := range g {
+			merged[k] = v
+		}
+		for k, v := range l {
+			if gv, ok := g[k]; ok {
+				merged[k] = mergeGlobal(gv, v)
+			} else {
+				merged[k] = v
+			}
+		}
+
+		return merged
+	case []interface{}:
+		if l, ok := local.([]interface{}); ok {
+			return append(append([]interface{}{}, g...), l...)
+		}
+	}
+
+	if local != nil {
+		return local
+	}
+
+	return global
+}
+
+// samExpander expands the resources of the AWS::Serverless-2016-10-31
+// transform into the CloudFormation resources they are deployed as. The
+// logical IDs of the generated resources follow the SAM conventions, so they
+// can be used as keys in the usage file, e.g. ServerlessRestApi for the API
+// that is created implicitly for functions with Api events.
+type samExpander struct {
+	resources map[string]interface{}
+	expanded  map[string]interface{}
+}
+
+// expandSAMResources replaces the resources in the template with the expanded
+// resources.
+func expandSAMResources(raw map[string]interface{}) {
+	e := &samExpander{
+		resources: mapValue(raw["Resources"]),
+		expanded:  map[string]interface{}{},
+	}
+
+	// Expand in a consistent order so the implicit APIs are always the same
+	names := make([]string, 0, len(e.resources))
+	for name := range e.resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resource := mapValue(e.resources[name])
+		props := mapValue(resource["Properties"])
+
+		switch resource["Type"] {
+		case "AWS::Serverless::Function":
+			e.expandFunction(name, resource, props)
+		case "AWS::Serverless::Api":
+			e.expandAPI(name, resource, props)
+		case "AWS::Serverless::HttpApi":
+			e.expandHTTPAPI(name, resource, props)
+		case "AWS::Serverless::SimpleTable":
+			e.expandSimpleTable(name, resource, props)
+		case "AWS::Serverless::LayerVersion":
+			e.add(name, "AWS::Lambda::LayerVersion", resource, pick(props, "CompatibleArchitectures", "CompatibleRuntimes", "Description", "LayerName", "LicenseInfo"))
+		case "AWS::Serverless::Application":
+			e.add(name, "AWS::CloudFormation::Stack", resource, map[string]interface{}{
+				"Parameters":  props["Parameters"],
+				"TemplateURL": props["Location"],
+			})
+		case "AWS::Serverless::StateMachine":
+			e.add(name, "AWS::StepFunctions::StateMachine", resource, map[string]interface{}{
+				"Definition":       props["Definition"],
+				"StateMachineName": props["Name"],
+				"StateMachineType": props["Type"],
+				"Tags":             samTags(props["Tags"], nil),
+			})
+		default:
+			e.expanded[name] = resource
+		}
+	}
+
+	raw["Resources"] = e.expanded
+}
+
+// add adds a resource of the given type, copying the resource attributes that
+// aren't properties from the SAM resource.
+func (e *samExpander) add(name, resourceType string, from map[string]interface{}, props map[string]interface{}) {
+	resource := map[string]interface{}{
+		"Type":       resourceType,
+		"Properties": withoutNil(props),
+	}
+
+	for _, attr := range []string{"DependsOn", "Metadata", "DeletionPolicy", "UpdateReplacePolicy"} {
+		if v, ok := from[attr]; ok {
+			resource[attr] = v
+		}
+	}
+
+	e.expanded[name] = resource
+}
+
+func (e *samExpander) expandFunction(name string, resource, props map[string]interface{}) {
+	fn := pick(props, lambdaFunctionProperties...)
+
+	switch {
+	case props["InlineCode"] != nil:
+		fn["Code"] = map[string]interface{}{"ZipFile": props["InlineCode"]}
+	case props["ImageUri"] != nil:
+		fn["Code"] = map[string]interface{}{"ImageUri": props["ImageUri"]}
+		if fn["PackageType"] == nil {
+			fn["PackageType"] = "Image"
+		}
+	default:
+		if code := s3Location(props["CodeUri"]); code != nil {
+			fn["Code"] = code
+		}
+	}
+
+	if mode, ok := props["Tracing"].(string); ok {
+		fn["TracingConfig"] = map[string]interface{}{"Mode": mode}
+	}
+
+	if arn := mapValue(props["DeadLetterQueue"])["TargetArn"]; arn != nil {
+		fn["DeadLetterConfig"] = map[string]interface{}{"TargetArn": arn}
+	}
+
+	if props["Role"] != nil {
+		fn["Role"] = props["Role"]
+	} else {
+		roleName := name + "Role"
+		e.add(roleName, "AWS::IAM::Role", resource, map[string]interface{}{
+			"AssumeRolePolicyDocument": map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{
+						"Action":    []interface{}{"sts:AssumeRole"},
+						"Effect":    "Allow",
+						"Principal": map[string]interface{}{"Service": []interface{}{"lambda.amazonaws.com"}},
+					},
+				},
+			},
+			"ManagedPolicyArns": []interface{}{"arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"},
+		})
+		fn["Role"] = roleName + ".Arn"
+	}
+
+	fn["Tags"] = samTags(props["Tags"], map[string]interface{}{"lambda:createdBy": "SAM"})
+
+	e.add(name, "AWS::Lambda::Function", resource, fn)
+
+	if alias, ok := props["AutoPublishAlias"].(string); ok && alias != "" {
+		e.add(name+"Version", "AWS::Lambda::Version", resource, map[string]interface{}{
+			"FunctionName": name,
+		})
+		e.add(name+"Alias"+logicalIDInvalidChars.ReplaceAllString(alias, ""), "AWS::Lambda::Alias", resource, map[string]interface{}{
+			"FunctionName":                 name,
+			"FunctionVersion":              name + "Version.Version",
+			"Name":                         alias,
+			"ProvisionedConcurrencyConfig": props["ProvisionedConcurrencyConfig"],
+		})
+	}
+
+	events := mapValue(props["Events"])
+	eventNames := make([]string, 0, len(events))
+	for eventName := range events {
+		eventNames = append(eventNames, eventName)
+	}
+	sort.Strings(eventNames)
+
+	for _, eventName := range eventNames {
+		e.expandEvent(name, eventName, resource, mapValue(events[eventName]))
+	}
+}
+
+func (e *samExpander) expandEvent(fnName, eventName string, resource, event map[string]interface{}) {
+	id := fnName + eventName
+	props := mapValue(event["Properties"])
+	principal := ""
+
+	switch event["Type"] {
+	case "Api":
+		if props["RestApiId"] == nil {
+			e.addImplicitRestAPI()
+		}
+		principal = "apigateway.amazonaws.com"
+	case "HttpApi":
+		if props["ApiId"] == nil {
+			e.addImplicitHTTPAPI()
+		}
+		principal = "apigateway.amazonaws.com"
+	case "SQS", "Kinesis", "DynamoDB", "MSK", "MQ", "SelfManagedKafka", "DocumentDB":
+		source := props["Queue"]
+		if source == nil {
+			source = props["Stream"]
+		}
+		if source == nil {
+			source = props["Broker"]
+		}
+
+		e.add(id, "AWS::Lambda::EventSourceMapping", resource, map[string]interface{}{
+			"BatchSize":        props["BatchSize"],
+			"Enabled":          props["Enabled"],
+			"EventSourceArn":   source,
+			"FunctionName":     fnName,
+			"StartingPosition": props["StartingPosition"],
+		})
+	case "Schedule", "CloudWatchEvent", "EventBridgeRule":
+		e.add(id, "AWS::Events::Rule", resource, map[string]interface{}{
+			"EventBusName":       props["EventBusName"],
+			"EventPattern":       props["Pattern"],
+			"ScheduleExpression": props["Schedule"],
+			"Targets": []interface{}{
+				map[string]interface{}{"Arn": fnName + ".Arn", "Id": id + "LambdaTarget"},
+			},
+		})
+		principal = "events.amazonaws.com"
+	case "SNS":
+		e.add(id, "AWS::SNS::Subscription", resource, map[string]interface{}{
+			"Endpoint": fnName + ".Arn",
+			"Protocol": "lambda",
+			"TopicArn": props["Topic"],
+		})
+		principal = "sns.amazonaws.com"
+	case "CloudWatchLogs":
+		e.add(id, "AWS::Logs::SubscriptionFilter", resource, map[string]interface{}{
+			"DestinationArn": fnName + ".Arn",
+			"FilterPattern":  props["FilterPattern"],
+			"LogGroupName":   props["LogGroupName"],
+		})
+		principal = "logs.amazonaws.com"
+	case "S3":
+		principal = "s3.amazonaws.com"
+	case "IoTRule":
+		principal = "iot.amazonaws.com"
+	case "Cognito":
+		principal = "cognito-idp.amazonaws.com"
+	case "AlexaSkill":
+		principal = "alexa-appkit.amazon.com"
+	}
+
+	if principal != "" {
+		e.add(id+"Permission", "AWS::Lambda::Permission", resource, map[string]interface{}{
+			"Action":       "lambda:InvokeFunction",
+			"FunctionName": fnName,
+			"Principal":    principal,
+		})
+	}
+}
+
+// addImplicitRestAPI adds the REST API that SAM creates for functions with Api
+// events that don't reference an API.
+func (e *samExpander) addImplicitRestAPI() {
+	if _, ok := e.expanded["ServerlessRestApi"]; ok {
+		return
+	}
+
+	e.add("ServerlessRestApi", "AWS::ApiGateway::RestApi", nil, map[string]interface{}{})
+	e.add("ServerlessRestApiDeployment", "AWS::ApiGateway::Deployment", nil, map[string]interface{}{
+		"RestApiId": "ServerlessRestApi",
+	})
+	e.add("ServerlessRestApiProdStage", "AWS::ApiGateway::Stage", nil, map[string]interface{}{
+		"DeploymentId": "ServerlessRestApiDeployment",
+		"RestApiId":    "ServerlessRestApi",
+		"StageName":    "Prod",
+	})
+}
+
+// addImplicitHTTPAPI adds the HTTP API that SAM creates for functions with
+// HttpApi events that don't reference an API.
+func (e *samExpander) addImplicitHTTPAPI() {
+	if _, ok := e.expanded["ServerlessHttpApi"]; ok {
+		return
+	}
+
+	e.add("ServerlessHttpApi", "AWS::ApiGatewayV2::Api", nil, map[string]interface{}{
+		"ProtocolType": "HTTP",
+	})
+	e.add("ServerlessHttpApiApiGatewayDefaultStage", "AWS::ApiGatewayV2::Stage", nil, map[string]interface{}{
+		"ApiId":      "ServerlessHttpApi",
+		"AutoDeploy": true,
+		"StageName":  "$default",
+	})
+}
+
+func (e *samExpander) expandAPI(name string, resource, props map[string]interface{}) {
+	api := pick(props, "Description", "Name")
+	if t, ok := props["EndpointConfiguration"].(string); ok {
+		api["EndpointConfiguration"] = map[string]interface{}{"Types": []interface{}{t}}
+	} else if c := mapValue(props["EndpointConfiguration"]); len(c) > 0 {
+		api["EndpointConfiguration"] = map[string]interface{}{"Types": []interface{}{c["Type"]}}
+	}
+	api["Tags"] = samTags(props["Tags"], nil)
+
+	e.add(name, "AWS::ApiGateway::RestApi", resource, api)
+	e.add(name+"Deployment", "AWS::ApiGateway::Deployment", resource, map[string]interface{}{
+		"RestApiId": name,
+	})
+
+	stageName := stringValue(props["StageName"])
+	stage := pick(props, "CacheClusterEnabled", "CacheClusterSize", "MethodSettings", "TracingEnabled", "Variables")
+	stage["DeploymentId"] = name + "Deployment"
+	stage["RestApiId"] = name
+	stage["StageName"] = stageName
+
+	e.add(name+logicalIDInvalidChars.ReplaceAllString(stageName, "")+"Stage", "AWS::ApiGateway::Stage", resource, stage)
+}
+
+func (e *samExpander) expandHTTPAPI(name string, resource, props map[string]interface{}) {
+	api := pick(props, "Description", "Name")
+	api["ProtocolType"] = "HTTP"
+
+	if t := mapValue(props["Tags"]); len(t) > 0 {
+		api["Tags"] = t
+	}
+
+	e.add(name, "AWS::ApiGatewayV2::Api", resource, api)
+
+	stageName := stringValue(props["StageName"])
+	stageID := name + "ApiGatewayDefaultStage"
+	if stageName == "" {
+		stageName = "$default"
+	} else {
+		stageID = name + logicalIDInvalidChars.ReplaceAllString(stageName, "") + "Stage"
+	}
+
+	e.add(stageID, "AWS::ApiGatewayV2::Stage", resource, map[string]interface{}{
+		"ApiId":      name,
+		"AutoDeploy": true,
+		"StageName":  stageName,
+	})
+}
+
+func (e *samExpander) expandSimpleTable(name string, resource, props map[string]interface{}) {
+	pk := mapValue(props["PrimaryKey"])
+	pkName := stringValue(pk["Name"])
+	if pkName == "" {
+		pkName = "id"
+	}
+
+	attrType := map[string]string{"String": "S", "Number": "N", "Binary": "B"}[stringValue(pk["Type"])]
+	if attrType == "" {
+		attrType = "S"
+	}
+
+	table := map[string]interface{}{
+		"AttributeDefinitions": []interface{}{
+			map[string]interface{}{"AttributeName": pkName, "AttributeType": attrType},
+		},
+		"KeySchema": []interface{}{
+			map[string]interface{}{"AttributeName": pkName, "KeyType": "HASH"},
+		},
+		"TableName": props["TableName"],
+		"Tags":      samTags(props["Tags"], nil),
+	}
+
+	if throughput := mapValue(props["ProvisionedThroughput"]); len(throughput) > 0 {
+		table["BillingMode"] = "PROVISIONED"
+		table["ProvisionedThroughput"] = throughput
+	} else {
+		table["BillingMode"] = "PAY_PER_REQUEST"
+	}
+
+	if sse := mapValue(props["SSESpecification"]); len(sse) > 0 {
+		table["SSESpecification"] = sse
+	}
+
+	e.add(name, "AWS::DynamoDB::Table", resource, table)
+}
+
+// samTags converts the map of tags used by SAM resources to the list of
+// key value pairs used by CloudFormation resources.
+func samTags(v interface{}, extra map[string]interface{}) interface{} {
+	m := map[string]interface{}{}
+	for k, v := range mapValue(v) {
+		m[k] = v
+	}
+	for k, v := range extra {
+		m[k] = v
+	}
+
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, map[string]interface{}{"Key": k, "Value": stringValue(m[k])})
+	}
+
+	return tags
+}
+
+// s3Location converts a SAM CodeUri to the Code property of a Lambda function.
+// Local paths are ignored since they are only known once the code is packaged.
+func s3Location(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case string:
+		if !strings.HasPrefix(val, "s3://") {
+			return nil
+		}
+
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(val, "s3://"), "/")
+		return map[string]interface{}{"S3Bucket": bucket, "S3Key": key}
+	case map[string]interface{}:
+		return withoutNil(map[string]interface{}{
+			"S3Bucket":        val["Bucket"],
+			"S3Key":           val["Key"],
+			"S3ObjectVersion": val["Version"],
+		})
+	}
+
+	return nil
+}
+
+func pick(m map[string]interface{}, keys ...string) map[string]interface{} {
+	picked := map[string]interface{}{}
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			picked[k] = v
+		}
+	}
+
+	return picked
+}
+
+func withoutNil(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if v == nil {
+			delete(m, k)
+		}
+	}
+
+	return m
+}
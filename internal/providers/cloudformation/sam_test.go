@@ -0,0 +1,106 @@
+package cloudformation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/apigateway"
+	"github.com/awslabs/goformation/v4/cloudformation/dynamodb"
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestDetectTemplateKind(t *testing.T) {
+	assert.Equal(t, KindCloudFormation, DetectTemplateKind("testdata/resources.yml"))
+	assert.Equal(t, KindSAM, DetectTemplateKind("testdata/sam.yml"))
+	assert.Equal(t, KindServerlessFramework, DetectTemplateKind("testdata/serverless"))
+	assert.Equal(t, KindServerlessFramework, DetectTemplateKind("testdata/serverless/serverless.yml"))
+	assert.Equal(t, "", DetectTemplateKind("testdata/intrinsics-params.json"))
+	assert.Equal(t, "", DetectTemplateKind("testdata"))
+}
+
+func TestDetectTemplateKindServerlessWithTerraform(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "serverless.yml"), []byte("service: orders\nprovider:\n  name: aws\n"), 0600))
+	assert.Equal(t, KindServerlessFramework, DetectTemplateKind(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("resource \"aws_sqs_queue\" \"orders\" {}\n"), 0600))
+	assert.Equal(t, "", DetectTemplateKind(dir))
+	assert.Equal(t, KindServerlessFramework, DetectTemplateKind(filepath.Join(dir, "serverless.yml")))
+}
+
+func TestLoadSAMTemplate(t *testing.T) {
+	template, err := loadTemplate("testdata/sam.yml", map[string]string{"Stage": "prod"})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"OrdersFunction", "OrdersFunctionRole", "OrdersFunctionVersion", "OrdersFunctionAliaslive",
+		"OrdersFunctionGetOrdersPermission", "OrdersFunctionQueue",
+		"ServerlessRestApi", "ServerlessRestApiDeployment", "ServerlessRestApiProdStage",
+		"ReportsFunction", "ReportsFunctionRole", "ReportsFunctionNightly", "ReportsFunctionNightlyPermission",
+		"ReportsFunctionHttpPermission", "ServerlessHttpApi", "ServerlessHttpApiApiGatewayDefaultStage",
+		"AdminApi", "AdminApiDeployment", "AdminApiprodStage",
+		"OrdersTable", "OrdersQueue",
+	}, resourceNames(template.resources))
+
+	orders := template.typed["OrdersFunction"].(*lambda.Function)
+	assert.Equal(t, 1024, orders.MemorySize)
+	assert.Equal(t, 10, orders.Timeout)
+	assert.Equal(t, "python3.12", orders.Runtime)
+	assert.Equal(t, "artifacts", orders.Code.S3Bucket)
+	assert.Equal(t, "OrdersFunctionRole.Arn", orders.Role)
+
+	alias := template.typed["OrdersFunctionAliaslive"].(*lambda.Alias)
+	assert.Equal(t, 5, alias.ProvisionedConcurrencyConfig.ProvisionedConcurrentExecutions)
+
+	stage := template.typed["AdminApiprodStage"].(*apigateway.Stage)
+	assert.Equal(t, "0.5", stage.CacheClusterSize)
+
+	table := template.typed["OrdersTable"].(*dynamodb.Table)
+	assert.Equal(t, "PAY_PER_REQUEST", table.BillingMode)
+	assert.Equal(t, "id", table.KeySchema[0].AttributeName)
+}
+
+func TestParseSAMTemplate(t *testing.T) {
+	template, err := loadTemplate("testdata/sam.yml", nil)
+	require.NoError(t, err)
+
+	usage := schema.NewUsageMapFromInterface(map[string]interface{}{
+		"OrdersFunction": map[string]interface{}{
+			"monthly_requests":    1000000,
+			"request_duration_ms": 200,
+		},
+	})
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, nil)
+	_, partials, err := NewParser(ctx).parseTemplate(template, usage)
+	require.NoError(t, err)
+	resources := buildResources(partials)
+
+	byName := map[string]*schema.Resource{}
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	for _, name := range []string{"OrdersFunction", "ReportsFunction", "OrdersFunctionAliaslive", "ServerlessRestApi", "ServerlessHttpApi", "AdminApidevStage", "OrdersTable"} {
+		r, ok := byName[name]
+		require.True(t, ok, name)
+		assert.False(t, r.IsSkipped, name)
+		assert.NotEmpty(t, r.CostComponents, name)
+	}
+
+	for _, name := range []string{"OrdersFunctionRole", "OrdersFunctionVersion", "ServerlessRestApiDeployment", "ReportsFunctionNightly"} {
+		assert.True(t, byName[name].NoPrice, name)
+	}
+
+	fn := byName["OrdersFunction"]
+	assert.Equal(t, "AWS::Lambda::Function", fn.ResourceType)
+	assert.Equal(t, &map[string]string{"Team": "payments", "lambda:createdBy": "SAM"}, fn.Tags)
+	assert.Equal(t, "1000000", fn.CostComponents[0].MonthlyQuantity.String())
+	assert.Contains(t, fn.CostComponents[0].Name, "Requests")
+}
@@ -0,0 +1,578 @@
+package cloudformation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/cloudformation/aws"
+)
+
+var (
+	serverlessConfigFiles = []string{"serverless.yml", "serverless.yaml", "serverless.json"}
+
+	// slsVariableRegex matches the innermost variables so nested variables such
+	// as ${self:custom.${opt:stage}} are resolved from the inside out.
+	slsVariableRegex = regexp.MustCompile(`\$\{([^{}]+)\}`)
+)
+
+// Defaults used by the Serverless Framework when they are not set in the
+// config.
+const (
+	slsDefaultStage      = "dev"
+	slsDefaultMemorySize = 1024
+	slsDefaultTimeout    = 6
+)
+
+// serverlessConfigPath returns the path of the Serverless Framework config if
+// path is a config file or a directory containing one.
+func serverlessConfigPath(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+
+	if !info.IsDir() {
+		for _, name := range serverlessConfigFiles {
+			if filepath.Base(path) == name {
+				return path, true
+			}
+		}
+
+		return "", false
+	}
+
+	for _, name := range serverlessConfigFiles {
+		p := filepath.Join(path, name)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// loadServerlessConfig converts a Serverless Framework config to the
+// CloudFormation template that it is deployed as. The stage and region can be
+// set using the stage and region parameters, the same as the --stage and
+// --region CLI options. Other parameters are used for ${param:...} variables.
+// It returns the template and the parameters to resolve it with.
+func loadServerlessConfig(path string, params map[string]string) (map[string]interface{}, map[string]string, error) {
+	config, err := readTemplate(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := mapValue(config["provider"])
+
+	s := &slsResolver{
+		config: config,
+		params: params,
+		stage:  params["stage"],
+		region: params["region"],
+	}
+
+	if s.stage == "" {
+		s.stage = stringValue(s.resolve(provider["stage"]))
+	}
+	if s.stage == "" {
+		s.stage = slsDefaultStage
+	}
+
+	if s.region == "" {
+		s.region = stringValue(s.resolve(provider["region"]))
+	}
+	if s.region == "" {
+		s.region = aws.DefaultRegion
+	}
+
+	config = mapValue(s.resolve(config))
+
+	c := &slsConverter{
+		config:    config,
+		provider:  mapValue(config["provider"]),
+		service:   slsServiceName(config["service"]),
+		stage:     s.stage,
+		resources: map[string]interface{}{},
+	}
+	template := c.convert()
+
+	resolved := map[string]string{}
+	for k, v := range params {
+		if k != "stage" && k != "region" {
+			resolved[k] = v
+		}
+	}
+	resolved["AWS::Region"] = s.region
+	resolved["AWS::StackName"] = c.service + "-" + c.stage
+
+	return template, resolved, nil
+}
+
+func slsServiceName(v interface{}) string {
+	// Older versions of the framework allow the service to be an object
+	if m, ok := v.(map[string]interface{}); ok {
+		return stringValue(m["name"])
+	}
+
+	return stringValue(v)
+}
+
+// slsResolver resolves the Serverless Framework variables that can be
+// resolved locally. Variables from other sources, such as SSM or other
+// stacks, are left as they are.
+type slsResolver struct {
+	config map[string]interface{}
+	params map[string]string
+	stage  string
+	region string
+	depth  int
+}
+
+func (s *slsResolver) resolve(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			resolved[k] = s.resolve(item)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			resolved = append(resolved, s.resolve(item))
+		}
+		return resolved
+	case string:
+		return s.resolveString(val)
+	}
+
+	return v
+}
+
+func (s *slsResolver) resolveString(str string) interface{} {
+	// Guard against self references that would never resolve
+	if s.depth > 10 {
+		return str
+	}
+
+	s.depth++
+	defer func() { s.depth-- }()
+
+	for {
+		m := slsVariableRegex.FindStringSubmatchIndex(str)
+		if m == nil {
+			return str
+		}
+
+		// If the whole string is a variable it can resolve to any type
+		if m[0] == 0 && m[1] == len(str) {
+			if v, ok := s.lookup(str[m[2]:m[3]]); ok {
+				if vs, ok := v.(string); ok {
+					return s.resolveString(vs)
+				}
+				return s.resolve(v)
+			}
+			return str
+		}
+
+		replaced := slsVariableRegex.ReplaceAllStringFunc(str, func(match string) string {
+			if v, ok := s.lookup(match[2 : len(match)-1]); ok {
+				return stringValue(s.resolve(v))
+			}
+
+			// Keep variables that can't be resolved, e.g. ${AWS::Region} in Fn::Sub
+			return match
+		})
+
+		if replaced == str {
+			return str
+		}
+		str = replaced
+	}
+}
+
+// lookup returns the value of the variable expression, which can include a
+// fallback value after a comma, e.g. ${opt:stage, 'dev'}.
+func (s *slsResolver) lookup(expr string) (interface{}, bool) {
+	source, fallback, hasFallback := strings.Cut(expr, ",")
+	source = strings.TrimSpace(source)
+
+	if v, ok := s.lookupSource(source); ok && v != nil {
+		return v, true
+	}
+
+	if !hasFallback {
+		if isSLSSource(source) {
+			logging.Logger.Debug().Msgf("Could not resolve Serverless Framework variable ${%s}", expr)
+		}
+		return nil, false
+	}
+
+	fallback = strings.TrimSpace(fallback)
+	if len(fallback) >= 2 && (fallback[0] == '\'' || fallback[0] == '"') && fallback[len(fallback)-1] == fallback[0] {
+		return fallback[1 : len(fallback)-1], true
+	}
+
+	if f, err := strconv.ParseFloat(fallback, 64); err == nil {
+		return f, true
+	}
+
+	if fallback == "true" || fallback == "false" {
+		return fallback == "true", true
+	}
+
+	return s.lookup(fallback)
+}
+
+func (s *slsResolver) lookupSource(source string) (interface{}, bool) {
+	switch {
+	case source == "opt:stage" || source == "sls:stage":
+		return s.stage, s.stage != ""
+	case source == "opt:region" || source == "aws:region":
+		return s.region, s.region != ""
+	case source == "aws:accountId":
+		return "123456789012", true
+	case source == "sls:instanceId":
+		return "infracost", true
+	case strings.HasPrefix(source, "self:"):
+		var v interface{} = s.config
+		for _, part := range strings.Split(strings.TrimPrefix(source, "self:"), ".") {
+			if part == "" {
+				continue
+			}
+
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			v, ok = m[part]
+			if !ok {
+				return nil, false
+			}
+		}
+		return v, true
+	case strings.HasPrefix(source, "env:"):
+		return os.LookupEnv(strings.TrimPrefix(source, "env:"))
+	case strings.HasPrefix(source, "param:"):
+		v, ok := s.params[strings.TrimPrefix(source, "param:")]
+		return v, ok
+	}
+
+	return nil, false
+}
+
+func isSLSSource(source string) bool {
+	for _, prefix := range []string{"self:", "opt:", "sls:", "env:", "param:", "aws:", "file(", "cf:", "cf(", "ssm:", "s3:", "sls:"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// slsConverter converts the functions of a Serverless Framework config to
+// CloudFormation resources. The logical IDs follow the Serverless Framework
+// naming conventions, e.g. HelloLambdaFunction for the hello function, so
+// they can be used as keys in the usage file.
+type slsConverter struct {
+	config    map[string]interface{}
+	provider  map[string]interface{}
+	service   string
+	stage     string
+	resources map[string]interface{}
+}
+
+func (c *slsConverter) convert() map[string]interface{} {
+	functions := mapValue(c.config["functions"])
+
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.convertFunction(name, mapValue(functions[name]))
+	}
+
+	if len(functions) > 0 && c.provider["role"] == nil && c.provider["iam"] == nil {
+		c.add("IamRoleLambdaExecution", "AWS::IAM::Role", map[string]interface{}{
+			"AssumeRolePolicyDocument": map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{
+						"Action":    []interface{}{"sts:AssumeRole"},
+						"Effect":    "Allow",
+						"Principal": map[string]interface{}{"Service": []interface{}{"lambda.amazonaws.com"}},
+					},
+				},
+			},
+		})
+	}
+
+	template := map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+	}
+
+	// The resources section is a CloudFormation template fragment that is
+	// merged into the generated template
+	custom := mapValue(c.config["resources"])
+	for _, section := range []string{"Conditions", "Mappings", "Outputs", "Parameters"} {
+		if v, ok := custom[section]; ok {
+			template[section] = v
+		}
+	}
+
+	for name, v := range mapValue(custom["Resources"]) {
+		resource := mapValue(v)
+		existing := mapValue(c.resources[name])
+
+		// Resources with the same logical ID as a generated resource override
+		// its properties
+		if len(existing) > 0 {
+			props := mapValue(existing["Properties"])
+			for k, p := range mapValue(resource["Properties"]) {
+				props[k] = p
+			}
+			existing["Properties"] = props
+			continue
+		}
+
+		c.resources[name] = resource
+	}
+
+	template["Resources"] = c.resources
+
+	return template
+}
+
+func (c *slsConverter) add(name, resourceType string, props map[string]interface{}) {
+	c.resources[name] = map[string]interface{}{
+		"Type":       resourceType,
+		"Properties": withoutNil(props),
+	}
+}
+
+func (c *slsConverter) convertFunction(name string, fn map[string]interface{}) {
+	id := slsNormalizedFunctionName(name)
+	functionName := stringValue(fn["name"])
+	if functionName == "" {
+		functionName = fmt.Sprintf("%s-%s-%s", c.service, c.stage, name)
+	}
+
+	props := map[string]interface{}{
+		"FunctionName":                 functionName,
+		"Handler":                      fn["handler"],
+		"MemorySize":                   c.functionOrProvider(fn, "memorySize", float64(slsDefaultMemorySize)),
+		"ReservedConcurrentExecutions": fn["reservedConcurrency"],
+		"Runtime":                      c.functionOrProvider(fn, "runtime", nil),
+		"Timeout":                      c.functionOrProvider(fn, "timeout", float64(slsDefaultTimeout)),
+		"Tags":                         samTags(mergeMaps(c.provider["tags"], fn["tags"]), nil),
+	}
+
+	if env := mergeMaps(c.provider["environment"], fn["environment"]); len(env) > 0 {
+		props["Environment"] = map[string]interface{}{"Variables": env}
+	}
+
+	if arch := c.functionOrProvider(fn, "architecture", nil); arch != nil {
+		props["Architectures"] = []interface{}{arch}
+	}
+
+	if size := c.functionOrProvider(fn, "ephemeralStorageSize", nil); size != nil {
+		props["EphemeralStorage"] = map[string]interface{}{"Size": size}
+	}
+
+	if role, ok := fn["role"]; ok {
+		props["Role"] = role
+	} else if role, ok := c.provider["role"]; ok {
+		props["Role"] = role
+	} else {
+		props["Role"] = "IamRoleLambdaExecution.Arn"
+	}
+
+	c.add(id+"LambdaFunction", "AWS::Lambda::Function", props)
+
+	c.add(id+"LogGroup", "AWS::Logs::LogGroup", map[string]interface{}{
+		"LogGroupName":    "/aws/lambda/" + functionName,
+		"RetentionInDays": c.functionOrProvider(fn, "logRetentionInDays", nil),
+	})
+
+	if pc, ok := fn["provisionedConcurrency"].(float64); ok && pc > 0 {
+		c.add(id+"ProvConcLambdaAlias", "AWS::Lambda::Alias", map[string]interface{}{
+			"FunctionName":    id + "LambdaFunction",
+			"FunctionVersion": "$LATEST",
+			"Name":            "provisioned",
+			"ProvisionedConcurrencyConfig": map[string]interface{}{
+				"ProvisionedConcurrentExecutions": pc,
+			},
+		})
+	}
+
+	events, _ := fn["events"].([]interface{})
+	for i, v := range events {
+		for eventType, event := range mapValue(v) {
+			c.convertEvent(id, i+1, eventType, event)
+		}
+	}
+}
+
+func (c *slsConverter) convertEvent(id string, index int, eventType string, event interface{}) {
+	fnID := id + "LambdaFunction"
+	props := mapValue(event)
+
+	switch eventType {
+	case "http":
+		if _, ok := c.resources["ApiGatewayRestApi"]; !ok {
+			c.add("ApiGatewayRestApi", "AWS::ApiGateway::RestApi", map[string]interface{}{
+				"Name": c.stage + "-" + c.service,
+			})
+			c.add("ApiGatewayDeployment", "AWS::ApiGateway::Deployment", map[string]interface{}{
+				"RestApiId": "ApiGatewayRestApi",
+				"StageName": c.stage,
+			})
+		}
+		c.addPermission(id+"LambdaPermissionApiGateway", fnID, "apigateway.amazonaws.com")
+	case "httpApi":
+		if _, ok := c.resources["HttpApi"]; !ok {
+			c.add("HttpApi", "AWS::ApiGatewayV2::Api", map[string]interface{}{
+				"Name":         c.stage + "-" + c.service,
+				"ProtocolType": "HTTP",
+			})
+			c.add("HttpApiStage", "AWS::ApiGatewayV2::Stage", map[string]interface{}{
+				"ApiId":      "HttpApi",
+				"AutoDeploy": true,
+				"StageName":  "$default",
+			})
+		}
+		c.addPermission(id+"LambdaPermissionHttpApi", fnID, "apigateway.amazonaws.com")
+	case "sqs", "stream", "kafka", "msk", "activemq", "rabbitmq":
+		arn := event
+		if len(props) > 0 {
+			arn = props["arn"]
+		}
+
+		c.add(fmt.Sprintf("%sEventSourceMapping%d", id, index), "AWS::Lambda::EventSourceMapping", map[string]interface{}{
+			"BatchSize":      props["batchSize"],
+			"EventSourceArn": arn,
+			"FunctionName":   fnID,
+		})
+	case "schedule":
+		rate := event
+		if len(props) > 0 {
+			rate = props["rate"]
+		}
+		if list, ok := rate.([]interface{}); ok && len(list) > 0 {
+			rate = list[0]
+		}
+
+		c.add(fmt.Sprintf("%sEventsRuleSchedule%d", id, index), "AWS::Events::Rule", map[string]interface{}{
+			"ScheduleExpression": rate,
+			"Targets": []interface{}{
+				map[string]interface{}{"Arn": fnID + ".Arn", "Id": id + "Schedule"},
+			},
+		})
+		c.addPermission(fmt.Sprintf("%sLambdaPermissionEventsRuleSchedule%d", id, index), fnID, "events.amazonaws.com")
+	case "sns":
+		topic := event
+		if len(props) > 0 {
+			topic = props["arn"]
+			if topic == nil {
+				topic = props["topicName"]
+			}
+		}
+
+		topicName := stringValue(topic)
+		topicArn := topic
+		if !strings.HasPrefix(topicName, "arn:") {
+			topicID := "SNSTopic" + slsNormalizedName(topicName)
+			c.add(topicID, "AWS::SNS::Topic", map[string]interface{}{
+				"TopicName": topicName,
+			})
+			topicArn = topicID
+		}
+
+		c.add(id+"SnsSubscription"+slsNormalizedName(topicName), "AWS::SNS::Subscription", map[string]interface{}{
+			"Endpoint": fnID + ".Arn",
+			"Protocol": "lambda",
+			"TopicArn": topicArn,
+		})
+		c.addPermission(id+"LambdaPermission"+slsNormalizedName(topicName)+"SNS", fnID, "sns.amazonaws.com")
+	case "s3":
+		bucket := event
+		existing := false
+		if len(props) > 0 {
+			bucket = props["bucket"]
+			existing, _ = props["existing"].(bool)
+		}
+
+		bucketName := stringValue(bucket)
+		if !existing {
+			c.add("S3Bucket"+slsNormalizedName(bucketName), "AWS::S3::Bucket", map[string]interface{}{
+				"BucketName": bucketName,
+			})
+		}
+		c.addPermission(id+"LambdaPermission"+slsNormalizedName(bucketName)+"S3", fnID, "s3.amazonaws.com")
+	}
+}
+
+func (c *slsConverter) addPermission(name, fnID, principal string) {
+	c.add(name, "AWS::Lambda::Permission", map[string]interface{}{
+		"Action":       "lambda:InvokeFunction",
+		"FunctionName": fnID + ".Arn",
+		"Principal":    principal,
+	})
+}
+
+// functionOrProvider returns the value of the function property, falling back
+// to the provider property and then to the default.
+func (c *slsConverter) functionOrProvider(fn map[string]interface{}, key string, def interface{}) interface{} {
+	if v, ok := fn[key]; ok && v != nil {
+		return v
+	}
+
+	if v, ok := c.provider[key]; ok && v != nil {
+		return v
+	}
+
+	return def
+}
+
+func mergeMaps(a, b interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range mapValue(a) {
+		merged[k] = v
+	}
+	for k, v := range mapValue(b) {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// slsNormalizedFunctionName returns the function name as it is used in
+// logical IDs by the Serverless Framework, e.g. my-func becomes MyDashfunc.
+func slsNormalizedFunctionName(name string) string {
+	name = strings.ReplaceAll(name, "-", "Dash")
+	name = strings.ReplaceAll(name, "_", "Underscore")
+
+	return slsNormalizedName(name)
+}
+
+// slsNormalizedName returns the name with the non-alphanumeric characters
+// removed and the first letter capitalized.
+func slsNormalizedName(name string) string {
+	name = logicalIDInvalidChars.ReplaceAllString(name, "")
+	if name == "" {
+		return name
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
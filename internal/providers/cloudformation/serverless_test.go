@@ -0,0 +1,64 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+	"github.com/awslabs/goformation/v4/cloudformation/logs"
+	"github.com/awslabs/goformation/v4/cloudformation/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadServerlessConfig(t *testing.T) {
+	template, err := loadTemplate("testdata/serverless", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-2", template.region)
+
+	assert.ElementsMatch(t, []string{
+		"IamRoleLambdaExecution",
+		"CreateDashorderLambdaFunction", "CreateDashorderLogGroup", "CreateDashorderProvConcLambdaAlias",
+		"CreateDashorderLambdaPermissionApiGateway", "CreateDashorderEventSourceMapping2",
+		"ApiGatewayRestApi", "ApiGatewayDeployment",
+		"ListUnderscoreordersLambdaFunction", "ListUnderscoreordersLogGroup", "ListUnderscoreordersLambdaPermissionHttpApi",
+		"ListUnderscoreordersEventsRuleSchedule2", "ListUnderscoreordersLambdaPermissionEventsRuleSchedule2",
+		"SNSTopicOrderevents", "ListUnderscoreordersSnsSubscriptionOrderevents", "ListUnderscoreordersLambdaPermissionOrdereventsSNS",
+		"HttpApi", "HttpApiStage",
+		"OrdersQueue",
+	}, resourceNames(template.resources))
+
+	create := template.typed["CreateDashorderLambdaFunction"].(*lambda.Function)
+	assert.Equal(t, "orders-dev-create-order", create.FunctionName)
+	assert.Equal(t, 256, create.MemorySize)
+	assert.Equal(t, 30, create.Timeout)
+	assert.Equal(t, "nodejs20.x", create.Runtime)
+	assert.Equal(t, "orders", create.Tags[0].Value)
+
+	list := template.typed["ListUnderscoreordersLambdaFunction"].(*lambda.Function)
+	assert.Equal(t, 512, list.MemorySize)
+	assert.Equal(t, 6, list.Timeout)
+	assert.Equal(t, "orders", list.Environment.Variables["TABLE"])
+
+	logGroup := template.typed["CreateDashorderLogGroup"].(*logs.LogGroup)
+	assert.Equal(t, 14, logGroup.RetentionInDays)
+
+	queue := template.typed["OrdersQueue"].(*sqs.Queue)
+	assert.Equal(t, "orders-dev", queue.QueueName)
+}
+
+func TestLoadServerlessConfigStage(t *testing.T) {
+	template, err := loadTemplate("testdata/serverless/serverless.yml", map[string]string{
+		"stage":  "prod",
+		"region": "us-west-2",
+		"table":  "orders-prod",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", template.region)
+
+	create := template.typed["CreateDashorderLambdaFunction"].(*lambda.Function)
+	assert.Equal(t, "orders-prod-create-order", create.FunctionName)
+	assert.Equal(t, 2048, create.MemorySize)
+
+	list := template.typed["ListUnderscoreordersLambdaFunction"].(*lambda.Function)
+	assert.Equal(t, "orders-prod", list.Environment.Variables["TABLE"])
+}
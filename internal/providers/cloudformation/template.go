@@ -0,0 +1,407 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/awslabs/goformation/v4"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/intrinsics"
+	yaml "github.com/sanathkr/go-yaml"
+
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// The kinds of template that are supported by the CloudFormation provider.
+const (
+	KindCloudFormation      = "cloudformation"
+	KindSAM                 = "sam"
+	KindServerlessFramework = "serverless_framework"
+	KindCDK                 = "cdk"
+)
+
+const (
+	samTransform = "AWS::Serverless-2016-10-31"
+
+	// maxNestedStackDepth limits how deep nested stacks are loaded, to guard
+	// against templates that include themselves.
+	maxNestedStackDepth = 5
+)
+
+// goformation registers the YAML tags globally each time a template is parsed
+// so it is not threadsafe, see https://github.com/awslabs/goformation/issues/363
+var templateMux = &sync.Mutex{}
+
+func init() {
+	// goformation doesn't register the short form of the Condition function,
+	// which is used to reference conditions from other conditions.
+	yaml.RegisterTagUnmarshaler("!Condition", conditionTagUnmarshaler{})
+}
+
+type conditionTagUnmarshaler struct{}
+
+func (conditionTagUnmarshaler) UnmarshalYAMLTag(tag string, fieldValue reflect.Value) reflect.Value {
+	output := reflect.ValueOf(make(map[interface{}]interface{}))
+	output.SetMapIndex(reflect.ValueOf("Condition"), fieldValue)
+
+	return output
+}
+
+// template is a CloudFormation template with its intrinsic functions resolved
+// and any transforms expanded.
+type template struct {
+	// resources are the resolved resources by logical ID, as they are in the
+	// template.
+	resources map[string]interface{}
+	// typed are the goformation resources by logical ID. Resource types that
+	// aren't supported by goformation are not included.
+	typed cloudformation.Resources
+	// names are the names to show for resources instead of their logical ID,
+	// e.g. the construct path of resources synthesized by the CDK.
+	names  map[string]string
+	region string
+}
+
+// DetectTemplateKind returns the kind of template at path, or an empty string
+// if it isn't a CloudFormation, SAM or Serverless Framework template or a CDK
+// cloud assembly. A directory with Terraform or OpenTofu files is left to the
+// Terraform provider, even if it also has a CDK app or a Serverless Framework
+// config.
+func DetectTemplateKind(path string) string {
+	if _, ok := cloudAssemblyPath(path); ok && !hasTerraformFiles(path) {
+		return KindCDK
+	}
+
+	if _, ok := serverlessConfigPath(path); ok && !hasTerraformFiles(path) {
+		return KindServerlessFramework
+	}
+
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return ""
+	}
+
+	raw, err := readTemplate(path)
+	if err != nil {
+		return ""
+	}
+
+	resources := mapValue(raw["Resources"])
+	if len(resources) == 0 {
+		return ""
+	}
+
+	for _, v := range resources {
+		if t, _ := mapValue(v)["Type"].(string); t == "" {
+			return ""
+		}
+	}
+
+	if isSAMTemplate(raw) {
+		return KindSAM
+	}
+
+	return KindCloudFormation
+}
+
+// hasTerraformFiles returns true if path is a directory with Terraform or
+// OpenTofu configuration files.
+func hasTerraformFiles(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+
+	return len(modules.ConfigFiles(entries)) > 0
+}
+
+// readTemplate reads a JSON or YAML template, converting the short form of
+// intrinsic functions in YAML templates to the long form.
+func readTemplate(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(path, ".json") {
+		templateMux.Lock()
+		// The goformation intrinsic processing is only used to convert YAML
+		// templates to JSON, since it doesn't support conditions on resources or
+		// resolving references to other resources.
+		data, err = intrinsics.ProcessYAML(data, &intrinsics.ProcessorOptions{NoProcess: true})
+		templateMux.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// loadTemplate reads the template or Serverless Framework config at path and
+// resolves its intrinsic functions using the parameter values. Nested stacks
+// with a local template are loaded and their resources are included in the
+// template, with logical IDs prefixed by the logical ID of the nested stack.
+func loadTemplate(path string, params map[string]string) (*template, error) {
+	t, err := loadTemplateResources(path, params, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	typed, err := parseTypedResources(t.resources)
+	if err != nil {
+		return nil, err
+	}
+
+	t.typed = typed
+	t.names = cdkResourceNames(t.resources)
+
+	return t, nil
+}
+
+func loadTemplateResources(path string, params map[string]string, depth int) (*template, error) {
+	var raw map[string]interface{}
+	var err error
+
+	if configPath, ok := serverlessConfigPath(path); ok {
+		raw, params, err = loadServerlessConfig(configPath, params)
+	} else {
+		raw, err = readTemplate(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sam := isSAMTemplate(raw)
+	if sam {
+		applySAMGlobals(raw)
+	}
+
+	r := newResolver(raw, params)
+	r.resolveTemplate(raw)
+
+	if sam {
+		expandSAMResources(raw)
+	}
+
+	t := &template{
+		resources: mapValue(raw["Resources"]),
+		region:    r.region,
+	}
+
+	loadNestedStacks(t, filepath.Dir(path), r, depth)
+
+	return t, nil
+}
+
+// loadNestedStacks adds the resources of nested stacks that have a local
+// template. The CDK adds the path of the template to the aws:asset:path
+// metadata, and templates that haven't been packaged can use a relative path
+// for the TemplateURL.
+func loadNestedStacks(t *template, dir string, r *resolver, depth int) {
+	nested := map[string]interface{}{}
+
+	for name, v := range t.resources {
+		resource := mapValue(v)
+		if resource["Type"] != "AWS::CloudFormation::Stack" {
+			continue
+		}
+
+		path := nestedStackTemplatePath(dir, resource)
+		if path == "" {
+			logging.Logger.Debug().Msgf("Skipping nested stack %s as its template is not a local file", name)
+			continue
+		}
+
+		if depth >= maxNestedStackDepth {
+			logging.Logger.Warn().Msgf("Skipping nested stack %s as stacks are nested more than %d levels deep", name, maxNestedStackDepth)
+			continue
+		}
+
+		params := map[string]string{
+			"AWS::Region":    r.region,
+			"AWS::AccountId": r.accountID,
+		}
+		for k, p := range mapValue(mapValue(resource["Properties"])["Parameters"]) {
+			params[k] = stringValue(p)
+		}
+
+		n, err := loadTemplateResources(path, params, depth+1)
+		if err != nil {
+			logging.Logger.Warn().Err(err).Msgf("Could not load the template for nested stack %s", name)
+			continue
+		}
+
+		for id, nr := range n.resources {
+			nested[name+"/"+id] = nr
+		}
+	}
+
+	for id, nr := range nested {
+		t.resources[id] = nr
+	}
+}
+
+func nestedStackTemplatePath(dir string, resource map[string]interface{}) string {
+	candidates := []string{}
+
+	if p, ok := mapValue(resource["Metadata"])["aws:asset:path"].(string); ok {
+		candidates = append(candidates, p)
+	}
+
+	if u, ok := mapValue(resource["Properties"])["TemplateURL"].(string); ok && !strings.Contains(u, "://") {
+		candidates = append(candidates, u)
+	}
+
+	for _, p := range candidates {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// parseTypedResources converts the resources to goformation resources.
+// goformation fails to parse the whole template if it contains any resource
+// types or properties it doesn't know about, so unknown types are left out and
+// unknown properties are removed. The mappers can still read these from the
+// raw values.
+func parseTypedResources(resources map[string]interface{}) (cloudformation.Resources, error) {
+	known := cloudformation.AllResources()
+	supported := map[string]interface{}{}
+
+	for name, v := range resources {
+		resource := mapValue(v)
+		t, _ := resource["Type"].(string)
+
+		if strings.HasPrefix(t, "Custom::") {
+			supported[name] = resource
+			continue
+		}
+
+		r, ok := known[t]
+		if !ok {
+			logging.Logger.Debug().Msgf("CloudFormation resource %s has type %s which is not supported by goformation", name, t)
+			continue
+		}
+
+		filtered := map[string]interface{}{}
+		for k, v := range resource {
+			filtered[k] = v
+		}
+		if props, ok := resource["Properties"]; ok {
+			filtered["Properties"] = knownProperties(props, reflect.TypeOf(r))
+		}
+
+		supported[name] = filtered
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"Resources": supported})
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := goformation.ParseJSONWithOptions(data, &intrinsics.ProcessorOptions{NoProcess: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Resources, nil
+}
+
+// knownProperties returns the value with any properties that aren't fields of
+// the goformation type t removed.
+func knownProperties(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		filtered := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			if fv, ok := m[name]; ok {
+				filtered[name] = knownProperties(fv, f.Type)
+			}
+		}
+
+		for k := range m {
+			if _, ok := filtered[k]; !ok {
+				logging.Logger.Debug().Msgf("CloudFormation property %s of %s is not supported by goformation", k, t.Name())
+			}
+		}
+
+		return filtered
+	case reflect.Slice:
+		l, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+
+		filtered := make([]interface{}, 0, len(l))
+		for _, item := range l {
+			filtered = append(filtered, knownProperties(item, t.Elem()))
+		}
+		return filtered
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		filtered := make(map[string]interface{}, len(m))
+		for k, item := range m {
+			filtered[k] = knownProperties(item, t.Elem())
+		}
+		return filtered
+	}
+
+	return v
+}
+
+func isSAMTemplate(raw map[string]interface{}) bool {
+	switch t := raw["Transform"].(type) {
+	case string:
+		return t == samTransform
+	case []interface{}:
+		for _, v := range t {
+			if v == samTransform {
+				return true
+			}
+		}
+	}
+
+	for _, v := range mapValue(raw["Resources"]) {
+		if t, _ := mapValue(v)["Type"].(string); strings.HasPrefix(t, "AWS::Serverless::") {
+			return true
+		}
+	}
+
+	return false
+}
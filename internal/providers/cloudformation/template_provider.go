@@ -1,7 +1,6 @@
 package cloudformation
 
 import (
-	"github.com/awslabs/goformation/v4"
 	"github.com/pkg/errors"
 
 	"github.com/infracost/infracost/internal/config"
@@ -35,7 +34,12 @@ func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
 }
 
 func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
-	template, err := goformation.Open(p.Path)
+	params, err := loadParameters(p.Path, p.ctx.ProjectConfig.CloudFormationParameterFiles, p.ctx.ProjectConfig.CloudFormationParameters)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	template, err := loadTemplate(p.Path, params)
 	if err != nil {
 		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
 	}
@@ -49,18 +53,28 @@ func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Proje
 	}
 
 	project := schema.NewProject(name, metadata)
-	parser := NewParser(p.ctx)
+	if err := parseProject(p.ctx, project, template, usage, p.includePastResources); err != nil {
+		return []*schema.Project{project}, err
+	}
+
+	return []*schema.Project{project}, nil
+}
+
+// parseProject adds the partial resources of the template to the project,
+// these are built into resources by Project.BuildResources.
+func parseProject(ctx *config.ProjectContext, project *schema.Project, template *template, usage schema.UsageMap, includePastResources bool) error {
+	parser := NewParser(ctx)
 	pastResources, resources, err := parser.parseTemplate(template, usage)
 	if err != nil {
-		return []*schema.Project{project}, errors.Wrap(err, "Error parsing CloudFormation template file")
+		return errors.Wrap(err, "Error parsing CloudFormation template file")
 	}
 
-	project.PastResources = pastResources
-	project.Resources = resources
+	project.PartialPastResources = pastResources
+	project.PartialResources = resources
 
-	if !p.includePastResources {
-		project.PastResources = nil
+	if !includePastResources {
+		project.PartialPastResources = nil
 	}
 
-	return []*schema.Project{project}, nil
+	return nil
 }
@@ -0,0 +1,127 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+	"github.com/awslabs/goformation/v4/cloudformation/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplateDefaults(t *testing.T) {
+	template, err := loadTemplate("testdata/intrinsics.yml", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", template.region)
+
+	assert.ElementsMatch(t, []string{"WebServer", "Address", "Database", "DevQueue"}, resourceNames(template.typed))
+
+	web := template.typed["WebServer"].(*ec2.Instance)
+	assert.Equal(t, "t3.micro", web.InstanceType)
+	assert.Equal(t, "subnet-1", web.SubnetId)
+	assert.False(t, web.Monitoring)
+	assert.Equal(t, "infracost-dev-web", web.Tags[0].Value)
+	assert.Equal(t, "team-dev", web.Tags[1].Value)
+
+	eip := template.typed["Address"].(*ec2.EIP)
+	assert.Equal(t, "WebServer", eip.InstanceId)
+
+	db := template.typed["Database"].(*rds.DBInstance)
+	assert.Equal(t, "db.t3.small", db.DBInstanceClass)
+	assert.Equal(t, "20", db.AllocatedStorage)
+	assert.False(t, db.MultiAZ)
+
+	queue := template.typed["DevQueue"].(*sqs.Queue)
+	assert.Equal(t, 60, queue.MessageRetentionPeriod)
+	assert.Equal(t, "dev-queue", queue.QueueName)
+}
+
+func TestLoadTemplateParameters(t *testing.T) {
+	params, err := loadParameters("testdata/intrinsics.yml", []string{"intrinsics-params.json"}, map[string]string{
+		"InstanceType": "m5.2xlarge",
+		"AWS::Region":  "eu-west-1",
+	})
+	require.NoError(t, err)
+
+	template, err := loadTemplate("testdata/intrinsics.yml", params)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", template.region)
+
+	assert.ElementsMatch(t, []string{"WebServer", "Address", "Database", "Replica"}, resourceNames(template.typed))
+
+	web := template.typed["WebServer"].(*ec2.Instance)
+	assert.Equal(t, "m5.2xlarge", web.InstanceType)
+	assert.True(t, web.Monitoring)
+
+	db := template.typed["Database"].(*rds.DBInstance)
+	assert.Equal(t, "db.m5.large", db.DBInstanceClass)
+	assert.Equal(t, "200", db.AllocatedStorage)
+	assert.True(t, db.MultiAZ)
+
+	replica := template.typed["Replica"].(*rds.DBInstance)
+	assert.Equal(t, "db.m5.large", replica.DBInstanceClass)
+	assert.Equal(t, "Database", replica.SourceDBInstanceIdentifier)
+}
+
+func TestParseParameterFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			name:     "create-stack parameters",
+			input:    `[{"ParameterKey": "Env", "ParameterValue": "prod"}, {"ParameterKey": "Size", "ParameterValue": "10"}]`,
+			expected: map[string]string{"Env": "prod", "Size": "10"},
+		},
+		{
+			name:     "deploy parameter overrides",
+			input:    `["Env=prod", "Url=https://example.com?a=b"]`,
+			expected: map[string]string{"Env": "prod", "Url": "https://example.com?a=b"},
+		},
+		{
+			name:     "template configuration",
+			input:    `{"Parameters": {"Env": "prod", "Size": 10}, "Tags": {"Team": "a"}}`,
+			expected: map[string]string{"Env": "prod", "Size": "10"},
+		},
+		{
+			name:     "map",
+			input:    `{"Env": "prod"}`,
+			expected: map[string]string{"Env": "prod"},
+		},
+		{
+			name:  "invalid override",
+			input: `["Env"]`,
+			err:   true,
+		},
+		{
+			name:  "invalid JSON",
+			input: `Env=prod`,
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseParameterFile([]byte(tt.input))
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func resourceNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+
+	return names
+}
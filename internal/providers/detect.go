@@ -6,14 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sync"
-
-	"github.com/awslabs/goformation/v4"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/hcl"
 	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/arm"
 	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/providers/kubernetes"
+	"github.com/infracost/infracost/internal/providers/pulumi"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
 )
@@ -46,8 +46,16 @@ func Detect(ctx *config.RunContext, project *config.Project, includePastResource
 		return []schema.Provider{terraform.NewTerragruntProvider(projectContext, includePastResources)}, nil
 	case ProjectTypeTerraformStateJSON:
 		return []schema.Provider{terraform.NewStateJSONProvider(projectContext, includePastResources)}, nil
-	case ProjectTypeCloudFormation:
+	case ProjectTypeCloudFormation, ProjectTypeSAM, ProjectTypeServerlessFramework:
 		return []schema.Provider{cloudformation.NewTemplateProvider(projectContext, includePastResources)}, nil
+	case ProjectTypeCDK:
+		return []schema.Provider{cloudformation.NewCloudAssemblyProvider(projectContext, includePastResources)}, nil
+	case ProjectTypePulumi:
+		return []schema.Provider{pulumi.NewProvider(projectContext, includePastResources)}, nil
+	case ProjectTypeARM:
+		return []schema.Provider{arm.NewTemplateProvider(projectContext, includePastResources)}, nil
+	case ProjectTypeKubernetes:
+		return []schema.Provider{kubernetes.NewManifestProvider(projectContext, includePastResources)}, nil
 	}
 
 	pathOverrides := make([]hcl.PathOverrideConfig, len(ctx.Config.Autodetect.PathOverrides))
@@ -184,18 +192,43 @@ var (
 	ProjectTypeTerragruntCLI       ProjectType = "terragrunt_cli"
 	ProjectTypeTerraformStateJSON  ProjectType = "terraform_state_json"
 	ProjectTypeCloudFormation      ProjectType = "cloudformation"
+	ProjectTypeSAM                 ProjectType = "sam"
+	ProjectTypeServerlessFramework ProjectType = "serverless_framework"
+	ProjectTypeCDK                 ProjectType = "cdk"
+	ProjectTypePulumi              ProjectType = "pulumi"
+	ProjectTypeARM                 ProjectType = "arm"
+	ProjectTypeKubernetes          ProjectType = "kubernetes"
 	ProjectTypeAutodetect          ProjectType = "autodetect"
 )
 
 func DetectProjectType(path string, forceCLI bool) ProjectType {
-	if isCloudFormationTemplate(path) {
+	switch cloudformation.DetectTemplateKind(path) {
+	case cloudformation.KindCloudFormation:
 		return ProjectTypeCloudFormation
+	case cloudformation.KindSAM:
+		return ProjectTypeSAM
+	case cloudformation.KindServerlessFramework:
+		return ProjectTypeServerlessFramework
+	case cloudformation.KindCDK:
+		return ProjectTypeCDK
 	}
 
 	if isTerraformPlanJSON(path) {
 		return ProjectTypeTerraformPlanJSON
 	}
 
+	if pulumi.IsPulumiJSON(path) {
+		return ProjectTypePulumi
+	}
+
+	if arm.IsTemplate(path) {
+		return ProjectTypeARM
+	}
+
+	if kubernetes.IsManifest(path) {
+		return ProjectTypeKubernetes
+	}
+
 	if isTerraformStateJSON(path) {
 		return ProjectTypeTerraformStateJSON
 	}
@@ -312,23 +345,3 @@ func isTerragruntNestedDir(path string, maxDepth int) bool {
 	}
 	return false
 }
-
-// goformation lib is not threadsafe, so we run this check synchronously
-// See: https://github.com/awslabs/goformation/issues/363
-var cfMux = &sync.Mutex{}
-
-func isCloudFormationTemplate(path string) bool {
-	cfMux.Lock()
-	defer cfMux.Unlock()
-
-	template, err := goformation.Open(path)
-	if err != nil {
-		return false
-	}
-
-	if len(template.Resources) > 0 {
-		return true
-	}
-
-	return false
-}
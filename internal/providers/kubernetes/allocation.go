@@ -0,0 +1,325 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// allocation is the number of nodes that the workloads need and the share
+// of the nodes that each workload uses.
+type allocation struct {
+	nodes       int64
+	shares      []float64
+	unallocated float64
+}
+
+// allocate schedules the workloads on the node pool. DaemonSets run a pod on
+// every node, so their requests are subtracted from the capacity of each
+// node. The nodes are then sized to fit the requests of the other workloads.
+// Each workload is allocated the share of the nodes of its dominant
+// resource, i.e. the larger of its share of the CPU and memory, and the
+// share of the nodes that isn't requested by any workload is unallocated.
+func allocate(workloads []workload, p *nodePool) allocation {
+	var daemonRequests, total requests
+	for _, w := range workloads {
+		if w.daemon {
+			daemonRequests = daemonRequests.add(w.requests)
+			continue
+		}
+
+		total = total.add(requests{
+			cpu:    w.requests.cpu * float64(w.replicas),
+			memory: w.requests.memory * float64(w.replicas),
+		})
+	}
+
+	allocatable := requests{
+		cpu:    p.capacity.cpu - daemonRequests.cpu,
+		memory: p.capacity.memory - daemonRequests.memory,
+	}
+	if allocatable.cpu <= 0 || allocatable.memory <= 0 {
+		log.Warn().Msgf("The DaemonSets request more than the capacity of a %s node", p.instanceType)
+		allocatable = requests{cpu: math.Max(allocatable.cpu, 0), memory: math.Max(allocatable.memory, 0)}
+	}
+
+	nodes := p.minNodes
+	if total.cpu > 0 || total.memory > 0 {
+		needed := int64(math.Ceil(math.Max(ratio(total.cpu, allocatable.cpu), ratio(total.memory, allocatable.memory))))
+		if needed > nodes {
+			nodes = needed
+		}
+	}
+	if nodes < 1 && len(workloads) > 0 {
+		nodes = 1
+	}
+
+	a := allocation{
+		nodes:  nodes,
+		shares: make([]float64, len(workloads)),
+	}
+
+	sum := 0.0
+	for i, w := range workloads {
+		share := math.Max(ratio(w.requests.cpu, p.capacity.cpu), ratio(w.requests.memory, p.capacity.memory))
+		if w.daemon {
+			share *= float64(nodes)
+		} else {
+			share *= float64(w.replicas)
+		}
+
+		a.shares[i] = share
+		sum += share
+	}
+
+	// The dominant resources of the workloads can add up to more than the
+	// nodes, e.g. when one workload uses most of the CPU and another most of
+	// the memory, so the shares are scaled down to the number of nodes.
+	if sum > float64(nodes) {
+		for i := range a.shares {
+			a.shares[i] *= float64(nodes) / sum
+		}
+		sum = float64(nodes)
+	}
+
+	a.unallocated = float64(nodes) - sum
+
+	return a
+}
+
+// ratio returns a / b, or +Inf if b is zero and a isn't, so that requests
+// that can't fit on a node are sized as needing more nodes.
+func ratio(a, b float64) float64 {
+	if a == 0 {
+		return 0
+	}
+	if b == 0 {
+		return math.Inf(1)
+	}
+
+	return a / b
+}
+
+// namespaceResources returns a resource for each namespace with the cost of
+// its workloads, volumes and load balancers, and a resource for the cost of
+// the nodes that aren't allocated to any workload.
+func namespaceResources(c *cluster, p *nodePool, usage schema.UsageMap) []*schema.Resource {
+	a := allocate(c.workloads, p)
+
+	byNamespace := map[string][]*schema.Resource{}
+	var namespaces []string
+	add := func(namespace string, r *schema.Resource) {
+		if r == nil {
+			return
+		}
+		if _, ok := byNamespace[namespace]; !ok {
+			namespaces = append(namespaces, namespace)
+		}
+		byNamespace[namespace] = append(byNamespace[namespace], r)
+	}
+
+	for i, w := range c.workloads {
+		if w.requests.cpu == 0 && w.requests.memory == 0 {
+			log.Warn().Msgf("%s in namespace %s has no resource requests, so no cost is allocated to it", w.displayName(), w.namespace)
+		}
+
+		r := p.nodeResource(address(w.namespace, w.kind, w.name))
+		if r == nil {
+			continue
+		}
+		r.Name = w.displayName()
+		schema.MultiplyQuantities(r, decimal.NewFromFloat(a.shares[i]))
+		add(w.namespace, r)
+	}
+
+	for _, v := range c.volumeClaims {
+		add(v.namespace, c.diskResource(v, p, usage))
+	}
+
+	for _, s := range c.services {
+		add(s.namespace, loadBalancerResource(s, p, usage))
+	}
+
+	sort.Strings(namespaces)
+
+	resources := make([]*schema.Resource, 0, len(namespaces)+1)
+	for _, ns := range namespaces {
+		resources = append(resources, &schema.Resource{
+			Name:         fmt.Sprintf("namespace/%s", ns),
+			ResourceType: "kubernetes_namespace",
+			SubResources: byNamespace[ns],
+		})
+	}
+
+	if a.unallocated > 0 {
+		r := p.nodeResource("node_pool")
+		if r != nil {
+			r.Name = "node_pool (unallocated)"
+			schema.MultiplyQuantities(r, decimal.NewFromFloat(a.unallocated))
+			resources = append(resources, r)
+		}
+	}
+
+	return resources
+}
+
+func address(namespace, kind, name string) string {
+	return strings.Join([]string{namespace, kind, name}, "/")
+}
+
+// diskResource returns the resource of the disks that are provisioned for
+// the volume claim.
+func (c *cluster) diskResource(v volumeClaim, p *nodePool, usage schema.UsageMap) *schema.Resource {
+	addr := address(v.namespace, "PersistentVolumeClaim", v.name)
+	size := math.Ceil(v.size / gibibyte)
+
+	className := v.storageClass
+	if className == "" {
+		className = c.defaultStorageClass
+	}
+	sc, found := c.storageClasses[className]
+
+	var r *schema.Resource
+	switch p.resourceType {
+	case eksNodeGroupType:
+		r = buildResource("aws_ebs_volume", "aws", addr, map[string]interface{}{
+			"region": p.region,
+			"size":   size,
+			"type":   awsVolumeType(className, sc, found),
+		}, usage.Get(addr))
+	case aksNodePoolType:
+		r = buildResource("azurerm_managed_disk", "azurerm", addr, map[string]interface{}{
+			"location":             p.region,
+			"disk_size_gb":         size,
+			"storage_account_type": azureDiskType(className, sc),
+		}, usage.Get(addr))
+	case gkeNodePoolType:
+		r = buildResource("google_compute_disk", "google", addr, map[string]interface{}{
+			"zone": p.region + "-a",
+			"size": size,
+			"type": gcpDiskType(className, sc),
+		}, usage.Get(addr))
+	}
+
+	if r == nil {
+		return nil
+	}
+
+	r.Name = fmt.Sprintf("PersistentVolumeClaim/%s", v.name)
+	if v.count > 1 {
+		schema.MultiplyQuantities(r, decimal.NewFromInt(v.count))
+	}
+
+	return r
+}
+
+var awsVolumeTypes = map[string]bool{
+	"gp2": true, "gp3": true, "io1": true, "io2": true, "st1": true, "sc1": true, "standard": true,
+}
+
+// awsVolumeType returns the EBS volume type of the storage class. EKS
+// clusters have a gp2 storage class by default, and the EBS CSI driver
+// creates gp3 volumes when the type isn't set.
+func awsVolumeType(className string, sc storageClass, found bool) string {
+	if t := strings.ToLower(sc.parameters["type"]); t != "" {
+		return t
+	}
+
+	if found {
+		if sc.provisioner == "ebs.csi.aws.com" {
+			return "gp3"
+		}
+		return "gp2"
+	}
+
+	if awsVolumeTypes[className] {
+		return className
+	}
+
+	return "gp2"
+}
+
+// azureDiskType returns the managed disk type of the storage class. The
+// built-in AKS storage classes are mapped to their disk types, and the
+// default storage class uses Standard SSDs.
+func azureDiskType(className string, sc storageClass) string {
+	for _, k := range []string{"skuName", "skuname", "storageaccounttype"} {
+		if t := sc.parameters[k]; t != "" {
+			return t
+		}
+	}
+
+	if strings.Contains(className, "premium") {
+		return "Premium_LRS"
+	}
+
+	return "StandardSSD_LRS"
+}
+
+// gcpDiskType returns the persistent disk type of the storage class. The
+// built-in GKE storage classes are mapped to their disk types, and the
+// standard storage class uses standard persistent disks.
+func gcpDiskType(className string, sc storageClass) string {
+	if t := sc.parameters["type"]; t != "" {
+		return t
+	}
+
+	switch className {
+	case "standard-rwo":
+		return "pd-balanced"
+	case "premium-rwo":
+		return "pd-ssd"
+	}
+
+	return "pd-standard"
+}
+
+// loadBalancerResource returns the resource of the cloud load balancer that
+// is created for the Service.
+func loadBalancerResource(s service, p *nodePool, usage schema.UsageMap) *schema.Resource {
+	addr := address(s.namespace, "Service", s.name)
+
+	var r *schema.Resource
+	switch p.resourceType {
+	case eksNodeGroupType:
+		// The AWS Load Balancer Controller creates an NLB, the in-tree cloud
+		// provider creates a Classic Load Balancer unless an NLB is requested.
+		lbType := strings.ToLower(s.annotations["service.beta.kubernetes.io/aws-load-balancer-type"])
+		if lbType == "nlb" || lbType == "external" || lbType == "nlb-ip" {
+			r = buildResource("aws_lb", "aws", addr, map[string]interface{}{
+				"region":             p.region,
+				"load_balancer_type": "network",
+			}, usage.Get(addr))
+		} else {
+			r = buildResource("aws_elb", "aws", addr, map[string]interface{}{
+				"region": p.region,
+			}, usage.Get(addr))
+		}
+	case aksNodePoolType:
+		// The AKS load balancer is shared by the cluster, so only the public
+		// IP address of the Service is priced.
+		r = buildResource("azurerm_public_ip", "azurerm", addr, map[string]interface{}{
+			"location":          p.region,
+			"sku":               "Standard",
+			"allocation_method": "Static",
+		}, usage.Get(addr))
+	case gkeNodePoolType:
+		r = buildResource("google_compute_forwarding_rule", "google", addr, map[string]interface{}{
+			"region": p.region,
+		}, usage.Get(addr))
+	}
+
+	if r == nil {
+		return nil
+	}
+
+	r.Name = fmt.Sprintf("Service/%s", s.name)
+
+	return r
+}
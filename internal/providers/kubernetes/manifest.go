@@ -0,0 +1,241 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/hcl/modules"
+)
+
+// object is a Kubernetes object from a manifest.
+type object struct {
+	APIVersion  string                 `yaml:"apiVersion"`
+	Kind        string                 `yaml:"kind"`
+	Metadata    objectMetadata         `yaml:"metadata"`
+	Spec        map[string]interface{} `yaml:"spec"`
+	Parameters  map[string]string      `yaml:"parameters"`
+	Provisioner string                 `yaml:"provisioner"`
+	Items       []object               `yaml:"items"`
+}
+
+type objectMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Annotations map[string]string `yaml:"annotations"`
+	Labels      map[string]string `yaml:"labels"`
+}
+
+// namespace returns the namespace of the object. Manifests rendered by
+// helm template don't set the namespace unless --namespace is used, so
+// objects without one are in the default namespace.
+func (o object) namespace() string {
+	if o.Metadata.Namespace == "" {
+		return "default"
+	}
+
+	return o.Metadata.Namespace
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func isHelmChart(path string) bool {
+	info, err := os.Stat(filepath.Join(path, "Chart.yaml"))
+	return err == nil && !info.IsDir()
+}
+
+// IsManifest returns true if the path is a Kubernetes manifest, a directory
+// of manifests or a Helm chart. Directories are only detected when all their
+// YAML files are Kubernetes manifests and they don't contain Terraform files,
+// so that Terraform projects with Kubernetes manifests aren't detected.
+func IsManifest(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if !info.IsDir() {
+		return isYAMLFile(path) && isManifestFile(path)
+	}
+
+	if isHelmChart(path) {
+		return true
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+
+	found := false
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		if modules.IsConfigFile(e.Name()) {
+			return false
+		}
+
+		if !isYAMLFile(e.Name()) {
+			continue
+		}
+
+		if !isManifestFile(filepath.Join(path, e.Name())) {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
+
+func isManifestFile(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	objects, err := parseManifest(b)
+	return err == nil && len(objects) > 0
+}
+
+// parseManifest parses the YAML documents of a manifest. It returns an error
+// if a document isn't a Kubernetes object. List objects are expanded into
+// their items.
+func parseManifest(b []byte) ([]object, error) {
+	var objects []object
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var raw map[string]interface{}
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Empty documents, e.g. from templates that render nothing
+		if len(raw) == 0 {
+			continue
+		}
+
+		if _, ok := raw["apiVersion"].(string); !ok {
+			return nil, errors.New("document is missing apiVersion")
+		}
+		if _, ok := raw["kind"].(string); !ok {
+			return nil, errors.New("document is missing kind")
+		}
+
+		out, err := yaml.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		var o object
+		if err := yaml.Unmarshal(out, &o); err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(o.Kind, "List") && o.Items != nil {
+			objects = append(objects, o.Items...)
+			continue
+		}
+
+		objects = append(objects, o)
+	}
+
+	return objects, nil
+}
+
+// loadManifests returns the objects from the manifest file, the manifests in
+// the directory or the manifests rendered from the Helm chart.
+func loadManifests(path string, releaseName string, valuesFiles []string) ([]object, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return parseManifest(b)
+	}
+
+	if isHelmChart(path) {
+		b, err := renderHelmChart(path, releaseName, valuesFiles)
+		if err != nil {
+			return nil, err
+		}
+		return parseManifest(b)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(path, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	var objects []object
+	for _, m := range matches {
+		if !isYAMLFile(m) {
+			continue
+		}
+
+		b, err := os.ReadFile(m)
+		if err != nil {
+			return nil, err
+		}
+
+		fileObjects, err := parseManifest(b)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", m, err)
+		}
+		objects = append(objects, fileObjects...)
+	}
+
+	return objects, nil
+}
+
+// renderHelmChart renders the chart with helm template. Relative values file
+// paths are relative to the chart directory.
+func renderHelmChart(path string, releaseName string, valuesFiles []string) ([]byte, error) {
+	helm, err := exec.LookPath("helm")
+	if err != nil {
+		return nil, errors.New("Helm charts need the Helm CLI to be installed, alternatively run 'helm template' and use the rendered manifests")
+	}
+
+	args := []string{"template", releaseName, path}
+	for _, f := range valuesFiles {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(path, f)
+		}
+		args = append(args, "--values", f)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(helm, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error rendering Helm chart: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,403 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	eksNodeGroupType = "aws_eks_node_group"
+	aksNodePoolType  = "azurerm_kubernetes_cluster_node_pool"
+	gkeNodePoolType  = "google_container_node_pool"
+)
+
+// nodePoolDefaults are the instance type and region that are used when the
+// node pool config doesn't set them.
+var nodePoolDefaults = map[string]struct {
+	instanceType string
+	region       string
+}{
+	eksNodeGroupType: {"m5.large", "us-east-1"},
+	aksNodePoolType:  {"Standard_DS2_v2", "eastus"},
+	gkeNodePoolType:  {"e2-medium", "us-central1"},
+}
+
+// nodePool is the nodes that the workloads are scheduled on.
+type nodePool struct {
+	resourceType string
+	instanceType string
+	region       string
+	minNodes     int64
+	capacity     requests
+}
+
+func newNodePool(c *config.KubernetesNodePool) (*nodePool, error) {
+	if c == nil {
+		c = &config.KubernetesNodePool{}
+	}
+
+	p := &nodePool{
+		resourceType: c.ResourceType,
+		instanceType: c.InstanceType,
+		region:       c.Region,
+		minNodes:     c.MinNodes,
+	}
+
+	if p.resourceType == "" {
+		log.Warn().Msgf("No Kubernetes node pool is configured, using %s", eksNodeGroupType)
+		p.resourceType = eksNodeGroupType
+	}
+
+	defaults, ok := nodePoolDefaults[p.resourceType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported Kubernetes node pool resource type %s, expected one of %s, %s or %s", p.resourceType, eksNodeGroupType, aksNodePoolType, gkeNodePoolType)
+	}
+
+	if p.instanceType == "" {
+		p.instanceType = defaults.instanceType
+	}
+	if p.region == "" {
+		p.region = defaults.region
+	}
+
+	cpu, memory := instanceCapacity(p.resourceType, p.instanceType)
+
+	if c.CPU != "" {
+		v, err := parseQuantity(c.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node pool CPU: %w", err)
+		}
+		cpu = v
+	}
+
+	if c.Memory != "" {
+		v, err := parseQuantity(c.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node pool memory: %w", err)
+		}
+		memory = v
+	}
+
+	if cpu <= 0 || memory <= 0 {
+		return nil, fmt.Errorf("unable to determine the CPU and memory of %s, set the cpu and memory of the node pool in the config file", p.instanceType)
+	}
+
+	p.capacity = requests{cpu: cpu, memory: memory}
+
+	return p, nil
+}
+
+// nodeResource returns the resource of a single node of the node pool.
+func (p *nodePool) nodeResource(address string) *schema.Resource {
+	var values map[string]interface{}
+	provider := ""
+
+	switch p.resourceType {
+	case eksNodeGroupType:
+		provider = "aws"
+		values = map[string]interface{}{
+			"region":         p.region,
+			"instance_types": []string{p.instanceType},
+			"scaling_config": []map[string]interface{}{{"desired_size": 1}},
+		}
+	case aksNodePoolType:
+		provider = "azurerm"
+		values = map[string]interface{}{
+			"location":   p.region,
+			"vm_size":    p.instanceType,
+			"node_count": 1,
+		}
+	case gkeNodePoolType:
+		provider = "google"
+		values = map[string]interface{}{
+			"location":       p.region,
+			"node_locations": []string{p.region + "-a"},
+			"node_count":     1,
+			"node_config":    []map[string]interface{}{{"machine_type": p.instanceType}},
+		}
+	}
+
+	return buildResource(p.resourceType, provider, address, values, nil)
+}
+
+// terraformNodePool returns the node pool config with the values that aren't
+// set read from the node pool resource in the cluster's Terraform project.
+func terraformNodePool(ctx *config.ProjectContext, c *config.KubernetesNodePool) (*config.KubernetesNodePool, error) {
+	if c == nil || c.TerraformPath == "" {
+		return c, nil
+	}
+
+	data, err := terraform.ProjectResourceData(ctx, c.TerraformPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*schema.ResourceData
+	for _, r := range data {
+		if _, ok := nodePoolDefaults[r.Type]; !ok {
+			continue
+		}
+		if c.Address != "" && r.Address != c.Address {
+			continue
+		}
+		if c.ResourceType != "" && r.Type != c.ResourceType {
+			continue
+		}
+
+		matches = append(matches, r)
+	}
+
+	if len(matches) == 0 {
+		if c.Address != "" {
+			return nil, fmt.Errorf("node pool %s not found in %s", c.Address, c.TerraformPath)
+		}
+		return nil, fmt.Errorf("no %s, %s or %s found in %s", eksNodeGroupType, aksNodePoolType, gkeNodePoolType, c.TerraformPath)
+	}
+
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("found %d node pools in %s, set the address of the node pool to use", len(matches), c.TerraformPath)
+	}
+
+	derived := nodePoolFromResourceData(matches[0])
+
+	merged := *c
+	merged.ResourceType = derived.ResourceType
+	if merged.InstanceType == "" {
+		merged.InstanceType = derived.InstanceType
+	}
+	if merged.Region == "" {
+		merged.Region = derived.Region
+	}
+	if merged.MinNodes == 0 {
+		merged.MinNodes = derived.MinNodes
+	}
+
+	return &merged, nil
+}
+
+// nodePoolFromResourceData returns the node pool config of the Terraform node
+// pool resource, using the same attributes and defaults as its resource
+// builder.
+func nodePoolFromResourceData(d *schema.ResourceData) config.KubernetesNodePool {
+	c := config.KubernetesNodePool{ResourceType: d.Type}
+
+	switch d.Type {
+	case eksNodeGroupType:
+		c.InstanceType = strings.ToLower(d.Get("instance_types.0").String())
+		launchTemplates := append(d.References("launch_template.0.id"), d.References("launch_template.0.name")...)
+		if c.InstanceType == "" && len(launchTemplates) > 0 {
+			c.InstanceType = strings.ToLower(launchTemplates[0].Get("instance_type").String())
+		}
+		if c.InstanceType == "" {
+			c.InstanceType = "t3.medium"
+		}
+		c.Region = d.Get("region").String()
+		c.MinNodes = d.Get("scaling_config.0.min_size").Int()
+	case aksNodePoolType:
+		c.InstanceType = d.Get("vm_size").String()
+		c.Region = referencedLocation(d, "kubernetes_cluster_id")
+		c.MinNodes = d.Get("node_count").Int()
+		if d.Get("min_count").Exists() {
+			c.MinNodes = d.Get("min_count").Int()
+		}
+	case gkeNodePoolType:
+		c.InstanceType = d.Get("node_config.0.machine_type").String()
+		if c.InstanceType == "" {
+			c.InstanceType = "e2-medium"
+		}
+		c.Region = referencedLocation(d, "cluster")
+		// GKE locations can be zones, which are priced as their region.
+		if m := gcpZoneRe.FindStringSubmatch(c.Region); m != nil {
+			c.Region = m[1]
+		}
+		c.MinNodes = d.Get("initial_node_count").Int()
+		if d.Get("node_count").Exists() {
+			c.MinNodes = d.Get("node_count").Int()
+		}
+		if d.Get("autoscaling.0.min_node_count").Exists() {
+			c.MinNodes = d.Get("autoscaling.0.min_node_count").Int()
+		}
+	}
+
+	return c
+}
+
+// referencedLocation returns the location of the resource, or the location
+// of the cluster that it references if it doesn't set one.
+func referencedLocation(d *schema.ResourceData, clusterKey string) string {
+	if l := d.Get("location").String(); l != "" {
+		return l
+	}
+
+	for _, cluster := range d.References(clusterKey) {
+		if l := cluster.Get("location").String(); l != "" {
+			return l
+		}
+	}
+
+	return ""
+}
+
+var (
+	gcpZoneRe     = regexp.MustCompile(`^([a-z]+-[a-z]+\d+)-[a-z]$`)
+	awsFamilyRe   = regexp.MustCompile(`^([a-z]+)(\d+)([a-z]*)\.`)
+	azureSizeRe   = regexp.MustCompile(`(?i)^(?:standard|basic)_([a-z]+)(\d+)`)
+	gcpCustomRe   = regexp.MustCompile(`custom-(\d+)-(\d+)`)
+	gcpStandardRe = regexp.MustCompile(`^([a-z0-9]+)-(standard|highmem|highcpu)-(\d+)$`)
+)
+
+// instanceCapacity estimates the CPU (in cores) and memory (in bytes) of the
+// instance type from its name, since the pricing data doesn't include them.
+// It returns zero values if the instance type isn't known.
+func instanceCapacity(resourceType, instanceType string) (float64, float64) {
+	switch resourceType {
+	case eksNodeGroupType:
+		return awsInstanceCapacity(instanceType)
+	case aksNodePoolType:
+		return azureInstanceCapacity(instanceType)
+	case gkeNodePoolType:
+		return gcpInstanceCapacity(instanceType)
+	}
+
+	return 0, 0
+}
+
+// awsMemoryPerVCPU is the GiB of memory per vCPU of the AWS instance families.
+var awsMemoryPerVCPU = map[string]float64{
+	"c": 2,
+	"m": 4,
+	"r": 8,
+	"x": 16,
+	"z": 8,
+	"i": 8,
+	"d": 8,
+	"g": 4,
+	"p": 8,
+}
+
+func awsInstanceCapacity(instanceType string) (float64, float64) {
+	vcpu, ok := aws.InstanceTypeToVCPU[instanceType]
+	if !ok {
+		return 0, 0
+	}
+
+	m := awsFamilyRe.FindStringSubmatch(instanceType)
+	if m == nil {
+		return 0, 0
+	}
+
+	// Burstable instances have 2 vCPUs up to the large size with memory
+	// doubling for each size, e.g. t3.medium has 4 GiB.
+	if strings.HasPrefix(m[1], "t") {
+		memory := map[string]float64{
+			"nano": 0.5, "micro": 1, "small": 2, "medium": 4, "large": 8, "xlarge": 16, "2xlarge": 32,
+		}[strings.TrimPrefix(instanceType, m[0])]
+		return float64(vcpu), memory * gibibyte
+	}
+
+	ratio, ok := awsMemoryPerVCPU[m[1][:1]]
+	if !ok {
+		return 0, 0
+	}
+
+	return float64(vcpu), float64(vcpu) * ratio * gibibyte
+}
+
+// azureMemoryPerVCPU is the GiB of memory per vCPU of the Azure VM series.
+var azureMemoryPerVCPU = map[string]float64{
+	"a":  2,
+	"b":  4,
+	"d":  4,
+	"ds": 3.5,
+	"e":  8,
+	"f":  2,
+	"fs": 2,
+	"l":  8,
+	"m":  28,
+}
+
+func azureInstanceCapacity(instanceType string) (float64, float64) {
+	m := azureSizeRe.FindStringSubmatch(instanceType)
+	if m == nil {
+		return 0, 0
+	}
+
+	series := strings.ToLower(m[1])
+	vcpu, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return 0, 0
+	}
+
+	// DS2_v2 is the older generation with 3.5 GiB per vCPU, D2s_v3 and newer
+	// have 4 GiB per vCPU.
+	ratio, ok := azureMemoryPerVCPU[series]
+	if !ok || (series == "ds" && !strings.HasSuffix(strings.ToLower(instanceType), "_v2")) {
+		ratio, ok = azureMemoryPerVCPU[series[:1]]
+	}
+	if !ok {
+		return 0, 0
+	}
+
+	return vcpu, vcpu * ratio * gibibyte
+}
+
+// gcpSharedCoreCapacity is the CPU and GiB of memory of the shared core
+// machine types.
+var gcpSharedCoreCapacity = map[string][2]float64{
+	"e2-micro":  {2, 1},
+	"e2-small":  {2, 2},
+	"e2-medium": {2, 4},
+	"f1-micro":  {1, 0.6},
+	"g1-small":  {1, 1.7},
+}
+
+func gcpInstanceCapacity(instanceType string) (float64, float64) {
+	if c, ok := gcpSharedCoreCapacity[instanceType]; ok {
+		return c[0], c[1] * gibibyte
+	}
+
+	if m := gcpCustomRe.FindStringSubmatch(instanceType); m != nil {
+		vcpu, _ := strconv.ParseFloat(m[1], 64)
+		memoryMiB, _ := strconv.ParseFloat(m[2], 64)
+		return vcpu, memoryMiB * (1 << 20)
+	}
+
+	m := gcpStandardRe.FindStringSubmatch(instanceType)
+	if m == nil {
+		return 0, 0
+	}
+
+	vcpu, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return 0, 0
+	}
+
+	ratio := map[string]float64{"standard": 4, "highmem": 8, "highcpu": 1}[m[2]]
+	if m[1] == "n1" {
+		ratio = map[string]float64{"standard": 3.75, "highmem": 6.5, "highcpu": 0.9}[m[2]]
+	}
+
+	return vcpu, vcpu * ratio * gibibyte
+}
+
+// buildResource builds the resource from the values using the Terraform
+// resource registry, so the pricing matches the equivalent Terraform
+// resource.
+func buildResource(resourceType, provider, address string, values map[string]interface{}, u *schema.UsageData) *schema.Resource {
+	b, _ := json.Marshal(values)
+	d := schema.NewResourceData(resourceType, provider, address, nil, gjson.ParseBytes(b))
+	d.UsageData = u
+
+	return terraform.BuildResourceFromData(d)
+}
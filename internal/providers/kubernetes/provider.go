@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type ManifestProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewManifestProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &ManifestProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *ManifestProvider) Type() string {
+	return "kubernetes"
+}
+
+func (p *ManifestProvider) DisplayType() string {
+	return "Kubernetes"
+}
+
+func (p *ManifestProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *ManifestProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	poolConfig, err := terraformNodePool(p.ctx, p.ctx.ProjectConfig.KubernetesNodePool)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading Kubernetes node pool from Terraform")
+	}
+
+	pool, err := newNodePool(poolConfig)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error configuring Kubernetes node pool")
+	}
+
+	objects, err := loadManifests(p.Path, releaseName(p.Path), p.ctx.ProjectConfig.HelmValuesFiles)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading Kubernetes manifests")
+	}
+
+	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+
+	// The manifests don't have a previous state, so the past resources are
+	// the same as the current ones, as for CloudFormation templates.
+	partials := partialResources(namespaceResources(newCluster(objects), pool, usage))
+	if p.includePastResources {
+		project.PartialPastResources = partials
+	}
+	project.PartialResources = partials
+
+	return []*schema.Project{project}, nil
+}
+
+// partialResources wraps the resources that are built from the manifests so
+// that they're added to the project when it builds its resources.
+func partialResources(resources []*schema.Resource) []*schema.PartialResource {
+	partials := make([]*schema.PartialResource, 0, len(resources))
+	for _, r := range resources {
+		d := schema.NewResourceData(r.ResourceType, "kubernetes", r.Name, nil, gjson.Result{})
+		partials = append(partials, schema.NewPartialResource(d, r, nil, nil))
+	}
+
+	return partials
+}
+
+// releaseName returns the release name used to render a Helm chart, which is
+// the name of the chart directory.
+func releaseName(path string) string {
+	return filepath.Base(filepath.Clean(path))
+}
@@ -0,0 +1,198 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestIsManifest(t *testing.T) {
+	assert.True(t, IsManifest("testdata/manifests"))
+	assert.True(t, IsManifest("testdata/manifests/app.yaml"))
+	assert.True(t, IsManifest("testdata/chart"))
+	assert.False(t, IsManifest("testdata/not_manifest.yaml"))
+	assert.False(t, IsManifest("testdata"))
+}
+
+func TestNewCluster(t *testing.T) {
+	objects, err := loadManifests("testdata/manifests", "manifests", nil)
+	require.NoError(t, err)
+
+	c := newCluster(objects)
+
+	workloads := map[string]workload{}
+	for _, w := range c.workloads {
+		workloads[w.displayName()] = w
+	}
+
+	web := workloads["Deployment/web"]
+	assert.Equal(t, int64(2), web.replicas)
+	assert.InDelta(t, 1.5, web.requests.cpu, 1e-9)
+	assert.InDelta(t, 1.25*gibibyte, web.requests.memory, 1)
+
+	assert.Equal(t, int64(2), workloads["StatefulSet/postgres"].replicas)
+	assert.True(t, workloads["DaemonSet/node-exporter"].daemon)
+	assert.NotContains(t, workloads, "CronJob/report")
+
+	require.Len(t, c.volumeClaims, 2)
+	assert.Equal(t, volumeClaim{name: "data-postgres", namespace: "shop", storageClass: "fast", size: 100 * gibibyte, count: 2}, c.volumeClaims[0])
+	require.Len(t, c.services, 1)
+}
+
+func TestAllocate(t *testing.T) {
+	p := &nodePool{capacity: requests{cpu: 2, memory: 8 * gibibyte}}
+	workloads := []workload{
+		{kind: "DaemonSet", daemon: true, requests: requests{cpu: 0.5}},
+		{kind: "Deployment", replicas: 4, requests: requests{cpu: 0.5, memory: gibibyte}},
+	}
+
+	a := allocate(workloads, p)
+	assert.Equal(t, int64(2), a.nodes)
+	assert.InDelta(t, 0.5, a.shares[0], 1e-9)
+	assert.InDelta(t, 1, a.shares[1], 1e-9)
+	assert.InDelta(t, 0.5, a.unallocated, 1e-9)
+}
+
+func TestInstanceCapacity(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		instanceType string
+		cpu          float64
+		memoryGiB    float64
+	}{
+		{eksNodeGroupType, "m5.large", 2, 8},
+		{eksNodeGroupType, "t3.medium", 2, 4},
+		{eksNodeGroupType, "r5.xlarge", 4, 32},
+		{aksNodePoolType, "Standard_DS2_v2", 2, 7},
+		{aksNodePoolType, "Standard_D4s_v3", 4, 16},
+		{aksNodePoolType, "Standard_E8s_v5", 8, 64},
+		{gkeNodePoolType, "e2-medium", 2, 4},
+		{gkeNodePoolType, "n2-standard-4", 4, 16},
+		{gkeNodePoolType, "n1-standard-2", 2, 7.5},
+		{gkeNodePoolType, "n2-custom-4-10240", 4, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			cpu, memory := instanceCapacity(tt.resourceType, tt.instanceType)
+			assert.Equal(t, tt.cpu, cpu)
+			assert.InDelta(t, tt.memoryGiB*gibibyte, memory, 1)
+		})
+	}
+}
+
+func TestManifestProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: "testdata/manifests",
+		KubernetesNodePool: &config.KubernetesNodePool{
+			ResourceType: "aws_eks_node_group",
+			InstanceType: "m5.xlarge",
+			Region:       "eu-west-1",
+		},
+	}, nil)
+
+	projects, err := NewManifestProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "kubernetes", projects[0].Metadata.Type)
+	assert.Empty(t, projects[0].PartialPastResources)
+
+	schema.BuildResources(projects, nil)
+
+	byName := map[string]*schema.Resource{}
+	for _, r := range projects[0].Resources {
+		byName[r.Name] = r
+	}
+
+	shop := byName["namespace/shop"]
+	require.NotNil(t, shop)
+
+	subResources := []string{}
+	for _, s := range shop.SubResources {
+		subResources = append(subResources, s.Name)
+	}
+	assert.Equal(t, []string{"Deployment/web", "StatefulSet/postgres", "PersistentVolumeClaim/data-postgres", "Service/web"}, subResources)
+
+	web := shop.SubResources[0]
+	assert.Equal(t, "eu-west-1", *web.CostComponents[0].ProductFilter.Region)
+	// 2 replicas requesting 1.5 of the 4 vCPUs of an m5.xlarge
+	assert.Equal(t, "547.5", web.CostComponents[0].MonthlyQuantity.String())
+
+	disk := shop.SubResources[2]
+	assert.Equal(t, "200", disk.CostComponents[0].MonthlyQuantity.String())
+
+	require.NotNil(t, byName["namespace/monitoring"])
+	require.NotNil(t, byName["node_pool (unallocated)"])
+}
+
+func TestManifestProviderPastResources(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:               "testdata/manifests",
+		KubernetesNodePool: &config.KubernetesNodePool{ResourceType: "aws_eks_node_group"},
+	}, nil)
+
+	projects, err := NewManifestProvider(ctx, true).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	schema.BuildResources(projects, nil)
+
+	names := func(resources []*schema.Resource) []string {
+		var n []string
+		for _, r := range resources {
+			n = append(n, r.Name)
+		}
+		return n
+	}
+
+	assert.NotEmpty(t, projects[0].Resources)
+	assert.Equal(t, names(projects[0].Resources), names(projects[0].PastResources))
+}
+
+func TestTerraformNodePool(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/manifests"}, nil)
+
+	c, err := terraformNodePool(ctx, &config.KubernetesNodePool{
+		TerraformPath: "testdata/eks_cluster",
+		Address:       "aws_eks_node_group.workers",
+		Region:        "eu-west-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &config.KubernetesNodePool{
+		TerraformPath: "testdata/eks_cluster",
+		Address:       "aws_eks_node_group.workers",
+		ResourceType:  "aws_eks_node_group",
+		InstanceType:  "m5.xlarge",
+		Region:        "eu-west-1",
+		MinNodes:      2,
+	}, c)
+
+	c, err = terraformNodePool(ctx, &config.KubernetesNodePool{TerraformPath: "testdata/eks_cluster", Address: "aws_eks_node_group.system"})
+	require.NoError(t, err)
+	assert.Equal(t, "t3.large", c.InstanceType)
+	assert.Equal(t, "eu-west-2", c.Region)
+
+	_, err = terraformNodePool(ctx, &config.KubernetesNodePool{TerraformPath: "testdata/eks_cluster"})
+	assert.ErrorContains(t, err, "found 2 node pools")
+
+	_, err = terraformNodePool(ctx, &config.KubernetesNodePool{TerraformPath: "testdata/eks_cluster", Address: "aws_eks_node_group.missing"})
+	assert.Error(t, err)
+}
+
+func TestNodePoolFromResourceData(t *testing.T) {
+	aksCluster := schema.NewResourceData("azurerm_kubernetes_cluster", "azurerm", "azurerm_kubernetes_cluster.main", nil, gjson.Parse(`{"location": "westeurope"}`))
+	aks := schema.NewResourceData(aksNodePoolType, "azurerm", "azurerm_kubernetes_cluster_node_pool.workers", nil, gjson.Parse(`{"vm_size": "Standard_D4s_v3", "node_count": 2, "min_count": 1}`))
+	aks.ReferencesMap["kubernetes_cluster_id"] = []*schema.ResourceData{aksCluster}
+
+	gkeCluster := schema.NewResourceData("google_container_cluster", "google", "google_container_cluster.main", nil, gjson.Parse(`{"location": "europe-west1-b"}`))
+	gke := schema.NewResourceData(gkeNodePoolType, "google", "google_container_node_pool.workers", nil, gjson.Parse(`{"node_count": 3, "node_config": [{"machine_type": "n2-standard-4"}]}`))
+	gke.ReferencesMap["cluster"] = []*schema.ResourceData{gkeCluster}
+
+	assert.Equal(t, config.KubernetesNodePool{ResourceType: aksNodePoolType, InstanceType: "Standard_D4s_v3", Region: "westeurope", MinNodes: 1}, nodePoolFromResourceData(aks))
+	assert.Equal(t, config.KubernetesNodePool{ResourceType: gkeNodePoolType, InstanceType: "n2-standard-4", Region: "europe-west1", MinNodes: 3}, nodePoolFromResourceData(gke))
+}
@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var quantityRe = regexp.MustCompile(`^([+-]?[0-9.]+(?:[eE][+-]?[0-9]+)?)([a-zA-Z]*)$`)
+
+var quantitySuffixes = map[string]float64{
+	"":   1,
+	"n":  1e-9,
+	"u":  1e-6,
+	"m":  1e-3,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// parseQuantity parses a Kubernetes resource quantity, e.g. 500m CPU or 1Gi
+// of memory, and returns its value in the base unit, i.e. cores or bytes.
+// See https://kubernetes.io/docs/reference/kubernetes-api/common-definitions/quantity/
+func parseQuantity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	m := quantityRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	multiplier, ok := quantitySuffixes[m[2]]
+	if !ok {
+		return 0, fmt.Errorf("invalid quantity suffix %q", m[2])
+	}
+
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	return v * multiplier, nil
+}
+
+const gibibyte = 1 << 30
@@ -0,0 +1,37 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"", 0},
+		{"2", 2},
+		{"0.5", 0.5},
+		{"250m", 0.25},
+		{"100000u", 0.1},
+		{"1Ki", 1024},
+		{"1Gi", 1 << 30},
+		{"1.5Gi", 1.5 * (1 << 30)},
+		{"128M", 128e6},
+		{"1e3", 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := parseQuantity(tt.input)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, actual, 1e-9)
+		})
+	}
+
+	_, err := parseQuantity("1Gb")
+	assert.Error(t, err)
+}
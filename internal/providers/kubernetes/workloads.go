@@ -0,0 +1,304 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// requests are the CPU (in cores) and memory (in bytes) requested by a pod.
+type requests struct {
+	cpu    float64
+	memory float64
+}
+
+func (r requests) add(o requests) requests {
+	return requests{cpu: r.cpu + o.cpu, memory: r.memory + o.memory}
+}
+
+func (r requests) max(o requests) requests {
+	if o.cpu > r.cpu {
+		r.cpu = o.cpu
+	}
+	if o.memory > r.memory {
+		r.memory = o.memory
+	}
+	return r
+}
+
+// workload is a set of pods created by a Deployment, StatefulSet, DaemonSet
+// or other controller.
+type workload struct {
+	kind      string
+	name      string
+	namespace string
+	replicas  int64
+	daemon    bool
+	requests  requests
+}
+
+func (w workload) displayName() string {
+	return fmt.Sprintf("%s/%s", w.kind, w.name)
+}
+
+// volumeClaim is a PersistentVolumeClaim. StatefulSets create a claim for
+// each replica from their volumeClaimTemplates, so count is the number of
+// replicas.
+type volumeClaim struct {
+	name         string
+	namespace    string
+	storageClass string
+	size         float64
+	count        int64
+}
+
+// service is a Service of type LoadBalancer.
+type service struct {
+	name        string
+	namespace   string
+	annotations map[string]string
+}
+
+// storageClass is a StorageClass, which determines the disk type of the
+// volumes that are provisioned for claims.
+type storageClass struct {
+	provisioner string
+	parameters  map[string]string
+}
+
+// cluster is the objects of the manifests that affect the cost.
+type cluster struct {
+	workloads           []workload
+	volumeClaims        []volumeClaim
+	services            []service
+	storageClasses      map[string]storageClass
+	defaultStorageClass string
+}
+
+var replicatedKinds = map[string]bool{
+	"Deployment":            true,
+	"StatefulSet":           true,
+	"ReplicaSet":            true,
+	"ReplicationController": true,
+	// Argo Rollouts
+	"Rollout": true,
+}
+
+// newCluster collects the workloads, volume claims, load balancers and
+// storage classes from the objects. Jobs and CronJobs are skipped since
+// their pods only run for part of the month.
+func newCluster(objects []object) *cluster {
+	c := &cluster{
+		storageClasses: map[string]storageClass{},
+	}
+
+	hpaMinReplicas := horizontalPodAutoscalerMinReplicas(objects)
+
+	for _, o := range objects {
+		switch {
+		case replicatedKinds[o.Kind]:
+			replicas := int64(1)
+			if v, ok := o.Spec["replicas"]; ok {
+				replicas = toInt64(v)
+			}
+
+			key := scaleTargetKey(o.namespace(), o.Kind, o.Metadata.Name)
+			if v, ok := hpaMinReplicas[key]; ok {
+				replicas = v
+			}
+
+			c.addWorkload(o, getMap(o.Spec, "template", "spec"), replicas, false)
+
+			if o.Kind == "StatefulSet" {
+				for _, t := range getSlice(o.Spec, "volumeClaimTemplates") {
+					tm, _ := t.(map[string]interface{})
+					name := getString(tm, "metadata", "name")
+					c.addVolumeClaim(fmt.Sprintf("%s-%s", name, o.Metadata.Name), o.namespace(), getMap(tm, "spec"), replicas)
+				}
+			}
+		case o.Kind == "DaemonSet":
+			c.addWorkload(o, getMap(o.Spec, "template", "spec"), 0, true)
+		case o.Kind == "Pod":
+			c.addWorkload(o, o.Spec, 1, false)
+		case o.Kind == "PersistentVolumeClaim":
+			c.addVolumeClaim(o.Metadata.Name, o.namespace(), o.Spec, 1)
+		case o.Kind == "Service":
+			if getString(o.Spec, "type") == "LoadBalancer" {
+				c.services = append(c.services, service{
+					name:        o.Metadata.Name,
+					namespace:   o.namespace(),
+					annotations: o.Metadata.Annotations,
+				})
+			}
+		case o.Kind == "StorageClass":
+			c.storageClasses[o.Metadata.Name] = storageClass{
+				provisioner: o.Provisioner,
+				parameters:  o.Parameters,
+			}
+			if o.Metadata.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+				c.defaultStorageClass = o.Metadata.Name
+			}
+		case o.Kind == "Job" || o.Kind == "CronJob":
+			log.Debug().Msgf("Skipping %s %s as it doesn't run continuously", o.Kind, o.Metadata.Name)
+		}
+	}
+
+	sort.SliceStable(c.workloads, func(i, j int) bool {
+		return c.workloads[i].namespace < c.workloads[j].namespace
+	})
+
+	return c
+}
+
+func (c *cluster) addWorkload(o object, podSpec map[string]interface{}, replicas int64, daemon bool) {
+	c.workloads = append(c.workloads, workload{
+		kind:      o.Kind,
+		name:      o.Metadata.Name,
+		namespace: o.namespace(),
+		replicas:  replicas,
+		daemon:    daemon,
+		requests:  podRequests(fmt.Sprintf("%s %s", o.Kind, o.Metadata.Name), podSpec),
+	})
+}
+
+func (c *cluster) addVolumeClaim(name, namespace string, spec map[string]interface{}, count int64) {
+	size, err := parseQuantity(getString(spec, "resources", "requests", "storage"))
+	if err != nil {
+		log.Warn().Msgf("Invalid storage request for PersistentVolumeClaim %s: %s", name, err)
+	}
+
+	sc := getString(spec, "storageClassName")
+
+	c.volumeClaims = append(c.volumeClaims, volumeClaim{
+		name:         name,
+		namespace:    namespace,
+		storageClass: sc,
+		size:         size,
+		count:        count,
+	})
+}
+
+// podRequests returns the resources requested by the pod. The scheduler
+// reserves the larger of the sum of the containers and the largest init
+// container, since init containers run before the other containers start.
+// Containers without requests use their limits, matching the defaults that
+// Kubernetes applies.
+func podRequests(name string, spec map[string]interface{}) requests {
+	var total requests
+	for _, c := range getSlice(spec, "containers") {
+		total = total.add(containerRequests(name, c))
+	}
+
+	var init requests
+	for _, c := range getSlice(spec, "initContainers") {
+		init = init.max(containerRequests(name, c))
+	}
+
+	return total.max(init)
+}
+
+func containerRequests(name string, c interface{}) requests {
+	cm, _ := c.(map[string]interface{})
+
+	var r requests
+	for _, key := range []string{"limits", "requests"} {
+		if cpu := getString(cm, "resources", key, "cpu"); cpu != "" {
+			v, err := parseQuantity(cpu)
+			if err != nil {
+				log.Warn().Msgf("Invalid CPU %s for %s: %s", key, name, err)
+			}
+			r.cpu = v
+		}
+
+		if memory := getString(cm, "resources", key, "memory"); memory != "" {
+			v, err := parseQuantity(memory)
+			if err != nil {
+				log.Warn().Msgf("Invalid memory %s for %s: %s", key, name, err)
+			}
+			r.memory = v
+		}
+	}
+
+	return r
+}
+
+// horizontalPodAutoscalerMinReplicas returns the minimum replicas of the
+// autoscaled workloads, keyed by their namespace, kind and name.
+func horizontalPodAutoscalerMinReplicas(objects []object) map[string]int64 {
+	m := map[string]int64{}
+
+	for _, o := range objects {
+		if o.Kind != "HorizontalPodAutoscaler" {
+			continue
+		}
+
+		kind := getString(o.Spec, "scaleTargetRef", "kind")
+		name := getString(o.Spec, "scaleTargetRef", "name")
+
+		minReplicas := int64(1)
+		if v, ok := o.Spec["minReplicas"]; ok {
+			minReplicas = toInt64(v)
+		}
+
+		m[scaleTargetKey(o.namespace(), kind, name)] = minReplicas
+	}
+
+	return m
+}
+
+func scaleTargetKey(namespace, kind, name string) string {
+	return strings.Join([]string{namespace, kind, name}, "/")
+}
+
+func getValue(m map[string]interface{}, path ...string) interface{} {
+	var v interface{} = m
+	for _, p := range path {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = vm[p]
+	}
+
+	return v
+}
+
+func getMap(m map[string]interface{}, path ...string) map[string]interface{} {
+	v, _ := getValue(m, path...).(map[string]interface{})
+	return v
+}
+
+func getSlice(m map[string]interface{}, path ...string) []interface{} {
+	v, _ := getValue(m, path...).([]interface{})
+	return v
+}
+
+// getString returns the value as a string. Quantities can be written as
+// numbers in YAML, e.g. cpu: 2, so numbers are converted.
+func getString(m map[string]interface{}, path ...string) string {
+	switch t := getValue(m, path...).(type) {
+	case string:
+		return t
+	case int:
+		return fmt.Sprintf("%d", t)
+	case float64:
+		return fmt.Sprintf("%g", t)
+	}
+
+	return ""
+}
+
+func toInt64(v interface{}) int64 {
+	switch t := v.(type) {
+	case int:
+		return int64(t)
+	case int64:
+		return t
+	case float64:
+		return int64(t)
+	}
+
+	return 0
+}
@@ -0,0 +1,151 @@
+package pulumi
+
+import (
+	"strings"
+)
+
+// azureNativeConverter converts the inputs of an azure-native resource to the
+// values of the equivalent azurerm Terraform resource. It returns the
+// Terraform resource type and values.
+type azureNativeConverter func(props map[string]interface{}) (string, map[string]interface{})
+
+// azureNativeConverters are the azure-native resources that can be priced.
+// The azure-native provider is generated from the Azure Resource Manager API
+// rather than bridged from the azurerm Terraform provider, so the resource
+// inputs have to be converted explicitly.
+var azureNativeConverters = map[string]azureNativeConverter{
+	"azure-native:compute:Disk":                    convertAzureNativeDisk,
+	"azure-native:compute:VirtualMachine":          convertAzureNativeVirtualMachine,
+	"azure-native:containerservice:ManagedCluster": convertAzureNativeManagedCluster,
+	"azure-native:network:PublicIPAddress":         convertAzureNativePublicIP,
+	"azure-native:resources:ResourceGroup":         convertAzureNativeResourceGroup,
+	"azure-native:storage:StorageAccount":          convertAzureNativeStorageAccount,
+	"azure-native:web:AppServicePlan":              convertAzureNativeAppServicePlan,
+}
+
+// convertAzureNative returns the Terraform resource type and values for an
+// azure-native resource, or false if the resource type isn't supported.
+func convertAzureNative(token string, props map[string]interface{}) (string, map[string]interface{}, bool) {
+	pkg, module, name, ok := parseToken(token)
+	if !ok {
+		return "", nil, false
+	}
+
+	convert, ok := azureNativeConverters[pkg+":"+module+":"+name]
+	if !ok {
+		return "", nil, false
+	}
+
+	resourceType, values := convert(props)
+	values["location"] = props["location"]
+	if tags, ok := props["tags"]; ok {
+		values["tags"] = tags
+	}
+
+	return resourceType, values, true
+}
+
+func convertAzureNativeResourceGroup(props map[string]interface{}) (string, map[string]interface{}) {
+	return "azurerm_resource_group", map[string]interface{}{
+		"name": props["resourceGroupName"],
+	}
+}
+
+func convertAzureNativeVirtualMachine(props map[string]interface{}) (string, map[string]interface{}) {
+	osDisk := nestedMap(props, "storageProfile", "osDisk")
+
+	resourceType := "azurerm_linux_virtual_machine"
+	if len(nestedMap(props, "osProfile", "windowsConfiguration")) > 0 || strings.EqualFold(stringValue(osDisk["osType"]), "Windows") {
+		resourceType = "azurerm_windows_virtual_machine"
+	}
+
+	return resourceType, map[string]interface{}{
+		"size": nestedMap(props, "hardwareProfile")["vmSize"],
+		"os_disk": []interface{}{
+			map[string]interface{}{
+				"disk_size_gb":         osDisk["diskSizeGB"],
+				"storage_account_type": nestedMap(osDisk, "managedDisk")["storageAccountType"],
+			},
+		},
+	}
+}
+
+func convertAzureNativeDisk(props map[string]interface{}) (string, map[string]interface{}) {
+	return "azurerm_managed_disk", map[string]interface{}{
+		"disk_size_gb":         props["diskSizeGB"],
+		"disk_iops_read_write": props["diskIOPSReadWrite"],
+		"disk_mbps_read_write": props["diskMBpsReadWrite"],
+		"storage_account_type": nestedMap(props, "sku")["name"],
+	}
+}
+
+func convertAzureNativeStorageAccount(props map[string]interface{}) (string, map[string]interface{}) {
+	// The SKU name combines the tier and replication type, e.g. Standard_LRS
+	tier, replication, _ := strings.Cut(stringValue(nestedMap(props, "sku")["name"]), "_")
+
+	return "azurerm_storage_account", map[string]interface{}{
+		"access_tier":              props["accessTier"],
+		"account_kind":             props["kind"],
+		"account_replication_type": replication,
+		"account_tier":             tier,
+	}
+}
+
+func convertAzureNativeAppServicePlan(props map[string]interface{}) (string, map[string]interface{}) {
+	osType := "Windows"
+	if reserved, _ := props["reserved"].(bool); reserved || strings.Contains(strings.ToLower(stringValue(props["kind"])), "linux") {
+		osType = "Linux"
+	}
+
+	return "azurerm_service_plan", map[string]interface{}{
+		"os_type":      osType,
+		"sku_name":     nestedMap(props, "sku")["name"],
+		"worker_count": nestedMap(props, "sku")["capacity"],
+	}
+}
+
+func convertAzureNativeManagedCluster(props map[string]interface{}) (string, map[string]interface{}) {
+	values := map[string]interface{}{
+		"sku_tier": nestedMap(props, "sku")["tier"],
+	}
+
+	if pools, ok := props["agentPoolProfiles"].([]interface{}); ok && len(pools) > 0 {
+		pool, _ := pools[0].(map[string]interface{})
+		values["default_node_pool"] = []interface{}{
+			map[string]interface{}{
+				"name":            pool["name"],
+				"node_count":      pool["count"],
+				"os_disk_size_gb": pool["osDiskSizeGB"],
+				"vm_size":         pool["vmSize"],
+			},
+		}
+	}
+
+	return "azurerm_kubernetes_cluster", values
+}
+
+func convertAzureNativePublicIP(props map[string]interface{}) (string, map[string]interface{}) {
+	return "azurerm_public_ip", map[string]interface{}{
+		"allocation_method": props["publicIPAllocationMethod"],
+		"sku":               nestedMap(props, "sku")["name"],
+	}
+}
+
+// nestedMap returns the object at the path of keys, or nil if it doesn't
+// exist.
+func nestedMap(m map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, k := range keys {
+		v, ok := m[k].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = v
+	}
+
+	return m
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,66 @@
+package pulumi
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Provider loads resources from the output of pulumi preview --json or
+// pulumi stack export. The resources are converted to a Terraform plan JSON
+// and parsed with the Terraform plan JSON provider.
+type Provider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &Provider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *Provider) Type() string {
+	return "pulumi"
+}
+
+func (p *Provider) DisplayType() string {
+	return "Pulumi"
+}
+
+func (p *Provider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *Provider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error reading Pulumi JSON file %w", err)
+	}
+
+	d, err := parseDeployment(b)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error parsing Pulumi JSON file %w", err)
+	}
+
+	j, err := d.planJSON()
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error converting Pulumi resources %w", err)
+	}
+
+	project, err := terraform.NewPlanJSONProvider(p.ctx, p.includePastResources).LoadResourcesFromSrc(usage, j, nil)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	return []*schema.Project{project}, nil
+}
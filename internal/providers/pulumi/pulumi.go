@@ -0,0 +1,573 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+const (
+	// unknownValue is used by Pulumi for values that aren't known until the
+	// resource is created.
+	unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+
+	// signatureKey marks objects that are special Pulumi values, such as
+	// secrets, assets and resource references.
+	signatureKey    = "4dabf18193072939515e22adb298388d"
+	secretSignature = "1b47061264138c4ac30d75fd1eb44270"
+
+	providerTypePrefix = "pulumi:providers:"
+)
+
+// mapAttributes are the Terraform attributes that are maps rather than
+// nested blocks, so their keys are kept as they are.
+var mapAttributes = map[string]bool{
+	"default_labels":        true,
+	"effective_labels":      true,
+	"environment_variables": true,
+	"labels":                true,
+	"metadata":              true,
+	"resource_labels":       true,
+	"tags":                  true,
+	"tags_all":              true,
+	"terraform_labels":      true,
+	"user_labels":           true,
+	"variables":             true,
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+type resourceState struct {
+	URN                  string                 `json:"urn"`
+	Custom               bool                   `json:"custom"`
+	Delete               bool                   `json:"delete"`
+	Type                 string                 `json:"type"`
+	Inputs               map[string]interface{} `json:"inputs"`
+	Outputs              map[string]interface{} `json:"outputs"`
+	Provider             string                 `json:"provider"`
+	PropertyDependencies map[string][]string    `json:"propertyDependencies"`
+}
+
+type step struct {
+	Op       string         `json:"op"`
+	URN      string         `json:"urn"`
+	OldState *resourceState `json:"oldState"`
+	NewState *resourceState `json:"newState"`
+}
+
+// pulumiJSON is the output of pulumi preview --json or pulumi stack export.
+type pulumiJSON struct {
+	Config     map[string]interface{} `json:"config"`
+	Steps      []step                 `json:"steps"`
+	Deployment *struct {
+		Resources []resourceState `json:"resources"`
+	} `json:"deployment"`
+}
+
+// deployment is the past and current resources of a Pulumi stack.
+type deployment struct {
+	config  map[string]string
+	past    []*resourceState
+	current []*resourceState
+	// changed are the URNs of the resources that are changed by a preview.
+	changed []string
+}
+
+// IsPulumiJSON returns true if path is the output of pulumi preview --json or
+// pulumi stack export.
+func IsPulumiJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	_, err = parseDeployment(b)
+	return err == nil
+}
+
+func parseDeployment(b []byte) (*deployment, error) {
+	var j pulumiJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return nil, err
+	}
+
+	d := &deployment{
+		config: map[string]string{},
+	}
+	for k, v := range j.Config {
+		if s, ok := v.(string); ok {
+			d.config[k] = s
+		}
+	}
+
+	switch {
+	case len(j.Steps) > 0:
+		for _, s := range j.Steps {
+			if !strings.HasPrefix(s.URN, "urn:pulumi:") {
+				return nil, errors.New("invalid Pulumi preview step")
+			}
+
+			if s.NewState != nil && isCurrentOp(s.Op) {
+				d.current = append(d.current, s.NewState)
+			}
+
+			if s.OldState != nil && isPastOp(s.Op) {
+				d.past = append(d.past, s.OldState)
+			}
+
+			if s.Op != "same" && s.Op != "read" {
+				d.changed = append(d.changed, s.URN)
+			}
+		}
+	case j.Deployment != nil && len(j.Deployment.Resources) > 0:
+		for i := range j.Deployment.Resources {
+			r := &j.Deployment.Resources[i]
+			if !strings.HasPrefix(r.URN, "urn:pulumi:") {
+				return nil, errors.New("invalid Pulumi stack resource")
+			}
+
+			// Resources pending deletion have been replaced by another resource
+			if !r.Delete {
+				d.current = append(d.current, r)
+			}
+		}
+
+		// A stack export is the deployed state, so nothing changes
+		d.past = d.current
+	default:
+		return nil, errors.New("expected the output of pulumi preview --json or pulumi stack export")
+	}
+
+	return d, nil
+}
+
+func isCurrentOp(op string) bool {
+	switch op {
+	case "delete", "delete-replaced", "discard", "discard-replaced", "read", "read-replacement", "read-discard":
+		return false
+	}
+
+	return true
+}
+
+func isPastOp(op string) bool {
+	switch op {
+	case "create", "create-replacement", "import", "import-replacement", "read", "read-replacement":
+		return false
+	}
+
+	return true
+}
+
+// planJSON converts the deployment to a Terraform plan JSON, so it can be
+// parsed by the Terraform provider. Resources of bridged providers are mapped
+// to the Terraform resource they are bridged from, and resources of other
+// providers are left out.
+func (d *deployment) planJSON() ([]byte, error) {
+	c := &planConverter{
+		deployment: d,
+		providers:  map[string]*resourceState{},
+		addresses:  map[string]string{},
+		used:       map[string]bool{},
+	}
+
+	return c.convert()
+}
+
+type planConverter struct {
+	*deployment
+	providers map[string]*resourceState
+	addresses map[string]string
+	used      map[string]bool
+}
+
+type convertedResource struct {
+	state        *resourceState
+	address      string
+	resourceType string
+	name         string
+	values       map[string]interface{}
+}
+
+func (c *planConverter) convert() ([]byte, error) {
+	for _, r := range append(append([]*resourceState{}, c.current...), c.past...) {
+		if strings.HasPrefix(r.Type, providerTypePrefix) {
+			c.providers[r.URN] = r
+		}
+	}
+
+	current := c.convertResources(c.current)
+	past := c.convertResources(c.past)
+
+	// The configuration is only used to look up the provider and references
+	// of each resource, so the past and current resources share one entry.
+	confResources := make([]interface{}, 0, len(current))
+	seen := map[string]bool{}
+	for _, r := range append(append([]convertedResource{}, current...), past...) {
+		if seen[r.address] {
+			continue
+		}
+		seen[r.address] = true
+
+		confResources = append(confResources, map[string]interface{}{
+			"address":             r.address,
+			"mode":                "managed",
+			"type":                r.resourceType,
+			"name":                r.name,
+			"provider_config_key": c.providerKey(r.state),
+			"expressions":         c.referenceExpressions(r.state),
+		})
+	}
+
+	changes := make([]interface{}, 0, len(c.changed))
+	for _, urn := range c.changed {
+		if addr, ok := c.addresses[urn]; ok {
+			changes = append(changes, map[string]interface{}{"address": addr})
+		}
+	}
+
+	plan := map[string]interface{}{
+		"format_version": "1.1",
+		"planned_values": map[string]interface{}{
+			"root_module": map[string]interface{}{"resources": planResources(current)},
+		},
+		"prior_state": map[string]interface{}{
+			"values": map[string]interface{}{
+				"root_module": map[string]interface{}{"resources": planResources(past)},
+			},
+		},
+		"resource_changes": changes,
+		"configuration": map[string]interface{}{
+			"provider_config": c.providerConfig(),
+			"root_module":     map[string]interface{}{"resources": confResources},
+		},
+	}
+
+	return json.Marshal(plan)
+}
+
+func (c *planConverter) convertResources(states []*resourceState) []convertedResource {
+	resources := make([]convertedResource, 0, len(states))
+
+	for _, s := range states {
+		if !s.Custom || strings.HasPrefix(s.Type, "pulumi:") {
+			continue
+		}
+
+		props := mergeProperties(s.Outputs, s.Inputs)
+
+		var resourceType string
+		var values map[string]interface{}
+
+		if strings.HasPrefix(s.Type, "azure-native:") {
+			var ok bool
+			resourceType, values, ok = convertAzureNative(s.Type, props)
+			if !ok {
+				logging.Logger.Debug().Msgf("Skipping Pulumi resource %s as type %s is not supported", s.URN, s.Type)
+				continue
+			}
+		} else {
+			resourceType = terraformType(s.Type)
+			if resourceType == "" {
+				logging.Logger.Debug().Msgf("Skipping Pulumi resource %s as type %s is not from a bridged provider", s.URN, s.Type)
+				continue
+			}
+			values = convertValues(props)
+		}
+
+		address := c.address(s.URN, resourceType)
+		_, name, _ := strings.Cut(address, ".")
+
+		resources = append(resources, convertedResource{
+			state:        s,
+			address:      address,
+			resourceType: resourceType,
+			name:         name,
+			values:       values,
+		})
+	}
+
+	return resources
+}
+
+// address returns the Terraform address for the resource, which is the type
+// and the name of the resource. The same address is used for the past and
+// current state of a resource.
+func (c *planConverter) address(urn, resourceType string) string {
+	if addr, ok := c.addresses[urn]; ok {
+		return addr
+	}
+
+	name := invalidNameChars.ReplaceAllString(urnName(urn), "_")
+	addr := resourceType + "." + name
+	for i := 2; c.used[addr]; i++ {
+		addr = fmt.Sprintf("%s.%s_%d", resourceType, name, i)
+	}
+
+	c.addresses[urn] = addr
+	c.used[addr] = true
+
+	return addr
+}
+
+// providerKey returns the key of the provider config for the resource. Default
+// providers use the Terraform provider name as the key and explicit providers
+// use the name of the provider resource as an alias.
+func (c *planConverter) providerKey(s *resourceState) string {
+	prefix := terraformPrefixes[strings.SplitN(s.Type, ":", 2)[0]]
+	if strings.HasPrefix(s.Type, "azure-native:") {
+		prefix = "azurerm"
+	}
+
+	providerURN := providerURN(s.Provider)
+	if providerURN == "" || isDefaultProvider(providerURN) {
+		return prefix
+	}
+
+	return prefix + "." + invalidNameChars.ReplaceAllString(urnName(providerURN), "_")
+}
+
+func (c *planConverter) providerConfig() map[string]interface{} {
+	conf := map[string]interface{}{}
+
+	urns := make([]string, 0, len(c.providers))
+	for urn := range c.providers {
+		urns = append(urns, urn)
+	}
+	sort.Strings(urns)
+
+	for _, urn := range urns {
+		p := c.providers[urn]
+		pkg := strings.TrimPrefix(p.Type, providerTypePrefix)
+
+		prefix, ok := terraformPrefixes[pkg]
+		if !ok {
+			continue
+		}
+
+		key := prefix
+		if !isDefaultProvider(urn) {
+			key = prefix + "." + invalidNameChars.ReplaceAllString(urnName(urn), "_")
+		}
+
+		region, _ := mergeProperties(p.Outputs, p.Inputs)["region"].(string)
+		conf[key] = providerConfigEntry(prefix, region)
+	}
+
+	// Default providers get their region from the stack config
+	for pkg, prefix := range terraformPrefixes {
+		region := c.config[pkg+":region"]
+		if _, ok := conf[prefix]; ok || region == "" {
+			continue
+		}
+
+		conf[prefix] = providerConfigEntry(prefix, region)
+	}
+
+	return conf
+}
+
+func providerConfigEntry(name, region string) map[string]interface{} {
+	entry := map[string]interface{}{"name": name}
+	if region != "" {
+		entry["expressions"] = map[string]interface{}{
+			"region": map[string]interface{}{"constant_value": region},
+		}
+	}
+
+	return entry
+}
+
+// referenceExpressions returns the references of each property to other
+// resources, using the dependencies recorded by Pulumi.
+func (c *planConverter) referenceExpressions(s *resourceState) map[string]interface{} {
+	exps := map[string]interface{}{}
+
+	for prop, urns := range s.PropertyDependencies {
+		refs := []interface{}{}
+		for _, urn := range urns {
+			if addr, ok := c.addresses[urn]; ok {
+				refs = append(refs, addr+".id", addr)
+			}
+		}
+
+		if len(refs) > 0 {
+			exps[toSnakeCase(prop)] = map[string]interface{}{"references": refs}
+		}
+	}
+
+	return exps
+}
+
+func planResources(resources []convertedResource) []interface{} {
+	l := make([]interface{}, 0, len(resources))
+	for _, r := range resources {
+		l = append(l, map[string]interface{}{
+			"address":       r.address,
+			"mode":          "managed",
+			"type":          r.resourceType,
+			"name":          r.name,
+			"provider_name": "registry.terraform.io/hashicorp/" + strings.SplitN(r.resourceType, "_", 2)[0],
+			"values":        r.values,
+		})
+	}
+
+	return l
+}
+
+// mergeProperties returns the outputs with the inputs taking precedence, since
+// the outputs of resources that are going to be created aren't known.
+func mergeProperties(outputs, inputs map[string]interface{}) map[string]interface{} {
+	props := map[string]interface{}{}
+	for k, v := range outputs {
+		props[k] = v
+	}
+	for k, v := range inputs {
+		props[k] = v
+	}
+
+	for k, v := range props {
+		v = cleanValue(v)
+		if v == nil {
+			delete(props, k)
+			continue
+		}
+		props[k] = v
+	}
+
+	return props
+}
+
+// cleanValue removes unknown values and unwraps secrets.
+func cleanValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		if val == unknownValue {
+			return nil
+		}
+	case map[string]interface{}:
+		if sig, ok := val[signatureKey]; ok {
+			if sig != secretSignature {
+				return nil
+			}
+
+			var plaintext interface{}
+			if s, ok := val["plaintext"].(string); ok && json.Unmarshal([]byte(s), &plaintext) == nil {
+				return cleanValue(plaintext)
+			}
+
+			return nil
+		}
+
+		cleaned := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if item = cleanValue(item); item != nil {
+				cleaned[k] = item
+			}
+		}
+		return cleaned
+	case []interface{}:
+		cleaned := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			if item = cleanValue(item); item != nil {
+				cleaned = append(cleaned, item)
+			}
+		}
+		return cleaned
+	}
+
+	return v
+}
+
+// convertValues converts the Pulumi properties to Terraform values. Pulumi
+// uses camel case names, flattens blocks that can only be set once to an
+// object and uses plural names for lists of blocks, e.g. ebsBlockDevices for
+// the ebs_block_device blocks.
+func convertValues(props map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(props))
+
+	for k, v := range props {
+		// Skip Pulumi metadata such as __defaults
+		if strings.HasPrefix(k, "__") {
+			continue
+		}
+
+		key := toSnakeCase(k)
+		if mapAttributes[key] {
+			values[key] = v
+			continue
+		}
+
+		switch val := v.(type) {
+		case map[string]interface{}:
+			values[key] = []interface{}{convertValues(val)}
+		case []interface{}:
+			list := make([]interface{}, 0, len(val))
+			blocks := false
+			for _, item := range val {
+				if m, ok := item.(map[string]interface{}); ok {
+					blocks = true
+					list = append(list, convertValues(m))
+					continue
+				}
+				list = append(list, item)
+			}
+
+			values[key] = list
+			if singular := singularize(key); blocks && singular != key {
+				if _, ok := props[singular]; !ok {
+					values[singular] = list
+				}
+			}
+		default:
+			values[key] = val
+		}
+	}
+
+	return values
+}
+
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return strings.TrimSuffix(s, "s")
+	}
+
+	return s
+}
+
+// urnName returns the name of the resource from its URN, which has the format
+// urn:pulumi:stack::project::type::name.
+func urnName(urn string) string {
+	i := strings.LastIndex(urn, "::")
+	if i == -1 {
+		return urn
+	}
+
+	return urn[i+2:]
+}
+
+// providerURN returns the URN of a provider reference, which has the format
+// urn::id.
+func providerURN(ref string) string {
+	i := strings.LastIndex(ref, "::")
+	if i == -1 {
+		return ""
+	}
+
+	return ref[:i]
+}
+
+func isDefaultProvider(urn string) bool {
+	return strings.HasPrefix(urnName(urn), "default")
+}
@@ -0,0 +1,145 @@
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestTerraformType(t *testing.T) {
+	tests := map[string]string{
+		"aws:ec2/instance:Instance":                             "aws_instance",
+		"aws:rds/instance:Instance":                             "aws_db_instance",
+		"aws:s3/bucket:Bucket":                                  "aws_s3_bucket",
+		"aws:ec2/natGateway:NatGateway":                         "aws_nat_gateway",
+		"aws:lambda/function:Function":                          "aws_lambda_function",
+		"aws:apigateway/restApi:RestApi":                        "aws_api_gateway_rest_api",
+		"aws:cloudwatch/logGroup:LogGroup":                      "aws_cloudwatch_log_group",
+		"aws:lb/loadBalancer:LoadBalancer":                      "aws_lb",
+		"gcp:compute/instance:Instance":                         "google_compute_instance",
+		"gcp:sql/databaseInstance:DatabaseInstance":             "google_sql_database_instance",
+		"azure:compute/linuxVirtualMachine:LinuxVirtualMachine": "azurerm_linux_virtual_machine",
+		"azure:storage/account:Account":                         "azurerm_storage_account",
+		"azure:core/resourceGroup:ResourceGroup":                "azurerm_resource_group",
+		"random:index/randomPassword:RandomPassword":            "",
+		"kubernetes:apps/v1:Deployment":                         "",
+		"invalid":                                               "",
+	}
+
+	for token, expected := range tests {
+		assert.Equal(t, expected, terraformType(token), token)
+	}
+}
+
+func TestConvertValues(t *testing.T) {
+	actual := convertValues(map[string]interface{}{
+		"__defaults":      []interface{}{},
+		"instanceType":    "t3.micro",
+		"tags":            map[string]interface{}{"CostCenter": "123"},
+		"rootBlockDevice": map[string]interface{}{"volumeSize": 20.0},
+		"ebsBlockDevices": []interface{}{map[string]interface{}{"volumeSize": 100.0}},
+		"securityGroups":  []interface{}{"sg-1"},
+		"environment":     map[string]interface{}{"variables": map[string]interface{}{"LOG_LEVEL": "debug"}},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"instance_type":     "t3.micro",
+		"tags":              map[string]interface{}{"CostCenter": "123"},
+		"root_block_device": []interface{}{map[string]interface{}{"volume_size": 20.0}},
+		"ebs_block_devices": []interface{}{map[string]interface{}{"volume_size": 100.0}},
+		"ebs_block_device":  []interface{}{map[string]interface{}{"volume_size": 100.0}},
+		"security_groups":   []interface{}{"sg-1"},
+		"environment":       []interface{}{map[string]interface{}{"variables": map[string]interface{}{"LOG_LEVEL": "debug"}}},
+	}, actual)
+}
+
+func TestLoadResourcesPreview(t *testing.T) {
+	require.True(t, IsPulumiJSON("testdata/preview.json"))
+
+	projects := loadProjects(t, "testdata/preview.json", true)
+
+	current := resourcesByAddress(projects[0].PartialResources)
+	assert.ElementsMatch(t, []string{"aws_instance.web-server", "aws_db_instance.orders-db", "aws_ebs_volume.data"}, keys(current))
+
+	past := resourcesByAddress(projects[0].PartialPastResources)
+	assert.ElementsMatch(t, []string{"aws_instance.web-server", "aws_nat_gateway.nat"}, keys(past))
+
+	web := schema.BuildResource(current["aws_instance.web-server"], nil)
+	assert.Equal(t, "Instance usage (Linux/UNIX, on-demand, m5.large)", web.CostComponents[0].Name)
+	assert.Equal(t, "us-west-2", *web.CostComponents[0].ProductFilter.Region)
+	assert.Equal(t, "50", web.SubResources[0].CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, &map[string]string{"Name": "web"}, web.Tags)
+
+	oldWeb := schema.BuildResource(past["aws_instance.web-server"], nil)
+	assert.Equal(t, "Instance usage (Linux/UNIX, on-demand, t3.medium)", oldWeb.CostComponents[0].Name)
+
+	db := schema.BuildResource(current["aws_db_instance.orders-db"], nil)
+	assert.Equal(t, "Database instance (on-demand, Multi-AZ, db.t3.large)", db.CostComponents[0].Name)
+	assert.Equal(t, "eu-west-1", *db.CostComponents[0].ProductFilter.Region)
+
+	projects = loadProjects(t, "testdata/preview.json", false)
+	assert.Empty(t, projects[0].PartialPastResources)
+}
+
+func TestLoadResourcesStackExport(t *testing.T) {
+	require.True(t, IsPulumiJSON("testdata/export.json"))
+	assert.False(t, IsPulumiJSON("testdata/missing.json"))
+
+	projects := loadProjects(t, "testdata/export.json", true)
+
+	current := resourcesByAddress(projects[0].PartialResources)
+	assert.ElementsMatch(t, []string{
+		"google_compute_instance.api",
+		"google_storage_bucket.assets",
+		"azurerm_storage_account.logs",
+		"azurerm_linux_virtual_machine.jump",
+	}, keys(current))
+
+	api := schema.BuildResource(current["google_compute_instance.api"], nil)
+	assert.Contains(t, api.CostComponents[0].Name, "e2-standard-4")
+	assert.Equal(t, "europe-west1", *api.CostComponents[0].ProductFilter.Region)
+
+	storage := schema.BuildResource(current["azurerm_storage_account.logs"], nil)
+	assert.False(t, storage.IsSkipped)
+	assert.NotEmpty(t, storage.CostComponents)
+
+	vm := schema.BuildResource(current["azurerm_linux_virtual_machine.jump"], nil)
+	assert.Contains(t, vm.CostComponents[0].Name, "Standard_B2s")
+
+	// Stack exports only have the current state
+	assert.Len(t, projects[0].PartialPastResources, len(projects[0].PartialResources))
+}
+
+func loadProjects(t *testing.T, path string, includePastResources bool) []*schema.Project {
+	t.Helper()
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: path}, nil)
+	projects, err := NewProvider(ctx, includePastResources).LoadResources(schema.NewUsageMapFromInterface(map[string]interface{}{}))
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "pulumi", projects[0].Metadata.Type)
+
+	return projects
+}
+
+func resourcesByAddress(resources []*schema.PartialResource) map[string]*schema.PartialResource {
+	m := make(map[string]*schema.PartialResource, len(resources))
+	for _, r := range resources {
+		m[r.Address] = r
+	}
+
+	return m
+}
+
+func keys[T any](m map[string]T) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+
+	return l
+}
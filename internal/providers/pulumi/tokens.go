@@ -0,0 +1,126 @@
+package pulumi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+// terraformPrefixes are the Terraform provider prefixes for the Pulumi
+// packages that are bridged from Terraform providers.
+var terraformPrefixes = map[string]string{
+	"aws":   "aws",
+	"azure": "azurerm",
+	"gcp":   "google",
+}
+
+// typeOverrides are the Pulumi type tokens that don't follow the naming
+// convention of the Terraform resource type they are bridged from.
+var typeOverrides = map[string]string{
+	"aws:alb/loadBalancer:LoadBalancer": "aws_alb",
+	"aws:elb/loadBalancer:LoadBalancer": "aws_elb",
+	"aws:lb/loadBalancer:LoadBalancer":  "aws_lb",
+	"aws:rds/instance:Instance":         "aws_db_instance",
+	"aws:s3/bucketV2:BucketV2":          "aws_s3_bucket",
+}
+
+// moduleAliases are the Pulumi modules whose name differs from the prefix
+// used in the Terraform resource types of the module.
+var moduleAliases = map[string]string{
+	"aws:apigateway":        "api_gateway",
+	"aws:directoryservice":  "directory_service",
+	"aws:ec2clientvpn":      "ec2_client_vpn",
+	"aws:ec2transitgateway": "ec2",
+	"aws:elasticbeanstalk":  "elastic_beanstalk",
+	"azure:appservice":      "app_service",
+	"azure:core":            "",
+	"gcp:cloudfunctionsv2":  "cloudfunctions2",
+	"gcp:organizations":     "",
+	"gcp:serviceaccount":    "service",
+}
+
+// terraformType returns the Terraform resource type for the Pulumi type token,
+// e.g. aws_instance for aws:ec2/instance:Instance. Bridged providers name
+// their tokens after the Terraform types, so the module prefixed name is tried
+// first and then the name without the module. Tokens of packages that aren't
+// bridged return an empty string.
+func terraformType(token string) string {
+	if t, ok := typeOverrides[token]; ok {
+		return t
+	}
+
+	pkg, module, name, ok := parseToken(token)
+	if !ok {
+		return ""
+	}
+
+	prefix, ok := terraformPrefixes[pkg]
+	if !ok {
+		return ""
+	}
+
+	if alias, ok := moduleAliases[pkg+":"+module]; ok {
+		module = alias
+	}
+
+	snakeName := toSnakeCase(name)
+
+	candidates := []string{prefix + "_" + snakeName}
+	if module != "" {
+		candidates = append([]string{prefix + "_" + module + "_" + snakeName}, candidates...)
+	}
+
+	registryMap := terraform.GetResourceRegistryMap()
+	for _, c := range candidates {
+		if _, ok := (*registryMap)[c]; ok {
+			return c
+		}
+	}
+
+	// Use the most likely type so the resource is shown as unsupported
+	return candidates[0]
+}
+
+// parseToken splits a type token, e.g. aws:ec2/instance:Instance, into the
+// package, module and resource name. The module of bridged providers includes
+// the file name of the resource, which is removed, and the module of
+// azure-native resources can include the API version, which is also removed.
+func parseToken(token string) (string, string, string, bool) {
+	parts := strings.Split(token, ":")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+
+	module, _, _ := strings.Cut(parts[1], "/")
+	if module == "index" {
+		module = ""
+	}
+
+	return parts[0], module, parts[2], true
+}
+
+// toSnakeCase converts a camel or pascal case name to snake case, e.g.
+// rootBlockDevice to root_block_device.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteRune('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
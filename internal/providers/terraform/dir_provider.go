@@ -194,6 +194,7 @@ func (p *DirProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, e
 		project.HasDiff = !p.UseState
 		if project.HasDiff {
 			project.PartialPastResources = parsed.PastResources
+			project.Metadata.RemovedResources = parsed.RemovedResources
 		}
 		project.PartialResources = parsed.CurrentResources
 
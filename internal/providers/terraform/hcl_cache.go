@@ -0,0 +1,257 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/version"
+)
+
+// The HCL evaluation cache stores the plan JSON that the HCLProvider builds
+// from an evaluated Terraform directory, so that projects that haven't changed
+// since the last run are served from the cache rather than being parsed and
+// evaluated again. Unlike the plan cache, entries don't expire. Instead they
+// are keyed by a hash of everything the evaluation depends on, so any change to
+// the project files, var files, env variables or modules misses the cache.
+var hclCacheFileVersion = "0.1"
+var hclCacheDirName = "hcl_evaluation_cache"
+
+type hclCacheWarning struct {
+	Code            int         `json:"code"`
+	Message         string      `json:"message"`
+	Data            interface{} `json:"data"`
+	IsError         bool        `json:"is_error"`
+	FriendlyMessage string      `json:"friendly_message"`
+}
+
+type hclCacheFile struct {
+	Version string `json:"version"`
+	Key     string `json:"key"`
+
+	RootPath            string            `json:"root_path"`
+	ModulePath          string            `json:"module_path"`
+	ModuleSuffix        string            `json:"module_suffix"`
+	TerraformVarsPaths  []string          `json:"terraform_vars_paths"`
+	Warnings            []hclCacheWarning `json:"warnings"`
+	RemovedResources    []string          `json:"removed_resources"`
+	SuppliedDataSources []string          `json:"supplied_data_sources"`
+	MockedDataSources   []string          `json:"mocked_data_sources"`
+
+	Plan []byte `json:"plan"`
+}
+
+// UseHCLCache returns true if the evaluated project can be read from, and
+// written to, the HCL evaluation cache.
+func UseHCLCache(p *HCLProvider) bool {
+	if !p.ctx.RunContext.Config.HCLEvaluationCache {
+		return false
+	}
+
+	if p.ctx.RunContext.Config.NoCache {
+		// cache was turned off with --no-cache
+		return false
+	}
+
+	if p.shouldPromptMissingVars() {
+		// the project needs to be evaluated to find the variables to
+		// prompt for.
+		return false
+	}
+
+	if len(p.ctx.ProjectConfig.TerraformRemoteStates) > 0 {
+		// the outputs of remote states are read from other projects and
+		// state files that aren't part of the cache key.
+		return false
+	}
+
+	return true
+}
+
+// ReadHCLCache returns the project from the HCL evaluation cache if the inputs
+// of the project haven't changed since it was written.
+func ReadHCLCache(p *HCLProvider) (HCLProject, error) {
+	key, err := calcHCLCacheKey(p)
+	if err != nil {
+		p.logger.Debug().Err(err).Msg("Skipping HCL evaluation cache: could not calculate cache key")
+		p.ctx.CacheErr = "no key"
+		return HCLProject{}, fmt.Errorf("no key")
+	}
+
+	if key == "" {
+		p.logger.Debug().Msg("Skipping HCL evaluation cache: modules have not been loaded")
+		p.ctx.CacheErr = "not found"
+		return HCLProject{}, fmt.Errorf("not found")
+	}
+
+	data, err := os.ReadFile(hclCacheFilePath(p))
+	if err != nil {
+		p.logger.Debug().Msg("Skipping HCL evaluation cache: Cache file does not exist")
+		p.ctx.CacheErr = "not found"
+		return HCLProject{}, fmt.Errorf("not found")
+	}
+
+	var cf hclCacheFile
+	err = json.Unmarshal(data, &cf)
+	if err != nil {
+		p.logger.Debug().Msgf("Skipping HCL evaluation cache: Error unmarshalling cache file: %v", err)
+		p.ctx.CacheErr = "bad format"
+		return HCLProject{}, fmt.Errorf("bad format")
+	}
+
+	if cf.Version != hclCacheFileVersion {
+		p.logger.Debug().Msg("Skipping HCL evaluation cache: version changed")
+		p.ctx.CacheErr = "version changed"
+		return HCLProject{}, fmt.Errorf("version changed")
+	}
+
+	if cf.Key != key {
+		p.logger.Debug().Msg("Skipping HCL evaluation cache: Project inputs have changed")
+		p.ctx.CacheErr = "change detected"
+		return HCLProject{}, fmt.Errorf("change detected")
+	}
+
+	warnings := make([]*schema.ProjectDiag, 0, len(cf.Warnings))
+	for _, w := range cf.Warnings {
+		warnings = append(warnings, &schema.ProjectDiag{
+			Code:            w.Code,
+			Message:         w.Message,
+			Data:            w.Data,
+			IsError:         w.IsError,
+			FriendlyMessage: w.FriendlyMessage,
+		})
+	}
+
+	p.logger.Debug().Msgf("Read plan JSON from %s", hclCacheDirName)
+	p.ctx.UsingCache = true
+
+	return HCLProject{
+		JSON: cf.Plan,
+		Module: &hcl.Module{
+			RootPath:           cf.RootPath,
+			ModulePath:         cf.ModulePath,
+			ModuleSuffix:       cf.ModuleSuffix,
+			TerraformVarsPaths: cf.TerraformVarsPaths,
+			Warnings:           warnings,
+			HasChanges:         p.Parser.HasChanges(),
+		},
+		RemovedResources:    cf.RemovedResources,
+		SuppliedDataSources: cf.SuppliedDataSources,
+		MockedDataSources:   cf.MockedDataSources,
+	}, nil
+}
+
+// WriteHCLCache writes the evaluated project to the HCL evaluation cache.
+func WriteHCLCache(p *HCLProvider, project HCLProject) {
+	if p.Parser.UsesRemoteVariables() {
+		p.logger.Debug().Msg("Not writing HCL evaluation cache: project uses remote variables")
+		return
+	}
+
+	// the key is calculated after the project has been evaluated since this
+	// is when the module manifest is written.
+	key, err := calcHCLCacheKey(p)
+	if err != nil || key == "" {
+		p.logger.Debug().Err(err).Msg("Not writing HCL evaluation cache: could not calculate cache key")
+		return
+	}
+
+	warnings := make([]hclCacheWarning, 0, len(project.Module.Warnings))
+	for _, w := range project.Module.Warnings {
+		warnings = append(warnings, hclCacheWarning{
+			Code:            w.Code,
+			Message:         w.Message,
+			Data:            w.Data,
+			IsError:         w.IsError,
+			FriendlyMessage: w.FriendlyMessage,
+		})
+	}
+
+	cacheJSON, err := json.Marshal(hclCacheFile{
+		Version:             hclCacheFileVersion,
+		Key:                 key,
+		RootPath:            project.Module.RootPath,
+		ModulePath:          project.Module.ModulePath,
+		ModuleSuffix:        project.Module.ModuleSuffix,
+		TerraformVarsPaths:  project.Module.TerraformVarsPaths,
+		Warnings:            warnings,
+		RemovedResources:    project.RemovedResources,
+		SuppliedDataSources: project.SuppliedDataSources,
+		MockedDataSources:   project.MockedDataSources,
+		Plan:                project.JSON,
+	})
+	if err != nil {
+		p.logger.Debug().Msgf("Failed to marshal HCL evaluation cache: %v", err)
+		return
+	}
+
+	cachePath := hclCacheFilePath(p)
+	err = os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err != nil {
+		p.logger.Debug().Msgf("Couldn't create %v directory: %v", filepath.Dir(cachePath), err)
+		return
+	}
+
+	err = os.WriteFile(cachePath, cacheJSON, 0600)
+	if err != nil {
+		p.logger.Debug().Msgf("Failed to write HCL evaluation cache: %v", err)
+		return
+	}
+
+	p.logger.Debug().Msgf("Wrote plan JSON to %s", hclCacheDirName)
+}
+
+// hclCacheFilePath returns the path of the cache file of the project. There is
+// a single file per project, rather than per key, so that the cache doesn't
+// grow each time a project changes. Projects are identified by their path and
+// the config that can give the same path different inputs, e.g. the var files
+// of each environment of an auto-detected project.
+func hclCacheFilePath(p *HCLProvider) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "path:%s\n", p.Parser.Path())
+	fmt.Fprintf(h, "name:%s\n", p.ctx.ProjectConfig.Name)
+	fmt.Fprintf(h, "workspace:%s\n", p.ctx.ProjectConfig.TerraformWorkspace)
+	for _, varFile := range p.Parser.TerraformVarFiles() {
+		fmt.Fprintf(h, "var_file:%s\n", varFile)
+	}
+
+	return filepath.Join(p.ctx.RunContext.Config.CachePath(), config.InfracostDir, hclCacheDirName, fmt.Sprintf("%x.json", h.Sum(nil)))
+}
+
+// calcHCLCacheKey returns a hash of everything the evaluation of the project
+// depends on. It's empty if this can't be known until the project has been
+// evaluated, see hcl.Parser.Hash.
+func calcHCLCacheKey(p *HCLProvider) (string, error) {
+	parserHash, err := p.Parser.Hash()
+	if err != nil || parserHash == "" {
+		return "", err
+	}
+
+	var mocksHash string
+	if mocksPath := p.ctx.ProjectConfig.TerraformDataMocksFile; mocksPath != "" {
+		if !filepath.IsAbs(mocksPath) {
+			mocksPath = filepath.Join(p.Parser.Path(), mocksPath)
+		}
+
+		b, err := os.ReadFile(mocksPath)
+		if err != nil {
+			return "", err
+		}
+		mocksHash = fmt.Sprintf("%x", sha256.Sum256(b))
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "infracost_version:%s\n", version.Version)
+	fmt.Fprintf(h, "parser:%s\n", parserHash)
+	fmt.Fprintf(h, "data_mocks:%s\n", mocksHash)
+	fmt.Fprintf(h, "config_env:%s\n", envToString(p.ctx.ProjectConfig.Env))
+	fmt.Fprintf(h, "tf_env:%s\n", tfEnvToString())
+	fmt.Fprintf(h, "graph_evaluator:%s\n", os.Getenv("INFRACOST_GRAPH_EVALUATOR"))
+	fmt.Fprintf(h, "source_map:%v\n", p.ctx.RunContext.Config.TerraformSourceMap)
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
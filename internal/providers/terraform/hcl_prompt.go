@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// missingVarsPromptMutex stops the prompts of projects that are evaluated in
+// parallel from being interleaved.
+var missingVarsPromptMutex sync.Mutex
+
+// shouldPromptMissingVars returns true if the user asked to be prompted for
+// missing variables and is able to answer the prompts.
+func (p *HCLProvider) shouldPromptMissingVars() bool {
+	return p.ctx.RunContext.Config.PromptMissingVars && !p.ctx.RunContext.IsCIRun() && ui.IsInteractive()
+}
+
+// promptMissingVars prompts the user for the values of the missing variables of
+// the module and saves them to the prompted var file of the project, see
+// hcl.PromptedVarFilePath, so they're used from now on. It returns true if any
+// values were saved, in which case the project needs to be parsed again.
+func (p *HCLProvider) promptMissingVars(module *hcl.Module) bool {
+	if len(module.MissingVariables) == 0 || !p.shouldPromptMissingVars() {
+		return false
+	}
+
+	missingVarsPromptMutex.Lock()
+	defer missingVarsPromptMutex.Unlock()
+
+	w := p.ctx.RunContext.OutWriter
+	fmt.Fprintf(w, "\nInput values were not provided for %d Terraform variables of %s.\n", len(module.MissingVariables), ui.PrimaryString(p.Parser.Path()))
+	fmt.Fprintln(w, ui.FaintString("Strings can be entered without quotes. Press enter to skip a variable."))
+
+	values := make(map[string]cty.Value)
+	for _, v := range module.MissingVariables {
+		fmt.Fprintln(w)
+		if v.Description != "" {
+			fmt.Fprintln(w, ui.FaintString(v.Description))
+		}
+
+		if !v.CostRelevant {
+			fmt.Fprintln(w, ui.FaintString("This variable isn't used by any resources so it won't change the costs."))
+		}
+
+		for _, validation := range v.Validations {
+			fmt.Fprintln(w, ui.FaintString(fmt.Sprintf("Validation: %s", validation)))
+		}
+
+		input := ui.StringPrompt(fmt.Sprintf("var.%s (%s)", v.Name, v.TypeString()), func(input string) error {
+			if input == "" {
+				return nil
+			}
+
+			_, err := v.Parse(input)
+			return err
+		})
+		if input == "" {
+			continue
+		}
+
+		values[v.Name], _ = v.Parse(input)
+	}
+
+	if len(values) == 0 {
+		return false
+	}
+
+	path := hcl.PromptedVarFilePath(p.Parser.Path())
+	err := hcl.WriteVarFile(path, values)
+	if err != nil {
+		ui.PrintWarningf(p.ctx.RunContext.ErrWriter, "Could not save the variable values to %s: %s", path, err)
+		return false
+	}
+
+	fmt.Fprintf(w, "\nSaved the variable values to %s. Edit or delete the file to change them.\n\n", ui.PrimaryString(path))
+
+	return true
+}
@@ -38,10 +38,11 @@ type HCLProvider struct {
 	planJSONParser *Parser
 	logger         zerolog.Logger
 
-	schema *PlanSchema
-	ctx    *config.ProjectContext
-	cache  *HCLProject
-	config HCLProviderConfig
+	schema       *PlanSchema
+	refactorings hcl.Refactorings
+	ctx          *config.ProjectContext
+	cache        *HCLProject
+	config       HCLProviderConfig
 }
 
 type HCLProviderConfig struct {
@@ -115,6 +116,26 @@ func NewHCLProvider(ctx *config.ProjectContext, rootPath hcl.RootPath, config *H
 		options = append(options, withInputVars)
 	}
 
+	if ctx.ProjectConfig.TerraformDataMocksFile != "" {
+		mocksPath := ctx.ProjectConfig.TerraformDataMocksFile
+		if !filepath.IsAbs(mocksPath) {
+			mocksPath = filepath.Join(rootPath.Path, mocksPath)
+		}
+
+		mocks, err := hcl.LoadDataMocks(mocksPath)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, hcl.OptionWithDataMocks(mocks))
+	}
+
+	states, err := remoteStates(ctx, rootPath.Path)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, hcl.OptionWithRemoteStates(states...))
+
 	options = append(options, opts...)
 
 	credsSource, err := modules.NewTerraformCredentialsSource(modules.BaseCredentialSet{
@@ -130,8 +151,13 @@ func NewHCLProvider(ctx *config.ProjectContext, rootPath hcl.RootPath, config *H
 		)
 	}
 
+	// projects are already evaluated in parallel so this only speeds up large
+	// projects that have vertices that can be evaluated concurrently.
+	parallelism, _ := ctx.RunContext.GetParallelism()
+
 	options = append(options,
 		hcl.OptionWithTerraformWorkspace(localWorkspace),
+		hcl.OptionWithGraphParallelism(parallelism),
 	)
 
 	logger := ctx.Logger().With().Str("provider", "terraform_dir").Logger()
@@ -226,6 +252,8 @@ func (p *HCLProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, e
 
 	project.AddProviderMetadata(parsedConf.ProviderMetadata)
 	project.Metadata.RemoteModuleCalls = parsedConf.RemoteModuleCalls
+	project.Metadata.RemovedResources = j.RemovedResources
+	project.Metadata.SuppliedDataSources, project.Metadata.MockedDataSources = j.SuppliedDataSources, j.MockedDataSources
 
 	project.PartialPastResources = parsedConf.PastResources
 	project.PartialResources = parsedConf.CurrentResources
@@ -288,13 +316,41 @@ type HCLProject struct {
 	JSON   []byte
 	Module *hcl.Module
 	Error  error
+
+	// RemovedResources, SuppliedDataSources and MockedDataSources are
+	// collected from the Module when the JSON is built, so that they're
+	// available when the project is read from the HCL evaluation cache and
+	// the Module only has the fields of the root module.
+	RemovedResources    []string
+	SuppliedDataSources []string
+	MockedDataSources   []string
 }
 
 // LoadPlanJSON parses the RootPath and return the blocks in Terraform plan JSON format.
+// If the HCL evaluation cache is enabled, unchanged projects are read from the cache
+// instead, see ReadHCLCache.
 func (p *HCLProvider) LoadPlanJSON() HCLProject {
+	useCache := UseHCLCache(p)
+	if useCache {
+		cached, err := ReadHCLCache(p)
+		if err == nil {
+			return cached
+		}
+	}
+
 	module := p.Module()
+	if module.Error == nil && p.promptMissingVars(module.Module) {
+		module = p.InvalidateCache().Module()
+	}
+
 	if module.Error == nil {
 		module.JSON, module.Error = p.modulesToPlanJSON(module.Module)
+		module.RemovedResources = module.Module.Refactorings().Forgotten()
+		module.SuppliedDataSources, module.MockedDataSources = module.Module.DataSources()
+
+		if module.Error == nil && useCache {
+			WriteHCLCache(p, module)
+		}
 
 		if os.Getenv("INFRACOST_JSON_DUMP") == "true" {
 			err := os.WriteFile(fmt.Sprintf("%s-out.json", strings.ReplaceAll(module.Module.ModulePath, "/", "-")), module.JSON, os.ModePerm)
@@ -373,6 +429,7 @@ func (p *HCLProvider) newPlanSchema() {
 
 func (p *HCLProvider) modulesToPlanJSON(rootModule *hcl.Module) ([]byte, error) {
 	p.newPlanSchema()
+	p.refactorings = rootModule.Refactorings()
 
 	mo := p.marshalModule(rootModule)
 	p.schema.Configuration.RootModule = mo.ModuleConfig
@@ -398,7 +455,9 @@ func (p *HCLProvider) marshalModule(module *hcl.Module) ModuleOut {
 
 	for _, block := range module.Blocks {
 		if block.Type() == "provider" {
-			p.marshalProviderBlock(block)
+			for _, instance := range block.ProviderInstances() {
+				p.marshalProviderBlock(instance)
+			}
 		}
 	}
 
@@ -410,7 +469,7 @@ func (p *HCLProvider) marshalModule(module *hcl.Module) ModuleOut {
 	configResources := map[string]struct{}{}
 	for _, block := range module.Blocks {
 		if block.Type() == "resource" {
-			out := p.getResourceOutput(block)
+			out := p.getResourceOutput(module, block)
 
 			if _, ok := configResources[out.Configuration.Address]; !ok {
 				moduleConfig.Resources = append(moduleConfig.Resources, out.Configuration)
@@ -449,7 +508,7 @@ func (p *HCLProvider) marshalModule(module *hcl.Module) ModuleOut {
 	}
 }
 
-func (p *HCLProvider) getResourceOutput(block *hcl.Block) ResourceOutput {
+func (p *HCLProvider) getResourceOutput(module *hcl.Module, block *hcl.Block) ResourceOutput {
 	jsonValues := marshalAttributeValues(block.Type(), block.Values())
 	p.marshalBlock(block, jsonValues)
 	planned := ResourceJSON{
@@ -468,6 +527,18 @@ func (p *HCLProvider) getResourceOutput(block *hcl.Block) ResourceOutput {
 		},
 	}
 
+	if prev := p.refactorings.PreviousAddress(planned.Address); prev != "" {
+		planned.InfracostMetadata[schema.PreviousAddressMetadataKey] = prev
+	}
+
+	if p.refactorings.IsImported(planned.Address) {
+		planned.InfracostMetadata[schema.ImportingMetadataKey] = true
+	}
+
+	if provenance := module.Provenance(block); len(provenance) > 0 {
+		planned.InfracostMetadata[schema.ProvenanceMetadataKey] = provenance
+	}
+
 	changes := ResourceChangesJSON{
 		Address:       block.FullName(),
 		ModuleAddress: newString(block.ModuleAddress()),
@@ -720,6 +791,10 @@ func (p *HCLProvider) marshalBlock(block *hcl.Block, jsonValues map[string]inter
 	}
 }
 
+// writeOnlyAttributeSuffix is the naming convention providers use for
+// write-only attributes, e.g. password_wo.
+const writeOnlyAttributeSuffix = "_wo"
+
 func marshalAttributeValues(blockType string, value cty.Value) map[string]interface{} {
 	if value.IsNull() {
 		return nil
@@ -741,6 +816,13 @@ func marshalAttributeValues(blockType string, value cty.Value) map[string]interf
 			continue
 		}
 
+		// Write-only attributes are never persisted, so they're null in the
+		// plan JSON. They're usually set from ephemeral values which we can
+		// only mock, so leave them out to match.
+		if blockType == "resource" && strings.HasSuffix(key, writeOnlyAttributeSuffix) {
+			continue
+		}
+
 		ret[key] = stdJson.RawMessage(vJSON)
 	}
 	return ret
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 	"text/template"
@@ -172,6 +174,15 @@ func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 		{
 			name: "builds module configuration correctly with count",
 		},
+		{
+			name: "renders provider instances",
+		},
+		{
+			name: "renders refactoring metadata",
+		},
+		{
+			name: "renders ephemeral values",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -244,3 +255,154 @@ func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestHCLProvider_RemoteStateFromProject(t *testing.T) {
+	dir := t.TempDir()
+	networkPath := filepath.Join(dir, "network")
+	appPath := filepath.Join(dir, "app")
+	require.NoError(t, os.MkdirAll(networkPath, 0700))
+	require.NoError(t, os.MkdirAll(appPath, 0700))
+
+	require.NoError(t, os.WriteFile(filepath.Join(networkPath, "main.tf"), []byte(`
+variable "node_count" {}
+
+output "node_count" {
+  value = var.node_count
+}
+`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "main.tf"), []byte(`
+data "terraform_remote_state" "network" {
+  backend = "s3"
+  config = {
+    bucket = "acme-terraform-state"
+    key    = "network/terraform.tfstate"
+  }
+}
+
+resource "aws_eks_node_group" "workers" {
+  scaling_config {
+    desired_size = data.terraform_remote_state.network.outputs.node_count
+  }
+}
+`), 0600))
+
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.Projects = []*config.Project{
+		{Path: networkPath, TerraformVars: map[string]string{"node_count": "4"}},
+		{Path: appPath},
+	}
+
+	ctx := config.NewProjectContext(runCtx, &config.Project{
+		Path: appPath,
+		TerraformRemoteStates: []config.TerraformRemoteState{
+			{Backend: "s3", Config: map[string]string{"key": "network/terraform.tfstate"}, Path: networkPath},
+		},
+	}, logrus.Fields{})
+
+	p, err := NewHCLProvider(ctx, hcl.RootPath{Path: appPath}, &HCLProviderConfig{SuppressLogging: true})
+	require.NoError(t, err)
+
+	parsed := p.Module()
+	require.NoError(t, parsed.Error)
+
+	resources := parsed.Module.Blocks.OfType("resource")
+	require.Len(t, resources, 1)
+	assert.Equal(t, int64(4), resources[0].GetChildBlock("scaling_config").GetAttribute("desired_size").AsInt())
+
+	supplied, mocked := parsed.Module.DataSources()
+	assert.Equal(t, []string{"data.terraform_remote_state.network"}, supplied)
+	assert.Empty(t, mocked)
+}
+
+func TestRemoteStatesRequireOneSource(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		TerraformRemoteStates: []config.TerraformRemoteState{
+			{Backend: "s3", StateFile: "network.tfstate", Path: "network"},
+		},
+	}, logrus.Fields{})
+
+	_, err := remoteStates(ctx, ".")
+	assert.Error(t, err)
+}
+
+func TestHCLProvider_EvaluationCache(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules", "db"), 0700))
+
+	writeFile := func(name string, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	writeFile("main.tf", `
+variable "instance_type" {}
+
+module "db" {
+  source         = "./modules/db"
+  instance_class = "db.t3.micro"
+}
+
+resource "aws_instance" "web" {
+  instance_type = var.instance_type
+}
+`)
+	writeFile("prod.tfvars", `instance_type = "m5.large"`)
+	writeFile("modules/db/main.tf", `
+variable "instance_class" {}
+
+resource "aws_db_instance" "db" {
+  instance_class = var.instance_class
+}
+`)
+
+	load := func(t *testing.T) (HCLProject, *config.ProjectContext) {
+		t.Helper()
+
+		runCtx := config.EmptyRunContext()
+		runCtx.Config.RootPath = dir
+		runCtx.Config.HCLEvaluationCache = true
+
+		ctx := config.NewProjectContext(runCtx, &config.Project{
+			Path:              dir,
+			TerraformVarFiles: []string{"prod.tfvars"},
+		}, logrus.Fields{})
+
+		p, err := NewHCLProvider(ctx, hcl.RootPath{Path: dir}, &HCLProviderConfig{SuppressLogging: true})
+		require.NoError(t, err)
+
+		parsed := p.LoadPlanJSON()
+		require.NoError(t, parsed.Error)
+
+		return parsed, ctx
+	}
+
+	first, ctx := load(t)
+	assert.False(t, ctx.UsingCache)
+	assert.Contains(t, string(first.JSON), "m5.large")
+
+	cached, ctx := load(t)
+	assert.True(t, ctx.UsingCache)
+	assert.JSONEq(t, string(first.JSON), string(cached.JSON))
+	assert.Equal(t, first.Module.RootPath, cached.Module.RootPath)
+
+	changes := []struct {
+		name    string
+		content string
+	}{
+		{name: "prod.tfvars", content: `instance_type = "m5.xlarge"`},
+		{name: "modules/db/main.tf", content: `resource "aws_db_instance" "db" { instance_class = "db.t3.large" }`},
+	}
+
+	for _, change := range changes {
+		t.Run(change.name, func(t *testing.T) {
+			writeFile(change.name, change.content)
+
+			changed, ctx := load(t)
+			assert.False(t, ctx.UsingCache)
+			assert.Equal(t, "change detected", ctx.CacheErr)
+			assert.NotEqual(t, string(first.JSON), string(changed.JSON))
+
+			_, ctx = load(t)
+			assert.True(t, ctx.UsingCache)
+		})
+	}
+}
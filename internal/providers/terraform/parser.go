@@ -74,7 +74,12 @@ func (p *Parser) createParsedResource(d *schema.ResourceData, u *schema.UsageDat
 		// support advanced features such as Infracost Cloud usage estimates
 		// and actual costs.
 		if registryItem.CoreRFunc != nil {
-			coreRes := registryItem.CoreRFunc(d)
+			var coreRes schema.CoreResource
+			d.TrackAccess(func() {
+				coreRes = registryItem.CoreRFunc(d)
+			})
+			setUncertainInputs(d)
+
 			if coreRes != nil {
 				return parsedResource{
 					PartialResource: schema.NewPartialResource(d, nil, coreRes, registryItem.CloudResourceIDFunc(d)),
@@ -82,7 +87,12 @@ func (p *Parser) createParsedResource(d *schema.ResourceData, u *schema.UsageDat
 				}
 			}
 		} else {
-			res := registryItem.RFunc(d, u)
+			var res *schema.Resource
+			d.TrackAccess(func() {
+				res = registryItem.RFunc(d, u)
+			})
+			setUncertainInputs(d)
+
 			if res != nil {
 				if u != nil {
 					res.EstimationSummary = u.CalcEstimationSummary()
@@ -112,6 +122,25 @@ func (p *Parser) createParsedResource(d *schema.ResourceData, u *schema.UsageDat
 	}
 }
 
+// setUncertainInputs adds the attributes that the resource was priced from
+// and were mocked or unknown when evaluating the HCL to the resource metadata.
+func setUncertainInputs(d *schema.ResourceData) {
+	uncertain := d.UncertainInputs()
+	if len(uncertain) == 0 {
+		return
+	}
+
+	b, err := stdJson.Marshal(uncertain)
+	if err != nil {
+		return
+	}
+
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]gjson.Result)
+	}
+	d.Metadata[schema.UncertainInputsMetadataKey] = gjson.ParseBytes(b)
+}
+
 func (p *Parser) parseJSONResources(parsePrior bool, baseResources []parsedResource, usage schema.UsageMap, confLoader *ConfLoader, parsed, providerConf, vars gjson.Result) []parsedResource {
 	var resources []parsedResource
 	resources = append(resources, baseResources...)
@@ -130,11 +159,15 @@ func (p *Parser) parseJSONResources(parsePrior bool, baseResources []parsedResou
 	}
 
 	resData := p.parseResourceData(isState, confLoader, providerConf, vals, vars)
+	if !parsePrior {
+		annotateResourceChanges(resData, parsed.Get("resource_changes").Array())
+	}
 
 	p.parseReferences(resData, confLoader)
 	p.parseTags(resData, confLoader, providerConf)
 
 	p.stripDataResources(resData)
+	p.stripBuiltinResources(resData)
 	p.populateUsageData(resData, usage)
 
 	for _, d := range resData {
@@ -159,6 +192,9 @@ type ParsedPlanConfiguration struct {
 	CurrentResourceDatas []*schema.ResourceData
 	ProviderMetadata     []schema.ProviderMetadata
 	RemoteModuleCalls    []string
+	// RemovedResources are the addresses of resources that the plan removes
+	// from state without destroying.
+	RemovedResources []string
 }
 
 func newParsedPlanConfiguration(pastResources, currentResources []parsedResource, metadatas []schema.ProviderMetadata, remoteModuleCalls []string) *ParsedPlanConfiguration {
@@ -207,13 +243,17 @@ func (p *Parser) parseJSON(j []byte, usage schema.UsageMap) (*ParsedPlanConfigur
 	confLoader := NewConfLoader(conf)
 	calledRemoteModules := collectModulesSourceUrls(conf.Get("module_calls.*").Array())
 	resources := p.parseJSONResources(false, baseResources, usage, confLoader, parsed, providerConf, vars)
+	resourceChanges := parsed.Get("resource_changes").Array()
 	if !p.includePastResources || !parsed.Get("prior_state").Exists() {
-		return newParsedPlanConfiguration(
+		ppc := newParsedPlanConfiguration(
 			nil,
 			resources,
 			providerMetadata,
 			calledRemoteModules,
-		), nil
+		)
+		ppc.RemovedResources = forgottenResourceAddresses(resourceChanges)
+
+		return ppc, nil
 	}
 
 	// Check if the prior state is the same as the planned state
@@ -228,15 +268,66 @@ func (p *Parser) parseJSON(j []byte, usage schema.UsageMap) (*ParsedPlanConfigur
 	}
 
 	pastResources := p.parseJSONResources(true, baseResources, usage, confLoader, parsed, providerConf, vars)
-	resourceChanges := parsed.Get("resource_changes").Array()
 	pastResources = stripNonTargetResources(pastResources, resources, resourceChanges)
 
-	return newParsedPlanConfiguration(
+	ppc := newParsedPlanConfiguration(
 		pastResources,
 		resources,
 		providerMetadata,
 		calledRemoteModules,
-	), nil
+	)
+	ppc.RemovedResources = forgottenResourceAddresses(resourceChanges)
+
+	return ppc, nil
+}
+
+// forgottenResourceAddresses returns the addresses of the resource changes
+// that remove a resource from state without destroying it. Terraform plans
+// these with a "forget" action when a `removed` block sets destroy = false.
+func forgottenResourceAddresses(resourceChanges []gjson.Result) []string {
+	var addrs []string
+	for _, change := range resourceChanges {
+		for _, action := range change.Get("change.actions").Array() {
+			if action.String() == "forget" {
+				addrs = append(addrs, strings.Clone(change.Get("address").String()))
+				break
+			}
+		}
+	}
+
+	return addrs
+}
+
+// annotateResourceChanges adds metadata to the planned resources that are
+// being moved from another address or imported into state, so that diffs can
+// pair them with their past resource or avoid counting them as new spend.
+func annotateResourceChanges(resData map[string]*schema.ResourceData, resourceChanges []gjson.Result) {
+	for _, change := range resourceChanges {
+		d, ok := resData[change.Get("address").String()]
+		if !ok {
+			continue
+		}
+
+		prev := change.Get("previous_address").String()
+		importing := change.Get("change.importing").Exists()
+		if prev == "" && !importing {
+			continue
+		}
+
+		if d.Metadata == nil {
+			d.Metadata = make(map[string]gjson.Result)
+		}
+
+		// Perf/memory leak: Copy gjson string slices that may be returned so we don't prevent
+		// the entire underlying parsed json from being garbage collected.
+		if prev != "" {
+			d.Metadata[schema.PreviousAddressMetadataKey] = gjson.Parse(strconv.Quote(prev))
+		}
+
+		if importing {
+			d.Metadata[schema.ImportingMetadataKey] = gjson.Parse("true")
+		}
+	}
 }
 
 func collectModulesSourceUrls(moduleCalls []gjson.Result) []string {
@@ -593,6 +684,22 @@ func (p *Parser) stripDataResources(resData map[string]*schema.ResourceData) {
 	}
 }
 
+// builtinResourceTypes are resource types that are built into Terraform itself
+// rather than a provider. They never represent infrastructure so they're
+// removed along with data resources instead of being reported as free or
+// unsupported resources.
+var builtinResourceTypes = map[string]struct{}{
+	"terraform_data": {},
+}
+
+func (p *Parser) stripBuiltinResources(resData map[string]*schema.ResourceData) {
+	for addr, d := range resData {
+		if _, ok := builtinResourceTypes[d.Type]; ok {
+			delete(resData, addr)
+		}
+	}
+}
+
 func (p *Parser) parseReferences(resData map[string]*schema.ResourceData, confLoader *ConfLoader) {
 	registryMap := GetResourceRegistryMap()
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
 
 	"github.com/infracost/infracost/internal/config"
@@ -438,6 +439,101 @@ func TestParseJSONResources(t *testing.T) {
 	}
 }
 
+func TestParseJSONRefactorings(t *testing.T) {
+	testData := `{
+		"format_version": "1.2",
+		"terraform_version": "1.7.0",
+		"planned_values": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "aws_eip.renamed",
+						"mode": "managed",
+						"type": "aws_eip",
+						"name": "renamed",
+						"values": {}
+					},
+					{
+						"address": "aws_eip.imported",
+						"mode": "managed",
+						"type": "aws_eip",
+						"name": "imported",
+						"values": {}
+					}
+				]
+			}
+		},
+		"resource_changes": [
+			{
+				"address": "aws_eip.renamed",
+				"previous_address": "aws_eip.original",
+				"change": {"actions": ["no-op"]}
+			},
+			{
+				"address": "aws_eip.imported",
+				"change": {"actions": ["no-op"], "importing": {"id": "eipalloc-123"}}
+			},
+			{
+				"address": "aws_eip.kept",
+				"change": {"actions": ["forget"]}
+			}
+		]
+	}`
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, map[string]interface{}{}), true)
+
+	parsed, err := p.parseJSON([]byte(testData), schema.UsageMap{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws_eip.kept"}, parsed.RemovedResources)
+
+	resources := make(map[string]*schema.Resource)
+	for _, pr := range parsed.CurrentResources {
+		r := schema.BuildResource(pr, nil)
+		resources[r.Name] = r
+	}
+
+	require.Len(t, resources, 2)
+	assert.Equal(t, "aws_eip.original", resources["aws_eip.renamed"].PreviousAddress())
+	assert.False(t, resources["aws_eip.renamed"].IsImported())
+	assert.Equal(t, "", resources["aws_eip.imported"].PreviousAddress())
+	assert.True(t, resources["aws_eip.imported"].IsImported())
+}
+
+func TestParseJSONStripsBuiltinResources(t *testing.T) {
+	testData := `{
+		"format_version": "1.2",
+		"terraform_version": "1.9.0",
+		"planned_values": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "terraform_data.replacement",
+						"mode": "managed",
+						"type": "terraform_data",
+						"name": "replacement",
+						"values": {"input": "v1"}
+					},
+					{
+						"address": "aws_eip.ip",
+						"mode": "managed",
+						"type": "aws_eip",
+						"name": "ip",
+						"values": {}
+					}
+				]
+			}
+		}
+	}`
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, map[string]interface{}{}), true)
+
+	parsed, err := p.parseJSON([]byte(testData), schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, parsed.CurrentResources, 1)
+	assert.Equal(t, "aws_eip.ip", parsed.CurrentResources[0].Address)
+}
+
 func TestCreateResource(t *testing.T) {
 	tests := []struct {
 		data     *schema.ResourceData
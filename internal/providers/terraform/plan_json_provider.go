@@ -96,6 +96,7 @@ func (p *PlanJSONProvider) LoadResourcesFromSrc(usage schema.UsageMap, j []byte,
 	}
 
 	project.AddProviderMetadata(parsedConf.ProviderMetadata)
+	project.Metadata.RemovedResources = parsedConf.RemovedResources
 
 	project.PartialPastResources = parsedConf.PastResources
 	project.PartialResources = parsedConf.CurrentResources
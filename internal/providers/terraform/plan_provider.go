@@ -70,6 +70,7 @@ func (p *PlanProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project,
 	}
 
 	project.AddProviderMetadata(parsedConf.ProviderMetadata)
+	project.Metadata.RemovedResources = parsedConf.RemovedResources
 
 	project.PartialPastResources = parsedConf.PastResources
 	project.PartialResources = parsedConf.CurrentResources
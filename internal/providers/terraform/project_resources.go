@@ -0,0 +1,45 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ProjectResourceData evaluates the Terraform project at path and returns the
+// data of its resources, so that other providers can price their resources
+// using the resources of a Terraform project, e.g. the node pools of a
+// Kubernetes cluster.
+func ProjectResourceData(ctx *config.ProjectContext, path string) ([]*schema.ResourceData, error) {
+	projectCfg := projectConfig(ctx, path)
+
+	projectCtx := config.NewProjectContext(ctx.RunContext, projectCfg, nil)
+	provider, err := NewHCLProvider(projectCtx, hcl.RootPath{Path: path, RepoPath: ctx.RunContext.Config.RepoPath()}, &HCLProviderConfig{SuppressLogging: true})
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := provider.LoadResources(schema.UsageMap{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return nil, errors.New("no project found")
+	}
+
+	project := projects[0]
+	if len(project.Metadata.Errors) > 0 {
+		return nil, fmt.Errorf("could not evaluate Terraform project %s: %s", path, project.Metadata.Errors[0].Message)
+	}
+
+	data := make([]*schema.ResourceData, 0, len(project.PartialResources))
+	for _, partial := range project.PartialResources {
+		data = append(data, partial.ResourceData)
+	}
+
+	return data, nil
+}
@@ -107,3 +107,41 @@ func createFreeResources(l []string, defaultRefsFunc schema.ReferenceIDFunc, res
 	}
 	return freeResources
 }
+
+// NewPartialResourceFromData creates a partial resource from d using the
+// registry, it uses d.UsageData for any usage values. This is used to rebuild
+// resources with modified attributes, so it returns nil for free and
+// unsupported resources.
+func NewPartialResourceFromData(d *schema.ResourceData) *schema.PartialResource {
+	item, ok := (*GetResourceRegistryMap())[d.Type]
+	if !ok || item.NoPrice {
+		return nil
+	}
+
+	if item.CoreRFunc != nil {
+		coreRes := item.CoreRFunc(d)
+		if coreRes == nil {
+			return nil
+		}
+
+		return schema.NewPartialResource(d, nil, coreRes, nil)
+	}
+
+	res := item.RFunc(d, d.UsageData)
+	if res == nil {
+		return nil
+	}
+
+	return schema.NewPartialResource(d, res, nil, nil)
+}
+
+// BuildResourceFromData builds a resource from d using the registry. It returns
+// nil for free and unsupported resources.
+func BuildResourceFromData(d *schema.ResourceData) *schema.Resource {
+	partial := NewPartialResourceFromData(d)
+	if partial == nil {
+		return nil
+	}
+
+	return schema.BuildResource(partial, nil)
+}
@@ -0,0 +1,115 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	infSync "github.com/infracost/infracost/internal/sync"
+)
+
+// projectOutputCache holds the evaluated outputs of the projects that are used
+// to resolve terraform_remote_state data sources. This is global so that a
+// project only has to be evaluated once, even if it's used by many others.
+var projectOutputCache = &TerragruntOutputCache{
+	cache: sync.Map{},
+	mu:    &infSync.KeyMutex{},
+}
+
+// remoteStates converts the terraform_remote_states in the project config into
+// hcl.RemoteStates. projectPath is the path of the project that the state files
+// are relative to.
+func remoteStates(ctx *config.ProjectContext, projectPath string) ([]hcl.RemoteState, error) {
+	var states []hcl.RemoteState
+
+	for i, rs := range ctx.ProjectConfig.TerraformRemoteStates {
+		state := hcl.RemoteState{
+			Backend: rs.Backend,
+			Config:  rs.Config,
+		}
+
+		switch {
+		case rs.StateFile != "" && rs.Path != "":
+			return nil, fmt.Errorf("terraform_remote_states[%d] must set only one of state_file or path", i)
+		case rs.StateFile != "":
+			stateFile := rs.StateFile
+			if !filepath.IsAbs(stateFile) {
+				stateFile = filepath.Join(projectPath, stateFile)
+			}
+
+			state.Outputs = func() (cty.Value, error) {
+				return hcl.LoadStateOutputs(stateFile)
+			}
+		case rs.Path != "":
+			path := rs.Path
+			state.Outputs = func() (cty.Value, error) {
+				return projectOutputs(ctx, path)
+			}
+		default:
+			return nil, fmt.Errorf("terraform_remote_states[%d] must set one of state_file or path", i)
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// projectOutputs evaluates the Terraform project at path and returns its root
+// module outputs. If path is a project in the config file, its config is used
+// to evaluate it, e.g. so that it uses the same var files as in the main run.
+func projectOutputs(ctx *config.ProjectContext, path string) (cty.Value, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("could not resolve project path %s: %w", path, err)
+	}
+
+	return projectOutputCache.Set(abs, func() (cty.Value, error) {
+		projectCfg := projectConfig(ctx, path)
+
+		// only keep the remote states that are read from files, so that projects
+		// that read each other's outputs can't cause evaluation to loop forever.
+		var fileStates []config.TerraformRemoteState
+		for _, rs := range projectCfg.TerraformRemoteStates {
+			if rs.Path == "" {
+				fileStates = append(fileStates, rs)
+			}
+		}
+		projectCfg.TerraformRemoteStates = fileStates
+
+		projectCtx := config.NewProjectContext(ctx.RunContext, projectCfg, nil)
+		provider, err := NewHCLProvider(projectCtx, hcl.RootPath{Path: path, RepoPath: ctx.RunContext.Config.RepoPath()}, &HCLProviderConfig{SuppressLogging: true})
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		parsed := provider.Module()
+		if parsed.Error != nil {
+			return cty.NilVal, parsed.Error
+		}
+
+		if parsed.Module == nil {
+			return cty.NilVal, errors.New("no module found")
+		}
+
+		return parsed.Module.Blocks.Outputs(true), nil
+	})
+}
+
+// projectConfig returns a copy of the config of the project at path, or a
+// config with only the path set if path isn't a project in the config file.
+func projectConfig(ctx *config.ProjectContext, path string) *config.Project {
+	for _, p := range ctx.RunContext.Config.Projects {
+		if filepath.Clean(p.Path) == filepath.Clean(path) {
+			cp := *p
+			return &cp
+		}
+	}
+
+	return &config.Project{Path: path}
+}
@@ -0,0 +1,261 @@
+package terraform
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tgconfigstack "github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TerragruntUnit is a directory containing a Terragrunt config file that is
+// evaluated as its own Terraform project.
+type TerragruntUnit struct {
+	// Path is the directory of the unit, relative to the TerragruntGraph Path.
+	Path string `json:"path"`
+	// Dependencies are the paths of the units whose outputs this unit reads
+	// with dependency blocks, relative to the TerragruntGraph Path.
+	Dependencies []string `json:"dependencies"`
+	// Cycle is true if the unit depends on itself through its dependencies.
+	// Units in a cycle can't all be evaluated before each other so any
+	// outputs of units in the cycle that haven't been evaluated are mocked.
+	Cycle bool `json:"cycle,omitempty"`
+	// Excluded is true if the unit is excluded from the run, e.g. by the
+	// exclude_paths of the project.
+	Excluded bool `json:"excluded,omitempty"`
+
+	module *tgconfigstack.TerraformModule
+	deps   []*TerragruntUnit
+
+	evaluated bool
+	outputs   cty.Value
+	err       error
+}
+
+// TerragruntGraph is the dependency graph of the units in a Terragrunt
+// directory.
+type TerragruntGraph struct {
+	// Path is the root directory of the Terragrunt project.
+	Path string `json:"path"`
+	// Units are ordered so that each unit comes after the units it depends
+	// on, apart from units in a cycle.
+	Units []*TerragruntUnit `json:"units"`
+
+	byConfigPath map[string]*TerragruntUnit
+}
+
+// newTerragruntGraph builds the TerragruntGraph from the modules of a Terragrunt
+// stack. The order of the units is deterministic: it only depends on the paths
+// of the modules and their dependencies.
+func newTerragruntGraph(path string, modules []*tgconfigstack.TerraformModule) *TerragruntGraph {
+	sorted := make([]*tgconfigstack.TerraformModule, len(modules))
+	copy(sorted, modules)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	g := &TerragruntGraph{
+		Path:         path,
+		byConfigPath: make(map[string]*TerragruntUnit, len(modules)),
+	}
+
+	units := make([]*TerragruntUnit, 0, len(sorted))
+	byPath := make(map[string]*TerragruntUnit, len(sorted))
+	for _, mod := range sorted {
+		u := &TerragruntUnit{
+			Path:         g.relPath(mod.Path),
+			Dependencies: []string{},
+			Excluded:     mod.FlagExcluded,
+			module:       mod,
+		}
+
+		units = append(units, u)
+		byPath[mod.Path] = u
+		g.byConfigPath[mod.TerragruntOptions.TerragruntConfigPath] = u
+	}
+
+	for _, u := range units {
+		for _, dep := range u.module.Dependencies {
+			u.Dependencies = append(u.Dependencies, g.relPath(dep.Path))
+
+			// external dependencies aren't part of the stack.
+			if d, ok := byPath[dep.Path]; ok {
+				u.deps = append(u.deps, d)
+			}
+		}
+		sort.Strings(u.Dependencies)
+		sort.Slice(u.deps, func(i, j int) bool {
+			return u.deps[i].Path < u.deps[j].Path
+		})
+	}
+
+	for _, component := range stronglyConnectedUnits(units) {
+		if len(component) > 1 {
+			for _, u := range component {
+				u.Cycle = true
+			}
+		} else if u := component[0]; containsString(u.Dependencies, u.Path) {
+			u.Cycle = true
+		}
+
+		g.Units = append(g.Units, component...)
+	}
+
+	return g
+}
+
+// MergeTerragruntGraphs returns a single TerragruntGraph with all the units in
+// graphs, with paths relative to path. Terragrunt directories are detected as a
+// project per unit, each with a graph of the unit and its dependencies, so this
+// is used to show the units of all the projects in a directory together.
+func MergeTerragruntGraphs(path string, graphs ...*TerragruntGraph) *TerragruntGraph {
+	seen := map[string]struct{}{}
+	var modules []*tgconfigstack.TerraformModule
+
+	for _, g := range graphs {
+		for _, u := range g.Units {
+			if _, ok := seen[u.module.Path]; ok {
+				continue
+			}
+
+			seen[u.module.Path] = struct{}{}
+			modules = append(modules, u.module)
+		}
+	}
+
+	return newTerragruntGraph(path, modules)
+}
+
+// stronglyConnectedUnits returns the strongly connected components of the
+// units using Tarjan's algorithm. As the edges point from a unit to its
+// dependencies, each component is returned after all the components it
+// depends on, which is the order the units need to be evaluated in. A
+// component with more than one unit is a dependency cycle.
+func stronglyConnectedUnits(units []*TerragruntUnit) [][]*TerragruntUnit {
+	var (
+		index      int
+		stack      []*TerragruntUnit
+		indexes    = map[*TerragruntUnit]int{}
+		lowLinks   = map[*TerragruntUnit]int{}
+		onStack    = map[*TerragruntUnit]bool{}
+		components [][]*TerragruntUnit
+	)
+
+	var visit func(u *TerragruntUnit)
+	visit = func(u *TerragruntUnit) {
+		indexes[u] = index
+		lowLinks[u] = index
+		index++
+		stack = append(stack, u)
+		onStack[u] = true
+
+		for _, dep := range u.deps {
+			if _, visited := indexes[dep]; !visited {
+				visit(dep)
+				if lowLinks[dep] < lowLinks[u] {
+					lowLinks[u] = lowLinks[dep]
+				}
+			} else if onStack[dep] && indexes[dep] < lowLinks[u] {
+				lowLinks[u] = indexes[dep]
+			}
+		}
+
+		if lowLinks[u] != indexes[u] {
+			return
+		}
+
+		var component []*TerragruntUnit
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+
+			if last == u {
+				break
+			}
+		}
+
+		sort.Slice(component, func(i, j int) bool {
+			return component[i].Path < component[j].Path
+		})
+		components = append(components, component)
+	}
+
+	for _, u := range units {
+		if _, visited := indexes[u]; !visited {
+			visit(u)
+		}
+	}
+
+	return components
+}
+
+// unit returns the unit with the Terragrunt config file at configPath.
+func (g *TerragruntGraph) unit(configPath string) *TerragruntUnit {
+	if g == nil {
+		return nil
+	}
+
+	return g.byConfigPath[configPath]
+}
+
+// Cycles returns the paths of the units that are in a dependency cycle.
+func (g *TerragruntGraph) Cycles() []string {
+	var paths []string
+	for _, u := range g.Units {
+		if u.Cycle {
+			paths = append(paths, u.Path)
+		}
+	}
+
+	return paths
+}
+
+// String renders the graph as a list of units in evaluation order, each
+// followed by the units it depends on.
+func (g *TerragruntGraph) String() string {
+	var b strings.Builder
+
+	for _, u := range g.Units {
+		b.WriteString(u.Path)
+
+		var labels []string
+		if u.Cycle {
+			labels = append(labels, "cycle")
+		}
+		if u.Excluded {
+			labels = append(labels, "excluded")
+		}
+		if len(labels) > 0 {
+			b.WriteString(fmt.Sprintf(" (%s)", strings.Join(labels, ", ")))
+		}
+		b.WriteString("\n")
+
+		for i, dep := range u.Dependencies {
+			prefix := "├─"
+			if i == len(u.Dependencies)-1 {
+				prefix = "└─"
+			}
+			b.WriteString(fmt.Sprintf("  %s %s\n", prefix, dep))
+		}
+	}
+
+	return b.String()
+}
+
+func (g *TerragruntGraph) relPath(path string) string {
+	base, err := filepath.Abs(g.Path)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
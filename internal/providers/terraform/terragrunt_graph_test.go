@@ -0,0 +1,104 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+
+	tgconfigstack "github.com/gruntwork-io/terragrunt/configstack"
+	tgoptions "github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTerragruntModule(path string, dependencies ...*tgconfigstack.TerraformModule) *tgconfigstack.TerraformModule {
+	return &tgconfigstack.TerraformModule{
+		Path:         path,
+		Dependencies: dependencies,
+		TerragruntOptions: &tgoptions.TerragruntOptions{
+			TerragruntConfigPath: filepath.Join(path, "terragrunt.hcl"),
+		},
+	}
+}
+
+func unitPaths(units []*TerragruntUnit) []string {
+	paths := make([]string, 0, len(units))
+	for _, u := range units {
+		paths = append(paths, u.Path)
+	}
+
+	return paths
+}
+
+func TestNewTerragruntGraphOrder(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "live")
+
+	network := newTestTerragruntModule(filepath.Join(root, "network"))
+	db := newTestTerragruntModule(filepath.Join(root, "db"), network)
+	app := newTestTerragruntModule(filepath.Join(root, "app"), network)
+	web := newTestTerragruntModule(filepath.Join(root, "web"), db, app)
+
+	g := newTerragruntGraph(root, []*tgconfigstack.TerraformModule{web, db, network, app})
+
+	assert.Equal(t, []string{"network", "app", "db", "web"}, unitPaths(g.Units))
+	assert.Empty(t, g.Cycles())
+
+	u := g.unit(filepath.Join(root, "web", "terragrunt.hcl"))
+	require.NotNil(t, u)
+	assert.Equal(t, []string{"app", "db"}, u.Dependencies)
+	assert.Equal(t, []string{"app", "db"}, unitPaths(u.deps))
+}
+
+func TestNewTerragruntGraphSelfDependency(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "live")
+
+	network := newTestTerragruntModule(filepath.Join(root, "network"))
+	app := newTestTerragruntModule(filepath.Join(root, "app"), network)
+	app.Dependencies = append(app.Dependencies, app)
+
+	g := newTerragruntGraph(root, []*tgconfigstack.TerraformModule{app, network})
+
+	assert.Equal(t, []string{"network", "app"}, unitPaths(g.Units))
+	assert.Equal(t, []string{"app"}, g.Cycles())
+	assert.Equal(t, []string{"app", "network"}, g.Units[1].Dependencies)
+}
+
+func TestNewTerragruntGraphCycle(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "live")
+
+	network := newTestTerragruntModule(filepath.Join(root, "network"))
+	app := newTestTerragruntModule(filepath.Join(root, "app"), network)
+	queue := newTestTerragruntModule(filepath.Join(root, "queue"), network)
+	worker := newTestTerragruntModule(filepath.Join(root, "worker"), queue)
+	events := newTestTerragruntModule(filepath.Join(root, "events"), worker)
+	queue.Dependencies = append(queue.Dependencies, events)
+	api := newTestTerragruntModule(filepath.Join(root, "api"), worker, app)
+
+	g := newTerragruntGraph(root, []*tgconfigstack.TerraformModule{worker, queue, network, events, app, api})
+
+	assert.Equal(t, []string{"network", "app", "events", "queue", "worker", "api"}, unitPaths(g.Units))
+	assert.Equal(t, []string{"events", "queue", "worker"}, g.Cycles())
+
+	components := stronglyConnectedUnits(g.Units)
+	paths := make([][]string, 0, len(components))
+	for _, c := range components {
+		paths = append(paths, unitPaths(c))
+	}
+	assert.Equal(t, [][]string{{"network"}, {"app"}, {"events", "queue", "worker"}, {"api"}}, paths)
+}
+
+func TestNewTerragruntGraphExternalDependency(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "live")
+
+	vpc := newTestTerragruntModule(filepath.Join(dir, "shared", "vpc"))
+	app := newTestTerragruntModule(filepath.Join(root, "app"), vpc)
+
+	g := newTerragruntGraph(root, []*tgconfigstack.TerraformModule{app})
+
+	require.Len(t, g.Units, 1)
+	assert.Equal(t, "app", g.Units[0].Path)
+	assert.Equal(t, []string{filepath.Join("..", "shared", "vpc")}, g.Units[0].Dependencies)
+	assert.Empty(t, g.Units[0].deps)
+	assert.Empty(t, g.Cycles())
+	assert.Nil(t, g.unit(filepath.Join(dir, "shared", "vpc", "terragrunt.hcl")))
+}
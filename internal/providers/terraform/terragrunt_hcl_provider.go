@@ -102,10 +102,15 @@ type TerragruntHCLProvider struct {
 	ctx           *config.ProjectContext
 	Path          hcl.RootPath
 	stack         *tgconfigstack.Stack
+	graph         *TerragruntGraph
 	excludedPaths []string
 	env           map[string]string
 	sourceCache   map[string]string
 	logger        zerolog.Logger
+
+	// evaluating holds the Terragrunt config paths of the units that are
+	// being evaluated, so that a dependency cycle isn't evaluated forever.
+	evaluating map[string]struct{}
 }
 
 // NewTerragruntHCLProvider creates a new provider intialized with the configured project path (usually the terragrunt
@@ -121,6 +126,7 @@ func NewTerragruntHCLProvider(rootPath hcl.RootPath, ctx *config.ProjectContext)
 		excludedPaths: ctx.ProjectConfig.ExcludePaths,
 		env:           getEnvVars(ctx),
 		sourceCache:   map[string]string{},
+		evaluating:    map[string]struct{}{},
 		logger:        logger,
 	}
 }
@@ -232,6 +238,21 @@ func (i *terragruntWorkingDirInfo) addWarning(pd *schema.ProjectDiag) {
 	i.warnings = append(i.warnings, pd)
 }
 
+// outputs returns the evaluated outputs of the working dir, or an error if it
+// couldn't be evaluated. A nil info is a skipped working dir, which has no
+// outputs.
+func (i *terragruntWorkingDirInfo) outputs() (cty.Value, error) {
+	if i == nil {
+		return cty.EmptyObjectVal, nil
+	}
+
+	if i.error != nil {
+		return cty.NilVal, i.error
+	}
+
+	return i.evaluatedOutputs, nil
+}
+
 // LoadResources finds any Terragrunt projects, prepares them by downloading any required source files, then
 // process each with an HCLProvider.
 func (p *TerragruntHCLProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
@@ -390,21 +411,30 @@ func (p *TerragruntHCLProvider) initTerraformVars(tfVars map[string]string, inpu
 	return m
 }
 
-func (p *TerragruntHCLProvider) prepWorkingDirs() ([]*terragruntWorkingDirInfo, error) {
-	terragruntConfigPath := tgconfig.GetDefaultConfigPath(p.Path.Path)
+// Graph returns the dependency graph of the Terragrunt units in the project
+// path without evaluating them.
+func (p *TerragruntHCLProvider) Graph() (*TerragruntGraph, error) {
+	opts := p.newTerragruntOptions()
+	opts.RunTerragrunt = func(opts *tgoptions.TerragruntOptions) error {
+		return nil
+	}
 
-	terragruntCacheDir := filepath.Join(config.InfracostDir, ".terragrunt-cache")
-	terragruntDownloadDir := filepath.Join(p.ctx.RunContext.Config.CachePath(), terragruntCacheDir)
-	err := os.MkdirAll(terragruntDownloadDir, os.ModePerm)
+	err := p.initGraph(opts)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create download directories for terragrunt in working directory: %w", err)
+		return nil, err
 	}
 
-	mu := sync.Mutex{}
-	var workingDirsToEstimate []*terragruntWorkingDirInfo
+	return p.graph, nil
+}
+
+func (p *TerragruntHCLProvider) newTerragruntOptions() *tgoptions.TerragruntOptions {
+	terragruntConfigPath := tgconfig.GetDefaultConfigPath(p.Path.Path)
+
+	terragruntCacheDir := filepath.Join(config.InfracostDir, ".terragrunt-cache")
+	terragruntDownloadDir := filepath.Join(p.ctx.RunContext.Config.CachePath(), terragruntCacheDir)
 
 	tgLog := logrus.StandardLogger().WithFields(logrus.Fields{"library": "terragrunt"})
-	terragruntOptions := &tgoptions.TerragruntOptions{
+	return &tgoptions.TerragruntOptions{
 		TerragruntConfigPath:       terragruntConfigPath,
 		Logger:                     tgLog,
 		LogLevel:                   logrus.DebugLevel,
@@ -417,36 +447,6 @@ func (p *TerragruntHCLProvider) prepWorkingDirs() ([]*terragruntWorkingDirInfo,
 		Env:                        p.env,
 		IgnoreExternalDependencies: true,
 		SourceMap:                  p.ctx.RunContext.Config.TerraformSourceMap,
-		RunTerragrunt: func(opts *tgoptions.TerragruntOptions) (err error) {
-			defer func() {
-				unexpectedErr := recover()
-				if unexpectedErr != nil {
-					err = panicError{msg: fmt.Sprintf("%s\n%s", unexpectedErr, debug.Stack())}
-					mu.Lock()
-					workingDirsToEstimate = append(
-						workingDirsToEstimate,
-						&terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: opts.WorkingDir, error: err},
-					)
-					mu.Unlock()
-				}
-			}()
-
-			workingDirInfo := p.runTerragrunt(opts)
-			_, _ = terragruntOutputCache.Set(opts.TerragruntConfigPath, func() (cty.Value, error) {
-				if workingDirInfo == nil {
-					return cty.EmptyObjectVal, errors.New("nil outputs")
-				}
-
-				return workingDirInfo.evaluatedOutputs, nil
-			})
-			if workingDirInfo != nil {
-				mu.Lock()
-				workingDirsToEstimate = append(workingDirsToEstimate, workingDirInfo)
-				mu.Unlock()
-			}
-
-			return
-		},
 		Functions: func(baseDir string) map[string]function.Function {
 			funcs := hcl.ExpFunctions(baseDir, p.logger)
 
@@ -457,27 +457,98 @@ func (p *TerragruntHCLProvider) prepWorkingDirs() ([]*terragruntWorkingDirInfo,
 		},
 		Parallelism: 1,
 	}
+}
 
+// initGraph resolves the Terragrunt stack for the project path and builds the
+// dependency graph of its units.
+func (p *TerragruntHCLProvider) initGraph(terragruntOptions *tgoptions.TerragruntOptions) error {
 	howThesePathsWereFound := fmt.Sprintf("Terragrunt config file found in a subdirectory of %s", terragruntOptions.WorkingDir)
 	s, err := createStackForTerragruntConfigPaths(terragruntOptions.WorkingDir, []string{
-		terragruntConfigPath,
+		terragruntOptions.TerragruntConfigPath,
 	}, terragruntOptions, howThesePathsWereFound)
 	if err != nil {
-		return nil, err
+		return err
 	}
+
 	p.stack = s
+	p.graph = newTerragruntGraph(terragruntOptions.WorkingDir, s.Modules)
+
+	return nil
+}
+
+func newTerragruntParseError(err error) error {
+	return clierror.NewCLIError(
+		errors.Errorf(
+			"%s\n%v%s",
+			"Failed to parse the Terragrunt code using the Terragrunt library:",
+			err.Error(),
+			fmt.Sprintf("For a list of known issues and workarounds, see: %s", ui.LinkString("https://infracost.io/docs/features/terragrunt/")),
+		),
+		fmt.Sprintf("Error parsing the Terragrunt code using the Terragrunt library: %s", err),
+	)
+}
 
-	err = s.Run(terragruntOptions)
+func (p *TerragruntHCLProvider) prepWorkingDirs() ([]*terragruntWorkingDirInfo, error) {
+	terragruntOptions := p.newTerragruntOptions()
+	err := os.MkdirAll(terragruntOptions.DownloadDir, os.ModePerm)
 	if err != nil {
-		return nil, clierror.NewCLIError(
-			errors.Errorf(
-				"%s\n%v%s",
-				"Failed to parse the Terragrunt code using the Terragrunt library:",
-				err.Error(),
-				fmt.Sprintf("For a list of known issues and workarounds, see: %s", ui.LinkString("https://infracost.io/docs/features/terragrunt/")),
-			),
-			fmt.Sprintf("Error parsing the Terragrunt code using the Terragrunt library: %s", err),
-		)
+		return nil, fmt.Errorf("Failed to create download directories for terragrunt in working directory: %w", err)
+	}
+
+	var workingDirsToEstimate []*terragruntWorkingDirInfo
+
+	terragruntOptions.RunTerragrunt = func(opts *tgoptions.TerragruntOptions) (err error) {
+		defer func() {
+			unexpectedErr := recover()
+			if unexpectedErr != nil {
+				err = panicError{msg: fmt.Sprintf("%s\n%s", unexpectedErr, debug.Stack())}
+				workingDirsToEstimate = append(
+					workingDirsToEstimate,
+					&terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: opts.WorkingDir, error: err},
+				)
+			}
+		}()
+
+		workingDirInfo := p.runTerragrunt(opts)
+		if unit := p.graph.unit(opts.TerragruntConfigPath); unit != nil {
+			unit.evaluated = true
+			unit.outputs, unit.err = workingDirInfo.outputs()
+		}
+
+		_, _ = terragruntOutputCache.Set(opts.TerragruntConfigPath, workingDirInfo.outputs)
+		if workingDirInfo != nil {
+			workingDirsToEstimate = append(workingDirsToEstimate, workingDirInfo)
+		}
+
+		return
+	}
+
+	err = p.initGraph(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if cycles := p.graph.Cycles(); len(cycles) > 0 {
+		p.logger.Warn().Msgf("Terragrunt dependency cycle found between %s, the outputs of these dependencies will be mocked until they are evaluated", strings.Join(cycles, ", "))
+	}
+
+	// Units are evaluated one at a time in dependency order so that the
+	// outputs of each unit have been evaluated before any unit that uses them.
+	var errs []string
+	for _, unit := range p.graph.Units {
+		if unit.Excluded || unit.module.AssumeAlreadyApplied {
+			continue
+		}
+
+		err := terragruntOptions.RunTerragrunt(unit.module.TerragruntOptions)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", unit.Path, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		err := errors.New(strings.Join(errs, "\n"))
+		return nil, newTerragruntParseError(err)
 	}
 
 	return workingDirsToEstimate, nil
@@ -493,8 +564,11 @@ func (p *TerragruntHCLProvider) prepWorkingDirs() ([]*terragruntWorkingDirInfo,
 //  3. we then evaluate the Terraform project built by Terragrunt storing any outputs so that we can use
 //     these for further runTerragrunt calls that use the dependency outputs.
 func (p *TerragruntHCLProvider) runTerragrunt(opts *tgoptions.TerragruntOptions) (info *terragruntWorkingDirInfo) {
+	p.evaluating[opts.TerragruntConfigPath] = struct{}{}
+	defer delete(p.evaluating, opts.TerragruntConfigPath)
+
 	info = &terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: opts.WorkingDir}
-	outputs := p.fetchDependencyOutputs(opts)
+	outputs := p.fetchDependencyOutputs(opts, info)
 	terragruntConfig, err := tgconfig.ParseConfigFile(opts.TerragruntConfigPath, opts, nil, &outputs)
 	if err != nil {
 		info.error = err
@@ -558,7 +632,7 @@ func (p *TerragruntHCLProvider) runTerragrunt(opts *tgoptions.TerragruntOptions)
 		}
 
 		if updatedWorkingDir != "" {
-			info = &terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: updatedWorkingDir}
+			info = &terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: updatedWorkingDir, warnings: info.warnings}
 		}
 	}
 
@@ -712,8 +786,8 @@ var (
 	mapRegexp   = regexp.MustCompile(`\["([\w\d]+)"]`)
 )
 
-func (p *TerragruntHCLProvider) fetchDependencyOutputs(opts *tgoptions.TerragruntOptions) cty.Value {
-	moduleOutputs, err := p.fetchModuleOutputs(opts)
+func (p *TerragruntHCLProvider) fetchDependencyOutputs(opts *tgoptions.TerragruntOptions, info *terragruntWorkingDirInfo) cty.Value {
+	moduleOutputs, err := p.fetchModuleOutputs(opts, info)
 	if err != nil {
 		p.logger.Debug().Err(err).Msg("failed to fetch real module outputs, defaulting to mocked outputs from file regexp")
 	}
@@ -906,7 +980,8 @@ func mergeListWithDependencyMap(valueMap map[string]cty.Value, pieces []string,
 }
 
 // fetchModuleOutputs returns the Terraform outputs from the dependencies of Terragrunt file provided in the opts input.
-func (p *TerragruntHCLProvider) fetchModuleOutputs(opts *tgoptions.TerragruntOptions) (cty.Value, error) {
+// Dependencies that can't be evaluated are added to the warnings of info, as their outputs are mocked.
+func (p *TerragruntHCLProvider) fetchModuleOutputs(opts *tgoptions.TerragruntOptions, info *terragruntWorkingDirInfo) (cty.Value, error) {
 	outputs := cty.MapVal(map[string]cty.Value{
 		"outputs": cty.ObjectVal(map[string]cty.Value{
 			"mock": cty.StringVal("val"),
@@ -929,21 +1004,15 @@ func (p *TerragruntHCLProvider) fetchModuleOutputs(opts *tgoptions.TerragruntOpt
 			}
 
 			out := map[string]cty.Value{}
+			var mocked []string
 			for dir, dep := range blocks {
-				value, depErr := terragruntOutputCache.Set(dir, func() (cty.Value, error) {
-					info := p.runTerragrunt(opts.Clone(dir))
-					if info != nil && info.error != nil {
-						return cty.NilVal, fmt.Errorf("could not evaluate dependency %s at dir %s err: %w", dep.Name, dir, err)
-					}
-
-					if info == nil {
-						return cty.EmptyObjectVal, nil
-					}
-
-					return info.evaluatedOutputs, nil
-				})
+				value, depErr := p.dependencyOutputs(opts, dir)
 				if depErr != nil {
-					return outputs, depErr
+					// leave the dependency out so that any of its outputs that are
+					// used are mocked.
+					p.logger.Debug().Err(depErr).Msgf("could not evaluate dependency %s at dir %s, using mocked outputs", dep.Name, dir)
+					mocked = append(mocked, dep.Name)
+					continue
 				}
 
 				out[dep.Name] = cty.MapVal(map[string]cty.Value{
@@ -951,6 +1020,11 @@ func (p *TerragruntHCLProvider) fetchModuleOutputs(opts *tgoptions.TerragruntOpt
 				})
 			}
 
+			if len(mocked) > 0 {
+				sort.Strings(mocked)
+				info.addWarning(schema.NewDiagMockedDependencyOutputs(mocked...))
+			}
+
 			if len(out) > 0 {
 				encoded, err := toCtyValue(out, generateTypeFromValuesMap(out))
 				if err == nil {
@@ -965,6 +1039,30 @@ func (p *TerragruntHCLProvider) fetchModuleOutputs(opts *tgoptions.TerragruntOpt
 	return outputs, nil
 }
 
+// dependencyOutputs returns the evaluated outputs of the dependency with the
+// Terragrunt config file at configPath. Units in the graph have already been
+// evaluated if they're a dependency of opts, unless they are in a dependency
+// cycle with it, in which case their outputs aren't available yet.
+func (p *TerragruntHCLProvider) dependencyOutputs(opts *tgoptions.TerragruntOptions, configPath string) (cty.Value, error) {
+	if unit := p.graph.unit(configPath); unit != nil {
+		if unit.evaluated {
+			return unit.outputs, unit.err
+		}
+
+		if unit.Cycle {
+			return cty.NilVal, fmt.Errorf("dependency %s is in a dependency cycle and has not been evaluated yet", unit.Path)
+		}
+	}
+
+	if _, ok := p.evaluating[configPath]; ok {
+		return cty.NilVal, fmt.Errorf("dependency %s depends on itself", configPath)
+	}
+
+	return terragruntOutputCache.Set(configPath, func() (cty.Value, error) {
+		return p.runTerragrunt(opts.Clone(configPath)).outputs()
+	})
+}
+
 func toCtyValue(val map[string]cty.Value, ty cty.Type) (v cty.Value, err error) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -1006,12 +1104,8 @@ func createStackForTerragruntConfigPaths(path string, terragruntConfigPaths []st
 		return nil, err
 	}
 
-	stack := &tgconfigstack.Stack{Path: path, Modules: modules}
-	if err := stack.CheckForCycles(); err != nil {
-		return nil, err
-	}
-
-	return stack, nil
+	// cycles aren't an error, see newTerragruntGraph.
+	return &tgconfigstack.Stack{Path: path, Modules: modules}, nil
 }
 
 // decodeDependencyBlocks parses the file at filename and returns a map containing all the hcl blocks with the "dependency" label.
@@ -0,0 +1,67 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestTerragruntHCLProviderMockedDependencyWarning(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"modules/app/main.tf": `variable "subnet_id" {}
+
+resource "aws_instance" "app" {
+  ami           = "ami-674cbc1e"
+  instance_type = "t3.micro"
+  subnet_id     = var.subnet_id
+}
+`,
+		"network/terragrunt.hcl": `terraform {
+  source = "../modules//missing"
+}
+`,
+		"app/terragrunt.hcl": `terraform {
+  source = "../modules//app"
+}
+
+dependency "network" {
+  config_path = "../network"
+}
+
+inputs = {
+  subnet_id = dependency.network.outputs.subnet_id
+}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	runCtx, err := config.NewRunContextFromEnv(context.Background())
+	require.NoError(t, err)
+	runCtx.Config.RootPath = dir
+
+	appDir := filepath.Join(dir, "app")
+	ctx := config.NewProjectContext(runCtx, &config.Project{Path: appDir}, nil)
+	provider := NewTerragruntHCLProvider(hcl.RootPath{Path: appDir, IsTerragrunt: true}, ctx)
+
+	projects, err := provider.LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	require.Len(t, projects[0].Metadata.Warnings, 1)
+	assert.Equal(t, schema.NewDiagMockedDependencyOutputs("network"), projects[0].Metadata.Warnings[0])
+}
@@ -144,6 +144,7 @@ func (p *TerragruntProvider) LoadResources(usage schema.UsageMap) ([]*schema.Pro
 		project.HasDiff = !p.UseState
 		if project.HasDiff {
 			project.PartialPastResources = parsedConf.PastResources
+			project.Metadata.RemovedResources = parsedConf.RemovedResources
 		}
 		project.PartialResources = parsedConf.CurrentResources
 
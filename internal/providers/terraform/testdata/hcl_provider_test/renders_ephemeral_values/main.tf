@@ -0,0 +1,29 @@
+provider "aws" {
+  region = "us-east-1"
+}
+
+ephemeral "aws_secretsmanager_secret_version" "db" {
+  secret_id = "db-password"
+}
+
+resource "aws_db_instance" "db" {
+  instance_class      = "db.t3.micro"
+  engine              = "mysql"
+  password_wo         = ephemeral.aws_secretsmanager_secret_version.db.secret_string
+  password_wo_version = 1
+}
+
+resource "terraform_data" "replacement" {
+  input = aws_db_instance.db.instance_class
+}
+
+check "db" {
+  data "aws_db_instance" "db" {
+    db_instance_identifier = aws_db_instance.db.identifier
+  }
+
+  assert {
+    condition     = data.aws_db_instance.db.db_instance_status == "available"
+    error_message = "db is not available"
+  }
+}
@@ -0,0 +1,25 @@
+provider "aws" {
+  region = "us-east-1"
+}
+
+moved {
+  from = aws_eip.original
+  to   = aws_eip.renamed
+}
+
+import {
+  to = aws_eip.imported
+  id = "eipalloc-123"
+}
+
+removed {
+  from = aws_eip.kept
+
+  lifecycle {
+    destroy = false
+  }
+}
+
+resource "aws_eip" "renamed" {}
+
+resource "aws_eip" "imported" {}
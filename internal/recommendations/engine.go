@@ -0,0 +1,212 @@
+// Package recommendations generates cost optimisation recommendations for
+// resources using a set of local rules. Each rule looks at the parsed resource
+// data and, where possible, suggests an attribute change which is priced to
+// estimate the monthly saving.
+package recommendations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Rule checks a resource for a cost optimisation opportunity.
+type Rule struct {
+	ID          string
+	Title       string
+	Description string
+	// ResourceTypes are the resource types the rule is checked against.
+	ResourceTypes []string
+	// Check returns a Finding if the rule applies to d, otherwise nil. all
+	// contains the data of every resource in the project so rules can look at
+	// related resources.
+	Check func(d *schema.ResourceData, all []*schema.ResourceData) *Finding
+}
+
+// Finding is the result of a rule matching a resource.
+type Finding struct {
+	// Suggested is a short human readable description of the suggested change.
+	Suggested string
+	// Patch contains the attribute values to change on the resource. When set the
+	// resource is rebuilt and priced with these values to estimate the saving.
+	Patch map[string]interface{}
+	// Remove means the suggestion is to remove the resource, so the saving is the
+	// full cost of the resource, apart from KeepCostComponents.
+	Remove bool
+	// KeepCostComponents are the names of the cost components of a removed
+	// resource that aren't saved, e.g. because the usage moves to another
+	// resource.
+	KeepCostComponents []string
+}
+
+// BuildFunc builds a resource from resource data, it returns nil if the
+// resource can't be priced.
+type BuildFunc func(d *schema.ResourceData) *schema.Resource
+
+// PriceFunc populates the prices of the cost components of resources.
+type PriceFunc func(resources []*schema.Resource) error
+
+// Engine evaluates rules against the resources of a project.
+type Engine struct {
+	Rules []*Rule
+	Build BuildFunc
+	Price PriceFunc
+}
+
+// NewEngine returns an Engine with the default rules.
+func NewEngine(build BuildFunc, price PriceFunc) *Engine {
+	return &Engine{
+		Rules: DefaultRules(),
+		Build: build,
+		Price: price,
+	}
+}
+
+type candidate struct {
+	rule     *Rule
+	finding  *Finding
+	original *schema.Resource
+	patched  *schema.Resource
+}
+
+// Evaluate checks every rule against the resources of the project and returns
+// the recommendations sorted by the largest saving first. The project must have
+// been built and priced. Recommendations with a negative saving are dropped.
+func (e *Engine) Evaluate(project *schema.Project) (schema.Recommendations, error) {
+	all := make([]*schema.ResourceData, 0, len(project.PartialResources))
+	for _, partial := range project.PartialResources {
+		if partial.ResourceData != nil {
+			all = append(all, partial.ResourceData)
+		}
+	}
+
+	rulesByType := map[string][]*Rule{}
+	for _, rule := range e.Rules {
+		for _, t := range rule.ResourceTypes {
+			rulesByType[t] = append(rulesByType[t], rule)
+		}
+	}
+
+	var candidates []*candidate
+	var toPrice []*schema.Resource
+
+	for i, partial := range project.PartialResources {
+		d := partial.ResourceData
+		if d == nil || i >= len(project.Resources) {
+			continue
+		}
+
+		for _, rule := range rulesByType[d.Type] {
+			f := rule.Check(d, all)
+			if f == nil {
+				continue
+			}
+
+			c := &candidate{rule: rule, finding: f, original: project.Resources[i]}
+
+			if len(f.Patch) > 0 && e.Build != nil {
+				c.patched = e.Build(patchedData(partial, f.Patch))
+				if c.patched != nil {
+					toPrice = append(toPrice, c.patched)
+				}
+			}
+
+			candidates = append(candidates, c)
+		}
+	}
+
+	if len(toPrice) > 0 && e.Price != nil {
+		if err := e.Price(toPrice); err != nil {
+			return nil, fmt.Errorf("error pricing recommendations: %w", err)
+		}
+	}
+
+	recs := make(schema.Recommendations, 0, len(candidates))
+	for _, c := range candidates {
+		saving := c.saving()
+		if saving != nil && saving.IsNegative() {
+			continue
+		}
+
+		recs = append(recs, schema.Recommendation{
+			ID:            c.rule.ID,
+			Title:         c.rule.Title,
+			Description:   c.rule.Description,
+			ResourceType:  c.original.ResourceType,
+			Address:       c.original.Name,
+			Suggested:     c.finding.Suggested,
+			MonthlySaving: saving,
+		})
+	}
+
+	sort.Sort(recs)
+
+	return recs, nil
+}
+
+func (c *candidate) saving() *decimal.Decimal {
+	if c.original.MonthlyCost == nil {
+		return nil
+	}
+
+	if c.finding.Remove {
+		s := *c.original.MonthlyCost
+		for _, cc := range c.original.CostComponents {
+			if cc.MonthlyCost != nil && containsString(c.finding.KeepCostComponents, cc.Name) {
+				s = s.Sub(*cc.MonthlyCost)
+			}
+		}
+
+		return &s
+	}
+
+	if c.patched == nil {
+		return nil
+	}
+
+	c.patched.CalculateCosts()
+	if c.patched.MonthlyCost == nil {
+		return nil
+	}
+
+	s := c.original.MonthlyCost.Sub(*c.patched.MonthlyCost)
+	return &s
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// patchedData returns a copy of the resource data of partial with the patch
+// applied. The usage data of the copy includes any Infracost Cloud estimates so
+// the patched resource is priced with the same usage as the original.
+func patchedData(partial *schema.PartialResource, patch map[string]interface{}) *schema.ResourceData {
+	d := partial.ResourceData
+
+	c := &schema.ResourceData{
+		Type:          d.Type,
+		ProviderName:  d.ProviderName,
+		Address:       d.Address,
+		Tags:          d.Tags,
+		RawValues:     d.RawValues,
+		ReferencesMap: d.ReferencesMap,
+		CFResource:    d.CFResource,
+		UsageData:     partial.UsageData.Merge(partial.FetchedUsageData),
+		Metadata:      d.Metadata,
+	}
+
+	for k, v := range patch {
+		c.Set(k, v)
+	}
+
+	return c
+}
@@ -0,0 +1,238 @@
+package recommendations
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+// volumePrices are the stub prices used to build aws_ebs_volume resources.
+var volumePrices = map[string]decimal.Decimal{
+	"gp2": decimal.RequireFromString("0.1"),
+	"gp3": decimal.RequireFromString("0.08"),
+	"io1": decimal.RequireFromString("0.125"),
+}
+
+func buildVolume(d *schema.ResourceData) *schema.Resource {
+	t := d.GetStringOrDefault("type", "gp2")
+
+	return &schema.Resource{
+		Name:         d.Address,
+		ResourceType: d.Type,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Storage",
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(d.Get("size").Int())),
+				ProductFilter:   &schema.ProductFilter{Sku: testutil.StrPtr(t)},
+			},
+		},
+	}
+}
+
+func priceVolumes(resources []*schema.Resource) error {
+	for _, r := range resources {
+		for _, c := range r.CostComponents {
+			c.SetPrice(volumePrices[*c.ProductFilter.Sku])
+		}
+	}
+
+	return nil
+}
+
+func newProject(data ...*schema.ResourceData) *schema.Project {
+	project := schema.NewProject("test", &schema.ProjectMetadata{})
+
+	for _, d := range data {
+		partial := schema.NewPartialResource(d, buildVolume(d), nil, nil)
+		project.PartialResources = append(project.PartialResources, partial)
+		project.Resources = append(project.Resources, schema.BuildResource(partial, nil))
+	}
+
+	_ = priceVolumes(project.Resources)
+	schema.CalculateCosts(project)
+
+	return project
+}
+
+func newData(resourceType, address, raw string) *schema.ResourceData {
+	return schema.NewResourceData(resourceType, "aws", address, nil, gjson.Parse(raw))
+}
+
+func TestEvaluate(t *testing.T) {
+	project := newProject(
+		newData("aws_ebs_volume", "aws_ebs_volume.default", `{"size": 100}`),
+		newData("aws_ebs_volume", "aws_ebs_volume.gp2", `{"type": "gp2", "size": 500}`),
+		newData("aws_ebs_volume", "aws_ebs_volume.gp3", `{"type": "gp3", "size": 500}`),
+		newData("aws_eip", "aws_eip.unattached", `{}`),
+		newData("aws_eip", "aws_eip.attached", `{"instance": "i-123"}`),
+	)
+
+	e := NewEngine(buildVolume, priceVolumes)
+	recs, err := e.Evaluate(project)
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(recs))
+	for _, r := range recs {
+		saving := "nil"
+		if r.MonthlySaving != nil {
+			saving = r.MonthlySaving.String()
+		}
+
+		actual = append(actual, r.ID+" "+r.Address+" "+saving)
+	}
+
+	assert.Equal(t, []string{
+		"aws_ebs_gp2_to_gp3 aws_ebs_volume.gp2 10",
+		"aws_ebs_gp2_to_gp3 aws_ebs_volume.default 2",
+		"aws_unattached_eip aws_eip.unattached 0",
+	}, actual)
+	assert.Equal(t, "type = gp3", recs[0].Suggested)
+}
+
+func TestEvaluateDropsNegativeSavings(t *testing.T) {
+	project := newProject(newData("aws_ebs_volume", "aws_ebs_volume.gp2", `{"type": "gp2", "size": 100}`))
+
+	e := &Engine{
+		Rules: []*Rule{
+			{
+				ID:            "to_io1",
+				ResourceTypes: []string{"aws_ebs_volume"},
+				Check: func(d *schema.ResourceData, all []*schema.ResourceData) *Finding {
+					return &Finding{Patch: map[string]interface{}{"type": "io1"}}
+				},
+			},
+		},
+		Build: buildVolume,
+		Price: priceVolumes,
+	}
+
+	recs, err := e.Evaluate(project)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+}
+
+func TestCurrentGenerationInstanceType(t *testing.T) {
+	tests := map[string]string{
+		"t2.micro":      "t3.micro",
+		"m4.2xlarge":    "m5.2xlarge",
+		"db.r4.large":   "db.r5.large",
+		"m5.large":      "",
+		"db.t3.micro":   "",
+		"invalid":       "",
+		"c4.8xlarge":    "c5.8xlarge",
+		"db.m3.medium":  "db.m5.medium",
+		"i2.xlarge":     "i3.xlarge",
+		"d2.2xlarge":    "d3.2xlarge",
+		"unknown.large": "",
+	}
+
+	for in, expected := range tests {
+		assert.Equal(t, expected, currentGenerationInstanceType(in), in)
+	}
+}
+
+func TestNATGatewayPerAZRule(t *testing.T) {
+	subnetA := newData("aws_subnet", "aws_subnet.a", `{"availability_zone": "us-east-1a"}`)
+	subnetB := newData("aws_subnet", "aws_subnet.b", `{"availability_zone": "us-east-1b"}`)
+	subnetA2 := newData("aws_subnet", "aws_subnet.a2", `{"availability_zone": "us-east-1a"}`)
+	subnetUnknownAZ := newData("aws_subnet", "aws_subnet.c", `{}`)
+
+	natGateway := func(address, region string, subnet *schema.ResourceData) *schema.ResourceData {
+		d := newData("aws_nat_gateway", address, `{"region": "`+region+`"}`)
+		if subnet != nil {
+			d.AddReference("subnet_id", subnet, nil)
+		}
+
+		return d
+	}
+
+	all := []*schema.ResourceData{
+		natGateway("aws_nat_gateway.a", "us-east-1", subnetA),
+		natGateway("aws_nat_gateway.a_same_subnet", "us-east-1", subnetA),
+		natGateway("aws_nat_gateway.a_same_az", "us-east-1", subnetA2),
+		natGateway("aws_nat_gateway.b", "us-east-1", subnetB),
+		natGateway("aws_nat_gateway.c", "us-east-1", subnetUnknownAZ),
+		natGateway("aws_nat_gateway.eu", "eu-west-1", subnetA),
+		natGateway("aws_nat_gateway.no_subnet", "us-east-1", nil),
+		natGateway("aws_nat_gateway.no_subnet2", "us-east-1", nil),
+	}
+
+	rule := natGatewayPerAZRule()
+	assert.Nil(t, rule.Check(all[0], all))
+	assert.NotNil(t, rule.Check(all[1], all))
+	assert.NotNil(t, rule.Check(all[2], all))
+	assert.Nil(t, rule.Check(all[3], all))
+	assert.Nil(t, rule.Check(all[4], all))
+	assert.Nil(t, rule.Check(all[5], all))
+	assert.Nil(t, rule.Check(all[6], all))
+	assert.Nil(t, rule.Check(all[7], all))
+
+	f := rule.Check(all[1], all)
+	assert.Equal(t, "route through aws_nat_gateway.a instead", f.Suggested)
+}
+
+func TestEvaluateNATGatewaySaving(t *testing.T) {
+	buildNATGateway := func(d *schema.ResourceData) *schema.Resource {
+		hourly := &schema.CostComponent{
+			Name:           "NAT gateway",
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(1)),
+		}
+		hourly.SetPrice(decimal.RequireFromString("0.045"))
+
+		data := &schema.CostComponent{
+			Name:            "Data processed",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: testutil.DecimalPtr(decimal.NewFromInt(100)),
+		}
+		data.SetPrice(decimal.RequireFromString("0.045"))
+
+		return &schema.Resource{
+			Name:           d.Address,
+			ResourceType:   d.Type,
+			CostComponents: []*schema.CostComponent{hourly, data},
+		}
+	}
+
+	subnet := newData("aws_subnet", "aws_subnet.a", `{"availability_zone": "us-east-1a"}`)
+
+	project := schema.NewProject("test", &schema.ProjectMetadata{})
+	for _, address := range []string{"aws_nat_gateway.a", "aws_nat_gateway.b"} {
+		d := newData("aws_nat_gateway", address, `{"region": "us-east-1"}`)
+		d.AddReference("subnet_id", subnet, nil)
+
+		partial := schema.NewPartialResource(d, buildNATGateway(d), nil, nil)
+		project.PartialResources = append(project.PartialResources, partial)
+		project.Resources = append(project.Resources, schema.BuildResource(partial, nil))
+	}
+	schema.CalculateCosts(project)
+
+	recs, err := NewEngine(nil, nil).Evaluate(project)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, "aws_nat_gateway.b", recs[0].Address)
+	// only the hourly charge is saved, the data processed moves to aws_nat_gateway.a
+	assert.Equal(t, "32.85", recs[0].MonthlySaving.String())
+}
+
+func TestS3LifecycleRule(t *testing.T) {
+	rule := s3LifecycleRule()
+
+	assert.NotNil(t, rule.Check(newData("aws_s3_bucket", "aws_s3_bucket.a", `{}`), nil))
+	assert.NotNil(t, rule.Check(newData("aws_s3_bucket", "aws_s3_bucket.a", `{"lifecycle_rule": [{"enabled": false}]}`), nil))
+	assert.Nil(t, rule.Check(newData("aws_s3_bucket", "aws_s3_bucket.a", `{"lifecycle_rule": [{"enabled": true}]}`), nil))
+
+	d := newData("aws_s3_bucket", "aws_s3_bucket.a", `{}`)
+	d.AddReference("aws_s3_bucket_lifecycle_configuration.bucket", newData("aws_s3_bucket_lifecycle_configuration", "aws_s3_bucket_lifecycle_configuration.a", `{}`), nil)
+	assert.Nil(t, rule.Check(d, nil))
+}
@@ -0,0 +1,226 @@
+package recommendations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+var (
+	// previousGenerationFamilies maps previous generation EC2 and RDS instance
+	// families to the current generation family with the same sizes.
+	previousGenerationFamilies = map[string]string{
+		"t1": "t3",
+		"t2": "t3",
+		"m1": "m5",
+		"m3": "m5",
+		"m4": "m5",
+		"c1": "c5",
+		"c3": "c5",
+		"c4": "c5",
+		"r3": "r5",
+		"r4": "r5",
+		"i2": "i3",
+		"d2": "d3",
+	}
+
+	// eipReferences are the references that attach an EIP to another resource,
+	// these match the reference attributes of the aws_eip registry item.
+	eipReferences = []string{
+		"aws_nat_gateway.allocation_id",
+		"aws_eip_association.allocation_id",
+		"aws_lb.subnet_mapping.#.allocation_id",
+	}
+)
+
+// DefaultRules returns the built-in recommendation rules.
+func DefaultRules() []*Rule {
+	return []*Rule{
+		ebsGP3Rule(),
+		previousGenerationInstanceRule(),
+		unattachedEIPRule(),
+		natGatewayPerAZRule(),
+		s3LifecycleRule(),
+	}
+}
+
+func ebsGP3Rule() *Rule {
+	return &Rule{
+		ID:            "aws_ebs_gp2_to_gp3",
+		Title:         "Use gp3 EBS volumes",
+		Description:   "gp3 volumes are cheaper than gp2 volumes and include 3,000 IOPS and 125 MB/s of throughput regardless of size.",
+		ResourceTypes: []string{"aws_ebs_volume"},
+		Check: func(d *schema.ResourceData, all []*schema.ResourceData) *Finding {
+			// gp2 is the default volume type when one isn't set
+			t := d.GetStringOrDefault("type", "gp2")
+			if t != "gp2" {
+				return nil
+			}
+
+			return &Finding{
+				Suggested: "type = gp3",
+				Patch:     map[string]interface{}{"type": "gp3"},
+			}
+		},
+	}
+}
+
+func previousGenerationInstanceRule() *Rule {
+	return &Rule{
+		ID:            "aws_previous_generation_instance",
+		Title:         "Use current generation instance types",
+		Description:   "Previous generation instance types are more expensive and have lower performance than the current generation.",
+		ResourceTypes: []string{"aws_instance", "aws_db_instance"},
+		Check: func(d *schema.ResourceData, all []*schema.ResourceData) *Finding {
+			key := "instance_type"
+			if d.Type == "aws_db_instance" {
+				key = "instance_class"
+			}
+
+			current := d.Get(key).String()
+			suggested := currentGenerationInstanceType(current)
+			if suggested == "" {
+				return nil
+			}
+
+			return &Finding{
+				Suggested: fmt.Sprintf("%s = %s", key, suggested),
+				Patch:     map[string]interface{}{key: suggested},
+			}
+		},
+	}
+}
+
+// currentGenerationInstanceType returns the current generation instance type
+// with the same size as t, or an empty string if t is not a previous generation
+// instance type. RDS instance classes with the "db." prefix are supported.
+func currentGenerationInstanceType(t string) string {
+	prefix := ""
+	if strings.HasPrefix(t, "db.") {
+		prefix = "db."
+		t = strings.TrimPrefix(t, prefix)
+	}
+
+	parts := strings.SplitN(t, ".", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+
+	family, ok := previousGenerationFamilies[parts[0]]
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%s.%s", prefix, family, parts[1])
+}
+
+func unattachedEIPRule() *Rule {
+	return &Rule{
+		ID:            "aws_unattached_eip",
+		Title:         "Remove unattached Elastic IPs",
+		Description:   "Elastic IPs that are not attached to a running instance, network interface, NAT gateway or load balancer are charged by the hour.",
+		ResourceTypes: []string{"aws_eip"},
+		Check: func(d *schema.ResourceData, all []*schema.ResourceData) *Finding {
+			if len(d.References(eipReferences...)) > 0 {
+				return nil
+			}
+
+			if !d.IsEmpty("customer_owned_ipv4_pool") || !d.IsEmpty("instance") || !d.IsEmpty("network_interface") {
+				return nil
+			}
+
+			return &Finding{
+				Suggested: "remove the Elastic IP or attach it to a resource",
+				Remove:    true,
+			}
+		},
+	}
+}
+
+func natGatewayPerAZRule() *Rule {
+	return &Rule{
+		ID:            "aws_nat_gateway_per_az",
+		Title:         "Use one NAT gateway per availability zone",
+		Description:   "A NAT gateway in each availability zone is enough for resilience, additional NAT gateways in the same availability zone can be removed.",
+		ResourceTypes: []string{"aws_nat_gateway"},
+		Check: func(d *schema.ResourceData, all []*schema.ResourceData) *Finding {
+			az := natGatewayAZ(d)
+			if az == "" {
+				return nil
+			}
+
+			region := d.Get("region").String()
+
+			var addresses []string
+			for _, other := range all {
+				if other.Type == d.Type && other.Get("region").String() == region && natGatewayAZ(other) == az {
+					addresses = append(addresses, other.Address)
+				}
+			}
+
+			if len(addresses) < 2 {
+				return nil
+			}
+
+			// Keep the first NAT gateway so only the additional ones are flagged
+			sort.Strings(addresses)
+			if addresses[0] == d.Address {
+				return nil
+			}
+
+			return &Finding{
+				Suggested: fmt.Sprintf("route through %s instead", addresses[0]),
+				Remove:    true,
+				// the traffic moves to the remaining NAT gateway, so it's still
+				// charged for.
+				KeepCostComponents: []string{"Data processed"},
+			}
+		},
+	}
+}
+
+// natGatewayAZ returns the availability zone of the subnet of the NAT gateway.
+// If the availability zone of the subnet isn't known the subnet is used
+// instead, since a subnet is always in a single availability zone. An empty
+// string is returned if the subnet isn't known.
+func natGatewayAZ(d *schema.ResourceData) string {
+	for _, subnet := range d.References("subnet_id") {
+		if az := subnet.Get("availability_zone").String(); az != "" {
+			return az
+		}
+
+		if az := subnet.Get("availability_zone_id").String(); az != "" {
+			return az
+		}
+
+		return subnet.Address
+	}
+
+	return d.Get("subnet_id").String()
+}
+
+func s3LifecycleRule() *Rule {
+	return &Rule{
+		ID:            "aws_s3_bucket_lifecycle",
+		Title:         "Add S3 lifecycle rules",
+		Description:   "Lifecycle rules can transition objects to cheaper storage classes or expire them once they are no longer needed.",
+		ResourceTypes: []string{"aws_s3_bucket"},
+		Check: func(d *schema.ResourceData, all []*schema.ResourceData) *Finding {
+			if len(d.References("aws_s3_bucket_lifecycle_configuration.bucket")) > 0 {
+				return nil
+			}
+
+			for _, rule := range d.Get("lifecycle_rule").Array() {
+				if rule.Get("enabled").Bool() {
+					return nil
+				}
+			}
+
+			return &Finding{
+				Suggested: "add a lifecycle configuration",
+			}
+		},
+	}
+}
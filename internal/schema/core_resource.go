@@ -69,6 +69,14 @@ type PartialResource struct {
 	// CloudResourceIDs are collected during parsing in case they need to be uploaded to the
 	// Cloud Usage API to be used in the usage estimate calculations.
 	CloudResourceIDs []string
+
+	// ResourceData is the parsed provider data the resource was generated from. It
+	// is kept so that the resource can be rebuilt with different attribute values.
+	ResourceData *ResourceData
+
+	// FetchedUsageData is the usage estimate retrieved from Infracost Cloud that was
+	// merged with UsageData when the resource was built.
+	FetchedUsageData *UsageData
 }
 
 func NewPartialResource(d *ResourceData, r *Resource, cr CoreResource, cloudResourceIds []string) *PartialResource {
@@ -81,6 +89,7 @@ func NewPartialResource(d *ResourceData, r *Resource, cr CoreResource, cloudReso
 		CoreResource:     cr,
 		Resource:         r,
 		CloudResourceIDs: cloudResourceIds,
+		ResourceData:     d,
 	}
 }
 
@@ -88,6 +97,8 @@ func NewPartialResource(d *ResourceData, r *Resource, cr CoreResource, cloudReso
 // a previously built Resource
 func BuildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource {
 	var res *Resource
+	partial.FetchedUsageData = fetchedUsage
+
 	if partial.CoreResource != nil {
 		u := partial.UsageData
 		u = u.Merge(fetchedUsage)
@@ -110,6 +121,11 @@ func BuildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource
 	res.ResourceType = partial.Type
 	res.Tags = partial.Tags
 	res.Metadata = partial.Metadata
+
+	if partial.ResourceData != nil {
+		setCostComponentUncertainInputs(res, partial.ResourceData, partial.Metadata[UncertainInputsMetadataKey])
+	}
+
 	return res
 }
 
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"strings"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -21,8 +23,16 @@ type CostComponent struct {
 	price                decimal.Decimal
 	customPrice          *decimal.Decimal
 	priceHash            string
+	productHash          string
+	productAttributes    map[string]string
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
+	// MonthlyCO2e is the estimated monthly emissions in kgCO2e, it is nil if
+	// emissions weren't estimated for the cost component.
+	MonthlyCO2e *decimal.Decimal
+	// UncertainInputs are the attributes of the resource with mocked values
+	// that the cost component is priced from, see UncertainInputsMetadataKey.
+	UncertainInputs []string
 }
 
 func (c *CostComponent) CalculateCosts() {
@@ -60,6 +70,25 @@ func (c *CostComponent) PriceHash() string {
 	return c.priceHash
 }
 
+func (c *CostComponent) SetProductHash(productHash string) {
+	c.productHash = productHash
+}
+
+func (c *CostComponent) ProductHash() string {
+	return c.productHash
+}
+
+// SetProductAttributes sets the attributes of the product that was matched by
+// the ProductFilter. These are only populated when the pricing client is
+// configured to request them.
+func (c *CostComponent) SetProductAttributes(attributes map[string]string) {
+	c.productAttributes = attributes
+}
+
+func (c *CostComponent) ProductAttributes() map[string]string {
+	return c.productAttributes
+}
+
 func (c *CostComponent) SetCustomPrice(price *decimal.Decimal) {
 	c.customPrice = price
 }
@@ -112,3 +141,28 @@ func (c *CostComponent) UnitMultiplierMonthlyQuantity() *decimal.Decimal {
 
 	return &m
 }
+
+// usesValue returns true if the lowercase value v is in the name or the
+// product and price filters of the cost component.
+func (c *CostComponent) usesValue(v string) bool {
+	values := []*string{&c.Name}
+
+	if f := c.ProductFilter; f != nil {
+		values = append(values, f.Service, f.ProductFamily, f.Region, f.Sku)
+		for _, a := range f.AttributeFilters {
+			values = append(values, a.Value, a.ValueRegex)
+		}
+	}
+
+	if f := c.PriceFilter; f != nil {
+		values = append(values, f.PurchaseOption, f.Unit, f.Description, f.DescriptionRegex, f.TermLength, f.TermPurchaseOption, f.TermOfferingClass)
+	}
+
+	for _, s := range values {
+		if s != nil && strings.Contains(strings.ToLower(*s), v) {
+			return true
+		}
+	}
+
+	return false
+}
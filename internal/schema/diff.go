@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
 )
 
 // nameBracketReg matches the part of a cost component name before the brackets, and the part in the brackets
@@ -29,15 +30,33 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 	// calculate the diff for them. This way a complete diff for
 	// all resources is calculated.
 
+	// Resources that have been renamed with a `moved` block are keyed by their
+	// new name in the past hashmap so that they are diffed against themselves
+	// rather than showing up as a removal and an addition.
+	renames := movedResourceNames(past, current)
+
 	pastRMap := make(map[string]*Resource)
-	fillResourcesMap(pastRMap, "", past)
+	for _, resource := range past {
+		resourceKey := pastResourceKey(resource, renames)
+		pastRMap[resourceKey] = resource
+		fillResourcesMap(pastRMap, resourceKey, resource.SubResources)
+	}
 	currentRMap := make(map[string]*Resource)
 	fillResourcesMap(currentRMap, "", current)
 
 	diff := make([]*Resource, 0)
 
 	for _, resource := range past {
-		resourceKey := resource.Name
+		resourceKey := pastResourceKey(resource, renames)
+
+		// Resources removed from state without being destroyed keep costing
+		// money, so they're shown in the diff without any savings.
+		if _, ok := currentRMap[resourceKey]; !ok && resource.IsForgotten() {
+			diff = append(diff, unchangedDiff(resource))
+			delete(pastRMap, resourceKey)
+			continue
+		}
+
 		changed, resources := diffResourcesByKey(resourceKey, pastRMap, currentRMap)
 		if changed {
 			diff = append(diff, resources)
@@ -49,6 +68,15 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 		if _, ok := currentRMap[resourceKey]; !ok {
 			continue
 		}
+
+		// Imported resources are newly managed but already exist, so they're
+		// shown in the diff without being counted as new spend.
+		if _, ok := pastRMap[resourceKey]; !ok && resource.IsImported() {
+			diff = append(diff, unchangedDiff(resource))
+			delete(currentRMap, resourceKey)
+			continue
+		}
+
 		changed, resources := diffResourcesByKey(resourceKey, pastRMap, currentRMap)
 		if changed {
 			diff = append(diff, resources)
@@ -58,6 +86,60 @@ func CalculateDiff(past []*Resource, current []*Resource) []*Resource {
 	return diff
 }
 
+// movedResourceNames returns a map of past resource name to current resource
+// name for the current resources that have been moved from a past resource.
+// Moves are ignored if the new address already existed or the previous address
+// is still in use.
+func movedResourceNames(past []*Resource, current []*Resource) map[string]string {
+	pastNames := make(map[string]bool, len(past))
+	for _, r := range past {
+		pastNames[r.Name] = true
+	}
+
+	currentNames := make(map[string]bool, len(current))
+	for _, r := range current {
+		currentNames[r.Name] = true
+	}
+
+	renames := make(map[string]string)
+	for _, r := range current {
+		prev := r.PreviousAddress()
+		if prev == "" || !pastNames[prev] || pastNames[r.Name] || currentNames[prev] {
+			continue
+		}
+
+		renames[prev] = r.Name
+	}
+
+	return renames
+}
+
+// pastResourceKey returns the key of the past resource in the diff hashmaps.
+func pastResourceKey(resource *Resource, renames map[string]string) string {
+	if to, ok := renames[resource.Name]; ok {
+		return to
+	}
+
+	return resource.Name
+}
+
+// unchangedDiff returns a diff for a resource whose costs are neither added
+// nor removed, e.g. because it's being imported or forgotten.
+func unchangedDiff(resource *Resource) *Resource {
+	return &Resource{
+		Name:         resource.Name,
+		IsSkipped:    resource.IsSkipped,
+		NoPrice:      resource.NoPrice,
+		SkipMessage:  resource.SkipMessage,
+		ResourceType: resource.ResourceType,
+		Tags:         resource.Tags,
+		Metadata:     diffMetadata(resource),
+
+		HourlyCost:  decimalPtr(decimal.Zero),
+		MonthlyCost: decimalPtr(decimal.Zero),
+	}
+}
+
 // diffResourcesByKey calculates the diff between two resources given their resourcesMap and
 // their key.
 func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string]*Resource) (bool, *Resource) {
@@ -83,9 +165,11 @@ func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string
 		SkipMessage:  baseResource.SkipMessage,
 		ResourceType: baseResource.ResourceType,
 		Tags:         baseResource.Tags,
+		Metadata:     diffMetadata(baseResource),
 
 		HourlyCost:  diffDecimals(current.HourlyCost, past.HourlyCost),
 		MonthlyCost: diffDecimals(current.MonthlyCost, past.MonthlyCost),
+		MonthlyCO2e: diffOptionalDecimals(current.MonthlyCO2e, past.MonthlyCO2e),
 	}
 	for _, subResource := range past.SubResources {
 		subKey := fmt.Sprintf("%v.%v", resourceKey, subResource.Name)
@@ -186,6 +270,7 @@ func diffCostComponents(past *CostComponent, current *CostComponent) (bool, *Cos
 		ProductFilter:        baseCostComponent.ProductFilter,
 		PriceFilter:          baseCostComponent.PriceFilter,
 		priceHash:            baseCostComponent.priceHash,
+		UncertainInputs:      baseCostComponent.UncertainInputs,
 
 		HourlyQuantity:      diffDecimals(current.HourlyQuantity, past.HourlyQuantity),
 		MonthlyQuantity:     diffDecimals(current.MonthlyQuantity, past.MonthlyQuantity),
@@ -193,10 +278,12 @@ func diffCostComponents(past *CostComponent, current *CostComponent) (bool, *Cos
 		price:               *diffDecimals(&current.price, &past.price),
 		HourlyCost:          diffDecimals(current.HourlyCost, past.HourlyCost),
 		MonthlyCost:         diffDecimals(current.MonthlyCost, past.MonthlyCost),
+		MonthlyCO2e:         diffOptionalDecimals(current.MonthlyCO2e, past.MonthlyCO2e),
 	}
 	if !diff.HourlyQuantity.IsZero() || !diff.MonthlyQuantity.IsZero() ||
 		diff.MonthlyDiscountPerc != 0 || !diff.price.IsZero() ||
-		!diff.HourlyCost.IsZero() || !diff.MonthlyCost.IsZero() {
+		!diff.HourlyCost.IsZero() || !diff.MonthlyCost.IsZero() ||
+		(diff.MonthlyCO2e != nil && !diff.MonthlyCO2e.IsZero()) {
 		changed = true
 	}
 
@@ -241,6 +328,15 @@ func diffDecimals(current *decimal.Decimal, past *decimal.Decimal) *decimal.Deci
 	return &diff
 }
 
+// diffOptionalDecimals is like diffDecimals but returns nil if neither value
+// is set, so values that are only sometimes calculated stay unset in the diff.
+func diffOptionalDecimals(current *decimal.Decimal, past *decimal.Decimal) *decimal.Decimal {
+	if past == nil && current == nil {
+		return nil
+	}
+	return diffDecimals(current, past)
+}
+
 // diffName creates a new cost component name for the diff cost component based on the existing cost components.
 // Anything that is in brackets is treated as a label and any difference in the labels across the past and current
 // are represented as "old → new"
@@ -302,3 +398,24 @@ func fillResourcesMap(resourcesMap map[string]*Resource, rootKey string, resourc
 		fillResourcesMap(resourcesMap, key, resource.SubResources)
 	}
 }
+
+// diffMetadata returns the metadata that is carried over from a resource to
+// its diff. This describes how the resource has been moved, imported or
+// forgotten and which of the inputs it's priced from are uncertain.
+func diffMetadata(r *Resource) map[string]gjson.Result {
+	var m map[string]gjson.Result
+
+	for _, key := range []string{PreviousAddressMetadataKey, ImportingMetadataKey, ForgottenMetadataKey, UncertainInputsMetadataKey} {
+		v, ok := r.Metadata[key]
+		if !ok {
+			continue
+		}
+
+		if m == nil {
+			m = make(map[string]gjson.Result)
+		}
+		m[key] = v
+	}
+
+	return m
+}
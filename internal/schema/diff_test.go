@@ -5,6 +5,7 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestCalculateDiff(t *testing.T) {
@@ -133,6 +134,90 @@ func TestCalculateDiff(t *testing.T) {
 	assert.Equal(t, expectedDiff, diff)
 }
 
+func TestCalculateDiffRefactorings(t *testing.T) {
+	newResource := func(name string, cost int64, metadata map[string]gjson.Result) *Resource {
+		return &Resource{
+			Name:        name,
+			HourlyCost:  decimalPtr(decimal.NewFromInt(cost)),
+			MonthlyCost: decimalPtr(decimal.NewFromInt(cost * 730)),
+			Metadata:    metadata,
+			CostComponents: []*CostComponent{
+				{
+					Name:        "cc",
+					HourlyCost:  decimalPtr(decimal.NewFromInt(cost)),
+					MonthlyCost: decimalPtr(decimal.NewFromInt(cost * 730)),
+				},
+			},
+		}
+	}
+
+	t.Run("moved resources are paired with their previous address", func(t *testing.T) {
+		past := []*Resource{newResource("aws_instance.old", 2, nil)}
+		current := []*Resource{newResource("aws_instance.new", 2, map[string]gjson.Result{
+			PreviousAddressMetadataKey: gjson.Parse(`"aws_instance.old"`),
+		})}
+
+		assert.Empty(t, CalculateDiff(past, current))
+
+		current[0].CostComponents[0].MonthlyCost = decimalPtr(decimal.NewFromInt(2920))
+		current[0].MonthlyCost = decimalPtr(decimal.NewFromInt(2920))
+		diff := CalculateDiff(past, current)
+		assert.Len(t, diff, 1)
+		assert.Equal(t, "aws_instance.new", diff[0].Name)
+		assert.Equal(t, "aws_instance.old", diff[0].PreviousAddress())
+		assert.Equal(t, "1460", diff[0].MonthlyCost.String())
+	})
+
+	t.Run("moves are ignored when the previous address is still used", func(t *testing.T) {
+		past := []*Resource{newResource("aws_instance.old", 2, nil)}
+		current := []*Resource{
+			newResource("aws_instance.old", 2, nil),
+			newResource("aws_instance.new", 1, map[string]gjson.Result{
+				PreviousAddressMetadataKey: gjson.Parse(`"aws_instance.old"`),
+			}),
+		}
+
+		diff := CalculateDiff(past, current)
+		assert.Len(t, diff, 1)
+		assert.Equal(t, "730", diff[0].MonthlyCost.String())
+	})
+
+	t.Run("imported resources are not new spend", func(t *testing.T) {
+		current := []*Resource{newResource("aws_instance.imported", 2, map[string]gjson.Result{
+			ImportingMetadataKey: gjson.Parse("true"),
+		})}
+
+		diff := CalculateDiff(nil, current)
+		assert.Len(t, diff, 1)
+		assert.True(t, diff[0].IsImported())
+		assert.True(t, diff[0].MonthlyCost.IsZero())
+		assert.Empty(t, diff[0].CostComponents)
+	})
+
+	t.Run("forgotten resources are not savings", func(t *testing.T) {
+		past := []*Resource{
+			newResource("module.app.aws_instance.web[0]", 2, nil),
+			newResource("aws_instance.destroyed", 1, nil),
+		}
+		MarkForgottenResources(past, []string{"module.app"})
+
+		diff := CalculateDiff(past, nil)
+		assert.Len(t, diff, 2)
+		assert.True(t, diff[0].IsForgotten())
+		assert.True(t, diff[0].MonthlyCost.IsZero())
+		assert.False(t, diff[1].IsForgotten())
+		assert.Equal(t, "-730", diff[1].MonthlyCost.String())
+	})
+}
+
+func TestAddressWithin(t *testing.T) {
+	assert.True(t, AddressWithin("aws_instance.web", "aws_instance.web"))
+	assert.True(t, AddressWithin(`aws_instance.web["a"]`, "aws_instance.web"))
+	assert.True(t, AddressWithin("module.app[0].aws_instance.web", "module.app"))
+	assert.False(t, AddressWithin("aws_instance.webserver", "aws_instance.web"))
+	assert.False(t, AddressWithin("aws_instance.web", ""))
+}
+
 func TestDiffCostComponentsByResource(t *testing.T) {
 	pastRS := &Resource{
 		Name: "rs",
@@ -253,6 +338,19 @@ func TestDiffDecimals(t *testing.T) {
 	assert.Equal(t, decimal.Zero, *diffDecimals(nil, nil))
 }
 
+func TestDiffCostComponentsCO2e(t *testing.T) {
+	past := &CostComponent{Name: "Instance usage", MonthlyCO2e: decimalPtr(decimal.NewFromInt(10))}
+	current := &CostComponent{Name: "Instance usage", MonthlyCO2e: decimalPtr(decimal.NewFromInt(15))}
+
+	changed, diff := diffCostComponents(past, current)
+	assert.True(t, changed)
+	assert.Equal(t, decimal.NewFromInt(5), *diff.MonthlyCO2e)
+
+	changed, diff = diffCostComponents(&CostComponent{Name: "Requests"}, &CostComponent{Name: "Requests"})
+	assert.False(t, changed)
+	assert.Nil(t, diff.MonthlyCO2e)
+}
+
 func TestGetResourcesMap(t *testing.T) {
 	rs1 := &Resource{
 		Name: "rs1",
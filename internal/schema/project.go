@@ -21,6 +21,8 @@ const (
 	diagTerragruntEvaluationFailure       = 103
 	diagTerragruntModuleEvaluationFailure = 104
 	diagMissingVars                       = 105
+	diagFailedConditions                  = 106
+	diagMockedDependencyOutputs           = 107
 
 	// Diags for git module issues
 	diagPrivateModuleDownloadFailure = 201
@@ -149,6 +151,41 @@ func NewDiagMissingVars(vars ...string) *ProjectDiag {
 	}
 }
 
+// NewDiagFailedConditions returns a ProjectDiag for Terraform variable
+// validations, preconditions and postconditions that failed. This usually means
+// that the wrong var files were used for the project, so the costs might not be
+// accurate. It is considered a non-critical error and is used to notify the
+// user.
+func NewDiagFailedConditions(failures ...string) *ProjectDiag {
+	return &ProjectDiag{
+		Code:    diagFailedConditions,
+		Message: "Failed Terraform conditions",
+		Data:    failures,
+		FriendlyMessage: fmt.Sprintf(
+			"The following Terraform validations and conditions failed: %s. %s",
+			joinQuotes(failures),
+			"Check that the right var files are used for the project, the costs might not be accurate.",
+		),
+	}
+}
+
+// NewDiagMockedDependencyOutputs returns a ProjectDiag for Terragrunt
+// dependencies that couldn't be evaluated, so any of their outputs that the
+// project uses are mocked. It is considered a non-critical error and is used to
+// notify the user.
+func NewDiagMockedDependencyOutputs(dependencies ...string) *ProjectDiag {
+	return &ProjectDiag{
+		Code:    diagMockedDependencyOutputs,
+		Message: "Mocked Terragrunt dependency outputs",
+		Data:    dependencies,
+		FriendlyMessage: fmt.Sprintf(
+			"The following Terragrunt dependencies could not be evaluated: %s. %s",
+			joinQuotes(dependencies),
+			"Their outputs were mocked, so the costs might not be accurate.",
+		),
+	}
+}
+
 func joinQuotes(elems []string) string {
 
 	quoted := make([]string, len(elems))
@@ -182,6 +219,15 @@ type ProjectMetadata struct {
 	Policies            Policies           `json:"policies,omitempty"`
 	Providers           []ProviderMetadata `json:"providers,omitempty"`
 	RemoteModuleCalls   []string           `json:"remoteModuleCalls,omitempty"`
+	// RemovedResources are the addresses that are removed from state by
+	// `removed` blocks without being destroyed.
+	RemovedResources []string `json:"removedResources,omitempty"`
+	// SuppliedDataSources are the addresses of the data sources with values
+	// from the user's data mocks file.
+	SuppliedDataSources []string `json:"suppliedDataSources,omitempty"`
+	// MockedDataSources are the addresses of the data sources whose values
+	// were mocked as they weren't supplied by the user.
+	MockedDataSources []string `json:"mockedDataSources,omitempty"`
 }
 
 type ProviderMetadata struct {
@@ -273,6 +319,7 @@ type Project struct {
 	Resources            []*Resource
 	Diff                 []*Resource
 	HasDiff              bool
+	Recommendations      Recommendations
 }
 
 func (p *Project) AddProviderMetadata(metadatas []ProviderMetadata) {
@@ -374,6 +421,9 @@ func (p *Project) BuildResources(usageMap UsageMap) {
 // CalculateDiff calculates the diff of past and current resources
 func (p *Project) CalculateDiff() {
 	if p.HasDiff {
+		if p.Metadata != nil {
+			MarkForgottenResources(p.PastResources, p.Metadata.RemovedResources)
+		}
 		p.Diff = CalculateDiff(p.PastResources, p.Resources)
 	}
 }
@@ -0,0 +1,173 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Provenance describes where the value of a resource attribute came from when
+// the resource was evaluated from HCL.
+type Provenance string
+
+const (
+	// ProvenanceLiteral is a value that is set directly in the config.
+	ProvenanceLiteral Provenance = "literal"
+	// ProvenanceVarFile is a value from an input variable that was set in a
+	// var file, with a -var flag or a TF_VAR_ environment variable.
+	ProvenanceVarFile Provenance = "var_file"
+	// ProvenanceDefault is a value from an input variable's default.
+	ProvenanceDefault Provenance = "default"
+	// ProvenanceModuleOutput is a value from the output of a module.
+	ProvenanceModuleOutput Provenance = "module_output"
+	// ProvenanceDataSource is a value from a data source that was supplied by
+	// the user, e.g. in a data mocks file, or computed by Infracost.
+	ProvenanceDataSource Provenance = "data_source"
+	// ProvenanceMocked is a value that was substituted with a mock because it
+	// could not be evaluated, e.g. the attributes of a data source.
+	ProvenanceMocked Provenance = "mocked"
+	// ProvenanceUnknown is a value that is not known until apply.
+	ProvenanceUnknown Provenance = "unknown"
+)
+
+const (
+	// ProvenanceMetadataKey is the resource metadata key holding the
+	// Provenance of the resource attributes that weren't set literally.
+	ProvenanceMetadataKey = "provenance"
+	// UncertainInputsMetadataKey is the resource metadata key holding the
+	// attributes used to price the resource that were mocked or unknown. The
+	// mocked attributes are also set on the cost components that use their
+	// values, see CostComponent.UncertainInputs.
+	UncertainInputsMetadataKey = "uncertainInputs"
+)
+
+var provenanceRanks = map[Provenance]int{
+	ProvenanceLiteral:      0,
+	ProvenanceVarFile:      1,
+	ProvenanceDefault:      2,
+	ProvenanceModuleOutput: 3,
+	ProvenanceDataSource:   4,
+	ProvenanceMocked:       5,
+	ProvenanceUnknown:      6,
+}
+
+// LeastCertain returns whichever of p and other is the least reliable source
+// for a value, e.g. a value that depends on both a literal and a mocked
+// value is mocked.
+func (p Provenance) LeastCertain(other Provenance) Provenance {
+	if provenanceRanks[other] > provenanceRanks[p] {
+		return other
+	}
+
+	return p
+}
+
+// IsUncertain returns true if the value was mocked or is unknown, so any
+// cost that's calculated from it may be wrong.
+func (p Provenance) IsUncertain() bool {
+	return p == ProvenanceMocked || p == ProvenanceUnknown
+}
+
+// UncertainInputs returns the attributes that were read from the
+// ResourceData and have an uncertain Provenance, mapped to their Provenance.
+func (d *ResourceData) UncertainInputs() map[string]Provenance {
+	provenance, ok := d.Metadata[ProvenanceMetadataKey]
+	if !ok || len(d.accessed) == 0 {
+		return nil
+	}
+
+	var uncertain map[string]Provenance
+	provenance.ForEach(func(key, value gjson.Result) bool {
+		p := Provenance(value.String())
+		if !p.IsUncertain() {
+			return true
+		}
+
+		for accessed := range d.accessed {
+			if keysOverlap(accessed, key.String()) {
+				if uncertain == nil {
+					uncertain = make(map[string]Provenance)
+				}
+
+				uncertain[key.String()] = p
+				break
+			}
+		}
+
+		return true
+	})
+
+	return uncertain
+}
+
+// keysOverlap returns true if one of the gjson paths is within the other,
+// e.g. root_block_device reads root_block_device.0.volume_size. A # in
+// either path matches any array index.
+func keysOverlap(a, b string) bool {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	n := len(aParts)
+	if len(bParts) < n {
+		n = len(bParts)
+	}
+
+	for i := 0; i < n; i++ {
+		if aParts[i] != bParts[i] && aParts[i] != "#" && bParts[i] != "#" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setCostComponentUncertainInputs sets the UncertainInputs of the cost
+// components of r, and of its sub resources, that are priced from the mocked
+// inputs of the resource. Attribute reads happen before the cost components
+// are built, so they can't be attributed to a component directly. Instead, a
+// component uses a mocked input if the mock value, e.g. instance_type-mock,
+// is in its name or price lookup. Unknown inputs don't have a value in d, so
+// they're only listed on the resource.
+func setCostComponentUncertainInputs(r *Resource, d *ResourceData, inputs gjson.Result) {
+	mocked := map[string]string{}
+	inputs.ForEach(func(key, value gjson.Result) bool {
+		if Provenance(value.String()) != ProvenanceMocked {
+			return true
+		}
+
+		v := d.RawValues.Get(key.String())
+		if v.Type == gjson.String && v.String() != "" {
+			mocked[key.String()] = strings.ToLower(v.String())
+		}
+
+		return true
+	})
+
+	if len(mocked) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(mocked))
+	for k := range mocked {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var set func(r *Resource)
+	set = func(r *Resource) {
+		for _, c := range r.CostComponents {
+			c.UncertainInputs = nil
+			for _, k := range keys {
+				if c.usesValue(mocked[k]) {
+					c.UncertainInputs = append(c.UncertainInputs, k)
+				}
+			}
+		}
+
+		for _, s := range r.SubResources {
+			set(s)
+		}
+	}
+	set(r)
+}
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Recommendation is a cost optimisation suggestion for a single resource that
+// is generated locally, as opposed to a Policy which is returned by Infracost
+// Cloud.
+type Recommendation struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	ResourceType string `json:"resourceType"`
+	Address      string `json:"address"`
+	Suggested    string `json:"suggested,omitempty"`
+	// MonthlySaving is the estimated monthly saving of applying the suggested
+	// change. It is nil if the saving depends on usage that can't be estimated.
+	MonthlySaving *decimal.Decimal `json:"monthlySaving,omitempty"`
+}
+
+// Recommendations is a slice of Recommendation that is ordered by the largest
+// monthly saving first, then by address.
+type Recommendations []Recommendation
+
+func (r Recommendations) Len() int {
+	return len(r)
+}
+
+func (r Recommendations) Less(i, j int) bool {
+	a := r[i]
+	b := r[j]
+
+	if a.MonthlySaving == nil && b.MonthlySaving == nil {
+		if a.Address == b.Address {
+			return a.ID < b.ID
+		}
+
+		return a.Address < b.Address
+	}
+
+	if a.MonthlySaving == nil {
+		return false
+	}
+
+	if b.MonthlySaving == nil {
+		return true
+	}
+
+	if a.MonthlySaving.Equal(*b.MonthlySaving) {
+		if a.Address == b.Address {
+			return a.ID < b.ID
+		}
+
+		return a.Address < b.Address
+	}
+
+	return a.MonthlySaving.GreaterThan(*b.MonthlySaving)
+}
+
+func (r Recommendations) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// PreviousAddressMetadataKey is the resource metadata key holding the
+	// address a resource had before it was renamed with a `moved` block.
+	PreviousAddressMetadataKey = "previousAddress"
+	// ImportingMetadataKey is the resource metadata key set on resources that
+	// already exist and are being brought under management with an `import`
+	// block.
+	ImportingMetadataKey = "importing"
+	// ForgottenMetadataKey is the resource metadata key set on past resources
+	// that are removed from state with a `removed` block but not destroyed.
+	ForgottenMetadataKey = "forgotten"
+)
+
+// AddressWithin returns true if addr is base or is contained by base. base can
+// be a module address, a resource address without an instance key or a full
+// resource instance address, e.g. aws_instance.web is within module.app and
+// aws_instance.web["a"] is within aws_instance.web.
+func AddressWithin(addr, base string) bool {
+	if base == "" || !strings.HasPrefix(addr, base) {
+		return false
+	}
+
+	rest := addr[len(base):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
+// PreviousAddress returns the address the resource had before it was moved,
+// or "" if it has not been moved.
+func (r *Resource) PreviousAddress() string {
+	return r.Metadata[PreviousAddressMetadataKey].String()
+}
+
+// IsImported returns true if the resource already exists and is being imported
+// rather than created, so it does not represent new spend.
+func (r *Resource) IsImported() bool {
+	return r.Metadata[ImportingMetadataKey].Bool()
+}
+
+// IsForgotten returns true if the resource is being removed from state without
+// being destroyed, so its cost does not go away.
+func (r *Resource) IsForgotten() bool {
+	return r.Metadata[ForgottenMetadataKey].Bool()
+}
+
+// MarkForgottenResources flags the resources that are within any of the given
+// addresses as forgotten. This is used to apply the `removed` blocks of the
+// current configuration to the resources from a prior run.
+func MarkForgottenResources(resources []*Resource, addresses []string) {
+	for _, r := range resources {
+		for _, addr := range addresses {
+			if !AddressWithin(r.Name, addr) {
+				continue
+			}
+
+			if r.Metadata == nil {
+				r.Metadata = make(map[string]gjson.Result)
+			}
+			r.Metadata[ForgottenMetadataKey] = gjson.Result{Type: gjson.True, Raw: "true"}
+			break
+		}
+	}
+}
@@ -24,6 +24,7 @@ type Resource struct {
 	SubResources      []*Resource
 	HourlyCost        *decimal.Decimal
 	MonthlyCost       *decimal.Decimal
+	MonthlyCO2e       *decimal.Decimal
 	IsSkipped         bool
 	NoPrice           bool
 	SkipMessage       string
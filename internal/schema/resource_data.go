@@ -18,6 +18,10 @@ type ResourceData struct {
 	CFResource    cloudformation.Resource
 	UsageData     *UsageData
 	Metadata      map[string]gjson.Result
+
+	// accessed holds the keys that have been read while tracking with TrackAccess.
+	accessed map[string]struct{}
+	tracking bool
 }
 
 func NewResourceData(resourceType string, providerName string, address string, tags *map[string]string, rawValues gjson.Result) *ResourceData {
@@ -45,9 +49,28 @@ func NewCFResourceData(resourceType string, providerName string, address string,
 }
 
 func (d *ResourceData) Get(key string) gjson.Result {
+	d.recordAccess(key)
 	return gjson.Parse(strings.Clone(d.RawValues.Get(key).Raw))
 }
 
+// TrackAccess records the keys that are read from the ResourceData while f
+// runs. This is used to find the attributes that a resource is priced from.
+func (d *ResourceData) TrackAccess(f func()) {
+	d.accessed = make(map[string]struct{})
+	d.tracking = true
+	defer func() {
+		d.tracking = false
+	}()
+
+	f()
+}
+
+func (d *ResourceData) recordAccess(key string) {
+	if d.tracking {
+		d.accessed[key] = struct{}{}
+	}
+}
+
 // GetStringOrDefault returns the value of key within ResourceData as a string.
 // If the retrieved value is not set GetStringOrDefault will return def.
 func (d *ResourceData) GetStringOrDefault(key, def string) string {
@@ -89,6 +112,7 @@ func (d *ResourceData) GetBoolOrDefault(key string, def bool) bool {
 // Return true if the key doesn't exist, is null, or is an empty string.
 // Needed because gjson.Exists returns true as long as a key exists, even if it's empty or null.
 func (d *ResourceData) IsEmpty(key string) bool {
+	d.recordAccess(key)
 	g := d.RawValues.Get(key)
 	return g.Type == gjson.Null || len(g.Raw) == 0 || g.Raw == "\"\"" || emptyObjectOrArray(g)
 }
@@ -72,3 +72,77 @@ func TestResourceDataEmpty(t *testing.T) {
 	}
 
 }
+
+func TestResourceDataUncertainInputs(t *testing.T) {
+	r := NewResourceData("aws_instance", "aws", "aws_instance.web", nil, gjson.Parse(`{
+		"ami": "ami-mock",
+		"instance_type": "t3.large",
+		"root_block_device": [{"volume_size": 50}],
+		"ebs_block_device": [{"volume_size": 100}]
+	}`))
+	r.Metadata = map[string]gjson.Result{
+		ProvenanceMetadataKey: gjson.Parse(`{
+			"ami": "mocked",
+			"instance_type": "var_file",
+			"root_block_device.0.volume_size": "unknown",
+			"ebs_block_device.0.volume_size": "mocked"
+		}`),
+	}
+
+	assert.Nil(t, r.UncertainInputs())
+
+	r.TrackAccess(func() {
+		r.Get("instance_type")
+		r.Get("root_block_device.#.volume_size")
+		r.IsEmpty("ami")
+	})
+
+	// reads after tracking has stopped are ignored.
+	r.Get("ebs_block_device")
+
+	assert.Equal(t, map[string]Provenance{
+		"ami":                             ProvenanceMocked,
+		"root_block_device.0.volume_size": ProvenanceUnknown,
+	}, r.UncertainInputs())
+}
+
+func TestBuildResourceSetsCostComponentUncertainInputs(t *testing.T) {
+	d := NewResourceData("aws_instance", "aws", "aws_instance.web", nil, gjson.Parse(`{
+		"instance_type": "instance_type-mock",
+		"ami": "ami-mock",
+		"root_block_device": [{"volume_size": "volume_size-mock"}]
+	}`))
+	d.Metadata = map[string]gjson.Result{
+		UncertainInputsMetadataKey: gjson.Parse(`{
+			"instance_type": "mocked",
+			"ami": "mocked",
+			"root_block_device.0.volume_size": "mocked",
+			"tenancy": "unknown"
+		}`),
+	}
+
+	instanceType := "instance_type-mock"
+	r := &Resource{
+		Name: "aws_instance.web",
+		CostComponents: []*CostComponent{
+			{
+				Name: "Instance usage (Linux/UNIX, on-demand, instance_type-mock)",
+				ProductFilter: &ProductFilter{
+					AttributeFilters: []*AttributeFilter{{Key: "instanceType", Value: &instanceType}},
+				},
+			},
+			{Name: "CPU credits"},
+		},
+		SubResources: []*Resource{
+			{Name: "root_block_device", CostComponents: []*CostComponent{{Name: "Storage (general purpose SSD, gp2)"}}},
+		},
+	}
+
+	built := BuildResource(NewPartialResource(d, r, nil, nil), nil)
+
+	assert.Equal(t, []string{"instance_type"}, built.CostComponents[0].UncertainInputs)
+	assert.Nil(t, built.CostComponents[1].UncertainInputs)
+	// the mocked volume size is read as a number, so it isn't in the cost
+	// component and is only listed on the resource.
+	assert.Nil(t, built.SubResources[0].CostComponents[0].UncertainInputs)
+}
@@ -270,6 +270,41 @@ func (usage UsageMap) Get(address string) *UsageData {
 	return data
 }
 
+// KeySource returns the usage map key that provides the value of the usage
+// attribute attr for the resource at address. It follows the same hierarchy as
+// Get, so a key for the exact resource address is preferred over a wildcard
+// key, which is preferred over a resource type default key. An empty string is
+// returned if no key in the usage map provides the attribute.
+func (usage UsageMap) KeySource(address string, attr string) string {
+	if ud := usage.data[address]; ud != nil {
+		if _, ok := ud.Attributes[attr]; ok {
+			return address
+		}
+	}
+
+	for _, key := range usage.wildcards {
+		if key.regexp.MatchString(address) {
+			if _, ok := usage.data[key.raw].Attributes[attr]; ok {
+				return key.raw
+			}
+
+			break
+		}
+	}
+
+	parsedAddress, err := addressParser.NewAddress(address)
+	if err == nil {
+		t := parsedAddress.ResourceSpec.Type
+		if ud := usage.data[t]; ud != nil {
+			if _, ok := ud.Attributes[attr]; ok {
+				return t
+			}
+		}
+	}
+
+	return ""
+}
+
 var wildCardRegxp = regexp.MustCompile(`\[.*?]`)
 
 // wildcard contains information about a wildcard specified usage key.
@@ -213,3 +213,38 @@ func TestUsageMap_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageMap_KeySource(t *testing.T) {
+	usage := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function": map[string]interface{}{
+			"monthly_requests":    100,
+			"request_duration_ms": 100,
+			"architecture":        "x86_64",
+		},
+		"aws_lambda_function.hello_world[*]": map[string]interface{}{
+			"monthly_requests":    200,
+			"request_duration_ms": 200,
+		},
+		`aws_lambda_function.hello_world["foo"]`: map[string]interface{}{
+			"monthly_requests": 300,
+		},
+	})
+
+	tests := []struct {
+		address string
+		attr    string
+		want    string
+	}{
+		{address: `aws_lambda_function.hello_world["foo"]`, attr: "monthly_requests", want: `aws_lambda_function.hello_world["foo"]`},
+		{address: `aws_lambda_function.hello_world["foo"]`, attr: "request_duration_ms", want: "aws_lambda_function.hello_world[*]"},
+		{address: `aws_lambda_function.hello_world["foo"]`, attr: "architecture", want: "aws_lambda_function"},
+		{address: `aws_lambda_function.hello_world["foo"]`, attr: "storage_gb", want: ""},
+		{address: "aws_lambda_function.other", attr: "monthly_requests", want: "aws_lambda_function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address+"/"+tt.attr, func(t *testing.T) {
+			assert.Equal(t, tt.want, usage.KeySource(tt.address, tt.attr))
+		})
+	}
+}
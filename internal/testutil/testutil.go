@@ -155,6 +155,18 @@ func formatCost(d *decimal.Decimal) string {
 	return formatAmount(*d)
 }
 
+// DecimalPtr returns a pointer to d, for building resources and cost
+// components in tests.
+func DecimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+// StrPtr returns a pointer to s, for building resources and cost components in
+// tests.
+func StrPtr(s string) *string {
+	return &s
+}
+
 func AssertGoldenFile(t *testing.T, goldenFilePath string, actual []byte) bool {
 	// Load the snapshot result
 	expected := []byte("")
@@ -61,3 +61,17 @@ func YesNoPrompt(label string) bool {
 		}
 	}
 }
+
+// IsInteractive returns true if the user can answer prompts, i.e. both stdin
+// and stdout are terminals. Prompts aren't shown if the output is redirected
+// since they'd be written into it.
+func IsInteractive() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		fi, err := f.Stat()
+		if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,204 @@
+// Package whatif re-prices projects with attribute, region and usage overrides
+// applied to the parsed resources, so the cost of a change can be estimated
+// without editing the Terraform code.
+package whatif
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	minScenariosFileVersion = "0.1"
+	maxScenariosFileVersion = "0.1"
+)
+
+// ScenariosFile is the format of a scenarios file.
+type ScenariosFile struct {
+	Version   string      `yaml:"version"`
+	Scenarios []*Scenario `yaml:"scenarios"`
+}
+
+// Scenario is a named set of overrides that are applied to the resources of
+// every project.
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// Regions overrides the region of all resources of a provider, keyed by the
+	// provider prefix of the resource type, e.g. aws, azurerm or google.
+	Regions   map[string]string `yaml:"regions,omitempty"`
+	Overrides []*Override       `yaml:"resources,omitempty"`
+}
+
+// Override patches the resources matched by Match.
+type Override struct {
+	// Match is either a resource type or a glob matched against the resource
+	// address, e.g. aws_instance or module.app.aws_instance.*
+	Match string `yaml:"match"`
+	// Region overrides the region of the matched resources.
+	Region string `yaml:"region,omitempty"`
+	// Attributes are top level attribute values that replace the values parsed
+	// from the Terraform code.
+	Attributes map[string]interface{} `yaml:"attributes,omitempty"`
+	// Usage values replace any values from the usage file.
+	Usage map[string]interface{} `yaml:"usage,omitempty"`
+
+	glob glob.Glob
+}
+
+// LoadScenariosFile reads and validates the scenarios file at path.
+func LoadScenariosFile(path string) ([]*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scenarios file: %w", err)
+	}
+
+	var f ScenariosFile
+	err = yaml.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing scenarios file: %w", err)
+	}
+
+	if !checkVersion(f.Version) {
+		return nil, fmt.Errorf("version '%s' is not supported, valid versions are %s ≤ x ≤ %s", f.Version, minScenariosFileVersion, maxScenariosFileVersion)
+	}
+
+	if len(f.Scenarios) == 0 {
+		return nil, errors.New("scenarios file does not contain any scenarios")
+	}
+
+	seen := map[string]bool{}
+	for _, s := range f.Scenarios {
+		if err := s.init(); err != nil {
+			return nil, err
+		}
+
+		if seen[s.Name] {
+			return nil, fmt.Errorf("duplicate scenario name %q", s.Name)
+		}
+		seen[s.Name] = true
+	}
+
+	return f.Scenarios, nil
+}
+
+// NewScenario creates a scenario from the --set, --set-usage and --region flag
+// values. sets and usageSets are in the format <match>:<key>=<value>, and
+// regions are in the format <provider>=<region>.
+func NewScenario(name string, sets, usageSets, regions []string) (*Scenario, error) {
+	s := &Scenario{
+		Name:    name,
+		Regions: map[string]string{},
+	}
+
+	byMatch := map[string]*Override{}
+	override := func(match string) *Override {
+		o, ok := byMatch[match]
+		if !ok {
+			o = &Override{Match: match, Attributes: map[string]interface{}{}, Usage: map[string]interface{}{}}
+			byMatch[match] = o
+			s.Overrides = append(s.Overrides, o)
+		}
+
+		return o
+	}
+
+	for _, v := range sets {
+		match, key, value, err := parseSet(v)
+		if err != nil {
+			return nil, err
+		}
+
+		override(match).Attributes[key] = value
+	}
+
+	for _, v := range usageSets {
+		match, key, value, err := parseSet(v)
+		if err != nil {
+			return nil, err
+		}
+
+		override(match).Usage[key] = value
+	}
+
+	for _, v := range regions {
+		provider, region, ok := strings.Cut(v, "=")
+		if !ok || provider == "" || region == "" {
+			return nil, fmt.Errorf("invalid region %q, expected format <provider>=<region>", v)
+		}
+
+		s.Regions[provider] = region
+	}
+
+	if err := s.init(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// parseSet parses a value in the format <match>:<key>=<value>. The value is
+// parsed as JSON so numbers and booleans keep their type, otherwise it is used
+// as a string.
+func parseSet(v string) (string, string, interface{}, error) {
+	match, rest, ok := strings.Cut(v, ":")
+	key, raw, ok2 := strings.Cut(rest, "=")
+	if !ok || !ok2 || match == "" || key == "" {
+		return "", "", nil, fmt.Errorf("invalid override %q, expected format <match>:<key>=<value>", v)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+
+	return match, key, value, nil
+}
+
+func checkVersion(v string) bool {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return semver.Compare(v, "v"+minScenariosFileVersion) >= 0 && semver.Compare(v, "v"+maxScenariosFileVersion) <= 0
+}
+
+func (s *Scenario) init() error {
+	if s.Name == "" {
+		return errors.New("scenario name is required")
+	}
+
+	if len(s.Overrides) == 0 && len(s.Regions) == 0 {
+		return fmt.Errorf("scenario %q does not contain any overrides", s.Name)
+	}
+
+	for _, o := range s.Overrides {
+		if o.Match == "" {
+			return fmt.Errorf("scenario %q has a resource override without a match", s.Name)
+		}
+
+		g, err := glob.Compile(o.Match)
+		if err != nil {
+			return fmt.Errorf("scenario %q has an invalid match %q: %w", s.Name, o.Match, err)
+		}
+		o.glob = g
+	}
+
+	return nil
+}
+
+// Matches returns if the override applies to the resource with the given type
+// and address.
+func (o *Override) Matches(resourceType, address string) bool {
+	if o.Match == resourceType || o.Match == address {
+		return true
+	}
+
+	return o.glob != nil && o.glob.Match(address)
+}
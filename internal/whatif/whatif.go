@@ -0,0 +1,148 @@
+package whatif
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// RebuildFunc creates a partial resource from resource data, it returns nil if
+// the resource can't be priced.
+type RebuildFunc func(d *schema.ResourceData) *schema.PartialResource
+
+// Result is a project with a scenario applied.
+type Result struct {
+	// Project has the baseline resources as its past resources and the
+	// resources with the scenario applied as its resources, so the diff between
+	// them is the cost impact of the scenario.
+	Project *schema.Project
+	// Changed are the resources that were rebuilt and need to be priced.
+	Changed []*schema.Resource
+}
+
+// Apply applies the scenario to a project that has been built and priced. The
+// resources that the scenario doesn't affect are reused from the baseline.
+func (s *Scenario) Apply(baseline *schema.Project, rebuild RebuildFunc) *Result {
+	project := schema.NewProject(fmt.Sprintf("%s (%s)", baseline.Name, s.Name), baseline.Metadata)
+	project.PastResources = baseline.Resources
+
+	res := &Result{Project: project}
+
+	for i, partial := range baseline.PartialResources {
+		if i >= len(baseline.Resources) {
+			break
+		}
+
+		r := baseline.Resources[i]
+
+		d := s.patch(partial)
+		if d != nil {
+			if p := rebuild(d); p != nil {
+				r = schema.BuildResource(p, partial.FetchedUsageData)
+				res.Changed = append(res.Changed, r)
+			}
+		}
+
+		project.PartialResources = append(project.PartialResources, partial)
+		project.Resources = append(project.Resources, r)
+	}
+
+	return res
+}
+
+// patch returns a copy of the resource data of partial with the scenario
+// overrides applied, or nil if no overrides apply to the resource.
+func (s *Scenario) patch(partial *schema.PartialResource) *schema.ResourceData {
+	orig := partial.ResourceData
+	if orig == nil {
+		return nil
+	}
+
+	region := s.Regions[providerPrefix(orig.Type)]
+	attributes := map[string]interface{}{}
+	usage := map[string]interface{}{}
+
+	for _, o := range s.Overrides {
+		if !o.Matches(orig.Type, orig.Address) {
+			continue
+		}
+
+		if o.Region != "" {
+			region = o.Region
+		}
+
+		for k, v := range o.Attributes {
+			attributes[k] = v
+		}
+
+		for k, v := range o.Usage {
+			usage[k] = v
+		}
+	}
+
+	if region == "" && len(attributes) == 0 && len(usage) == 0 {
+		return nil
+	}
+
+	d := &schema.ResourceData{
+		Type:          orig.Type,
+		ProviderName:  orig.ProviderName,
+		Address:       orig.Address,
+		Tags:          orig.Tags,
+		RawValues:     orig.RawValues,
+		ReferencesMap: orig.ReferencesMap,
+		CFResource:    orig.CFResource,
+		UsageData:     overrideUsage(partial.Address, partial.UsageData, usage),
+		Metadata:      orig.Metadata,
+	}
+
+	if region != "" {
+		d.Set("region", region)
+
+		// Azure resources look up the region from the location attribute first
+		if providerPrefix(d.Type) == "azurerm" {
+			d.Set("location", region)
+		}
+	}
+
+	for k, v := range attributes {
+		d.Set(k, v)
+	}
+
+	return d
+}
+
+// overrideUsage returns a copy of u with the values from overrides replacing
+// any existing values.
+func overrideUsage(address string, u *schema.UsageData, overrides map[string]interface{}) *schema.UsageData {
+	if len(overrides) == 0 {
+		return u
+	}
+
+	attrs := map[string]gjson.Result{}
+	if u != nil {
+		for k, v := range u.Attributes {
+			attrs[k] = v
+		}
+	}
+
+	for k, v := range overrides {
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+
+		attrs[k] = gjson.ParseBytes(b)
+	}
+
+	return schema.NewUsageData(address, attrs)
+}
+
+func providerPrefix(resourceType string) string {
+	prefix, _, _ := strings.Cut(resourceType, "_")
+	return prefix
+}
@@ -0,0 +1,182 @@
+package whatif
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestLoadScenariosFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{
+			name: "valid",
+			contents: `version: 0.1
+scenarios:
+  - name: graviton
+    resources:
+      - match: aws_instance
+        attributes:
+          instance_type: m7g.large
+        usage:
+          operating_system: linux
+  - name: eu-west-1
+    regions:
+      aws: eu-west-1
+`,
+		},
+		{
+			name:     "invalid version",
+			contents: "version: 0.2\nscenarios:\n  - name: a\n    regions:\n      aws: eu-west-1\n",
+			err:      "version '0.2' is not supported",
+		},
+		{
+			name:     "no overrides",
+			contents: "version: 0.1\nscenarios:\n  - name: a\n",
+			err:      `scenario "a" does not contain any overrides`,
+		},
+		{
+			name:     "duplicate name",
+			contents: "version: 0.1\nscenarios:\n  - name: a\n    regions:\n      aws: eu-west-1\n  - name: a\n    regions:\n      aws: eu-west-2\n",
+			err:      `duplicate scenario name "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenarios.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), os.ModePerm))
+
+			scenarios, err := LoadScenariosFile(path)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, scenarios, 2)
+			assert.Equal(t, "m7g.large", scenarios[0].Overrides[0].Attributes["instance_type"])
+			assert.Equal(t, "eu-west-1", scenarios[1].Regions["aws"])
+		})
+	}
+}
+
+func TestNewScenario(t *testing.T) {
+	s, err := NewScenario("whatif",
+		[]string{"aws_instance.*:instance_type=m7g.large", "aws_instance.*:ebs_optimized=true"},
+		[]string{"aws_lambda_function:monthly_requests=1000000"},
+		[]string{"aws=eu-west-1"},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, s.Overrides, 2)
+	assert.Equal(t, map[string]interface{}{"instance_type": "m7g.large", "ebs_optimized": true}, s.Overrides[0].Attributes)
+	assert.Equal(t, map[string]interface{}{"monthly_requests": float64(1000000)}, s.Overrides[1].Usage)
+	assert.Equal(t, map[string]string{"aws": "eu-west-1"}, s.Regions)
+
+	_, err = NewScenario("whatif", []string{"aws_instance.web"}, nil, nil)
+	assert.Error(t, err)
+
+	_, err = NewScenario("whatif", nil, nil, []string{"eu-west-1"})
+	assert.Error(t, err)
+}
+
+func TestOverrideMatches(t *testing.T) {
+	s, err := NewScenario("whatif", []string{
+		"aws_instance:a=1",
+		"module.app.*:a=1",
+		`aws_db_instance.db["prod"]:a=1`,
+	}, nil, nil)
+	require.NoError(t, err)
+
+	assert.True(t, s.Overrides[0].Matches("aws_instance", "module.other.aws_instance.web"))
+	assert.False(t, s.Overrides[0].Matches("aws_ebs_volume", "aws_ebs_volume.web"))
+	assert.True(t, s.Overrides[1].Matches("aws_ebs_volume", "module.app.aws_ebs_volume.web"))
+	assert.False(t, s.Overrides[1].Matches("aws_ebs_volume", "aws_ebs_volume.web"))
+	assert.True(t, s.Overrides[2].Matches("aws_db_instance", `aws_db_instance.db["prod"]`))
+}
+
+func TestApply(t *testing.T) {
+	baseline := schema.NewProject("my-project", &schema.ProjectMetadata{})
+
+	for _, raw := range []string{
+		`{"instance_type": "m5.large", "region": "us-east-1"}`,
+		`{"type": "gp2", "region": "us-east-1"}`,
+	} {
+		v := gjson.Parse(raw)
+		resourceType := "aws_instance"
+		if v.Get("type").Exists() {
+			resourceType = "aws_ebs_volume"
+		}
+
+		d := schema.NewResourceData(resourceType, "aws", resourceType+".web", nil, v)
+		d.UsageData = schema.NewUsageData(d.Address, map[string]gjson.Result{"monthly_hrs": gjson.Parse("100")})
+		partial := schema.NewPartialResource(d, &schema.Resource{Name: d.Address}, nil, nil)
+
+		baseline.PartialResources = append(baseline.PartialResources, partial)
+		baseline.Resources = append(baseline.Resources, schema.BuildResource(partial, nil))
+	}
+
+	s, err := NewScenario("graviton",
+		[]string{"aws_instance:instance_type=m7g.large"},
+		[]string{"aws_instance:operating_system=linux"},
+		nil,
+	)
+	require.NoError(t, err)
+
+	var rebuilt []*schema.ResourceData
+	res := s.Apply(baseline, func(d *schema.ResourceData) *schema.PartialResource {
+		rebuilt = append(rebuilt, d)
+		return schema.NewPartialResource(d, &schema.Resource{Name: d.Address, MonthlyCost: testutil.DecimalPtr(decimal.NewFromInt(1))}, nil, nil)
+	})
+
+	assert.Equal(t, "my-project (graviton)", res.Project.Name)
+	assert.Equal(t, baseline.Resources, res.Project.PastResources)
+	require.Len(t, res.Project.Resources, 2)
+	require.Len(t, res.Changed, 1)
+	assert.Same(t, res.Changed[0], res.Project.Resources[0])
+	assert.Same(t, baseline.Resources[1], res.Project.Resources[1])
+
+	require.Len(t, rebuilt, 1)
+	assert.Equal(t, "m7g.large", rebuilt[0].Get("instance_type").String())
+	assert.Equal(t, "us-east-1", rebuilt[0].Get("region").String())
+	assert.Equal(t, int64(100), rebuilt[0].UsageData.Get("monthly_hrs").Int())
+	assert.Equal(t, "linux", rebuilt[0].UsageData.Get("operating_system").String())
+
+	// the baseline resource data is not modified
+	assert.Equal(t, "m5.large", baseline.PartialResources[0].ResourceData.Get("instance_type").String())
+	assert.False(t, baseline.PartialResources[0].UsageData.Get("operating_system").Exists())
+}
+
+func TestApplyRegion(t *testing.T) {
+	baseline := schema.NewProject("my-project", &schema.ProjectMetadata{})
+	d := schema.NewResourceData("azurerm_linux_virtual_machine", "azurerm", "azurerm_linux_virtual_machine.vm", nil, gjson.Parse(`{"location": "eastus"}`))
+	partial := schema.NewPartialResource(d, &schema.Resource{Name: d.Address}, nil, nil)
+	baseline.PartialResources = append(baseline.PartialResources, partial)
+	baseline.Resources = append(baseline.Resources, schema.BuildResource(partial, nil))
+
+	s, err := NewScenario("westeurope", nil, nil, []string{"azurerm=westeurope", "aws=eu-west-1"})
+	require.NoError(t, err)
+
+	var rebuilt *schema.ResourceData
+	s.Apply(baseline, func(d *schema.ResourceData) *schema.PartialResource {
+		rebuilt = d
+		return nil
+	})
+
+	require.NotNil(t, rebuilt)
+	assert.Equal(t, "westeurope", rebuilt.Get("location").String())
+	assert.Equal(t, "westeurope", rebuilt.Get("region").String())
+}